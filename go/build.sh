@@ -32,10 +32,12 @@ export COMMANDS=(
 	socialapi/workers/cmd/realtime
 	socialapi/workers/cmd/realtime/gatekeeper
 	socialapi/workers/cmd/realtime/dispatcher
+	socialapi/workers/cmd/realtime/gateway
 	socialapi/workers/cmd/migrator
 	socialapi/workers/cmd/algoliaconnector
 	socialapi/workers/cmd/algoliaconnector/deletedaccountremover
 	socialapi/workers/cmd/presence
+	socialapi/workers/cmd/webhook
 	socialapi/workers/cmd/collaboration
 	socialapi/workers/cmd/email/emailsender
 	socialapi/workers/cmd/team
@@ -35,6 +35,37 @@ type ComputeStack struct {
 	Config   bson.M `bson:"config,omitempty"`
 	Meta     bson.M `bson:"meta,omitempty"`
 	Title    string `bson:"title,omitempty"`
+
+	// Drift holds the result of the most recent drift check.
+	Drift *StackDrift `bson:"drift,omitempty"`
+}
+
+// StackDrift describes differences between the Terraform state
+// of a stack and the actual state of its resources.
+type StackDrift struct {
+	CheckedAt time.Time          `bson:"checkedAt,omitempty" json:"checkedAt,omitempty"`
+	ClaimedAt time.Time          `bson:"claimedAt,omitempty" json:"claimedAt,omitempty"` // set while a check is in progress
+	Resources []*DriftedResource `bson:"resources,omitempty" json:"resources,omitempty"`
+	Error     string             `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// Checked returns true when the stack was checked for drift at
+// least once.
+func (sd *StackDrift) Checked() bool {
+	return sd != nil && !sd.CheckedAt.IsZero()
+}
+
+// Drifted returns true when any of the stack resources differ
+// from the state recorded on the last apply.
+func (sd *StackDrift) Drifted() bool {
+	return sd != nil && len(sd.Resources) != 0
+}
+
+// DriftedResource describes a single drifted resource.
+type DriftedResource struct {
+	Name       string   `bson:"name" json:"name"`   // e.g. "aws_instance.example"
+	State      string   `bson:"state" json:"state"` // "modified" or "deleted"
+	Attributes []string `bson:"attributes,omitempty" json:"attributes,omitempty"`
 }
 
 func (c *ComputeStack) State() stackstate.State {
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DefaultIdleMinutes is an idle threshold used by policies,
+// which do not specify one.
+const DefaultIdleMinutes = 50
+
+// IdlePolicy is a document from jIdlePolicies collection.
+//
+// It describes when running machines of a team are stopped
+// by kloud due to inactivity.
+type IdlePolicy struct {
+	Id bson.ObjectId `bson:"_id,omitempty" json:"-"`
+
+	// Group slug.
+	Group string `bson:"group" json:"group"`
+
+	// GroupID is an ID of the group, used by kloud to match
+	// machines with policies.
+	GroupID bson.ObjectId `bson:"groupId,omitempty" json:"-"`
+
+	// Enabled tells whether the policy is enforced. When disabled,
+	// machines are stopped after the default threshold.
+	Enabled bool `bson:"enabled" json:"enabled"`
+
+	// IdleMinutes is a number of minutes after which an inactive
+	// machine is stopped. If zero, DefaultIdleMinutes is used.
+	IdleMinutes int `bson:"idleMinutes,omitempty" json:"idleMinutes,omitempty"`
+
+	// ExemptMachines holds ids or labels of machines, which are
+	// never stopped due to inactivity.
+	ExemptMachines []string `bson:"exemptMachines,omitempty" json:"exemptMachines,omitempty"`
+
+	// ExemptUsers holds usernames, whose machines are never
+	// stopped due to inactivity.
+	ExemptUsers []string `bson:"exemptUsers,omitempty" json:"exemptUsers,omitempty"`
+
+	// Windows restricts the policy to the given time windows, e.g.
+	// outside of office hours. If empty, the policy is always enforced.
+	Windows []*IdleWindow `bson:"windows,omitempty" json:"windows,omitempty"`
+
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+	UpdatedBy string    `bson:"updatedBy,omitempty" json:"updatedBy,omitempty"` // username
+}
+
+// IdleWindow is a recurring time window, during which
+// an idle policy is enforced.
+type IdleWindow struct {
+	// Days is a list of week days the window applies to, where 0 is
+	// Sunday. If empty, the window applies to every day.
+	Days []time.Weekday `bson:"days,omitempty" json:"days,omitempty"`
+
+	// Start and End are times of the day in a "15:04" format.
+	// If End is before Start, the window spans over midnight.
+	Start string `bson:"start" json:"start"`
+	End   string `bson:"end" json:"end"`
+
+	// Timezone is an IANA location name, e.g. "Europe/Warsaw".
+	// If empty, UTC is used.
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// Valid implements the stack.Validator interface.
+func (p *IdlePolicy) Valid() error {
+	if p.Group == "" {
+		return errors.New("group is empty")
+	}
+
+	if p.IdleMinutes < 0 {
+		return fmt.Errorf("invalid idle threshold: %d", p.IdleMinutes)
+	}
+
+	for i, w := range p.Windows {
+		if err := w.Valid(); err != nil {
+			return fmt.Errorf("invalid window #%d: %s", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Threshold gives a duration after which an inactive
+// machine is stopped.
+func (p *IdlePolicy) Threshold() time.Duration {
+	if p.IdleMinutes != 0 {
+		return time.Duration(p.IdleMinutes) * time.Minute
+	}
+
+	return DefaultIdleMinutes * time.Minute
+}
+
+// IsExempt tells whether the machine with the given id and label,
+// or owned by the given user, is exempted from the policy.
+func (p *IdlePolicy) IsExempt(machineID, label, username string) bool {
+	for _, m := range p.ExemptMachines {
+		if m == machineID || (label != "" && m == label) {
+			return true
+		}
+	}
+
+	for _, u := range p.ExemptUsers {
+		if u == username {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsEnforced tells whether the policy is enforced at the given time.
+func (p *IdlePolicy) IsEnforced(t time.Time) bool {
+	if !p.Enabled {
+		return false
+	}
+
+	if len(p.Windows) == 0 {
+		return true
+	}
+
+	for _, w := range p.Windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Valid implements the stack.Validator interface.
+func (w *IdleWindow) Valid() error {
+	if _, err := time.Parse("15:04", w.Start); err != nil {
+		return fmt.Errorf("invalid start time %q", w.Start)
+	}
+
+	if _, err := time.Parse("15:04", w.End); err != nil {
+		return fmt.Errorf("invalid end time %q", w.End)
+	}
+
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", w.Timezone)
+	}
+
+	for _, day := range w.Days {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid day %d", day)
+		}
+	}
+
+	return nil
+}
+
+// Contains tells whether the given time falls within the window.
+//
+// An invalid window contains no time.
+func (w *IdleWindow) Contains(t time.Time) bool {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false
+	}
+
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return false
+	}
+
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return false
+	}
+
+	t = t.In(loc)
+
+	var (
+		now  = t.Hour()*60 + t.Minute()
+		from = start.Hour()*60 + start.Minute()
+		to   = end.Hour()*60 + end.Minute()
+		day  = t.Weekday()
+	)
+
+	switch {
+	case from <= to:
+		return now >= from && now < to && w.hasDay(day)
+	case now >= from:
+		return w.hasDay(day)
+	case now < to:
+		// The window started on the previous day.
+		return w.hasDay((day + 6) % 7)
+	default:
+		return false
+	}
+}
+
+func (w *IdleWindow) hasDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+
+	return false
+}
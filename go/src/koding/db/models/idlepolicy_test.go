@@ -0,0 +1,123 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"koding/db/models"
+)
+
+func TestIdleWindowContains(t *testing.T) {
+	// 2016-08-08 is a Monday.
+	at := func(hour, min int) time.Time {
+		return time.Date(2016, 8, 8, hour, min, 0, 0, time.UTC)
+	}
+
+	cases := map[string]struct {
+		window *models.IdleWindow
+		t      time.Time
+		ok     bool
+	}{
+		"within window": {
+			&models.IdleWindow{Start: "09:00", End: "17:00"},
+			at(12, 30),
+			true,
+		},
+		"end is exclusive": {
+			&models.IdleWindow{Start: "09:00", End: "17:00"},
+			at(17, 0),
+			false,
+		},
+		"other day": {
+			&models.IdleWindow{Days: []time.Weekday{time.Saturday, time.Sunday}, Start: "09:00", End: "17:00"},
+			at(12, 0),
+			false,
+		},
+		"overnight before midnight": {
+			&models.IdleWindow{Days: []time.Weekday{time.Monday}, Start: "20:00", End: "06:00"},
+			at(22, 0),
+			true,
+		},
+		"overnight after midnight": {
+			&models.IdleWindow{Days: []time.Weekday{time.Sunday}, Start: "20:00", End: "06:00"},
+			at(3, 0),
+			true,
+		},
+		"overnight started on other day": {
+			&models.IdleWindow{Days: []time.Weekday{time.Monday}, Start: "20:00", End: "06:00"},
+			at(3, 0),
+			false,
+		},
+		"timezone": {
+			&models.IdleWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"},
+			at(8, 0),
+			false,
+		},
+		"invalid window": {
+			&models.IdleWindow{Start: "9am", End: "17:00"},
+			at(12, 0),
+			false,
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if ok := cas.window.Contains(cas.t); ok != cas.ok {
+				t.Fatalf("got %t, want %t", ok, cas.ok)
+			}
+		})
+	}
+}
+
+func TestIdlePolicy(t *testing.T) {
+	p := &models.IdlePolicy{
+		Group:          "koding",
+		Enabled:        true,
+		IdleMinutes:    120,
+		ExemptMachines: []string{"build-server"},
+		ExemptUsers:    []string{"john"},
+		Windows: []*models.IdleWindow{
+			{Start: "18:00", End: "08:00"},
+		},
+	}
+
+	if err := p.Valid(); err != nil {
+		t.Fatalf("Valid()=%s", err)
+	}
+
+	if d := p.Threshold(); d != 2*time.Hour {
+		t.Fatalf("got %s, want %s", d, 2*time.Hour)
+	}
+
+	if !p.IsExempt("57a8c9f6", "build-server", "user") {
+		t.Fatal("expected machine to be exempted by label")
+	}
+
+	if !p.IsExempt("57a8c9f6", "", "john") {
+		t.Fatal("expected machine to be exempted by owner")
+	}
+
+	if p.IsExempt("57a8c9f6", "web", "user") {
+		t.Fatal("expected machine not to be exempted")
+	}
+
+	if p.IsEnforced(time.Date(2016, 8, 8, 12, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected policy not to be enforced during the day")
+	}
+
+	if !p.IsEnforced(time.Date(2016, 8, 8, 23, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected policy to be enforced during the night")
+	}
+
+	p.Enabled = false
+
+	if p.IsEnforced(time.Date(2016, 8, 8, 23, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected disabled policy not to be enforced")
+	}
+
+	p.Windows[0].Timezone = "Mars/Olympus_Mons"
+
+	if err := p.Valid(); err == nil {
+		t.Fatal("expected Valid() to fail")
+	}
+}
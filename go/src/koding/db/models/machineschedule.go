@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// MachineSchedule describes when a machine is automatically started
+// and stopped. It is stored under "meta.schedule" field of a jMachine
+// document.
+type MachineSchedule struct {
+	// Start and Stop are crontab expressions, which tell when the
+	// machine is started and stopped, e.g. "0 8 * * 1-5" for 08:00
+	// on weekdays. Descriptors like "@daily" are also supported.
+	// Either of them can be empty.
+	Start string `bson:"start,omitempty" json:"start,omitempty"`
+	Stop  string `bson:"stop,omitempty" json:"stop,omitempty"`
+
+	// Timezone is an IANA location name, e.g. "Europe/Warsaw".
+	// If empty, UTC is used.
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"`
+
+	// CheckedAt is the time the schedule was last executed by kloud.
+	CheckedAt time.Time `bson:"checkedAt,omitempty" json:"checkedAt,omitempty"`
+}
+
+// Scheduled actions returned by (*MachineSchedule).Due.
+const (
+	ScheduleStart = "start"
+	ScheduleStop  = "stop"
+)
+
+// maxScheduleLag is the longest period of time, which is looked back
+// when searching for due actions. It ensures a machine is not started
+// or stopped when its schedule was not checked for a long time.
+const maxScheduleLag = 24 * time.Hour
+
+// maxScheduleWindow is the longest period of time, which is looked back
+// when searching for the action which started the current window.
+const maxScheduleWindow = 7 * 24 * time.Hour
+
+// Valid implements the stack.Validator interface.
+func (s *MachineSchedule) Valid() error {
+	if s.Start == "" && s.Stop == "" {
+		return errors.New("schedule is empty")
+	}
+
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", s.Timezone)
+	}
+
+	if s.Start != "" {
+		if _, err := parseCron(s.Start); err != nil {
+			return fmt.Errorf("invalid start expression %q: %s", s.Start, err)
+		}
+	}
+
+	if s.Stop != "" {
+		if _, err := parseCron(s.Stop); err != nil {
+			return fmt.Errorf("invalid stop expression %q: %s", s.Stop, err)
+		}
+	}
+
+	return nil
+}
+
+// Next gives times of the next start and stop of the machine
+// after the given time. Zero time is returned for an empty
+// or invalid expression.
+func (s *MachineSchedule) Next(t time.Time) (start, stop time.Time) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+
+	t = t.In(loc)
+
+	return next(s.Start, t), next(s.Stop, t)
+}
+
+// Due gives an action, which was scheduled in the (from, to] period.
+// If both start and stop were scheduled, the one which was scheduled
+// later wins. If there was nothing scheduled, empty string is returned.
+func (s *MachineSchedule) Due(from, to time.Time) string {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return ""
+	}
+
+	if from.IsZero() || to.Sub(from) > maxScheduleLag {
+		from = to.Add(-maxScheduleLag)
+	}
+
+	from, to = from.In(loc), to.In(loc)
+
+	start := last(s.Start, from, to)
+	stop := last(s.Stop, from, to)
+
+	switch {
+	case start.IsZero() && stop.IsZero():
+		return ""
+	case start.After(stop):
+		return ScheduleStart
+	default:
+		return ScheduleStop
+	}
+}
+
+// On tells whether the machine is scheduled to be running at the given
+// time, that is the last action scheduled before t was a start.
+func (s *MachineSchedule) On(t time.Time) bool {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+
+	from, to := t.Add(-maxScheduleWindow).In(loc), t.In(loc)
+
+	start := last(s.Start, from, to)
+	stop := last(s.Stop, from, to)
+
+	return !start.IsZero() && start.After(stop)
+}
+
+// parseCron parses the given crontab expression. The expression
+// is expected to have 5 fields, as the seconds field is implied.
+func parseCron(expr string) (cron.Schedule, error) {
+	if expr == "" {
+		return nil, errors.New("empty expression")
+	}
+
+	if expr[0] != '@' {
+		expr = "0 " + expr
+
+		if n := len(strings.Fields(expr)); n != 6 {
+			return nil, fmt.Errorf("expected 5 fields, found %d", n-1)
+		}
+	}
+
+	return cron.Parse(expr)
+}
+
+func next(expr string, t time.Time) time.Time {
+	if expr == "" {
+		return time.Time{}
+	}
+
+	sched, err := parseCron(expr)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return sched.Next(t)
+}
+
+// last gives the last time the expression was scheduled
+// in the (from, to] period.
+func last(expr string, from, to time.Time) time.Time {
+	if expr == "" {
+		return time.Time{}
+	}
+
+	sched, err := parseCron(expr)
+	if err != nil {
+		return time.Time{}
+	}
+
+	var t time.Time
+
+	for n := sched.Next(from); !n.IsZero() && !n.After(to); n = sched.Next(n) {
+		t = n
+	}
+
+	return t
+}
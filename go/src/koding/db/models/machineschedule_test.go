@@ -0,0 +1,147 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"koding/db/models"
+)
+
+func TestMachineScheduleDue(t *testing.T) {
+	sched := &models.MachineSchedule{
+		Start:    "0 8 * * 1-5",
+		Stop:     "0 20 * * 1-5",
+		Timezone: "Europe/Warsaw",
+	}
+
+	// 2016-08-08 is a Monday, Europe/Warsaw is UTC+2 in August.
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2016, 8, day, hour, min, 0, 0, time.UTC)
+	}
+
+	cases := map[string]struct {
+		from, to time.Time
+		action   string
+	}{
+		"start": {
+			at(8, 5, 59), at(8, 6, 0), models.ScheduleStart,
+		},
+		"stop": {
+			at(8, 17, 59), at(8, 18, 1), models.ScheduleStop,
+		},
+		"nothing scheduled": {
+			at(8, 6, 0), at(8, 17, 59), "",
+		},
+		"weekend": {
+			at(6, 0, 0), at(7, 23, 0), "",
+		},
+		"latest wins": {
+			at(8, 0, 0), at(8, 19, 0), models.ScheduleStop,
+		},
+		"long since checked": {
+			at(1, 0, 0), at(8, 7, 0), models.ScheduleStart,
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if action := sched.Due(cas.from, cas.to); action != cas.action {
+				t.Fatalf("got %q, want %q", action, cas.action)
+			}
+		})
+	}
+}
+
+func TestMachineScheduleOn(t *testing.T) {
+	sched := &models.MachineSchedule{
+		Start:    "0 8 * * 1-5",
+		Stop:     "0 20 * * 1-5",
+		Timezone: "Europe/Warsaw",
+	}
+
+	// 2016-08-08 is a Monday, Europe/Warsaw is UTC+2 in August.
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2016, 8, day, hour, min, 0, 0, time.UTC)
+	}
+
+	cases := map[string]struct {
+		t  time.Time
+		on bool
+	}{
+		"before start": {at(8, 5, 59), false},
+		"after start":  {at(8, 6, 0), true},
+		"before stop":  {at(8, 17, 59), true},
+		"after stop":   {at(8, 18, 0), false},
+		"weekend":      {at(7, 12, 0), false},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if on := sched.On(cas.t); on != cas.on {
+				t.Fatalf("got %t, want %t", on, cas.on)
+			}
+		})
+	}
+
+	if (&models.MachineSchedule{Stop: "@midnight"}).On(at(8, 12, 0)) {
+		t.Fatal("want stop-only schedule to be never on")
+	}
+}
+
+func TestMachineScheduleNext(t *testing.T) {
+	sched := &models.MachineSchedule{
+		Start: "30 7 * * *",
+	}
+
+	start, stop := sched.Next(time.Date(2016, 8, 8, 12, 0, 0, 0, time.UTC))
+
+	if want := time.Date(2016, 8, 9, 7, 30, 0, 0, time.UTC); !start.Equal(want) {
+		t.Fatalf("got %s, want %s", start, want)
+	}
+
+	if !stop.IsZero() {
+		t.Fatalf("got %s, want zero time", stop)
+	}
+}
+
+func TestMachineScheduleValid(t *testing.T) {
+	cases := map[string]struct {
+		sched *models.MachineSchedule
+		ok    bool
+	}{
+		"valid": {
+			&models.MachineSchedule{Start: "0 8 * * 1-5", Stop: "@midnight", Timezone: "America/New_York"},
+			true,
+		},
+		"empty": {
+			&models.MachineSchedule{},
+			false,
+		},
+		"seconds field": {
+			&models.MachineSchedule{Start: "0 0 8 * * 1-5"},
+			false,
+		},
+		"invalid expression": {
+			&models.MachineSchedule{Stop: "0 25 * * *"},
+			false,
+		},
+		"invalid timezone": {
+			&models.MachineSchedule{Start: "0 8 * * *", Timezone: "Mars/Olympus_Mons"},
+			false,
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := cas.sched.Valid()
+
+			if cas.ok && err != nil {
+				t.Fatalf("Valid()=%s", err)
+			}
+
+			if !cas.ok && err == nil {
+				t.Fatal("expected Valid() to fail")
+			}
+		})
+	}
+}
@@ -41,6 +41,32 @@ type StackTemplate struct {
 	Title       string               `bson:"title"`
 }
 
+// LastUpdaterID gives an account ID of the last user that updated
+// the template content. If it's not known, it returns template owner.
+func (t *StackTemplate) LastUpdaterID() bson.ObjectId {
+	if id, ok := t.Template.Details["lastUpdaterId"].(bson.ObjectId); ok {
+		return id
+	}
+
+	return t.OriginID
+}
+
+// StackTemplateRevision is a document from jStackTemplateRevisions
+// collection.
+//
+// Revisions are immutable - each change of the stack template
+// content is recorded as a new revision.
+type StackTemplateRevision struct {
+	Id         bson.ObjectId `bson:"_id" json:"-"`
+	TemplateID bson.ObjectId `bson:"templateId" json:"templateId"`
+	Revision   int           `bson:"revision" json:"revision"`
+	Content    string        `bson:"content" json:"content,omitempty"`
+	RawContent string        `bson:"rawContent" json:"rawContent,omitempty"`
+	Sum        string        `bson:"sum" json:"sum"`
+	AuthorID   bson.ObjectId `bson:"authorId,omitempty" json:"authorId,omitempty"` // jAccount._id
+	CreatedAt  time.Time     `bson:"createdAt" json:"createdAt"`
+}
+
 func NewStackTemplate(provider, identifier string) *StackTemplate {
 	now := time.Now().UTC()
 
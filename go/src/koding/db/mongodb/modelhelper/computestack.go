@@ -30,6 +30,21 @@ func GetComputeStack(id string) (*models.ComputeStack, error) {
 	return computeStack, nil
 }
 
+// GetComputeStackByMachine gives a stack the given machine belongs to.
+func GetComputeStackByMachine(machineID bson.ObjectId) (*models.ComputeStack, error) {
+	var stack models.ComputeStack
+
+	query := func(c *mgo.Collection) error {
+		return c.Find(bson.M{"machines": machineID}).One(&stack)
+	}
+
+	if err := Mongo.Run(ComputeStackColl, query); err != nil {
+		return nil, err
+	}
+
+	return &stack, nil
+}
+
 func GetComputeStackByGroup(slug string, accountID bson.ObjectId) (*models.ComputeStack, error) {
 	var stack models.ComputeStack
 
@@ -98,6 +113,55 @@ func SetStackState(id, reason string, state stackstate.State) error {
 	return Mongo.Run(ComputeStackColl, query)
 }
 
+// SetStackDrift records the result of a drift check for the given stack
+// and releases the claim made by the check, if any.
+func SetStackDrift(id string, drift *models.StackDrift) error {
+	if !bson.IsObjectIdHex(id) {
+		return fmt.Errorf("Not valid ObjectIdHex: %q", id)
+	}
+
+	query := func(c *mgo.Collection) error {
+		return c.Update(
+			bson.M{
+				"_id": bson.ObjectIdHex(id),
+			},
+			bson.M{
+				"$set": bson.M{
+					"drift.checkedAt": drift.CheckedAt,
+					"drift.resources": drift.Resources,
+					"drift.error":     drift.Error,
+				},
+				"$unset": bson.M{
+					"drift.claimedAt": "",
+				},
+			})
+	}
+
+	return Mongo.Run(ComputeStackColl, query)
+}
+
+// ReleaseStackDrift releases the claim made by a drift check for the
+// given stack without recording any result.
+func ReleaseStackDrift(id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return fmt.Errorf("Not valid ObjectIdHex: %q", id)
+	}
+
+	query := func(c *mgo.Collection) error {
+		return c.Update(
+			bson.M{
+				"_id": bson.ObjectIdHex(id),
+			},
+			bson.M{
+				"$unset": bson.M{
+					"drift.claimedAt": "",
+				},
+			})
+	}
+
+	return Mongo.Run(ComputeStackColl, query)
+}
+
 func CreateComputeStack(stack *models.ComputeStack) error {
 	query := insertQuery(stack)
 	return Mongo.Run(ComputeStackColl, query)
@@ -306,6 +370,19 @@ func RemoveFromStack(sd *StackDetails) error {
 	return tryPull(ComputeStackColl, bson.M{"_id": stack.Id}, bson.M{"machines": sd.MachineID})
 }
 
+// SetStackResourceOverride records attributes of the given template
+// resource, which replace the ones from the stack template when
+// the stack is applied.
+func SetStackResourceOverride(stackID bson.ObjectId, typ, name string, attrs map[string]interface{}) error {
+	change := bson.M{
+		"$set": bson.M{
+			"config.overrides." + typ + "." + name: attrs,
+		},
+	}
+
+	return UpdateStack(stackID, change)
+}
+
 func UpdateStack(stackID bson.ObjectId, change interface{}) error {
 	return Mongo.Run(ComputeStackColl, func(c *mgo.Collection) error {
 		return c.UpdateId(stackID, change)
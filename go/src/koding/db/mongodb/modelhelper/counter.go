@@ -14,6 +14,10 @@ const (
 
 	CounterStacks    = "member_stacks"
 	CounterInstances = "member_instances"
+
+	// CounterStackTemplateRevisions is a type of per-template counter,
+	// which namespace is jStackTemplate._id.
+	CounterStackTemplateRevisions = "stacktemplate_revisions"
 )
 
 func CreateCounters(counters ...*models.Counter) error {
@@ -88,6 +92,39 @@ func DecrementOrCreateCounter(group, typ string, n int) error {
 	return Mongo.Run(CountersColl, query)
 }
 
+// IncrementCounter increments the given counter by one and returns
+// its new value. If the counter does not exist, it is created.
+func IncrementCounter(namespace, typ string) (int, error) {
+	var counter models.Counter
+
+	query := func(c *mgo.Collection) error {
+		change := mgo.Change{
+			Update: bson.M{
+				"$inc": bson.M{
+					"current": 1,
+				},
+			},
+			Upsert:    true,
+			ReturnNew: true,
+		}
+
+		_, err := c.Find(
+			bson.M{
+				"namespace": namespace,
+				"type":      typ,
+			},
+		).Apply(change, &counter)
+
+		return err
+	}
+
+	if err := Mongo.Run(CountersColl, query); err != nil {
+		return 0, err
+	}
+
+	return counter.Current, nil
+}
+
 func UpdateCounters(group string, stacks, instances int) error {
 	query := func(c *mgo.Collection) error {
 		stackSel := bson.M{
@@ -0,0 +1,69 @@
+package modelhelper
+
+import (
+	"koding/db/models"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const IdlePolicyColl = "jIdlePolicies"
+
+// GetIdlePolicy gives an idle policy of the given group.
+//
+// If the group has no policy, mgo.ErrNotFound is returned.
+func GetIdlePolicy(group string) (*models.IdlePolicy, error) {
+	var policy models.IdlePolicy
+
+	query := func(c *mgo.Collection) error {
+		return c.Find(bson.M{"group": group}).One(&policy)
+	}
+
+	if err := Mongo.Run(IdlePolicyColl, query); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// GetEnabledIdlePolicies gives all idle policies, which are enabled.
+func GetEnabledIdlePolicies() ([]*models.IdlePolicy, error) {
+	var policies []*models.IdlePolicy
+
+	query := func(c *mgo.Collection) error {
+		return c.Find(bson.M{"enabled": true}).All(&policies)
+	}
+
+	if err := Mongo.Run(IdlePolicyColl, query); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// SetIdlePolicy creates or replaces an idle policy of the policy's group.
+func SetIdlePolicy(policy *models.IdlePolicy) error {
+	query := func(c *mgo.Collection) error {
+		change := mgo.Change{
+			Update: bson.M{
+				"$set": bson.M{
+					"groupId":        policy.GroupID,
+					"enabled":        policy.Enabled,
+					"idleMinutes":    policy.IdleMinutes,
+					"exemptMachines": policy.ExemptMachines,
+					"exemptUsers":    policy.ExemptUsers,
+					"windows":        policy.Windows,
+					"updatedAt":      policy.UpdatedAt,
+					"updatedBy":      policy.UpdatedBy,
+				},
+			},
+			Upsert:    true,
+			ReturnNew: true,
+		}
+
+		_, err := c.Find(bson.M{"group": policy.Group}).Apply(change, policy)
+		return err
+	}
+
+	return Mongo.Run(IdlePolicyColl, query)
+}
@@ -0,0 +1,51 @@
+package modelhelper
+
+import (
+	"koding/db/models"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GetMachineSchedule reads a schedule stored in the machine's meta.
+//
+// If the machine has no schedule, nil is returned.
+func GetMachineSchedule(m *models.Machine) (*models.MachineSchedule, error) {
+	v, ok := m.Meta["schedule"]
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	p, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var s models.MachineSchedule
+
+	if err := bson.Unmarshal(p, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// SetMachineSchedule stores the schedule in the machine's meta. If
+// the schedule is nil, the schedule is removed from the machine.
+func SetMachineSchedule(id bson.ObjectId, s *models.MachineSchedule) error {
+	update := bson.M{
+		"$unset": bson.M{"meta.schedule": 1},
+	}
+
+	if s != nil {
+		update = bson.M{
+			"$set": bson.M{"meta.schedule": s},
+		}
+	}
+
+	query := func(c *mgo.Collection) error {
+		return c.UpdateId(id, update)
+	}
+
+	return Mongo.Run(MachinesColl, query)
+}
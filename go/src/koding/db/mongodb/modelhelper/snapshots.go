@@ -29,3 +29,24 @@ func DeleteSnapshot(snapshotId string) error {
 
 	return Mongo.Run(SnapshotCol, query)
 }
+
+// GetSnapshotsByMachine gives all snapshots of the given machine,
+// ordered from the latest one.
+func GetSnapshotsByMachine(machineID bson.ObjectId) ([]*models.Snapshot, error) {
+	var snapshots []*models.Snapshot
+
+	query := func(c *mgo.Collection) error {
+		return c.Find(bson.M{"machineId": machineID}).Sort("-createdAt").All(&snapshots)
+	}
+
+	if err := Mongo.Run(SnapshotCol, query); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+func CreateSnapshot(snapshot *models.Snapshot) error {
+	query := insertQuery(snapshot)
+	return Mongo.Run(SnapshotCol, query)
+}
@@ -3,6 +3,7 @@ package modelhelper
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"koding/db/models"
 
@@ -10,7 +11,10 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-const StackTemplateColl = "jStackTemplates"
+const (
+	StackTemplateColl         = "jStackTemplates"
+	StackTemplateRevisionColl = "jStackTemplateRevisions"
+)
 
 func GetStackTemplate(id string) (*models.StackTemplate, error) {
 	if !bson.IsObjectIdHex(id) {
@@ -87,7 +91,113 @@ func HasTemplateAccess(tmpl *models.StackTemplate, username string) error {
 	return nil
 }
 
+// CreateStackTemplate inserts the given stack template and records
+// its content as the first revision.
 func CreateStackTemplate(tmpl *models.StackTemplate) error {
 	query := insertQuery(tmpl)
-	return Mongo.Run(StackTemplateColl, query)
+
+	if err := Mongo.Run(StackTemplateColl, query); err != nil {
+		return err
+	}
+
+	_, err := AddStackTemplateRevision(tmpl, tmpl.LastUpdaterID())
+	return err
+}
+
+// AddStackTemplateRevision records current content of the given stack
+// template as a new revision.
+//
+// If the content did not change since the latest revision, no new
+// revision is created and the latest one is returned instead.
+//
+// Revision numbers are allocated with a jCounters document shared
+// with social's JStackTemplate, which records a revision on each
+// content update. The unique (templateId, revision) index guarantees
+// no revision is overwritten.
+func AddStackTemplateRevision(tmpl *models.StackTemplate, authorID bson.ObjectId) (*models.StackTemplateRevision, error) {
+	latest, err := GetStackTemplateRevision(tmpl.Id.Hex(), 0)
+	if err != nil && err != mgo.ErrNotFound {
+		return nil, err
+	}
+
+	if latest != nil && latest.Sum == tmpl.Template.Sum {
+		return latest, nil
+	}
+
+	n, err := IncrementCounter(tmpl.Id.Hex(), CounterStackTemplateRevisions)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &models.StackTemplateRevision{
+		Id:         bson.NewObjectId(),
+		TemplateID: tmpl.Id,
+		Revision:   n,
+		Content:    tmpl.Template.Content,
+		RawContent: tmpl.Template.RawContent,
+		Sum:        tmpl.Template.Sum,
+		AuthorID:   authorID,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := Mongo.Run(StackTemplateRevisionColl, insertQuery(rev)); err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// GetStackTemplateRevision gives the given revision of the stack template.
+//
+// If revision is 0, the latest revision is returned.
+func GetStackTemplateRevision(templateID string, revision int) (*models.StackTemplateRevision, error) {
+	if !bson.IsObjectIdHex(templateID) {
+		return nil, fmt.Errorf("Not valid ObjectIdHex: %q", templateID)
+	}
+
+	var rev models.StackTemplateRevision
+
+	query := func(c *mgo.Collection) error {
+		selector := bson.M{
+			"templateId": bson.ObjectIdHex(templateID),
+		}
+
+		if revision != 0 {
+			selector["revision"] = revision
+		}
+
+		return c.Find(selector).Sort("-revision").One(&rev)
+	}
+
+	if err := Mongo.Run(StackTemplateRevisionColl, query); err != nil {
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+// GetStackTemplateRevisions gives all revisions of the stack template,
+// starting from the latest one.
+//
+// The content fields of the returned revisions are empty.
+func GetStackTemplateRevisions(templateID string) ([]*models.StackTemplateRevision, error) {
+	if !bson.IsObjectIdHex(templateID) {
+		return nil, fmt.Errorf("Not valid ObjectIdHex: %q", templateID)
+	}
+
+	var revs []*models.StackTemplateRevision
+
+	query := func(c *mgo.Collection) error {
+		selector := bson.M{
+			"templateId": bson.ObjectIdHex(templateID),
+		}
+
+		return c.Find(selector).Select(bson.M{"content": 0, "rawContent": 0}).Sort("-revision").All(&revs)
+	}
+
+	if err := Mongo.Run(StackTemplateRevisionColl, query); err != nil {
+		return nil, err
+	}
+
+	return revs, nil
 }
@@ -34,6 +34,20 @@ type Eventer interface {
 	Close()
 }
 
+// Subscriber is implemented by eventers that are able to notify
+// about events as they are pushed.
+type Subscriber interface {
+	// Subscribe registers fn to be called with the current event
+	// first, as returned by Show, and then with every event pushed
+	// after the subscription was made.
+	//
+	// The fn is called asynchronously, one event at a time and in
+	// the order the events were pushed, so a slow fn does not
+	// block the producer. The returned function removes the
+	// subscription.
+	Subscribe(fn func(*Event)) (unsubscribe func())
+}
+
 // FromContext extracts the eventer from ctx, if present.
 func FromContext(ctx context.Context) (Eventer, bool) {
 	c, ok := ctx.Value(eventKey).(Eventer)
@@ -66,6 +80,12 @@ type Event struct {
 	Error string `json:"error"`
 }
 
+// Final tells whether the event is the last one pushed by
+// the process - it either finished or failed.
+func (e *Event) Final() bool {
+	return e.Percentage == 100 || e.Error != ""
+}
+
 func (e *Event) String() string {
 	return fmt.Sprintf("msg: %s, status: %s, timestamp: %s, percentage: %d",
 		e.Message, e.Status, e.TimeStamp, e.Percentage)
@@ -76,9 +96,17 @@ type Events struct {
 	eventId string
 	closed  bool
 
+	store Store
+	errFn func(error)
+
+	subs  map[int]*subscription
+	subID int
+
 	sync.Mutex
 }
 
+var _ Subscriber = (*Events)(nil)
+
 func New(id string) *Events {
 	return &Events{
 		events:  make([]*Event, 0),
@@ -88,9 +116,9 @@ func New(id string) *Events {
 
 func (e *Events) Push(ev *Event) {
 	e.Lock()
-	defer e.Unlock()
 
 	if e.closed {
+		e.Unlock()
 		return
 	}
 
@@ -98,12 +126,66 @@ func (e *Events) Push(ev *Event) {
 	ev.TimeStamp = time.Now()
 
 	e.events = append(e.events, ev)
+
+	store, errFn := e.store, e.errFn
+
+	// Events are queued while holding the lock, so each
+	// subscriber receives them in the order they were pushed.
+	for _, sub := range e.subs {
+		sub.push(ev)
+	}
+
+	e.Unlock()
+
+	// The event is persisted without holding the lock, so a slow
+	// store does not block readers of the eventer.
+	if store != nil {
+		if err := store.Append(ev); err != nil && errFn != nil {
+			errFn(err)
+		}
+	}
+}
+
+// Subscribe implements the Subscriber interface.
+func (e *Events) Subscribe(fn func(*Event)) func() {
+	sub := newSubscription(fn)
+
+	e.Lock()
+
+	if e.subs == nil {
+		e.subs = make(map[int]*subscription)
+	}
+
+	id := e.subID
+	e.subID++
+	e.subs[id] = sub
+
+	// The current event is queued together with registering
+	// the subscription, so no event is lost or reordered
+	// in between.
+	sub.push(e.show())
+
+	e.Unlock()
+
+	go sub.run()
+
+	return func() {
+		e.Lock()
+		delete(e.subs, id)
+		e.Unlock()
+
+		sub.close()
+	}
 }
 
 func (e *Events) Show() *Event {
 	e.Lock()
 	defer e.Unlock()
 
+	return e.show()
+}
+
+func (e *Events) show() *Event {
 	if len(e.events) == 0 {
 		return &Event{
 			EventId:   e.eventId,
@@ -135,3 +217,70 @@ func (e *Events) String() string {
 
 	return msg
 }
+
+// subscription delivers events to a single subscriber in the order
+// they were pushed. Events are queued without blocking the producer
+// and are passed to fn by a dedicated goroutine.
+type subscription struct {
+	fn func(*Event)
+
+	mu    sync.Mutex
+	queue []*Event
+
+	ready chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newSubscription(fn func(*Event)) *subscription {
+	return &subscription{
+		fn:    fn,
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+func (s *subscription) push(ev *Event) {
+	s.mu.Lock()
+	s.queue = append(s.queue, ev)
+	s.mu.Unlock()
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.ready:
+		}
+
+		for {
+			s.mu.Lock()
+			if len(s.queue) == 0 {
+				s.mu.Unlock()
+				break
+			}
+			ev := s.queue[0]
+			s.queue[0] = nil
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+
+			select {
+			case <-s.done:
+				return
+			default:
+			}
+
+			s.fn(ev)
+		}
+	}
+}
+
+func (s *subscription) close() {
+	s.once.Do(func() { close(s.done) })
+}
@@ -0,0 +1,217 @@
+package eventer_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"koding/kites/kloud/eventer"
+)
+
+type fakeStore struct {
+	events []*eventer.Event
+	err    error
+}
+
+var _ eventer.Store = (*fakeStore)(nil)
+
+func (fs *fakeStore) Append(ev *eventer.Event) error {
+	if fs.err != nil {
+		return fs.err
+	}
+
+	fs.events = append(fs.events, ev)
+	return nil
+}
+
+func (fs *fakeStore) Latest(eventID string) (*eventer.Event, error) {
+	if len(fs.events) == 0 {
+		return nil, eventer.ErrNoEvents
+	}
+
+	return fs.events[len(fs.events)-1], nil
+}
+
+func (fs *fakeStore) History(*eventer.HistoryFilter) ([]*eventer.Event, int, error) {
+	return fs.events, len(fs.events), nil
+}
+
+func TestNewStored(t *testing.T) {
+	store := &fakeStore{}
+	ev := eventer.NewStored("apply-123", store, nil)
+
+	ev.Push(&eventer.Event{Message: "building", Percentage: 10})
+	ev.Push(&eventer.Event{Message: "done", Percentage: 100})
+	ev.Close()
+	ev.Push(&eventer.Event{Message: "ignored"})
+
+	if len(store.events) != 2 {
+		t.Fatalf("got %d stored events, want 2", len(store.events))
+	}
+
+	for i, e := range store.events {
+		if e.EventId != "apply-123" {
+			t.Errorf("%d: got %q event id, want %q", i, e.EventId, "apply-123")
+		}
+	}
+
+	latest, err := store.Latest("apply-123")
+	if err != nil {
+		t.Fatalf("Latest()=%s", err)
+	}
+
+	if !reflect.DeepEqual(latest, ev.Show()) {
+		t.Fatalf("got %+v, want %+v", latest, ev.Show())
+	}
+}
+
+func TestNewStoredError(t *testing.T) {
+	var got error
+	want := errors.New("store failure")
+
+	ev := eventer.NewStored("apply-123", &fakeStore{err: want}, func(err error) {
+		got = err
+	})
+
+	ev.Push(&eventer.Event{Message: "building"})
+
+	if got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if msg := ev.Show().Message; msg != "building" {
+		t.Fatalf("got %q, want %q", msg, "building")
+	}
+}
+
+type showStore struct {
+	fakeStore
+	ev *eventer.Events
+}
+
+func (ss *showStore) Append(ev *eventer.Event) error {
+	// Calling the eventer while the event is persisted must
+	// not deadlock.
+	if got := ss.ev.Show(); got != ev {
+		return fmt.Errorf("got %+v, want %+v", got, ev)
+	}
+
+	return ss.fakeStore.Append(ev)
+}
+
+func TestNewStoredUnlocked(t *testing.T) {
+	var err error
+	store := &showStore{}
+	ev := eventer.NewStored("apply-123", store, func(e error) {
+		err = e
+	})
+	store.ev = ev
+
+	ev.Push(&eventer.Event{Message: "building"})
+
+	if err != nil {
+		t.Fatalf("Append()=%s", err)
+	}
+
+	if len(store.events) != 1 {
+		t.Fatalf("got %d stored events, want 1", len(store.events))
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ev := eventer.New("apply-123")
+	ev.Push(&eventer.Event{Message: "initializing"})
+
+	ch := make(chan string, 10)
+	unsubscribe := ev.Subscribe(func(e *eventer.Event) {
+		ch <- e.Message
+	})
+
+	ev.Push(&eventer.Event{Message: "building", Percentage: 10})
+	ev.Push(&eventer.Event{Message: "done", Percentage: 100})
+
+	want := []string{"initializing", "building", "done"}
+
+	for i, msg := range want {
+		select {
+		case got := <-ch:
+			if got != msg {
+				t.Fatalf("%d: got %q, want %q", i, got, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%d: timed out waiting for %q", i, msg)
+		}
+	}
+
+	unsubscribe()
+
+	ev.Push(&eventer.Event{Message: "ignored"})
+
+	select {
+	case got := <-ch:
+		t.Fatalf("got %q after unsubscribe", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscribeSlow(t *testing.T) {
+	ev := eventer.New("apply-123")
+
+	release := make(chan struct{})
+	ch := make(chan int, 100)
+
+	unsubscribe := ev.Subscribe(func(e *eventer.Event) {
+		<-release
+		ch <- e.Percentage
+	})
+	defer unsubscribe()
+
+	pushed := make(chan struct{})
+
+	go func() {
+		for i := 1; i <= 50; i++ {
+			ev.Push(&eventer.Event{Percentage: i})
+		}
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow subscriber blocked Push")
+	}
+
+	close(release)
+
+	for i := 0; i <= 50; i++ {
+		select {
+		case got := <-ch:
+			if got != i {
+				t.Fatalf("got %d, want %d", got, i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %d", i)
+		}
+	}
+}
+
+func TestEventFinal(t *testing.T) {
+	cases := map[string]struct {
+		ev    *eventer.Event
+		final bool
+	}{
+		"in progress": {&eventer.Event{Percentage: 40}, false},
+		"finished":    {&eventer.Event{Percentage: 100}, true},
+		"failed":      {&eventer.Event{Percentage: 40, Error: "failed"}, true},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if final := cas.ev.Final(); final != cas.final {
+				t.Fatalf("got %t, want %t", final, cas.final)
+			}
+		})
+	}
+}
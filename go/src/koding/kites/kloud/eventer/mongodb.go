@@ -0,0 +1,152 @@
+package eventer
+
+import (
+	"errors"
+	"time"
+
+	"koding/db/mongodb"
+	"koding/kites/kloud/machinestate"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const eventCollection = "jKloudEvents"
+
+// EventTTL is the time events are kept in the jKloudEvents collection,
+// older ones are removed by MongoDB.
+const EventTTL = 30 * 24 * time.Hour
+
+// EventDocument defines a single MongoDB document in the jKloudEvents
+// collection.
+type EventDocument struct {
+	Id         bson.ObjectId      `bson:"_id" json:"-"`
+	EventId    string             `bson:"eventId"`
+	Message    string             `bson:"message"`
+	Status     machinestate.State `bson:"status"`
+	Percentage int                `bson:"percentage"`
+	TimeStamp  time.Time          `bson:"timeStamp"`
+	Error      string             `bson:"error,omitempty"`
+}
+
+func (doc *EventDocument) toEvent() *Event {
+	return &Event{
+		EventId:    doc.EventId,
+		Message:    doc.Message,
+		Status:     doc.Status,
+		Percentage: doc.Percentage,
+		TimeStamp:  doc.TimeStamp,
+		Error:      doc.Error,
+	}
+}
+
+// MongodbStore implements Store interface by recording events
+// in the jKloudEvents collection.
+type MongodbStore struct {
+	DB *mongodb.MongoDB
+}
+
+var _ Store = (*MongodbStore)(nil)
+
+// NewMongodbStore gives new MongodbStore value.
+//
+// It ensures the indexes required for querying event history
+// and expiring old events are created.
+func NewMongodbStore(db *mongodb.MongoDB) (*MongodbStore, error) {
+	indexes := []mgo.Index{{
+		Key:        []string{"eventId", "-_id"},
+		Background: true,
+	}, {
+		Key:         []string{"timeStamp"},
+		Background:  true,
+		ExpireAfter: EventTTL,
+	}}
+
+	for _, index := range indexes {
+		if err := db.EnsureIndex(eventCollection, index); err != nil {
+			return nil, err
+		}
+	}
+
+	return &MongodbStore{
+		DB: db,
+	}, nil
+}
+
+// Append implements the Store interface.
+func (m *MongodbStore) Append(ev *Event) error {
+	if ev.EventId == "" {
+		return errors.New("event id is missing")
+	}
+
+	doc := &EventDocument{
+		Id:         bson.NewObjectId(),
+		EventId:    ev.EventId,
+		Message:    ev.Message,
+		Status:     ev.Status,
+		Percentage: ev.Percentage,
+		TimeStamp:  ev.TimeStamp.UTC(),
+		Error:      ev.Error,
+	}
+
+	return m.DB.Run(eventCollection, func(c *mgo.Collection) error {
+		return c.Insert(doc)
+	})
+}
+
+// Latest implements the Store interface.
+func (m *MongodbStore) Latest(eventID string) (*Event, error) {
+	var doc EventDocument
+
+	err := m.DB.Run(eventCollection, func(c *mgo.Collection) error {
+		return c.Find(bson.M{"eventId": eventID}).Sort("-_id").One(&doc)
+	})
+
+	if err == mgo.ErrNotFound {
+		return nil, ErrNoEvents
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.toEvent(), nil
+}
+
+// History implements the Store interface.
+func (m *MongodbStore) History(f *HistoryFilter) ([]*Event, int, error) {
+	if f.EventId == "" {
+		return nil, 0, errors.New("event id is missing")
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	var (
+		docs  []EventDocument
+		total int
+	)
+
+	query := func(c *mgo.Collection) (err error) {
+		q := c.Find(bson.M{"eventId": f.EventId})
+
+		if total, err = q.Count(); err != nil {
+			return err
+		}
+
+		return q.Sort("-_id").Skip(f.Skip).Limit(limit).All(&docs)
+	}
+
+	if err := m.DB.Run(eventCollection, query); err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]*Event, len(docs))
+	for i := range docs {
+		events[i] = docs[i].toEvent()
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,54 @@
+package eventer
+
+import "errors"
+
+// ErrNoEvents is returned by Store when there are no events recorded
+// for the requested id.
+var ErrNoEvents = errors.New("no events found")
+
+// HistoryFilter is used to page through the recorded events.
+type HistoryFilter struct {
+	// EventId is the id of the eventer, e.g. "apply-<stackId>".
+	EventId string
+
+	// Skip is the number of most recent events to skip.
+	Skip int
+
+	// Limit is the maximum number of events to return. If zero,
+	// DefaultHistoryLimit is used.
+	Limit int
+}
+
+// DefaultHistoryLimit is a default number of events returned by a
+// single Store.History call.
+const DefaultHistoryLimit = 50
+
+// Store is used to persist events pushed to an Eventer, so they
+// outlive the process that generated them.
+type Store interface {
+	// Append records the given event. The event is required to
+	// have non-empty EventId.
+	Append(*Event) error
+
+	// Latest gives the most recently recorded event for the
+	// given event id. If no events were recorded, it
+	// returns ErrNoEvents.
+	Latest(eventID string) (*Event, error)
+
+	// History gives events recorded for the given filter, ordered
+	// from the most recent one. It also returns the total number
+	// of events recorded for the filter's event id.
+	History(*HistoryFilter) (events []*Event, total int, err error)
+}
+
+// NewStored gives new eventer for the given id, which records
+// each pushed event in the given store.
+//
+// Any failure to persist an event is passed to the errFn,
+// if it's non-nil.
+func NewStored(id string, store Store, errFn func(error)) *Events {
+	e := New(id)
+	e.store = store
+	e.errFn = errFn
+	return e
+}
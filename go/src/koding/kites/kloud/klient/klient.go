@@ -346,10 +346,11 @@ func (k *Klient) SSHAddKeys(username string, keys ...string) error {
 }
 
 // MountHeadIndex returns the number and the overall size of files in a given
-// remote directory.
-func (k *Klient) MountHeadIndex(path string) (absPath string, count int, diskSize int64, err error) {
+// remote directory. Files matched by ignore patterns are not counted.
+func (k *Klient) MountHeadIndex(path string, ignore []string) (absPath string, count int, diskSize int64, err error) {
 	req := index.Request{
-		Path: path,
+		Path:   path,
+		Ignore: ignore,
 	}
 
 	raw, err := k.Client.TellWithTimeout("machine.index.head", k.timeout(), req)
@@ -366,10 +367,11 @@ func (k *Klient) MountHeadIndex(path string) (absPath string, count int, diskSiz
 }
 
 // MountGetIndex returns an index that describes the current state of remote
-// directory.
-func (k *Klient) MountGetIndex(path string) (*index.Index, error) {
+// directory. Files matched by ignore patterns are not indexed.
+func (k *Klient) MountGetIndex(path string, ignore []string) (*index.Index, error) {
 	req := index.Request{
-		Path: path,
+		Path:   path,
+		Ignore: ignore,
 	}
 
 	raw, err := k.Client.TellWithTimeout("machine.index.get", k.timeout(), req)
@@ -389,6 +391,59 @@ func (k *Klient) MountGetIndex(path string) (*index.Index, error) {
 	return resp.Index, nil
 }
 
+// MountGetDelta returns a delta that transforms a file described by given
+// chunks into the current state of remote file. Nil delta means that remote
+// file does not exist. The file path is relative to provided mount root.
+func (k *Klient) MountGetDelta(root, path string, chunks []index.Chunk) (*index.Delta, error) {
+	req := index.DeltaRequest{
+		Root:   root,
+		Path:   path,
+		Chunks: chunks,
+	}
+
+	raw, err := k.Client.TellWithTimeout("machine.index.delta", k.timeout(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := index.DeltaResponse{}
+	if err := raw.Unmarshal(&resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Delta, nil
+}
+
+// MountPatch applies provided delta to remote file and returns the entry of
+// patched file. Nil delta removes remote file. Unless forced, the patch is
+// applied only when remote file matches provided base entry. The file path is
+// relative to provided mount root.
+func (k *Klient) MountPatch(root, path string, d *index.Delta, base *index.Entry, force bool) (*index.Entry, error) {
+	req := index.PatchRequest{
+		Root:  root,
+		Path:  path,
+		Delta: d,
+		Base:  base,
+		Force: force,
+	}
+
+	raw, err := k.Client.TellWithTimeout("machine.index.patch", k.timeout(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := index.PatchResponse{}
+	if err := raw.Unmarshal(&resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Conflict {
+		return resp.Entry, index.ErrConflict
+	}
+
+	return resp.Entry, nil
+}
+
 // DiskBlocks gets basic information about volume pointed by provided path.
 func (k *Klient) DiskBlocks(path string) (size, total, free, used uint64, err error) {
 	req := fs.GetInfoOptions{
@@ -22,8 +22,10 @@ import (
 	"koding/kites/kloud/contexthelper/session"
 	"koding/kites/kloud/credential"
 	"koding/kites/kloud/dnsstorage"
+	"koding/kites/kloud/eventer"
 	"koding/kites/kloud/keycreator"
 	"koding/kites/kloud/machine"
+	"koding/kites/kloud/provider/docker"
 	"koding/kites/kloud/queue"
 	"koding/kites/kloud/stack"
 	"koding/kites/kloud/stack/provider"
@@ -136,6 +138,10 @@ type Config struct {
 
 	KodingURL *config.URL // Koding base URL
 	NoSneaker bool        // use Mongo for reading credentials, instead of /social/credential endpoint
+
+	// DockerAllowLocal allows docker credentials to point at a daemon
+	// listening on a unix socket or on a local or private address.
+	DockerAllowLocal bool
 }
 
 // New gives new, registered kloud kite.
@@ -218,6 +224,7 @@ func New(conf *Config) (*Kloud, error) {
 			Log:      sess.Log.New("queue"),
 			Kite:     k,
 			MongoDB:  sess.DB,
+			Session:  sess,
 		},
 	}
 
@@ -266,6 +273,32 @@ func New(conf *Config) (*Kloud, error) {
 
 	kloud.Stack.Metrics = stats
 
+	evStore, err := eventer.NewMongodbStore(sess.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	kloud.Stack.EventStore = evStore
+	kloud.Queue.EventStore = evStore
+	kloud.Queue.OnIdleStop = func(stop *queue.IdleStop) {
+		policy := "default"
+		if stop.Policy != nil {
+			policy = "team"
+		}
+
+		sess.Log.Info("machine %q (%s) of %q user stopped after %s of inactivity (%s policy, threshold %s)",
+			stop.Label, stop.MachineID, stop.Username, stop.Inactive, policy, stop.Threshold)
+
+		tags := []string{"policy:" + policy}
+		if stop.Group != "" {
+			tags = append(tags, "team:"+stop.Group)
+		}
+
+		if err := stats.Count("machine.idleStop", 1, tags, 1.0); err != nil {
+			sess.Log.Warning("failure sending idle stop metric: %s", err)
+		}
+	}
+
 	// RSA key pair that we add to the newly created machine for
 	// provisioning.
 	kloud.Stack.PublicKeys = stacker.SSHKey
@@ -275,7 +308,19 @@ func New(conf *Config) (*Kloud, error) {
 	kloud.Stack.Log = sess.Log
 	kloud.Stack.SecretKey = conf.KloudSecretKey
 
+	providerConfigs := map[string]interface{}{
+		"docker": &docker.Config{
+			AllowLocal: conf.DockerAllowLocal,
+		},
+	}
+
 	for _, p := range provider.All() {
+		if cfg, ok := providerConfigs[p.Name]; ok {
+			pCopy := *p
+			pCopy.Config = cfg
+			p = &pCopy
+		}
+
 		s := stacker.New(p)
 
 		if err = kloud.Stack.AddProvider(p.Name, s); err != nil {
@@ -312,6 +357,11 @@ func New(conf *Config) (*Kloud, error) {
 	k.HandleFunc("authenticate", kloud.Stack.Authenticate)
 	k.HandleFunc("bootstrap", kloud.Stack.Bootstrap)
 	k.HandleFunc("import", kloud.Stack.Import)
+	k.HandleFunc("stack.destroy", kloud.Stack.Destroy)
+	k.HandleFunc("stack.drift", kloud.Stack.Drift)
+	k.HandleFunc("template.history", kloud.Stack.TemplateHistory)
+	k.HandleFunc("template.diff", kloud.Stack.TemplateDiff)
+	k.HandleFunc("template.rollback", kloud.Stack.TemplateRollback)
 
 	// Credential handling.
 	k.HandleFunc("credential.describe", kloud.Stack.CredentialDescribe)
@@ -328,15 +378,26 @@ func New(conf *Config) (*Kloud, error) {
 	// Team handling.
 	k.HandleFunc("team.list", kloud.Stack.TeamList)
 	k.HandleFunc("team.whoami", kloud.Stack.TeamWhoami)
+	k.HandleFunc("team.idlePolicy", kloud.Stack.TeamIdlePolicy)
+	k.HandleFunc("team.setIdlePolicy", kloud.Stack.TeamSetIdlePolicy)
 
 	// Machine handling.
 	k.HandleFunc("machine.list", kloud.Stack.MachineList)
+	k.HandleFunc("machine.schedule", kloud.Stack.MachineSchedule)
+	k.HandleFunc("machine.setSchedule", kloud.Stack.MachineSetSchedule)
+	k.HandleFunc("machine.clearSchedule", kloud.Stack.MachineClearSchedule)
+	k.HandleFunc("machine.snapshot.create", kloud.Stack.SnapshotCreate)
+	k.HandleFunc("machine.snapshot.list", kloud.Stack.SnapshotList)
+	k.HandleFunc("machine.snapshot.delete", kloud.Stack.SnapshotDelete)
+	k.HandleFunc("machine.snapshot.restore", kloud.Stack.SnapshotRestore)
 
 	// Single machine handling.
 	k.HandleFunc("stop", kloud.Stack.Stop)
 	k.HandleFunc("start", kloud.Stack.Start)
 	k.HandleFunc("info", kloud.Stack.Info)
 	k.HandleFunc("event", kloud.Stack.Event)
+	k.HandleFunc("event.history", kloud.Stack.EventHistory)
+	k.HandleFunc("event.subscribe", kloud.Stack.EventSubscribe)
 
 	// Klient proxy methods.
 	k.HandleFunc("admin.add", kloud.Stack.AdminAdd)
@@ -138,6 +138,9 @@ func (s State) ValidMethods() []string {
 			"reinit",
 			"createSnapshot",
 			"deleteSnapshot",
+			"machine.snapshot.create",
+			"machine.snapshot.delete",
+			"machine.snapshot.restore",
 		}
 	case Stopped:
 		return []string{
@@ -147,6 +150,9 @@ func (s State) ValidMethods() []string {
 			"reinit",
 			"createSnapshot",
 			"deleteSnapshot",
+			"machine.snapshot.create",
+			"machine.snapshot.delete",
+			"machine.snapshot.restore",
 		}
 	case Terminated:
 		return []string{"build"}
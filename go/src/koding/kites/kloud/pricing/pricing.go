@@ -0,0 +1,230 @@
+// Package pricing provides cost estimates for machines
+// created by kloud providers.
+//
+// Prices are read from JSON tables, one per provider, which
+// are bundled with the package. The tables are not meant to be
+// accurate, they give a rough estimate of on-demand prices
+// for the most popular instance types.
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:generate $GOPATH/bin/go-bindata -mode 420 -modtime 1470666525 -pkg pricing -o tables.json.go tables/
+//go:generate gofmt -l -w -s tables.json.go
+
+// HoursPerMonth is an average number of hours in a month,
+// used for calculating monthly estimates.
+const HoursPerMonth = 730
+
+// ErrNoPrice is returned when there is no price for the
+// requested region and instance type.
+var ErrNoPrice = errors.New("pricing: no price available")
+
+// Estimate represents an estimated cost of a machine
+// or a whole stack.
+type Estimate struct {
+	Hourly   float64 `json:"hourly"`
+	Monthly  float64 `json:"monthly"`
+	Currency string  `json:"currency"`
+
+	// Partial is true when the estimate does not include
+	// all the machines, as some of them had no price.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// NewEstimate gives new estimate for the given hourly price.
+func NewEstimate(hourly float64, currency string) *Estimate {
+	return &Estimate{
+		Hourly:   hourly,
+		Monthly:  hourly * HoursPerMonth,
+		Currency: currency,
+	}
+}
+
+// Add adds the other estimate to e.
+//
+// If currencies of the estimates differ, e is marked
+// as partial and the other estimate is ignored.
+func (e *Estimate) Add(other *Estimate) {
+	if other == nil {
+		e.Partial = true
+		return
+	}
+
+	if e.Currency == "" {
+		e.Currency = other.Currency
+	}
+
+	if e.Currency != other.Currency {
+		e.Partial = true
+		return
+	}
+
+	e.Hourly += other.Hourly
+	e.Monthly += other.Monthly
+	e.Partial = e.Partial || other.Partial
+}
+
+// String implements the fmt.Stringer interface.
+func (e *Estimate) String() string {
+	s := fmt.Sprintf("%.4f %s/hour (%.2f %s/month)", e.Hourly, e.Currency, e.Monthly, e.Currency)
+
+	if e.Partial {
+		s += ", partial"
+	}
+
+	return s
+}
+
+// Table represents a price list of a single provider.
+type Table struct {
+	Provider string `json:"provider"`
+	Currency string `json:"currency"`
+
+	// TypeAttribute is a name of the machine attribute, which
+	// holds an instance type.
+	TypeAttribute string `json:"typeAttribute"`
+
+	// RegionAttribute is a name of the machine attribute, which
+	// holds a region or a zone. If empty or the attribute is
+	// not set, region is read from a credential.
+	RegionAttribute string `json:"regionAttribute,omitempty"`
+
+	// RegionCredential is a name of the credential field, which
+	// holds a region. If empty, "region" is used.
+	RegionCredential string `json:"regionCredential,omitempty"`
+
+	// DefaultType is an instance type used by the provider
+	// when the machine does not specify one.
+	DefaultType string `json:"defaultType,omitempty"`
+
+	// DefaultRegion is a region used by the provider
+	// when neither machine nor credential specify one.
+	DefaultRegion string `json:"defaultRegion,omitempty"`
+
+	// Prices maps a region to hourly prices of instance types.
+	//
+	// Prices under "*" region apply to all regions.
+	Prices map[string]map[string]float64 `json:"prices"`
+}
+
+// Lookup gives an estimate for the given region and instance type.
+//
+// If there is no price for the region, Lookup tries to interpret
+// it as a zone within a region, e.g. for "us-east-1a" it looks up
+// "us-east-1" and for "us-central1-f" - "us-central1".
+func (t *Table) Lookup(region, instanceType string) (*Estimate, error) {
+	if region == "" {
+		region = t.DefaultRegion
+	}
+
+	if instanceType == "" {
+		instanceType = t.DefaultType
+	}
+
+	for _, region := range []string{region, trimZone(region), "*"} {
+		if price, ok := t.Prices[region][instanceType]; ok {
+			return NewEstimate(price, t.Currency), nil
+		}
+	}
+
+	return nil, ErrNoPrice
+}
+
+// RegionCredentialField gives a name of the credential
+// field, which holds a region.
+func (t *Table) RegionCredentialField() string {
+	if t.RegionCredential != "" {
+		return t.RegionCredential
+	}
+
+	return "region"
+}
+
+func trimZone(zone string) string {
+	// AWS availability zone, e.g. us-east-1a.
+	if n := len(zone); n > 1 && unicode.IsLetter(rune(zone[n-1])) && unicode.IsDigit(rune(zone[n-2])) {
+		return zone[:n-1]
+	}
+
+	// Google Cloud zone, e.g. us-central1-f.
+	if i := strings.LastIndex(zone, "-"); i != -1 {
+		return zone[:i]
+	}
+
+	return zone
+}
+
+var (
+	tables     map[string]*Table
+	tablesErr  error
+	tablesOnce sync.Once
+)
+
+// Tables gives price tables bundled with the package,
+// mapped by provider name.
+func Tables() (map[string]*Table, error) {
+	tablesOnce.Do(func() {
+		tables, tablesErr = readTables()
+	})
+
+	return tables, tablesErr
+}
+
+// TableFor gives a price table for the given provider.
+func TableFor(provider string) (*Table, error) {
+	t, err := Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	table, ok := t[provider]
+	if !ok {
+		return nil, ErrNoPrice
+	}
+
+	return table, nil
+}
+
+// Lookup gives an estimate for the given provider, region and instance type,
+// using bundled price tables.
+func Lookup(provider, region, instanceType string) (*Estimate, error) {
+	table, err := TableFor(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return table.Lookup(region, instanceType)
+}
+
+func readTables() (map[string]*Table, error) {
+	tables := make(map[string]*Table)
+
+	for _, name := range AssetNames() {
+		if path.Ext(name) != ".json" {
+			continue
+		}
+
+		var t Table
+
+		if err := json.Unmarshal(MustAsset(name), &t); err != nil {
+			return nil, fmt.Errorf("pricing: unable to read %q: %s", name, err)
+		}
+
+		if t.Provider == "" {
+			t.Provider = strings.TrimSuffix(path.Base(name), ".json")
+		}
+
+		tables[t.Provider] = &t
+	}
+
+	return tables, nil
+}
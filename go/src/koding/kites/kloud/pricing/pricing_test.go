@@ -0,0 +1,103 @@
+package pricing_test
+
+import (
+	"reflect"
+	"testing"
+
+	"koding/kites/kloud/pricing"
+)
+
+func TestTables(t *testing.T) {
+	tables, err := pricing.Tables()
+	if err != nil {
+		t.Fatalf("Tables()=%s", err)
+	}
+
+	for _, provider := range []string{"aws", "azure", "digitalocean", "google"} {
+		table, ok := tables[provider]
+		if !ok {
+			t.Errorf("no price table for %q", provider)
+			continue
+		}
+
+		if table.Currency == "" || table.TypeAttribute == "" || len(table.Prices) == 0 {
+			t.Errorf("%s: invalid price table: %+v", provider, table)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	cases := map[string]struct {
+		provider     string
+		region       string
+		instanceType string
+		hourly       float64
+	}{
+		"aws region": {
+			"aws", "us-east-1", "t2.micro", 0.012,
+		},
+		"aws availability zone": {
+			"aws", "eu-west-1b", "t2.small", 0.025,
+		},
+		"aws default type": {
+			"aws", "us-east-1", "", 0.0059,
+		},
+		"google zone": {
+			"google", "us-central1-f", "n1-standard-1", 0.0475,
+		},
+		"digitalocean any region": {
+			"digitalocean", "nyc3", "2gb", 0.02976,
+		},
+		"azure default region": {
+			"azure", "", "Basic_A1", 0.044,
+		},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			e, err := pricing.Lookup(cas.provider, cas.region, cas.instanceType)
+			if err != nil {
+				t.Fatalf("Lookup()=%s", err)
+			}
+
+			want := pricing.NewEstimate(cas.hourly, "USD")
+
+			if !reflect.DeepEqual(e, want) {
+				t.Fatalf("got %+v, want %+v", e, want)
+			}
+		})
+	}
+}
+
+func TestLookupNoPrice(t *testing.T) {
+	cases := map[string][3]string{
+		"unknown provider": {"vagrant", "", "default"},
+		"unknown region":   {"aws", "mars-north-1", "t2.micro"},
+		"unknown type":     {"aws", "us-east-1", "x9.huge"},
+	}
+
+	for name, cas := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := pricing.Lookup(cas[0], cas[1], cas[2]); err != pricing.ErrNoPrice {
+				t.Fatalf("got %v, want %v", err, pricing.ErrNoPrice)
+			}
+		})
+	}
+}
+
+func TestEstimateAdd(t *testing.T) {
+	total := &pricing.Estimate{}
+
+	total.Add(pricing.NewEstimate(0.5, "USD"))
+	total.Add(pricing.NewEstimate(0.25, "USD"))
+
+	if total.Hourly != 0.75 || total.Monthly != 0.75*pricing.HoursPerMonth || total.Partial {
+		t.Fatalf("unexpected total: %+v", total)
+	}
+
+	total.Add(pricing.NewEstimate(1, "EUR"))
+
+	if total.Hourly != 0.75 || !total.Partial {
+		t.Fatalf("unexpected total: %+v", total)
+	}
+}
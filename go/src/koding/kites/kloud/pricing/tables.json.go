@@ -0,0 +1,306 @@
+// Code generated by go-bindata.
+// sources:
+// tables/aws.json
+// tables/azure.json
+// tables/digitalocean.json
+// tables/google.json
+// DO NOT EDIT!
+
+package pricing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func bindataRead(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, gz)
+	clErr := gz.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", name, err)
+	}
+	if clErr != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type asset struct {
+	bytes []byte
+	info  os.FileInfo
+}
+
+type bindataFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (fi bindataFileInfo) Name() string {
+	return fi.name
+}
+func (fi bindataFileInfo) Size() int64 {
+	return fi.size
+}
+func (fi bindataFileInfo) Mode() os.FileMode {
+	return fi.mode
+}
+func (fi bindataFileInfo) ModTime() time.Time {
+	return fi.modTime
+}
+func (fi bindataFileInfo) IsDir() bool {
+	return false
+}
+func (fi bindataFileInfo) Sys() interface{} {
+	return nil
+}
+
+var _tablesAwsJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x94\x4d\x6e\xab\x30\x14\x85\xe7\xac\xc2\x62\x9c\x44\xd8\xc6\x31\x30\x7b\xd2\xdb\xc1\x7b\x1d\x57\xae\xe3\xb6\x48\x40\x90\x7f\x9a\x46\x15\x7b\xaf\x1c\x2a\x62\xaa\x9e\x64\x01\x9d\x9e\x8f\x2b\xee\x77\xae\xe4\x8f\x8c\x90\x7c\xb4\xc7\xb7\xf6\x60\x6c\xde\x90\x5c\x9d\x5c\xbe\x89\xa1\x0e\xd6\x9a\x41\x9f\x63\xf8\xf0\xef\xef\x1c\xfa\xf3\x68\xfe\x78\x6f\xdb\xa7\xe0\x4d\x24\xed\xe0\xbc\x1a\xb4\x79\x8c\x64\xfe\xe6\x60\x9e\x55\xe8\xfc\xff\x18\x34\x24\xf7\x6c\x37\xa8\xe1\x38\xb3\xd1\xb6\xda\xb8\xbc\x21\xf1\xbf\x84\xe4\xc1\x6d\x8d\x72\x7e\x4b\x97\x88\x5c\x27\x1a\x52\xec\x8a\x42\xd4\x9b\x04\xf4\xad\xb6\x5f\x84\xb2\x14\xb8\x5e\x75\xdd\x0c\x18\x5f\x4d\x98\x43\x1b\xfa\x99\x94\x32\x25\x9d\xb2\x2f\x71\xc5\x62\x57\xd4\xe5\x02\xfa\x32\x01\xb4\xa8\x52\xf0\x7e\x25\x8c\x8a\x85\xe8\xd5\x48\x1a\x27\x03\xb4\xae\x2f\x60\xda\x2c\xea\x27\x73\x5b\x5d\x4a\xa4\x2e\x80\x3a\xa7\x48\x7d\xbf\x22\xc9\x5a\x8c\x01\x75\x56\x23\x75\x51\x01\x75\x56\x02\x79\x56\xfe\x2c\xcf\x7e\xdf\xdd\x4d\xb8\x7b\xf7\x3d\x47\xea\x1c\xa9\x0b\xa4\xbe\x02\xc9\x56\x05\x05\xe6\x14\x9e\x9d\xa3\xb3\x53\x0e\xdc\x19\xdb\x7f\x77\xd7\x66\xf0\x56\x75\x37\xf5\x2b\xa4\x5f\x22\x7d\x09\xf5\x57\x23\xf0\xc0\x09\x60\x15\xf2\x17\x12\xf9\x97\xd0\x5f\xae\xfc\xd5\xb8\x75\xc7\xe0\x5f\xef\xbd\x79\x92\xa3\x06\x04\x6a\xa0\x86\x0d\x54\xa0\x01\x2a\x51\x03\x02\x36\x80\x0a\x10\xa8\x00\x4e\x2f\x60\xca\x08\x99\xb2\x29\xfb\x1c\x00\xc4\xf4\x3a\x94\x6f\x06\x00\x00")
+
+func tablesAwsJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_tablesAwsJson,
+		"tables/aws.json",
+	)
+}
+
+func tablesAwsJson() (*asset, error) {
+	bytes, err := tablesAwsJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "tables/aws.json", size: 1647, mode: os.FileMode(420), modTime: time.Unix(1470666525, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _tablesAzureJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\xcf\xc1\x6a\x83\x40\x10\x06\xe0\xbb\x4f\x31\xec\x59\x8a\x5a\x53\x8d\xb7\xb4\xc9\x0b\x54\x42\x8f\x65\xab\xd3\xb2\x20\x2a\xb3\xb3\x85\xa4\xf8\xee\x65\xdd\x45\x1a\x35\x3d\xe4\x90\xeb\xff\xcd\xef\xfa\xff\x04\x00\xa2\xa7\xee\x5b\xd5\x48\xa2\x00\x21\xcf\x86\x50\x84\x36\xae\x0c\x11\xb6\xd5\xc9\xc6\xc7\x72\xef\x42\x3e\xf5\xb8\x63\x26\xf5\x61\x18\xad\x68\x75\xf6\xf7\x84\x5f\xaa\x6b\x2f\xb0\xe9\x2a\xc9\xaa\x6b\xff\x1e\xbc\x10\xd6\xd8\xb2\x92\xcd\xf2\xa2\xc6\x4f\x69\x1a\x7e\x1d\xbf\x64\xf9\x20\x35\xc3\xb1\x84\xc4\x79\x4f\xaa\x42\x2d\x0a\xb0\xff\x0d\x13\x4f\x01\x80\x78\x96\x5a\x55\xef\xbb\x48\x14\x10\x3d\x44\x71\x1e\xce\x20\x76\x90\x66\x73\x48\x1c\x6c\xd3\x09\x4a\x96\x6d\x2d\xa9\x9e\x4a\x4f\x2b\xe4\x6a\x71\xb2\xa4\xbd\x6f\x65\xd9\x8a\xf9\xda\x26\x1d\x69\x08\x2f\xf6\x40\x72\xcb\xa2\x74\x0e\x7e\x51\x9e\x2f\xdf\xbf\xdb\xa2\x37\xd4\x0c\x07\x43\x5d\x8f\x37\x6c\xda\xc4\x73\xf0\xaf\x44\xc9\x3f\x9b\xb2\xeb\xa3\x1e\xd3\xeb\xab\xf2\xed\x8a\xf9\x5e\x96\x8f\x34\x04\x00\x43\x30\x04\xbf\x03\x00\x53\x7e\xf0\x28\x37\x03\x00\x00")
+
+func tablesAzureJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_tablesAzureJson,
+		"tables/azure.json",
+	)
+}
+
+func tablesAzureJson() (*asset, error) {
+	bytes, err := tablesAzureJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "tables/azure.json", size: 823, mode: os.FileMode(420), modTime: time.Unix(1470666525, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _tablesDigitaloceanJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xce\x41\x6a\xc4\x20\x14\xc6\xf1\x7d\x4e\xf1\x70\x59\x86\x92\x67\x34\xd1\xec\x0a\xbd\x41\xdb\x03\x64\xcc\x6b\x10\xa6\x89\x38\xa6\x90\x96\xdc\xbd\x18\xa3\x65\x76\xf2\xfb\xcb\xa7\xbf\x15\x00\x73\x7e\xf9\xb6\x23\x79\xd6\x03\x1b\xed\x64\xc3\x70\x5b\x0c\x0d\x33\xbb\xc4\x6a\x56\xef\x69\x36\x5b\xac\x1f\x6f\xaf\x09\xc3\xe6\xe8\x25\x04\x6f\xaf\x6b\xa0\x58\xee\xf6\x87\x52\xf2\x34\xd9\x65\x7e\x88\x89\x52\x1e\xe9\x73\x58\x6f\xe1\x7d\x73\x47\x92\xc8\xbf\xae\xa9\x38\x6f\x0d\xdd\x59\x0f\xf1\x53\x00\xec\xa9\x1c\x21\xdf\xeb\xa1\x7e\xae\xeb\x4e\x88\x4b\x76\x9c\x4e\x45\xa1\x54\x51\x9e\x95\xeb\xae\x2d\x2a\xb2\x4a\x2d\x79\x51\x75\x2a\xa2\xae\x65\x51\x6c\x4f\xe6\x8d\xc2\xa2\x4d\x1e\x16\x5d\x8b\xba\xb0\xc8\x1b\x1d\x0a\xfe\xcf\x6d\x7e\x50\x4b\xde\xa8\x43\xf7\x0a\x60\xaf\xf6\xea\x6f\x00\xd3\xdd\x5a\x7c\x78\x01\x00\x00")
+
+func tablesDigitaloceanJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_tablesDigitaloceanJson,
+		"tables/digitalocean.json",
+	)
+}
+
+func tablesDigitaloceanJson() (*asset, error) {
+	bytes, err := tablesDigitaloceanJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "tables/digitalocean.json", size: 376, mode: os.FileMode(420), modTime: time.Unix(1470666525, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _tablesGoogleJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdc\xd1\xc1\x6e\x83\x20\x18\xc0\xf1\xbb\x4f\x41\x38\x97\x06\xa8\x2d\xda\xdb\x92\xbd\xc1\xb2\xf3\xc2\xf0\x9b\x92\x28\x18\xc0\x2d\xdd\xe2\xbb\x2f\xa8\x35\x6b\xe6\x58\xb2\xe3\xae\xdf\x0f\x85\x7f\xbe\x8f\x0c\x21\xdc\x3b\xfb\xaa\x2b\x70\xf8\x8c\x70\x6d\x6d\xdd\x02\xde\xc5\xb9\x1a\x9c\x03\xa3\x2e\x71\xfe\xf8\x70\x3f\x0f\xc3\xa5\x87\xbb\x10\x9c\x7e\x1e\x02\x44\xe9\xa4\x6a\xb4\x81\xa7\x08\xf3\x11\x07\xb5\xb6\xe6\xe6\xd0\xbb\x35\x0b\xf6\x4e\x2b\xf0\xf8\x8c\xe2\xd5\x08\xe1\xc1\x13\x05\x26\x38\xd9\xb2\x75\x88\x10\x7e\x61\xa4\xd3\xca\x59\x7c\x46\x74\x4f\xa9\x38\xed\xae\x52\x33\xe2\x3b\xd9\xb6\xb3\xf0\xa3\x58\xc5\x30\xe2\x83\x34\x95\x74\x15\x89\x3f\xa3\x7b\x9a\x8b\xe3\x26\xf3\x99\xcb\x6d\xcd\x27\x65\xe5\x57\x6c\x74\xdd\xa8\x7e\xb8\x7e\x29\xe8\x37\xed\xa0\x5b\x94\xb1\x22\x9f\x70\xdc\xad\x91\x20\x7d\xf8\xc7\x85\x30\x38\xdb\x03\x79\x83\x74\x65\xf1\x63\xa5\x28\x37\x1f\xba\x54\x1e\xf9\x61\x93\x97\xd7\xd0\xfc\xb4\xc9\x73\x26\xa7\x25\x4f\x85\x16\x89\xce\x03\xe5\x37\x9d\xd2\x6b\xf9\xeb\x2e\xff\x5c\x99\x5a\x25\x63\xa9\xc4\x54\x60\xc1\x53\x81\x62\xb2\x31\x43\x68\xcc\xc6\xec\x73\x00\xa5\xa5\x85\xb5\x11\x04\x00\x00")
+
+func tablesGoogleJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_tablesGoogleJson,
+		"tables/google.json",
+	)
+}
+
+func tablesGoogleJson() (*asset, error) {
+	bytes, err := tablesGoogleJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "tables/google.json", size: 1041, mode: os.FileMode(420), modTime: time.Unix(1470666525, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+// Asset loads and returns the asset for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func Asset(name string) ([]byte, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("Asset %s can't read by error: %v", name, err)
+		}
+		return a.bytes, nil
+	}
+	return nil, fmt.Errorf("Asset %s not found", name)
+}
+
+// MustAsset is like Asset but panics when Asset would return an error.
+// It simplifies safe initialization of global variables.
+func MustAsset(name string) []byte {
+	a, err := Asset(name)
+	if err != nil {
+		panic("asset: Asset(" + name + "): " + err.Error())
+	}
+
+	return a
+}
+
+// AssetInfo loads and returns the asset info for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func AssetInfo(name string) (os.FileInfo, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("AssetInfo %s can't read by error: %v", name, err)
+		}
+		return a.info, nil
+	}
+	return nil, fmt.Errorf("AssetInfo %s not found", name)
+}
+
+// AssetNames returns the names of the assets.
+func AssetNames() []string {
+	names := make([]string, 0, len(_bindata))
+	for name := range _bindata {
+		names = append(names, name)
+	}
+	return names
+}
+
+// _bindata is a table, holding each asset generator, mapped to its name.
+var _bindata = map[string]func() (*asset, error){
+	"tables/aws.json":          tablesAwsJson,
+	"tables/azure.json":        tablesAzureJson,
+	"tables/digitalocean.json": tablesDigitaloceanJson,
+	"tables/google.json":       tablesGoogleJson,
+}
+
+// AssetDir returns the file names below a certain
+// directory embedded in the file by go-bindata.
+// For example if you run go-bindata on data/... and data contains the
+// following hierarchy:
+//     data/
+//       foo.txt
+//       img/
+//         a.png
+//         b.png
+// then AssetDir("data") would return []string{"foo.txt", "img"}
+// AssetDir("data/img") would return []string{"a.png", "b.png"}
+// AssetDir("foo.txt") and AssetDir("notexist") would return an error
+// AssetDir("") will return []string{"data"}.
+func AssetDir(name string) ([]string, error) {
+	node := _bintree
+	if len(name) != 0 {
+		cannonicalName := strings.Replace(name, "\\", "/", -1)
+		pathList := strings.Split(cannonicalName, "/")
+		for _, p := range pathList {
+			node = node.Children[p]
+			if node == nil {
+				return nil, fmt.Errorf("Asset %s not found", name)
+			}
+		}
+	}
+	if node.Func != nil {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	rv := make([]string, 0, len(node.Children))
+	for childName := range node.Children {
+		rv = append(rv, childName)
+	}
+	return rv, nil
+}
+
+type bintree struct {
+	Func     func() (*asset, error)
+	Children map[string]*bintree
+}
+
+var _bintree = &bintree{nil, map[string]*bintree{
+	"tables": {nil, map[string]*bintree{
+		"aws.json":          {tablesAwsJson, map[string]*bintree{}},
+		"azure.json":        {tablesAzureJson, map[string]*bintree{}},
+		"digitalocean.json": {tablesDigitaloceanJson, map[string]*bintree{}},
+		"google.json":       {tablesGoogleJson, map[string]*bintree{}},
+	}},
+}}
+
+// RestoreAsset restores an asset under the given directory
+func RestoreAsset(dir, name string) error {
+	data, err := Asset(name)
+	if err != nil {
+		return err
+	}
+	info, err := AssetInfo(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(_filePath(dir, filepath.Dir(name)), os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(_filePath(dir, name), data, info.Mode())
+	if err != nil {
+		return err
+	}
+	err = os.Chtimes(_filePath(dir, name), info.ModTime(), info.ModTime())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreAssets restores an asset under the given directory recursively
+func RestoreAssets(dir, name string) error {
+	children, err := AssetDir(name)
+	// File
+	if err != nil {
+		return RestoreAsset(dir, name)
+	}
+	// Dir
+	for _, child := range children {
+		err = RestoreAssets(dir, filepath.Join(name, child))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func _filePath(dir, name string) string {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	return filepath.Join(append([]string{dir}, strings.Split(cannonicalName, "/")...)...)
+}
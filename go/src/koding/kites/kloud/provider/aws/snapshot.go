@@ -0,0 +1,136 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+
+	"koding/kites/kloud/api/amazon"
+	"koding/kites/kloud/machinestate"
+	"koding/kites/kloud/stack"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"golang.org/x/net/context"
+)
+
+var _ stack.Snapshotter = (*Machine)(nil)
+
+// CreateSnapshot creates a snapshot of the root EBS volume of the instance.
+func (m *Machine) CreateSnapshot(ctx context.Context, label string) (*stack.Snapshot, error) {
+	instance, err := m.AWSClient.Instance()
+	if err != nil {
+		return nil, err
+	}
+
+	volumeID, _, err := rootVolume(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	m.PushEvent("Creating snapshot of "+volumeID+" volume", 50, machinestate.Snapshotting)
+
+	snapshot, err := m.AWSClient.CreateSnapshot(volumeID, label)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotID := aws.StringValue(snapshot.SnapshotId)
+
+	tags := map[string]string{
+		"Name":             label,
+		"koding-machineId": m.ObjectId.Hex(),
+	}
+
+	if err := m.AWSClient.AddTags(snapshotID, tags); err != nil {
+		m.Log.Warning("failed to tag %q snapshot: %s", snapshotID, err)
+	}
+
+	return &stack.Snapshot{
+		ID:          snapshotID,
+		Region:      m.BaseMachine.Metadata.(*Meta).Region,
+		StorageSize: int(aws.Int64Value(snapshot.VolumeSize)),
+	}, nil
+}
+
+// DeleteSnapshot deletes the given EBS snapshot.
+func (m *Machine) DeleteSnapshot(_ context.Context, snapshotID string) error {
+	err := m.AWSClient.Client.DeleteSnapshot(snapshotID)
+	if amazon.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// RestoreSnapshot registers an AMI with the given EBS snapshot
+// as its root volume. The instance is recreated from the AMI
+// by Terraform, so it does not diverge from the stack state.
+func (m *Machine) RestoreSnapshot(_ context.Context, snapshotID string) (*stack.ResourceOverride, error) {
+	instance, err := m.AWSClient.Instance()
+	if err != nil {
+		return nil, err
+	}
+
+	oldVolumeID, device, err := rootVolume(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	oldVolume, err := m.AWSClient.Client.VolumeByID(oldVolumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.PushEvent("Creating image from "+snapshotID+" snapshot", 40, machinestate.Snapshotting)
+
+	params := &ec2.RegisterImageInput{
+		Name:               aws.String("koding-" + m.ObjectId.Hex() + "-" + snapshotID),
+		Architecture:       instance.Architecture,
+		RootDeviceName:     aws.String(device),
+		VirtualizationType: instance.VirtualizationType,
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{{
+			DeviceName: aws.String(device),
+			Ebs: &ec2.EbsBlockDevice{
+				SnapshotId:          aws.String(snapshotID),
+				VolumeType:          oldVolume.VolumeType,
+				DeleteOnTermination: aws.Bool(true),
+			},
+		}},
+	}
+
+	imageID, err := m.AWSClient.Client.RegisterImage(params)
+	if err != nil {
+		return nil, err
+	}
+
+	m.PushEvent("Waiting for "+imageID+" image", 60, machinestate.Snapshotting)
+
+	if err := m.AWSClient.Client.WaitImage(imageID); err != nil {
+		return nil, err
+	}
+
+	return &stack.ResourceOverride{
+		Type: "aws_instance",
+		Attributes: map[string]interface{}{
+			"ami": imageID,
+		},
+	}, nil
+}
+
+// rootVolume gives id of the root EBS volume of the instance
+// and the device it is attached to.
+func rootVolume(instance *ec2.Instance) (volumeID, device string, err error) {
+	device = aws.StringValue(instance.RootDeviceName)
+
+	for _, bd := range instance.BlockDeviceMappings {
+		if aws.StringValue(bd.DeviceName) == device && bd.Ebs != nil {
+			return aws.StringValue(bd.Ebs.VolumeId), device, nil
+		}
+	}
+
+	if device == "" {
+		return "", "", errors.New("instance has no root device")
+	}
+
+	return "", "", fmt.Errorf("no EBS volume attached to %q root device", device)
+}
@@ -0,0 +1,132 @@
+package do
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"koding/kites/kloud/machinestate"
+	"koding/kites/kloud/stack"
+
+	"github.com/digitalocean/godo"
+	"golang.org/x/net/context"
+)
+
+var _ stack.Snapshotter = (*Machine)(nil)
+
+// CreateSnapshot creates a snapshot of the droplet.
+//
+// DigitalOcean does not return the image in the snapshot action,
+// thus the snapshot is looked up by its unique name once
+// the action is completed.
+func (m *Machine) CreateSnapshot(ctx context.Context, label string) (*stack.Snapshot, error) {
+	dropletID, err := m.DropletID()
+	if err != nil {
+		return nil, err
+	}
+
+	if dropletID == 0 {
+		return nil, ErrInvalidDropletID
+	}
+
+	name := "koding-" + m.ObjectId.Hex() + "-" + strconv.FormatInt(time.Now().Unix(), 10)
+
+	m.PushEvent("Creating snapshot of "+strconv.Itoa(dropletID)+" droplet", 50, machinestate.Snapshotting)
+
+	action, _, err := m.client.DropletActions.Snapshot(dropletID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := waitForAction(ctx, m.client, action); err != nil {
+		return nil, err
+	}
+
+	image, err := m.snapshotByName(dropletID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &stack.Snapshot{
+		ID:          strconv.Itoa(image.ID),
+		StorageSize: image.MinDiskSize,
+	}
+
+	if len(image.Regions) != 0 {
+		snapshot.Region = image.Regions[0]
+	}
+
+	return snapshot, nil
+}
+
+// DeleteSnapshot deletes the given snapshot image.
+func (m *Machine) DeleteSnapshot(_ context.Context, snapshotID string) error {
+	imageID, err := strconv.Atoi(snapshotID)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot ID %q: %s", snapshotID, err)
+	}
+
+	resp, err := m.client.Images.Delete(imageID)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil
+	}
+
+	return err
+}
+
+// RestoreSnapshot restores the droplet from the given snapshot image.
+//
+// The droplet is restored in place, its ID does not change.
+func (m *Machine) RestoreSnapshot(ctx context.Context, snapshotID string) (*stack.ResourceOverride, error) {
+	dropletID, err := m.DropletID()
+	if err != nil {
+		return nil, err
+	}
+
+	if dropletID == 0 {
+		return nil, ErrInvalidDropletID
+	}
+
+	imageID, err := strconv.Atoi(snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot ID %q: %s", snapshotID, err)
+	}
+
+	m.PushEvent("Restoring droplet from "+snapshotID+" snapshot", 50, machinestate.Snapshotting)
+
+	action, _, err := m.client.DropletActions.Restore(dropletID, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, waitForAction(ctx, m.client, action)
+}
+
+// snapshotByName looks up droplet's snapshot with the given name.
+func (m *Machine) snapshotByName(dropletID int, name string) (*godo.Image, error) {
+	opts := &godo.ListOptions{PerPage: 200}
+
+	for {
+		images, resp, err := m.client.Droplets.Snapshots(dropletID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range images {
+			if images[i].Name == name {
+				return &images[i], nil
+			}
+		}
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			return nil, fmt.Errorf("snapshot %q not found", name)
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Page = page + 1
+	}
+}
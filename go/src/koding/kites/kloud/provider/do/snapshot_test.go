@@ -0,0 +1,128 @@
+package do
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"koding/kites/kloud/contexthelper/session"
+
+	"github.com/digitalocean/godo"
+	"golang.org/x/net/context"
+)
+
+func TestMachineCreateSnapshot(t *testing.T) {
+	dropletID := 12345
+	bm := newDoBaseMachine(dropletID)
+	bm.Session = &session.Session{}
+
+	machine, err := newMachine(bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var name string
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/droplets/12345/actions":
+			var req godo.ActionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			name, _ = req["name"].(string)
+
+			fallthrough
+		case "/v2/actions/1":
+			action := &godo.Action{
+				ID:     1,
+				Status: "completed",
+			}
+
+			_ = json.NewEncoder(w).Encode(newRootAction(action))
+		case "/v2/droplets/12345/snapshots":
+			snapshots := []godo.Image{{
+				ID:   2,
+				Name: "other",
+			}, {
+				ID:          3,
+				Name:        name,
+				Regions:     []string{"nyc1"},
+				MinDiskSize: 20,
+			}}
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"snapshots": snapshots})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+
+	err = withClient(http.HandlerFunc(handler), func(client *godo.Client) error {
+		m, ok := machine.(*Machine)
+		if !ok {
+			return fmt.Errorf("can't type assert %T to *Machine", machine)
+		}
+
+		m.client = client
+
+		snapshot, err := m.CreateSnapshot(context.Background(), "label")
+		if err != nil {
+			return err
+		}
+
+		if snapshot.ID != "3" {
+			return fmt.Errorf("want snapshot ID %q, got %q", "3", snapshot.ID)
+		}
+
+		if snapshot.Region != "nyc1" {
+			return fmt.Errorf("want region %q, got %q", "nyc1", snapshot.Region)
+		}
+
+		if snapshot.StorageSize != 20 {
+			return fmt.Errorf("want storage size %d, got %d", 20, snapshot.StorageSize)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMachineDeleteSnapshot(t *testing.T) {
+	bm := newDoBaseMachine(12345)
+	machine, err := newMachine(bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		// Already deleted snapshots are not treated as an error.
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	err = withClient(http.HandlerFunc(handler), func(client *godo.Client) error {
+		m, ok := machine.(*Machine)
+		if !ok {
+			return fmt.Errorf("can't type assert %T to *Machine", machine)
+		}
+
+		m.client = client
+
+		if err := m.DeleteSnapshot(context.Background(), "3"); err != nil {
+			return err
+		}
+
+		if err := m.DeleteSnapshot(context.Background(), "invalid"); err == nil {
+			return fmt.Errorf("expected DeleteSnapshot to fail for invalid ID")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
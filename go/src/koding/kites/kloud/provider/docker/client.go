@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"time"
+
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+// DefaultStopTimeout is a time the daemon waits for a container
+// to stop before killing it.
+var DefaultStopTimeout = 10 * time.Second
+
+// DefaultTimeout is a timeout for requests sent to the daemon.
+var DefaultTimeout = 2 * time.Minute
+
+// Config represents configuration of the docker provider.
+type Config struct {
+	// AllowLocal allows credentials to point at a Docker daemon
+	// listening on a unix socket or on a loopback, link-local or
+	// private address - e.g. the daemon kloud is running next to.
+	//
+	// It is disabled by default, as such credentials give users
+	// access to the network kloud itself is running in.
+	AllowLocal bool
+}
+
+// defaultConfig is used when the provider was not configured.
+var defaultConfig = &Config{}
+
+func configOf(v interface{}) *Config {
+	if cfg, ok := v.(*Config); ok && cfg != nil {
+		return cfg
+	}
+
+	return defaultConfig
+}
+
+// NewClient gives new client for the Docker daemon
+// described by the given credential.
+func NewClient(c *Credential, cfg *Config) (*dockerclient.Client, error) {
+	if err := c.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkHost(cfg); err != nil {
+		return nil, err
+	}
+
+	var client *dockerclient.Client
+	var err error
+
+	if c.hasTLS() {
+		client, err = dockerclient.NewTLSClientFromBytes(c.Host, []byte(c.CertMaterial), []byte(c.KeyMaterial), []byte(c.CAMaterial))
+	} else {
+		client, err = dockerclient.NewClient(c.Host)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetTimeout(DefaultTimeout)
+
+	return client, nil
+}
@@ -0,0 +1,24 @@
+// Package docker implements Kloud provider for a plain Docker daemon:
+//
+//   https://docs.docker.com/engine/reference/api/docker_remote_api/
+//
+// Each docker_container resource is a single machine, with klient
+// installed by an entrypoint injected into the container.
+//
+// The daemon can be either a local one, accessed through a unix
+// socket, or a remote one, accessed over TCP with optional TLS
+// authentication. It makes it possible to test stack templates
+// without a cloud account.
+package docker
+
+import "koding/kites/kloud/stack/provider"
+
+func init() {
+	provider.Register(&provider.Provider{
+		Name:         "docker",
+		ResourceName: "container",
+		Machine:      newMachine,
+		Stack:        newStack,
+		Schema:       schema,
+	})
+}
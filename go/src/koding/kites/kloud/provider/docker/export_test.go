@@ -0,0 +1,17 @@
+package docker
+
+import "net"
+
+// SetLookupIP replaces the host resolver for tests purposes,
+// the returned func restores the original one.
+func SetLookupIP(fn func(string) ([]net.IP, error)) func() {
+	orig := lookupIP
+	lookupIP = fn
+
+	return func() { lookupIP = orig }
+}
+
+// CheckHost exports checkHost for tests purposes.
+func (c *Credential) CheckHost(cfg *Config) error {
+	return c.checkHost(cfg)
+}
@@ -0,0 +1,141 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"koding/kites/kloud/machinestate"
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/stack/provider"
+
+	dockerclient "github.com/fsouza/go-dockerclient"
+	"golang.org/x/net/context"
+)
+
+// Machine represents a single Docker container.
+type Machine struct {
+	*provider.BaseMachine
+
+	client *dockerclient.Client
+}
+
+var (
+	_ provider.Machine = (*Machine)(nil) // public API
+	_ stack.Machiner   = (*Machine)(nil) // internal API
+)
+
+func newMachine(bm *provider.BaseMachine) (provider.Machine, error) {
+	cred, ok := bm.Credential.(*Credential)
+	if !ok {
+		return nil, errors.New("not a valid Docker credential")
+	}
+
+	client, err := NewClient(cred, configOf(bm.Config))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Machine{
+		BaseMachine: bm,
+		client:      client,
+	}, nil
+}
+
+// Start starts the container.
+func (m *Machine) Start(ctx context.Context) (interface{}, error) {
+	id, err := m.ContainerID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.client.StartContainer(id, nil)
+
+	// starting an already running container is a nop
+	if _, ok := err.(*dockerclient.ContainerAlreadyRunning); ok {
+		err = nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Stop stops the container.
+func (m *Machine) Stop(ctx context.Context) (interface{}, error) {
+	id, err := m.ContainerID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.client.StopContainer(id, uint(DefaultStopTimeout/time.Second))
+
+	// stopping an already stopped container is a nop
+	if _, ok := err.(*dockerclient.ContainerNotRunning); ok {
+		err = nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Info gives state of the container.
+func (m *Machine) Info(ctx context.Context) (machinestate.State, interface{}, error) {
+	id, err := m.ContainerID()
+	if err != nil {
+		return machinestate.Unknown, nil, err
+	}
+
+	container, err := m.client.InspectContainer(id)
+	if _, ok := err.(*dockerclient.NoSuchContainer); ok {
+		return machinestate.NotInitialized, nil, nil
+	}
+
+	if err != nil {
+		return machinestate.Unknown, nil, err
+	}
+
+	return containerState(&container.State), nil, nil
+}
+
+// ContainerID gives ID of the container associated with the machine.
+func (m *Machine) ContainerID() (string, error) {
+	meta, ok := m.BaseMachine.Metadata.(*Metadata)
+	if !ok {
+		return "", fmt.Errorf("metadata is not of type docker.Metadata: %T", m.BaseMachine.Metadata)
+	}
+
+	if err := meta.Valid(); err != nil {
+		return "", err
+	}
+
+	return meta.ContainerID, nil
+}
+
+// Credential gives a Docker credential that is attached
+// to the m Machine.
+func (m *Machine) Credential() *Credential {
+	return m.BaseMachine.Credential.(*Credential)
+}
+
+// containerState converts a container state to
+// a sensible machinestate.State enum.
+func containerState(s *dockerclient.State) machinestate.State {
+	switch {
+	case s == nil:
+		return machinestate.Unknown
+	case s.Restarting:
+		return machinestate.Starting
+	case s.Running && !s.Paused:
+		return machinestate.Running
+	case s.Dead:
+		return machinestate.Terminated
+	default:
+		return machinestate.Stopped
+	}
+}
@@ -0,0 +1,210 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/stack/provider"
+)
+
+var schema = &provider.Schema{
+	NewCredential: func() interface{} {
+		return &Credential{}
+	},
+	NewBootstrap: func() interface{} {
+		return &Bootstrap{}
+	},
+	NewMetadata: func(m *stack.Machine) interface{} {
+		if m == nil {
+			return &Metadata{}
+		}
+
+		return &Metadata{
+			ContainerID: m.Attributes["id"],
+			Name:        m.Attributes["name"],
+			Image:       m.Attributes["image"],
+		}
+	},
+}
+
+var (
+	_ stack.Validator = (*Credential)(nil)
+	_ stack.Validator = (*Bootstrap)(nil)
+	_ stack.Validator = (*Metadata)(nil)
+)
+
+// Credential represents credential information
+// that are required to access a Docker daemon.
+type Credential struct {
+	// Host is an address of the Docker daemon, e.g.:
+	//
+	//   tcp://docker.example.com:2376
+	//   unix:///var/run/docker.sock (requires Config.AllowLocal)
+	//
+	Host string `json:"host" bson:"host" hcl:"host"`
+
+	// CAMaterial, CertMaterial and KeyMaterial are PEM-encoded
+	// CA certificate, client certificate and client private key
+	// required to authenticate with the daemon - the content of
+	// ca.pem, cert.pem and key.pem files.
+	//
+	// Either all of them or none must be set.
+	CAMaterial   string `json:"ca_material" bson:"ca_material" hcl:"ca_material"`
+	CertMaterial string `json:"cert_material" bson:"cert_material" hcl:"cert_material"`
+	KeyMaterial  string `json:"key_material" bson:"key_material" hcl:"key_material"`
+}
+
+// Valid implements the stack.Validator interface.
+func (c *Credential) Valid() error {
+	if c.Host == "" {
+		return errors.New("invalid empty host")
+	}
+
+	u, err := url.Parse(c.Host)
+	if err != nil {
+		return errors.New("invalid host: " + err.Error())
+	}
+
+	switch u.Scheme {
+	case "unix", "tcp", "http", "https":
+	default:
+		return fmt.Errorf("unsupported host scheme: %q", u.Scheme)
+	}
+
+	if c.hasTLS() && (c.CAMaterial == "" || c.CertMaterial == "" || c.KeyMaterial == "") {
+		return errors.New("ca_material, cert_material and key_material must be set together")
+	}
+
+	return nil
+}
+
+func (c *Credential) hasTLS() bool {
+	return c.CAMaterial != "" || c.CertMaterial != "" || c.KeyMaterial != ""
+}
+
+// checkHost checks whether the daemon can be accessed with the given
+// configuration. Unless local daemons are allowed, the host is resolved
+// and none of its addresses can be a local or a private one.
+func (c *Credential) checkHost(cfg *Config) error {
+	if cfg.AllowLocal {
+		return nil
+	}
+
+	u, err := url.Parse(c.Host)
+	if err != nil {
+		return errors.New("invalid host: " + err.Error())
+	}
+
+	if u.Scheme == "unix" {
+		return errors.New("unix socket hosts are not allowed")
+	}
+
+	host := u.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ips, err := lookupIP(strings.Trim(host, "[]"))
+	if err != nil {
+		return fmt.Errorf("unable to resolve host %q: %s", host, err)
+	}
+
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("host %q resolves to a local address %s, which is not allowed", host, ip)
+		}
+	}
+
+	return nil
+}
+
+// lookupIP resolves daemon hosts, it is replaced in tests.
+var lookupIP = net.LookupIP
+
+// privateNets holds address ranges of local and private networks,
+// which include the docker0 bridge network (172.17.0.0/16).
+var privateNets = parseCIDRs(
+	"0.0.0.0/8",      // current network
+	"10.0.0.0/8",     // private
+	"100.64.0.0/10",  // carrier-grade NAT
+	"127.0.0.0/8",    // loopback
+	"169.254.0.0/16", // link-local
+	"172.16.0.0/12",  // private
+	"192.168.0.0/16", // private
+	"::1/128",        // loopback
+	"fc00::/7",       // unique local
+	"fe80::/10",      // link-local
+)
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets
+}
+
+// Bootstrap represents bootstrap data, which is shared
+// between all containers created with the same credential.
+type Bootstrap struct {
+	// NetworkID is an ID of the network all the containers
+	// are connected to.
+	NetworkID string `json:"network_id" bson:"network_id" hcl:"network_id"`
+
+	// NetworkName is a name of the network.
+	NetworkName string `json:"network_name" bson:"network_name" hcl:"network_name"`
+}
+
+// Valid implements the stack.Validator interface.
+func (b *Bootstrap) Valid() error {
+	if b.NetworkID == "" {
+		return errors.New("network ID is empty")
+	}
+
+	if b.NetworkName == "" {
+		return errors.New("network name is empty")
+	}
+
+	return nil
+}
+
+// Metadata represents a single container metadata.
+type Metadata struct {
+	ContainerID string `json:"container_id" bson:"container_id" hcl:"container_id"`
+	Name        string `json:"name" bson:"name" hcl:"name"`
+	Image       string `json:"image" bson:"image" hcl:"image"`
+}
+
+// Valid implements the stack.Validator interface.
+func (m *Metadata) Valid() error {
+	if m.ContainerID == "" {
+		return errors.New("container ID is empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package docker_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"koding/kites/kloud/provider/docker"
+)
+
+func TestCredentialValid(t *testing.T) {
+	cases := map[string]struct {
+		cred *docker.Credential
+		ok   bool
+	}{
+		"unix socket": {
+			&docker.Credential{Host: "unix:///var/run/docker.sock"},
+			true,
+		},
+		"remote tcp": {
+			&docker.Credential{Host: "tcp://192.168.99.100:2376"},
+			true,
+		},
+		"remote tls": {
+			&docker.Credential{
+				Host:         "tcp://192.168.99.100:2376",
+				CAMaterial:   "ca",
+				CertMaterial: "cert",
+				KeyMaterial:  "key",
+			},
+			true,
+		},
+		"partial tls": {
+			&docker.Credential{
+				Host:         "tcp://192.168.99.100:2376",
+				CertMaterial: "cert",
+			},
+			false,
+		},
+		"empty host": {
+			&docker.Credential{},
+			false,
+		},
+		"unsupported scheme": {
+			&docker.Credential{Host: "ftp://docker.local"},
+			false,
+		},
+	}
+
+	for name, cas := range cases {
+		err := cas.cred.Valid()
+
+		if cas.ok && err != nil {
+			t.Errorf("%s: Valid()=%s", name, err)
+		}
+
+		if !cas.ok && err == nil {
+			t.Errorf("%s: expected Valid() to fail", name)
+		}
+	}
+}
+
+func TestCredentialCheckHost(t *testing.T) {
+	hosts := map[string][]net.IP{
+		"docker.example.com": {net.ParseIP("93.184.216.34")},
+		"internal.example":   {net.ParseIP("93.184.216.34"), net.ParseIP("10.0.0.5")},
+		"bridge.example":     {net.ParseIP("172.17.0.1")},
+	}
+
+	defer docker.SetLookupIP(func(host string) ([]net.IP, error) {
+		if ip := net.ParseIP(host); ip != nil {
+			return []net.IP{ip}, nil
+		}
+
+		if ips, ok := hosts[host]; ok {
+			return ips, nil
+		}
+
+		return nil, errors.New("no such host")
+	})()
+
+	cases := map[string]struct {
+		host string
+		ok   bool // whether allowed without AllowLocal
+	}{
+		"unix socket":       {"unix:///var/run/docker.sock", false},
+		"loopback":          {"tcp://127.0.0.1:2375", false},
+		"ipv6 loopback":     {"tcp://[::1]:2375", false},
+		"link-local":        {"tcp://169.254.169.254:80", false},
+		"docker bridge":     {"tcp://172.17.0.1:2375", false},
+		"private":           {"tcp://192.168.99.100:2376", false},
+		"private name":      {"tcp://internal.example:2376", false},
+		"bridge name":       {"https://bridge.example", false},
+		"unresolvable name": {"tcp://unknown.example:2376", false},
+		"public":            {"tcp://93.184.216.34:2376", true},
+		"public name":       {"https://docker.example.com:2376", true},
+	}
+
+	for name, cas := range cases {
+		cred := &docker.Credential{Host: cas.host}
+
+		if err := cred.CheckHost(&docker.Config{AllowLocal: true}); err != nil {
+			t.Errorf("%s (AllowLocal): CheckHost()=%s", name, err)
+		}
+
+		err := cred.CheckHost(&docker.Config{})
+
+		if cas.ok && err != nil {
+			t.Errorf("%s: CheckHost()=%s", name, err)
+		}
+
+		if !cas.ok && err == nil {
+			t.Errorf("%s: expected CheckHost() to fail", name)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package docker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"koding/kites/config"
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/stack/provider"
+	"koding/kites/kloud/utils"
+)
+
+//go:generate $GOPATH/bin/go-bindata -mode 420 -modtime 1470666525 -pkg docker -o bootstrap.json.tmpl.go bootstrap.json.tmpl
+//go:generate gofmt -l -w -s bootstrap.json.tmpl.go
+
+var bootstrapTmpl = template.Must(
+	template.New("").Parse(string(MustAsset("bootstrap.json.tmpl"))),
+)
+
+// entrypoint is a script, which installs klient in a container
+// and then executes container's command.
+//
+// The script is passed to the "/bin/sh -c" as a container's
+// entrypoint, so the original command of the container
+// is passed to it as arguments.
+//
+// Braces are not used for shell variables, as they would be
+// interpolated by Terraform.
+const entrypoint = `set -e
+
+if [ ! -x /opt/kite/klient/klient ]; then
+	echo "[entrypoint] installing klient from $KODING_KLIENT_URL" >&2
+
+	if command -v curl >/dev/null 2>&1; then
+		curl -fsSL "$KODING_KLIENT_URL" | gzip --decompress --stdout > /tmp/klient
+	else
+		wget -qO- "$KODING_KLIENT_URL" | gzip --decompress --stdout > /tmp/klient
+	fi
+
+	chmod +x /tmp/klient
+	/tmp/klient -metadata "$KODING_METADATA" install
+fi
+
+if [ $# -gt 0 ]; then
+	/opt/kite/klient/klient start
+
+	echo "[entrypoint] executing: $@" >&2
+	exec "$@"
+fi
+
+exec /opt/kite/klient/klient
+`
+
+// ErrIncompatibleEntrypoint is returned by ApplyTemplate when a container
+// overwrites its entrypoint.
+var ErrIncompatibleEntrypoint = errors.New(`docker: setting "entrypoint" argument conflicts with Koding entrypoint injected into each container. Please use "command" argument instead.`)
+
+// Stack represents a set of Docker containers.
+type Stack struct {
+	*provider.BaseStack
+
+	KlientURL string
+}
+
+var (
+	_ provider.Stack = (*Stack)(nil) // public API
+	_ stack.Stacker  = (*Stack)(nil) // internal API
+)
+
+func newStack(bs *provider.BaseStack) (provider.Stack, error) {
+	s := &Stack{
+		BaseStack: bs,
+		KlientURL: stack.Konfig.KlientGzURL(),
+	}
+
+	return s, nil
+}
+
+// VerifyCredential checks whether the Docker daemon
+// is reachable with the given credentials.
+func (s *Stack) VerifyCredential(c *stack.Credential) error {
+	client, err := NewClient(c.Credential.(*Credential), s.Config())
+	if err != nil {
+		return err
+	}
+
+	return client.Ping()
+}
+
+// BootstrapTemplates gives a template, which creates a network
+// shared by all containers that use the given credential.
+func (s *Stack) BootstrapTemplates(c *stack.Credential) ([]*stack.Template, error) {
+	type tmplData struct {
+		NetworkName string
+		CertPath    string
+	}
+
+	cred, ok := c.Credential.(*Credential)
+	if !ok {
+		return nil, fmt.Errorf("credential is not of type docker.Credential: %T", c.Credential)
+	}
+
+	certPath, err := s.certPath(cred)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := bootstrapTmpl.Execute(&buf, &tmplData{
+		NetworkName: "koding-" + c.Identifier,
+		CertPath:    certPath,
+	}); err != nil {
+		return nil, err
+	}
+
+	return []*stack.Template{
+		{Content: buf.String()},
+	}, nil
+}
+
+// ApplyTemplate applies the given credentials to user's stack template.
+//
+// It injects Koding entrypoint and metadata into each of the containers
+// and connects them to the bootstrap network.
+func (s *Stack) ApplyTemplate(c *stack.Credential) (*stack.Template, error) {
+	cred, ok := c.Credential.(*Credential)
+	if !ok {
+		return nil, fmt.Errorf("credential is not of type docker.Credential: %T", c.Credential)
+	}
+
+	bootstrap, ok := c.Bootstrap.(*Bootstrap)
+	if !ok {
+		return nil, fmt.Errorf("bootstrap is not of type docker.Bootstrap: %T", c.Bootstrap)
+	}
+
+	t := s.Builder.Template
+
+	var resource struct {
+		DockerContainer map[string]map[string]interface{} `hcl:"docker_container"`
+	}
+
+	if err := t.DecodeResource(&resource); err != nil {
+		return nil, err
+	}
+
+	if len(resource.DockerContainer) == 0 {
+		return nil, errors.New("there are no containers available")
+	}
+
+	certPath, err := s.certPath(cred)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerProvider := map[string]interface{}{
+		"host": cred.Host,
+	}
+
+	if certPath != "" {
+		dockerProvider["cert_path"] = certPath
+	}
+
+	t.Provider["docker"] = dockerProvider
+
+	for name, container := range resource.DockerContainer {
+		if debug, ok := container["debug"].(bool); ok {
+			s.Debug = debug
+			delete(container, "debug")
+		}
+
+		if _, ok := container["entrypoint"]; ok {
+			return nil, ErrIncompatibleEntrypoint
+		}
+
+		container["entrypoint"] = []interface{}{"/bin/sh", "-c", entrypoint, "koding-entrypoint"}
+
+		if hostname, ok := container["hostname"].(string); !ok || hostname == "" {
+			container["hostname"] = s.Req.Username // no typo here. hostname = username
+		}
+
+		container["networks"] = appendSlice(container["networks"], bootstrap.NetworkName)
+
+		if err := s.injectMetadata(container, name); err != nil {
+			return nil, err
+		}
+	}
+
+	t.Resource["docker_container"] = resource.DockerContainer
+
+	if err := t.Flush(); err != nil {
+		return nil, errors.New("docker: error flushing template: " + err.Error())
+	}
+
+	content, err := t.JsonOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	return &stack.Template{
+		Content: content,
+	}, nil
+}
+
+// injectMetadata creates a Koding metadata for each of the containers
+// and passes it to the entrypoint via KODING_METADATA environment
+// variable.
+//
+// Each container must have a unique kite key, thus the metadata
+// is stored in a Terraform lookup map, which is used in conjunction
+// with the `count.index`.
+func (s *Stack) injectMetadata(container map[string]interface{}, name string) error {
+	count := 1
+	if n, ok := container["count"].(int); ok && n > 1 {
+		count = n
+	}
+
+	var labels []string
+	if count > 1 {
+		for i := 0; i < count; i++ {
+			labels = append(labels, fmt.Sprintf("%s.%d", name, i))
+		}
+	} else {
+		labels = append(labels, name)
+	}
+
+	metaName := "kodingmeta_" + name
+	countMeta := make(map[string]string, count)
+
+	for i, label := range labels {
+		kiteKey, err := s.BuildKiteKey(label, s.Req.Username)
+		if err != nil {
+			return err
+		}
+
+		tunnelID := utils.RandString(8)
+
+		if m, ok := s.Builder.Machines[label]; ok && m.Uid != "" {
+			tunnelID = m.Uid
+		}
+
+		konfig := &config.Konfig{
+			Endpoints: stack.Konfig.Endpoints,
+			TunnelID:  tunnelID,
+			KiteKey:   kiteKey,
+			Debug:     s.Debug,
+		}
+
+		metadata := map[string]interface{}{
+			"konfig.konfig.konfigs": map[string]interface{}{
+				konfig.ID(): konfig,
+			},
+			"konfig.konfig.konfigs.used": map[string]interface{}{
+				"id": konfig.ID(),
+			},
+		}
+
+		p, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+
+		countMeta[strconv.Itoa(i)] = base64.StdEncoding.EncodeToString(p)
+	}
+
+	s.Builder.Template.Variable[metaName] = map[string]interface{}{
+		"default": countMeta,
+	}
+
+	container["env"] = appendSlice(container["env"],
+		fmt.Sprintf("KODING_METADATA=${lookup(var.%s, count.index)}", metaName),
+		"KODING_KLIENT_URL="+s.KlientURL,
+	)
+
+	return nil
+}
+
+// CertDir is a directory, which holds TLS material of Docker credentials.
+var CertDir = filepath.Join(os.TempDir(), "kloud-docker")
+
+// certPath checks whether the given credential can be used and writes
+// its TLS material to ca.pem, cert.pem and key.pem files, which are
+// read by the Terraform provider from the returned cert_path.
+//
+// The directory is named after a digest of the material, thus files of
+// the same credential are reused. Terraformer is expected to run
+// alongside kloud, so it can read them.
+//
+// If the credential has no TLS material, empty path is returned.
+func (s *Stack) certPath(c *Credential) (string, error) {
+	if err := c.Valid(); err != nil {
+		return "", err
+	}
+
+	if err := c.checkHost(s.Config()); err != nil {
+		return "", err
+	}
+
+	if !c.hasTLS() {
+		return "", nil
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"ca.pem", c.CAMaterial},
+		{"cert.pem", c.CertMaterial},
+		{"key.pem", c.KeyMaterial},
+	}
+
+	h := sha256.New()
+
+	for _, f := range files {
+		io.WriteString(h, f.content)
+		h.Write([]byte{0})
+	}
+
+	dir := filepath.Join(CertDir, hex.EncodeToString(h.Sum(nil)))
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	for _, f := range files {
+		if err := writeFile(filepath.Join(dir, f.name), f.content); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// writeFile atomically writes the content to the given file,
+// which is readable by the owner only.
+func writeFile(file, content string) error {
+	f, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(f, content)
+
+	if e := f.Close(); e != nil && err == nil {
+		err = e
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), file)
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+
+	return err
+}
+
+// Config gives configuration of the docker provider.
+func (s *Stack) Config() *Config {
+	if s.BaseStack.Provider == nil {
+		return defaultConfig
+	}
+
+	return configOf(s.BaseStack.Provider.Config)
+}
+
+// Credential gives Docker credentials that are attached
+// to a current stack.
+func (s *Stack) Credential() *Credential {
+	return s.BaseStack.Credential.(*Credential)
+}
+
+func getSlice(v interface{}) []interface{} {
+	var slice []interface{}
+
+	switch v := v.(type) {
+	case nil:
+	case []string:
+		slice = make([]interface{}, 0, len(v))
+
+		for _, elem := range v {
+			slice = append(slice, elem)
+		}
+	case []interface{}:
+		slice = v
+	default:
+		slice = []interface{}{v}
+	}
+
+	return slice
+}
+
+func appendSlice(slice interface{}, elems ...interface{}) []interface{} {
+	return append(getSlice(slice), elems...)
+}
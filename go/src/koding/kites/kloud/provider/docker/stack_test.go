@@ -0,0 +1,291 @@
+package docker_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"koding/kites/config"
+	"koding/kites/kloud/contexthelper/session"
+	"koding/kites/kloud/keycreator"
+	"koding/kites/kloud/provider/docker"
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/stack/provider"
+	"koding/kites/kloud/stack/provider/providertest"
+	"koding/kites/kloud/userdata"
+
+	"github.com/koding/kite"
+	"github.com/koding/kite/testkeys"
+	"github.com/koding/logging"
+)
+
+func init() {
+	stack.Konfig = &config.Konfig{
+		Endpoints: &config.Endpoints{
+			Koding:       config.NewEndpoint(""),
+			Tunnel:       config.NewEndpoint(""),
+			KlientLatest: config.NewEndpoint(""),
+		},
+	}
+}
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "kloud-docker")
+	if err != nil {
+		panic(err)
+	}
+
+	docker.CertDir = dir
+
+	exit := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(exit)
+}
+
+// stripNondeterministicResources sets the following fields to "***",
+// as they change between test runs:
+//
+//   - variable.kodingmeta_*.default
+//   - resource.docker_container.*.entrypoint
+//   - provider.docker.cert_path
+//
+func stripNondeterministicResources(s string) string {
+	switch {
+	case strings.HasPrefix(s, "kodingmeta_"), s == "entrypoint", s == "cert_path":
+		return "***"
+	}
+
+	return ""
+}
+
+func noMask(string) string { return "" }
+
+func newStack(t *testing.T, file string) *docker.Stack {
+	p, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile()=%s", err)
+	}
+
+	template, err := provider.ParseTemplate(string(p), logging.NewCustom("test", true))
+	if err != nil {
+		t.Fatalf("ParseTemplate()=%s", err)
+	}
+
+	return &docker.Stack{
+		BaseStack: &provider.BaseStack{
+			Arg: &stack.ApplyRequest{
+				GroupName: "foobar",
+			},
+			Session: &session.Session{
+				Userdata: &userdata.Userdata{
+					KlientURL: "http://127.0.0.1/klient.gz",
+					Keycreator: &keycreator.Key{
+						KontrolURL:        "http://127.0.0.1/kontrol/kite",
+						KontrolPublicKey:  testkeys.Public,
+						KontrolPrivateKey: testkeys.Private,
+					},
+				},
+			},
+			Builder: &provider.Builder{
+				Template: template,
+			},
+			Provider: &provider.Provider{
+				Name: "docker",
+				Config: &docker.Config{
+					// Test credentials use private addresses.
+					AllowLocal: true,
+				},
+			},
+			Req: &kite.Request{
+				Username: "user",
+			},
+			KlientIDs: make(stack.KiteMap),
+		},
+		KlientURL: "$KLIENT_URL",
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	cred := &stack.Credential{
+		Identifier: "ident",
+		Credential: &docker.Credential{
+			Host:         "tcp://192.168.99.100:2376",
+			CAMaterial:   "ca",
+			CertMaterial: "cert",
+			KeyMaterial:  "key",
+		},
+		Bootstrap: &docker.Bootstrap{
+			NetworkID:   "ae2c4b6",
+			NetworkName: "koding-ident",
+		},
+	}
+
+	cases := map[string]struct {
+		stack  string
+		want   string
+		labels []string
+	}{
+		"single container": {
+			"testdata/single-container.json",
+			"testdata/single-container.json.golden",
+			[]string{"container"},
+		},
+		"multiple containers": {
+			"testdata/multi-container.json",
+			"testdata/multi-container.json.golden",
+			[]string{"web.0", "web.1"},
+		},
+	}
+
+	for name, cas := range cases {
+		// capture range variable here
+		cas := cas
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pWant, err := ioutil.ReadFile(cas.want)
+			if err != nil {
+				t.Fatalf("ReadFile()=%s", err)
+			}
+
+			s := newStack(t, cas.stack)
+
+			stack, err := s.ApplyTemplate(cred)
+			if err != nil {
+				t.Fatalf("ApplyTemplate()=%s", err)
+			}
+
+			if err := providertest.Equal(stack.Content, string(pWant), stripNondeterministicResources); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(s.KlientIDs) != len(cas.labels) {
+				t.Fatalf("got %d kite IDs, want %d", len(s.KlientIDs), len(cas.labels))
+			}
+
+			for _, label := range cas.labels {
+				if _, ok := s.KlientIDs[label]; !ok {
+					t.Errorf("no kite ID for %q", label)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyTemplateCertPath(t *testing.T) {
+	cred := &stack.Credential{
+		Identifier: "ident",
+		Credential: &docker.Credential{
+			Host:         "tcp://192.168.99.100:2376",
+			CAMaterial:   "ca",
+			CertMaterial: "cert",
+			KeyMaterial:  "key",
+		},
+		Bootstrap: &docker.Bootstrap{NetworkID: "ae2c4b6", NetworkName: "koding-ident"},
+	}
+
+	s := newStack(t, "testdata/single-container.json")
+
+	tmpl, err := s.ApplyTemplate(cred)
+	if err != nil {
+		t.Fatalf("ApplyTemplate()=%s", err)
+	}
+
+	var content struct {
+		Provider struct {
+			Docker struct {
+				CertPath string `json:"cert_path"`
+			} `json:"docker"`
+		} `json:"provider"`
+	}
+
+	if err := json.Unmarshal([]byte(tmpl.Content), &content); err != nil {
+		t.Fatalf("Unmarshal()=%s", err)
+	}
+
+	want := map[string]string{
+		"ca.pem":   "ca",
+		"cert.pem": "cert",
+		"key.pem":  "key",
+	}
+
+	for name, material := range want {
+		file := filepath.Join(content.Provider.Docker.CertPath, name)
+
+		p, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile()=%s", err)
+		}
+
+		if string(p) != material {
+			t.Errorf("%s: got %q, want %q", name, p, material)
+		}
+
+		fi, err := os.Stat(file)
+		if err != nil {
+			t.Fatalf("Stat()=%s", err)
+		}
+
+		if fi.Mode().Perm() != 0600 {
+			t.Errorf("%s: got %s mode, want 0600", name, fi.Mode())
+		}
+	}
+}
+
+func TestApplyTemplateLocalHost(t *testing.T) {
+	cred := &stack.Credential{
+		Identifier: "ident",
+		Credential: &docker.Credential{Host: "tcp://127.0.0.1:2375"},
+		Bootstrap:  &docker.Bootstrap{NetworkID: "ae2c4b6", NetworkName: "koding-ident"},
+	}
+
+	s := newStack(t, "testdata/single-container.json")
+	s.BaseStack.Provider.Config = &docker.Config{}
+
+	if _, err := s.ApplyTemplate(cred); err == nil {
+		t.Fatal("expected ApplyTemplate() to fail for a local host")
+	}
+}
+
+func TestApplyTemplateEntrypoint(t *testing.T) {
+	cred := &stack.Credential{
+		Identifier: "ident",
+		Credential: &docker.Credential{Host: "unix:///var/run/docker.sock"},
+		Bootstrap:  &docker.Bootstrap{NetworkID: "ae2c4b6", NetworkName: "koding-ident"},
+	}
+
+	s := newStack(t, "testdata/entrypoint.json")
+
+	if _, err := s.ApplyTemplate(cred); err != docker.ErrIncompatibleEntrypoint {
+		t.Fatalf("got %v, want %v", err, docker.ErrIncompatibleEntrypoint)
+	}
+}
+
+func TestBootstrapTemplates(t *testing.T) {
+	s := newStack(t, "testdata/single-container.json")
+
+	templates, err := s.BootstrapTemplates(&stack.Credential{
+		Identifier: "ident",
+		Credential: &docker.Credential{Host: "tcp://192.168.99.100:2376"},
+	})
+	if err != nil {
+		t.Fatalf("BootstrapTemplates()=%s", err)
+	}
+
+	if len(templates) != 1 {
+		t.Fatalf("got %d templates, want 1", len(templates))
+	}
+
+	pWant, err := ioutil.ReadFile("testdata/bootstrap.json.golden")
+	if err != nil {
+		t.Fatalf("ReadFile()=%s", err)
+	}
+
+	if err := providertest.Equal(templates[0].Content, string(pWant), noMask); err != nil {
+		t.Fatal(err)
+	}
+}
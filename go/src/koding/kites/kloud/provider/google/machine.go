@@ -20,6 +20,7 @@ import (
 type Machine struct {
 	*provider.BaseMachine
 
+	ComputeService   *compute.Service
 	InstancesService *compute.InstancesService
 }
 
@@ -37,6 +38,7 @@ func newMachine(bm *provider.BaseMachine) (provider.Machine, error) {
 		return nil, err
 	}
 
+	m.ComputeService = computeService
 	m.InstancesService = compute.NewInstancesService(computeService)
 	return m, nil
 }
@@ -0,0 +1,166 @@
+package google
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"koding/kites/kloud/machinestate"
+	"koding/kites/kloud/stack"
+
+	"golang.org/x/net/context"
+	compute "google.golang.org/api/compute/v1"
+)
+
+var _ stack.Snapshotter = (*Machine)(nil)
+
+// CreateSnapshot creates a snapshot of the boot disk of the instance.
+func (m *Machine) CreateSnapshot(ctx context.Context, label string) (*stack.Snapshot, error) {
+	project, zone, name := m.Location()
+
+	disk, err := m.bootDisk()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &compute.Snapshot{
+		Name:        "koding-" + m.ObjectId.Hex() + "-" + strconv.FormatInt(time.Now().Unix(), 10),
+		Description: label,
+	}
+
+	m.PushEvent("Creating snapshot of "+name+" instance", 50, machinestate.Snapshotting)
+
+	op, err := m.ComputeService.Disks.CreateSnapshot(project, zone, path.Base(disk.Source), snapshot).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.waitZoneOperation(ctx, op); err != nil {
+		return nil, err
+	}
+
+	snapshot, err = m.ComputeService.Snapshots.Get(project, snapshot.Name).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return &stack.Snapshot{
+		ID:          snapshot.Name,
+		Region:      zone,
+		StorageSize: int(snapshot.DiskSizeGb),
+	}, nil
+}
+
+// DeleteSnapshot deletes the given snapshot.
+func (m *Machine) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	project, _, _ := m.Location()
+
+	_, err := m.ComputeService.Snapshots.Delete(project, snapshotID).Do()
+	if isNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// RestoreSnapshot creates new disk from the given snapshot. The instance
+// is recreated with the disk as its boot disk by Terraform, so it
+// does not diverge from the stack state.
+//
+// Only the boot disk is kept in the instance resource.
+func (m *Machine) RestoreSnapshot(ctx context.Context, snapshotID string) (*stack.ResourceOverride, error) {
+	project, zone, name := m.Location()
+
+	oldDisk, err := m.bootDisk()
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := m.ComputeService.Disks.Get(project, zone, path.Base(oldDisk.Source)).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	disk := &compute.Disk{
+		Name:           name + "-" + strconv.FormatInt(time.Now().Unix(), 10),
+		SourceSnapshot: "global/snapshots/" + snapshotID,
+		Type:           old.Type,
+	}
+
+	m.PushEvent("Creating disk from "+snapshotID+" snapshot", 40, machinestate.Snapshotting)
+
+	op, err := m.ComputeService.Disks.Insert(project, zone, disk).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.waitZoneOperation(ctx, op); err != nil {
+		return nil, err
+	}
+
+	return &stack.ResourceOverride{
+		Type: "google_compute_instance",
+		Attributes: map[string]interface{}{
+			"disk": []interface{}{
+				map[string]interface{}{
+					"disk":        disk.Name,
+					"auto_delete": true,
+				},
+			},
+		},
+	}, nil
+}
+
+// bootDisk gives a boot disk of the instance.
+func (m *Machine) bootDisk() (*compute.AttachedDisk, error) {
+	project, zone, name := m.Location()
+
+	instance, err := m.InstancesService.Get(project, zone, name).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, disk := range instance.Disks {
+		if disk.Boot {
+			return disk, nil
+		}
+	}
+
+	return nil, errors.New("instance has no boot disk")
+}
+
+// waitZoneOperation waits until the given zone operation is done.
+func (m *Machine) waitZoneOperation(ctx context.Context, op *compute.Operation) error {
+	project, zone, _ := m.Location()
+
+	t := time.NewTicker(2 * time.Second)
+	defer t.Stop()
+
+	for op.Status != "DONE" {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %q operation", op.Name)
+		}
+
+		var err error
+		if op, err = m.ComputeService.ZoneOperations.Get(project, zone, op.Name).Do(); err != nil {
+			return err
+		}
+	}
+
+	if op.Error != nil && len(op.Error.Errors) != 0 {
+		msgs := make([]string, len(op.Error.Errors))
+
+		for i, e := range op.Error.Errors {
+			msgs[i] = e.Message
+		}
+
+		return fmt.Errorf("%s operation failed: %s", op.OperationType, strings.Join(msgs, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"time"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+	"koding/kites/kloud/eventer"
+	"koding/kites/kloud/machinestate"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// IdleStop describes a machine, which was stopped due to inactivity.
+type IdleStop struct {
+	MachineID string
+	Label     string
+	Username  string // owner of the machine
+	Group     string // team slug, empty if unknown
+
+	// Inactive is the inactive duration reported by klient.
+	Inactive time.Duration
+
+	// Threshold is the idle threshold the machine has reached.
+	Threshold time.Duration
+
+	// Policy is the team policy that was enforced, or nil if
+	// the machine was stopped after the default threshold.
+	Policy *models.IdlePolicy
+}
+
+// idlePolicies maps group IDs to enabled idle policies.
+type idlePolicies map[bson.ObjectId]*models.IdlePolicy
+
+// idlePolicies reads all enabled idle policies with a single query,
+// so they can be shared by all the checks of a queue tick.
+//
+// On failure it logs the error and returns no policies, in which
+// case the default threshold applies to all machines.
+func (q *Queue) idlePolicies() idlePolicies {
+	policies, err := modelhelper.GetEnabledIdlePolicies()
+	if err != nil {
+		q.Log.Warning("failed to read idle policies, using default one: %s", err)
+		return nil
+	}
+
+	m := make(idlePolicies, len(policies))
+
+	for _, p := range policies {
+		if p.GroupID.Valid() {
+			m[p.GroupID] = p
+		}
+	}
+
+	return m
+}
+
+// lookup gives an idle policy of the team the machine belongs to.
+//
+// It returns nil policy if the team has none, in which case the
+// default threshold applies.
+func (p idlePolicies) lookup(m *models.Machine) (group string, policy *models.IdlePolicy) {
+	if len(m.Groups) == 0 {
+		return "", nil
+	}
+
+	if policy, ok := p[m.Groups[0].Id]; ok {
+		return policy.Group, policy
+	}
+
+	return "", nil
+}
+
+// idleThreshold tells whether the machine should be checked for
+// inactivity and, if so, after what time it should be stopped.
+func idleThreshold(policy *models.IdlePolicy, m *models.Machine, username string, now time.Time) (time.Duration, bool) {
+	if policy == nil || !policy.Enabled {
+		return planTimeout, true
+	}
+
+	if policy.IsExempt(m.ObjectId.Hex(), m.Label, username) {
+		return 0, false
+	}
+
+	if !policy.IsEnforced(now) {
+		return 0, false
+	}
+
+	return policy.Threshold(), true
+}
+
+// idleStopped records an event for the stopped machine and
+// calls the OnIdleStop hook, if any.
+func (q *Queue) idleStopped(stop *IdleStop) {
+	if q.EventStore != nil {
+		ev := &eventer.Event{
+			EventId:    "stop-" + stop.MachineID,
+			Message:    "Machine is stopped due to inactivity",
+			Status:     machinestate.Stopped,
+			Percentage: 100,
+			TimeStamp:  time.Now().UTC(),
+		}
+
+		if err := q.EventStore.Append(ev); err != nil {
+			q.Log.Warning("[%s] failed to record idle stop event: %s", stop.MachineID, err)
+		}
+	}
+
+	if q.OnIdleStop != nil {
+		q.OnIdleStop(stop)
+	}
+}
@@ -9,9 +9,13 @@ import (
 	"koding/db/mongodb"
 	"koding/db/mongodb/modelhelper"
 	"koding/kites/kloud/contexthelper/request"
+	"koding/kites/kloud/contexthelper/session"
+	"koding/kites/kloud/eventer"
 	"koding/kites/kloud/klient"
 	"koding/kites/kloud/machinestate"
+	"koding/kites/kloud/stack"
 	"koding/kites/kloud/stack/provider"
+	"koding/kites/kloud/stackstate"
 	"koding/kites/kloud/utils/object"
 
 	"github.com/koding/kite"
@@ -22,8 +26,9 @@ import (
 )
 
 var (
-	defaultInterval = 15 * time.Second
-	planTimeout     = 50 * time.Minute
+	defaultInterval      = 15 * time.Second
+	defaultDriftInterval = 6 * time.Hour
+	planTimeout          = 50 * time.Minute
 )
 
 type Queue struct {
@@ -32,6 +37,21 @@ type Queue struct {
 	MongoDB  *mongodb.MongoDB
 	Kite     *kite.Kite
 
+	// DriftInterval tells how often each stack is checked for drift.
+	DriftInterval time.Duration
+
+	// Session is used by drift checks to connect to terraformer.
+	// If nil, stacks are not checked for drift.
+	Session *session.Session
+
+	// EventStore, if non-nil, is used to record a "stop-<machineId>"
+	// event for each machine stopped due to inactivity.
+	EventStore eventer.Store
+
+	// OnIdleStop, if non-nil, is called after a machine was
+	// stopped due to inactivity.
+	OnIdleStop func(*IdleStop)
+
 	stackers map[string]*provider.Stacker
 }
 
@@ -44,14 +64,36 @@ func (q *Queue) Run() {
 	defer t.Stop()
 
 	for range t.C {
+		var policies idlePolicies
+
+		if len(q.stackers) != 0 {
+			policies = q.idlePolicies()
+		}
+
 		for _, s := range q.stackers {
 
 			go func(s *provider.Stacker) {
-				if err := q.Check(s); err != nil {
+				if err := q.check(s, policies); err != nil {
 					q.Log.Debug("failed to check %q provider: %s", s.Provider.Name, err)
 				}
 			}(s)
 		}
+
+		if len(q.stackers) != 0 {
+			go func() {
+				if err := q.CheckSchedule(); err != nil {
+					q.Log.Debug("failed to check machine schedule: %s", err)
+				}
+			}()
+		}
+
+		if q.Session != nil {
+			go func() {
+				if err := q.CheckDrift(); err != nil {
+					q.Log.Debug("failed to check stack drift: %s", err)
+				}
+			}()
+		}
 	}
 }
 
@@ -95,6 +137,48 @@ func (q *Queue) FetchProvider(provider string, machine interface{}) error {
 	return q.MongoDB.Run("jMachines", query)
 }
 
+// FetchStack fetches a single stack, which is due for a drift check.
+func (q *Queue) FetchStack(stack *models.ComputeStack) error {
+	query := func(c *mgo.Collection) error {
+		now := time.Now().UTC()
+
+		// check only stacks that:
+		// 1. were successfully applied
+		// 2. were not checked in the last drift interval, or never
+		// 3. are not being checked by others, a claim older than
+		//    plan timeout is considered abandoned
+		egligibleStacks := bson.M{
+			"status.state": stackstate.Initialized.String(),
+			"$and": []bson.M{{
+				"$or": []bson.M{
+					{"drift.checkedAt": bson.M{"$exists": false}},
+					{"drift.checkedAt": bson.M{"$lt": now.Add(-q.driftInterval())}},
+				},
+			}, {
+				"$or": []bson.M{
+					{"drift.claimedAt": bson.M{"$exists": false}},
+					{"drift.claimedAt": bson.M{"$lt": now.Add(-planTimeout)}},
+				},
+			}},
+		}
+
+		// update so others don't pick up the same stack, the claim
+		// is removed once the result of the check is recorded
+		update := mgo.Change{
+			Update: bson.M{
+				"$set": bson.M{
+					"drift.claimedAt": now,
+				},
+			},
+		}
+
+		_, err := c.Find(egligibleStacks).Sort("drift.checkedAt").Limit(1).Apply(update, stack)
+		return err
+	}
+
+	return q.MongoDB.Run(modelhelper.ComputeStackColl, query)
+}
+
 func (q *Queue) Register(s *provider.Stacker) {
 	if q.stackers == nil {
 		q.stackers = make(map[string]*provider.Stacker)
@@ -115,7 +199,21 @@ func (q *Queue) interval() time.Duration {
 	return defaultInterval
 }
 
+func (q *Queue) driftInterval() time.Duration {
+	if q.DriftInterval != 0 {
+		return q.DriftInterval
+	}
+
+	return defaultDriftInterval
+}
+
+// Check fetches a single machine of the given provider and stops
+// it if it idles for longer than its team policy allows.
 func (q *Queue) Check(s *provider.Stacker) error {
+	return q.check(s, q.idlePolicies())
+}
+
+func (q *Queue) check(s *provider.Stacker, policies idlePolicies) error {
 	var m models.Machine
 
 	err := q.FetchProvider(s.Provider.Name, &m)
@@ -131,6 +229,13 @@ func (q *Queue) Check(s *provider.Stacker) error {
 		return fmt.Errorf("check %q provider error: %s", s.Provider.Name, err)
 	}
 
+	// The machine is not stopped due to inactivity while it is
+	// scheduled to be running, the schedule takes precedence.
+	if sched, err := modelhelper.GetMachineSchedule(&m); err == nil && sched != nil && sched.On(time.Now()) {
+		q.Log.Debug("[%s] skipping idle check of a machine scheduled to be running", m.ObjectId.Hex())
+		return nil
+	}
+
 	req := &kite.Request{
 		Method: "internal",
 	}
@@ -151,7 +256,7 @@ func (q *Queue) Check(s *provider.Stacker) error {
 		return err
 	}
 
-	switch err := q.CheckUsage(s.Provider.Name, machine, bm, ctx); err {
+	switch err := q.checkUsage(s.Provider.Name, machine, bm, ctx, policies); err {
 	case nil:
 		return nil
 	case kite.ErrNoKitesAvailable, kontrol.ErrQueryFieldsEmpty, klient.ErrDialingFailed:
@@ -162,8 +267,25 @@ func (q *Queue) Check(s *provider.Stacker) error {
 }
 
 func (q *Queue) CheckUsage(providerName string, m provider.Machine, bm *provider.BaseMachine, ctx context.Context) error {
+	return q.checkUsage(providerName, m, bm, ctx, q.idlePolicies())
+}
+
+func (q *Queue) checkUsage(providerName string, m provider.Machine, bm *provider.BaseMachine, ctx context.Context, policies idlePolicies) error {
 	q.Log.Debug("Checking %q machine\n%+v\n", providerName, bm.Machine)
 
+	var owner string
+	if u := bm.Machine.Owner(); u != nil {
+		owner = u.Username
+	}
+
+	group, policy := policies.lookup(bm.Machine)
+
+	threshold, ok := idleThreshold(policy, bm.Machine, owner, time.Now())
+	if !ok {
+		q.Log.Debug("[%s] machine is not subject to %q team idle policy", bm.ObjectId.Hex(), group)
+		return nil
+	}
+
 	c, err := klient.Connect(q.Kite, bm.QueryString)
 	if err != nil {
 		q.Log.Debug("Error connecting to klient, stopping if needed. Error: %s", err)
@@ -184,15 +306,15 @@ func (q *Queue) CheckUsage(providerName string, m provider.Machine, bm *provider
 		return fmt.Errorf("failure getting %q klient usage: %s", bm.QueryString, err)
 	}
 
-	q.Log.Debug("machine [%s] (aws) is inactive for %s (plan limit: %s)",
-		bm.IpAddress, usg.InactiveDuration, planTimeout)
+	q.Log.Debug("machine [%s] (%s) is inactive for %s (idle limit: %s)",
+		bm.IpAddress, providerName, usg.InactiveDuration, threshold)
 
 	// It still have plenty of time to work, do not stop it
-	if usg.InactiveDuration <= planTimeout {
+	if usg.InactiveDuration <= threshold {
 		return nil
 	}
 
-	q.Log.Info("machine [%s] has reached current plan limit of %s. Shutting down...",
+	q.Log.Info("machine [%s] has reached idle limit of %s. Shutting down...",
 		bm.IpAddress, usg.InactiveDuration)
 
 	// Hasta la vista, baby!
@@ -214,5 +336,82 @@ func (q *Queue) CheckUsage(providerName string, m provider.Machine, bm *provider
 	obj["status.state"] = machinestate.Stopped.String()
 	obj["status.reason"] = "Machine is stopped due to inactivity"
 
-	return modelhelper.UpdateMachine(bm.ObjectId, bson.M{"$set": obj})
+	if err := modelhelper.UpdateMachine(bm.ObjectId, bson.M{"$set": obj}); err != nil {
+		return err
+	}
+
+	q.idleStopped(&IdleStop{
+		MachineID: bm.ObjectId.Hex(),
+		Label:     bm.Label,
+		Username:  owner,
+		Group:     group,
+		Inactive:  usg.InactiveDuration,
+		Threshold: threshold,
+		Policy:    policy,
+	})
+
+	return nil
+}
+
+// CheckDrift fetches a single stack and checks whether its resources
+// differ from the Terraform state recorded on the last apply.
+func (q *Queue) CheckDrift() error {
+	var cs models.ComputeStack
+
+	if err := q.FetchStack(&cs); err != nil {
+		// no stacks to check
+		if err == mgo.ErrNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("fetch stack error: %s", err)
+	}
+
+	providerName, err := stack.ReadStackProvider(&cs, func(provider string) bool {
+		_, ok := q.stackers[provider]
+		return ok
+	})
+	if err != nil {
+		return fmt.Errorf("[%s] %s", cs.Id.Hex(), err)
+	}
+
+	s := q.stackers[providerName]
+
+	req := &kite.Request{
+		Method: "internal",
+	}
+
+	if account, err := modelhelper.GetAccountById(cs.OriginId.Hex()); err == nil {
+		req.Username = account.Profile.Nickname
+	}
+
+	teamReq := &stack.TeamRequest{
+		Provider:  s.Provider.Name,
+		StackID:   cs.Id.Hex(),
+		GroupName: cs.Group,
+	}
+
+	ctx := request.NewContext(context.Background(), req)
+	ctx = context.WithValue(ctx, stack.TeamRequestKey, teamReq)
+
+	bs, err := s.BaseStack(session.NewContext(ctx, q.Session))
+	if err != nil {
+		return err
+	}
+
+	drift, err := bs.Drift(teamReq.StackID, teamReq.GroupName)
+	if err == provider.ErrStackBusy {
+		q.Log.Debug("[%s] skipping drift check of a busy stack", teamReq.StackID)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("[%s] drift check error: %s", teamReq.StackID, err)
+	}
+
+	if drift.Drifted() {
+		q.Log.Info("[%s] stack has %d drifted resources", teamReq.StackID, len(drift.Resources))
+	}
+
+	return nil
 }
@@ -0,0 +1,173 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+	"koding/kites/kloud/contexthelper/request"
+	"koding/kites/kloud/eventer"
+	"koding/kites/kloud/machinestate"
+
+	"github.com/koding/kite"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// scheduleInterval tells how often a schedule of
+// a single machine is checked.
+var scheduleInterval = time.Minute
+
+// FetchScheduled fetches a single machine, which has a schedule
+// that was not checked in the last schedule interval.
+func (q *Queue) FetchScheduled(machine *models.Machine) error {
+	query := func(c *mgo.Collection) error {
+		// check only machines that:
+		// 1. have a schedule
+		// 2. are either running or stopped
+		// 3. are not assigned to anyone yet (unlocked)
+		// 4. were not checked in the last schedule interval
+		egligibleMachines := bson.M{
+			"meta.schedule": bson.M{"$exists": true},
+			"status.state": bson.M{"$in": []string{
+				machinestate.Running.String(),
+				machinestate.Stopped.String(),
+			}},
+			"assignee.inProgress":     bson.M{"$ne": true},
+			"meta.schedule.checkedAt": bson.M{"$lt": time.Now().UTC().Add(-scheduleInterval)},
+		}
+
+		// Apply returns the document before the update, so the previous
+		// value of checkedAt is available to the caller.
+		update := mgo.Change{
+			Update: bson.M{
+				"$set": bson.M{
+					"meta.schedule.checkedAt": time.Now().UTC(),
+				},
+			},
+		}
+
+		_, err := c.Find(egligibleMachines).Sort("meta.schedule.checkedAt").Limit(1).Apply(update, machine)
+		return err
+	}
+
+	return q.MongoDB.Run(modelhelper.MachinesColl, query)
+}
+
+// CheckSchedule fetches all scheduled machines, which are due for
+// a check, and starts or stops each of them, if the action was
+// scheduled since its schedule was last checked.
+func (q *Queue) CheckSchedule() error {
+	for {
+		m := &models.Machine{}
+
+		if err := q.FetchScheduled(m); err != nil {
+			// no more machines to check
+			if err == mgo.ErrNotFound {
+				return nil
+			}
+
+			return fmt.Errorf("fetch scheduled machine error: %s", err)
+		}
+
+		go func() {
+			if err := q.checkSchedule(m); err != nil {
+				q.Log.Warning("%s", err)
+			}
+		}()
+	}
+}
+
+func (q *Queue) checkSchedule(m *models.Machine) error {
+	sched, err := modelhelper.GetMachineSchedule(m)
+	if err != nil || sched == nil {
+		return fmt.Errorf("[%s] unable to read machine schedule: %v", m.ObjectId.Hex(), err)
+	}
+
+	action := sched.Due(sched.CheckedAt, time.Now())
+
+	switch {
+	case action == models.ScheduleStart && m.State() == machinestate.Stopped:
+	case action == models.ScheduleStop && m.State() == machinestate.Running:
+	default:
+		return nil
+	}
+
+	s, ok := q.stackers[m.Provider]
+	if !ok {
+		return fmt.Errorf("[%s] unknown %q provider", m.ObjectId.Hex(), m.Provider)
+	}
+
+	// Lock the machine the same way kloud's start and stop methods do,
+	// so the scheduled action does not race with a user request.
+	if err := s.Lock(m.ObjectId.Hex()); err != nil {
+		q.Log.Debug("[%s] machine is busy, postponing scheduled %s: %s", m.ObjectId.Hex(), action, err)
+
+		return q.resetScheduleCheck(m, sched.CheckedAt)
+	}
+	defer s.Unlock(m.ObjectId.Hex())
+
+	req := &kite.Request{
+		Method: "internal",
+	}
+
+	if u := m.Owner(); u != nil {
+		req.Username = u.Username
+	}
+
+	ctx := request.NewContext(context.Background(), req)
+
+	bm, err := s.BuildBaseMachine(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.BuildMachine(ctx, bm); err != nil {
+		return err
+	}
+
+	eventID := action + "-" + m.ObjectId.Hex()
+
+	if q.EventStore != nil {
+		bm.Eventer = eventer.NewStored(eventID, q.EventStore, func(err error) {
+			q.Log.Warning("[%s] failed to store event: %s", eventID, err)
+		})
+	}
+
+	q.Log.Info("[%s] ======> %s started (scheduled)<======", m.ObjectId.Hex(), action)
+
+	finalState, msg := machinestate.Running, "Machine is started due to schedule"
+
+	if action == models.ScheduleStart {
+		err = bm.HandleStart(ctx)
+	} else {
+		err = bm.HandleStop(ctx)
+		finalState, msg = machinestate.Stopped, "Machine is stopped due to schedule"
+	}
+
+	if err != nil {
+		q.Log.Info("[%s] ======> %s aborted (scheduled: %s)<======", m.ObjectId.Hex(), action, err)
+
+		bm.PushError(err, m.State())
+
+		return fmt.Errorf("[%s] scheduled %s error: %s", m.ObjectId.Hex(), action, err)
+	}
+
+	q.Log.Info("[%s] ======> %s finished (scheduled)<======", m.ObjectId.Hex(), action)
+
+	bm.PushEvent(msg, 100, finalState)
+
+	return nil
+}
+
+// resetScheduleCheck restores the previous check time of the machine
+// schedule, so the due action is retried during next check.
+func (q *Queue) resetScheduleCheck(m *models.Machine, checkedAt time.Time) error {
+	query := func(c *mgo.Collection) error {
+		return c.UpdateId(m.ObjectId, bson.M{"$set": bson.M{"meta.schedule.checkedAt": checkedAt}})
+	}
+
+	return q.MongoDB.Run(modelhelper.MachinesColl, query)
+}
@@ -39,6 +39,9 @@ type ControlResult struct {
 type machineFunc func(context.Context, Machiner) error
 
 // statePair defines a methods start and final states
+//
+// If final state is machinestate.Unknown, the machine is
+// expected to end up in the state it was before.
 type statePair struct {
 	start machinestate.State
 	final machinestate.State
@@ -54,6 +57,10 @@ var states = map[string]*statePair{
 	"resize":         {start: machinestate.Pending, final: machinestate.Running},
 	"createSnapshot": {start: machinestate.Snapshotting, final: machinestate.Running},
 	"deleteSnapshot": {start: machinestate.Snapshotting, final: machinestate.Running},
+
+	"machine.snapshot.create":  {start: machinestate.Snapshotting},
+	"machine.snapshot.delete":  {start: machinestate.Snapshotting},
+	"machine.snapshot.restore": {start: machinestate.Snapshotting},
 }
 
 // coreMethods is running and returning the response for the given machineFunc.
@@ -194,6 +201,10 @@ func (k *Kloud) coreMethods(r *kite.Request, fn machineFunc) (result interface{}
 			Percentage: 100,
 		}
 
+		if finalEvent.Status == machinestate.Unknown {
+			finalEvent.Status = m.State()
+		}
+
 		k.Log.Info("[%s] ======> %s started (requester: %s, provider: %s)<======",
 			args.MachineId, strings.ToUpper(r.Method), r.Username, args.Provider)
 		start := time.Now()
@@ -73,6 +73,7 @@ const (
 	ErrBadRequest             = 415
 	ErrNotAuthorized          = 416
 	ErrInternalServer         = 417
+	ErrSnapshotNotImplemented = 418
 
 	ErrTeamSubIsNotActive = 420
 
@@ -117,6 +118,7 @@ var kloudErrors = map[int]string{
 	ErrBadRequest:             "Bad request",
 	ErrNotAuthorized:          "Not Authorized",
 	ErrInternalServer:         "Internal server error",
+	ErrSnapshotNotImplemented: "Provider doesn't implement the snapshotter interface",
 
 	// Team errors
 	ErrTeamSubIsNotActive: "Team subscription is no longer active",
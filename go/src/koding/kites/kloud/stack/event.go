@@ -1,9 +1,18 @@
 package stack
 
 import (
+	"errors"
+	"strings"
+	"sync"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
 	"koding/kites/kloud/eventer"
 
 	"github.com/koding/kite"
+	"github.com/koding/kite/dnode"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 )
 
 type EventArg struct {
@@ -68,13 +77,20 @@ func (k *Kloud) NewEventer(id string) eventer.Eventer {
 
 	ev, ok := k.Eventers[id]
 	if ok {
-		// for now we delete old events, but in the future we might store them
-		// in the db for history/logging.
+		// Old events are deleted from the memory, if event store is
+		// configured they're still available via "event.history".
 		k.Log.Debug("[event] cleaning up previous events of id: %s", id)
 		delete(k.Eventers, id)
 	}
 
-	ev = eventer.New(id)
+	if k.EventStore != nil {
+		ev = eventer.NewStored(id, k.EventStore, func(err error) {
+			k.Log.Warning("[event] failed to store event for id %s: %s", id, err)
+		})
+	} else {
+		ev = eventer.New(id)
+	}
+
 	k.Eventers[id] = ev
 	return ev
 }
@@ -92,10 +108,359 @@ func (k *Kloud) GetEvent(eventId string) (*eventer.Event, error) {
 	k.mu.RLock()
 	ev, ok := k.Eventers[eventId]
 	k.mu.RUnlock()
+	if ok {
+		return ev.Show(), nil
+	}
+
+	// The eventer may be gone due to kloud restart, try to look
+	// up the last recorded event instead.
+	if k.EventStore != nil {
+		e, err := k.EventStore.Latest(eventId)
+		if err == nil {
+			return e, nil
+		}
+
+		if err != eventer.ErrNoEvents {
+			k.Log.Warning("[event] failed to read event for id %s: %s", eventId, err)
+		}
+	}
+
+	k.Log.Debug("[event] couldn't find eventer for id: %s", eventId)
+	return nil, NewError(ErrEventNotFound)
+}
+
+// EventHistoryRequest represents a request value for "event.history"
+// kite method.
+type EventHistoryRequest struct {
+	Type    string `json:"type"`
+	EventId string `json:"eventId"`
+
+	// Skip and Limit are used for paging the results,
+	// the most recent events are returned first.
+	Skip  int `json:"skip,omitempty"`
+	Limit int `json:"limit,omitempty"`
+}
+
+// Valid implements the Validator interface.
+func (req *EventHistoryRequest) Valid() error {
+	if req.EventId == "" {
+		return NewError(ErrEventIdMissing)
+	}
+
+	if req.Type == "" {
+		return NewError(ErrEventTypeMissing)
+	}
+
+	if req.Skip < 0 || req.Limit < 0 {
+		return errors.New("skip and limit values must not be negative")
+	}
+
+	return nil
+}
+
+// EventHistoryResponse represents a response value for "event.history"
+// kite method.
+type EventHistoryResponse struct {
+	EventId string           `json:"eventId"`
+	Events  []*eventer.Event `json:"events"`
+	Total   int              `json:"total"`
+}
+
+// EventHistory is a kite.Handler for "event.history" kite method.
+//
+// It pages through the events recorded for the given event type
+// and id, e.g. type="apply" and eventId="<stackId>".
+func (k *Kloud) EventHistory(r *kite.Request) (interface{}, error) {
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	var req EventHistoryRequest
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	if k.EventStore == nil {
+		return nil, errors.New("event history is not available")
+	}
+
+	if err := k.authorizeEvents(r, req.EventId); err != nil {
+		return nil, err
+	}
+
+	f := &eventer.HistoryFilter{
+		EventId: req.Type + "-" + req.EventId,
+		Skip:    req.Skip,
+		Limit:   req.Limit,
+	}
+
+	events, total, err := k.EventStore.History(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventHistoryResponse{
+		EventId: req.EventId,
+		Events:  events,
+		Total:   total,
+	}, nil
+}
+
+// EventSubscribeRequest represents a request value for "event.subscribe"
+// kite method.
+type EventSubscribeRequest struct {
+	Type    string `json:"type"`
+	EventId string `json:"eventId"`
+
+	// OnEvent is called with each *eventer.Event pushed
+	// by the eventer.
+	OnEvent dnode.Function `json:"onEvent"`
+}
+
+// Valid implements the Validator interface.
+func (req *EventSubscribeRequest) Valid() error {
+	if req.EventId == "" {
+		return NewError(ErrEventIdMissing)
+	}
+
+	if req.Type == "" {
+		return NewError(ErrEventTypeMissing)
+	}
+
+	if !req.OnEvent.IsValid() {
+		return errors.New("onEvent callback is not valid")
+	}
+
+	return nil
+}
+
+// EventSubscribeResponse represents a response value for "event.subscribe"
+// kite method.
+type EventSubscribeResponse struct {
+	EventId string `json:"eventId"`
+}
+
+// EventSubscribe is a kite.Handler for "event.subscribe" kite method.
+//
+// It calls the OnEvent callback with the current event first
+// and then with every event as it is produced. The subscription
+// ends after the final event was sent or when the caller
+// disconnects.
+func (k *Kloud) EventSubscribe(r *kite.Request) (interface{}, error) {
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	var req EventSubscribeRequest
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := k.authorizeEvents(r, req.EventId); err != nil {
+		return nil, err
+	}
+
+	id := req.Type + "-" + req.EventId
+
+	k.mu.RLock()
+	ev, ok := k.Eventers[id]
+	k.mu.RUnlock()
+
 	if !ok {
-		k.Log.Debug("[event] couldn't find eventer for id: %s", eventId)
 		return nil, NewError(ErrEventNotFound)
 	}
 
-	return ev.Show(), nil
+	sub, ok := ev.(eventer.Subscriber)
+	if !ok {
+		return nil, errors.New("eventer does not support subscriptions")
+	}
+
+	var once sync.Once
+	stop := make(chan struct{})
+	cancel := func() { once.Do(func() { close(stop) }) }
+	s := &subscription{cancel: cancel}
+
+	send := func(e *eventer.Event) {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if err := req.OnEvent.Call(e); err != nil {
+			k.Log.Debug("[event] failed to send event %s to %s: %s", id, r.Username, err)
+			cancel()
+		}
+
+		if e.Final() {
+			cancel()
+		}
+	}
+
+	// The subscription sends the current event first, followed
+	// by the pushed ones, each in a separate goroutine, so a slow
+	// caller does not block the process that produces events.
+	unsubscribe := sub.Subscribe(send)
+
+	k.addSubscription(r.Client, s)
+
+	go func() {
+		<-stop
+		unsubscribe()
+		k.delSubscription(r.Client, s)
+
+		k.Log.Debug("[event] %s unsubscribed from %s", r.Username, id)
+	}()
+
+	return &EventSubscribeResponse{
+		EventId: req.EventId,
+	}, nil
+}
+
+// subscription represents a single event.subscribe call.
+type subscription struct {
+	cancel func()
+}
+
+// addSubscription tracks the subscription of the client, so it is
+// cancelled when the client disconnects.
+//
+// Kite clients do not allow for removing disconnect handlers, thus
+// only one handler is registered for each client and it cancels all
+// of the client's subscriptions.
+func (k *Kloud) addSubscription(c *kite.Client, s *subscription) {
+	k.subsMu.Lock()
+	defer k.subsMu.Unlock()
+
+	if k.subs == nil {
+		k.subs = make(map[*kite.Client]map[*subscription]struct{})
+	}
+
+	subs, ok := k.subs[c]
+	if !ok {
+		subs = make(map[*subscription]struct{})
+		k.subs[c] = subs
+
+		c.OnDisconnect(func() {
+			k.cancelSubscriptions(c)
+		})
+	}
+
+	subs[s] = struct{}{}
+}
+
+// delSubscription stops tracking the finished subscription.
+func (k *Kloud) delSubscription(c *kite.Client, s *subscription) {
+	k.subsMu.Lock()
+	delete(k.subs[c], s)
+	k.subsMu.Unlock()
+}
+
+// cancelSubscriptions cancels all subscriptions of the disconnected
+// client.
+func (k *Kloud) cancelSubscriptions(c *kite.Client) {
+	k.subsMu.Lock()
+	subs := k.subs[c]
+	delete(k.subs, c)
+	k.subsMu.Unlock()
+
+	for s := range subs {
+		s.cancel()
+	}
+}
+
+// authorizeEvents verifies whether the requester is allowed to read
+// events with the given id.
+//
+// The id is either a stack id, a machine id or a "<group>-<identifier>"
+// pair. Stack events are readable by the owner of the stack and by
+// the users permitted to its machines, machine events by the users
+// permitted to the machine and the rest by the members of the group.
+func (k *Kloud) authorizeEvents(r *kite.Request, eventID string) error {
+	if IsKloudSecretAuth(r, k.SecretKey) {
+		return nil
+	}
+
+	if !bson.IsObjectIdHex(eventID) {
+		return authorizeGroupEvents(r.Username, eventID)
+	}
+
+	computeStack, err := modelhelper.GetComputeStack(eventID)
+	if err == mgo.ErrNotFound {
+		m, err := modelhelper.GetMachine(eventID)
+		if err != nil {
+			return models.ResError(err, "jMachine")
+		}
+
+		if !isPermitted(m, r.Username) {
+			return NewError(ErrNotAuthorized)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return models.ResError(err, "jComputeStack")
+	}
+
+	account, err := modelhelper.GetAccount(r.Username)
+	if err != nil {
+		return models.ResError(err, "jAccount")
+	}
+
+	if computeStack.OriginId == account.Id {
+		return nil
+	}
+
+	for _, id := range computeStack.Machines {
+		m, err := modelhelper.GetMachine(id.Hex())
+		if err == mgo.ErrNotFound {
+			continue
+		}
+
+		if err != nil {
+			return models.ResError(err, "jMachine")
+		}
+
+		if isPermitted(m, r.Username) {
+			return nil
+		}
+	}
+
+	return NewError(ErrNotAuthorized)
+}
+
+// authorizeGroupEvents verifies whether the user is a member of the
+// group the "<group>-<identifier>" event id was created for.
+func authorizeGroupEvents(username, eventID string) error {
+	// Group slugs may contain dashes, so every prefix is tried.
+	for i := strings.IndexByte(eventID, '-'); i > 0; {
+		ok, err := modelhelper.IsParticipant(username, eventID[:i])
+		if err != nil && err != mgo.ErrNotFound {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		j := strings.IndexByte(eventID[i+1:], '-')
+		if j == -1 {
+			break
+		}
+
+		i += j + 1
+	}
+
+	return NewError(ErrNotAuthorized)
 }
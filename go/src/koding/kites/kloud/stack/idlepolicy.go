@@ -0,0 +1,130 @@
+package stack
+
+import (
+	"errors"
+	"time"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+
+	"github.com/koding/kite"
+	"gopkg.in/mgo.v2"
+)
+
+// IdlePolicyRequest represents a request for "team.idlePolicy"
+// kloud's kite method.
+type IdlePolicyRequest struct {
+	GroupName string `json:"groupName"`
+}
+
+// Valid implements the Validator interface.
+func (req *IdlePolicyRequest) Valid() error {
+	if req.GroupName == "" {
+		return errors.New("groupName is empty")
+	}
+	return nil
+}
+
+// IdlePolicyResponse represents a response for "team.idlePolicy"
+// and "team.setIdlePolicy" kloud's kite methods.
+type IdlePolicyResponse struct {
+	// Policy is nil when the team has no idle policy configured,
+	// in which case machines are stopped after the default threshold.
+	Policy *models.IdlePolicy `json:"policy,omitempty"`
+}
+
+// SetIdlePolicyRequest represents a request for "team.setIdlePolicy"
+// kloud's kite method.
+type SetIdlePolicyRequest struct {
+	GroupName string             `json:"groupName"`
+	Policy    *models.IdlePolicy `json:"policy"`
+}
+
+// Valid implements the Validator interface.
+func (req *SetIdlePolicyRequest) Valid() error {
+	if req.GroupName == "" {
+		return errors.New("groupName is empty")
+	}
+
+	if req.Policy == nil {
+		return errors.New("policy is empty")
+	}
+
+	req.Policy.Group = req.GroupName
+
+	return req.Policy.Valid()
+}
+
+// TeamIdlePolicy is a kite.Handler for "team.idlePolicy" kite method.
+//
+// It gives an idle policy of the given team, which is
+// readable by all the team members.
+func (k *Kloud) TeamIdlePolicy(r *kite.Request) (interface{}, error) {
+	var req IdlePolicyRequest
+
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	if ok, err := modelhelper.IsParticipant(r.Username, req.GroupName); err != nil || !ok {
+		return nil, NewError(ErrNotAuthorized)
+	}
+
+	policy, err := modelhelper.GetIdlePolicy(req.GroupName)
+	if err == mgo.ErrNotFound {
+		return &IdlePolicyResponse{}, nil
+	}
+
+	if err != nil {
+		return nil, models.ResError(err, modelhelper.IdlePolicyColl)
+	}
+
+	return &IdlePolicyResponse{Policy: policy}, nil
+}
+
+// TeamSetIdlePolicy is a kite.Handler for "team.setIdlePolicy" kite method.
+//
+// It creates or replaces an idle policy of the given team. Only
+// team admins are allowed to change the policy.
+func (k *Kloud) TeamSetIdlePolicy(r *kite.Request) (interface{}, error) {
+	var req SetIdlePolicyRequest
+
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	if ok, err := modelhelper.IsAdmin(r.Username, req.GroupName); err != nil || !ok {
+		return nil, NewError(ErrNotAuthorized)
+	}
+
+	group, err := modelhelper.GetGroup(req.GroupName)
+	if err != nil {
+		return nil, models.ResError(err, "jGroup")
+	}
+
+	req.Policy.GroupID = group.Id
+	req.Policy.UpdatedAt = time.Now().UTC()
+	req.Policy.UpdatedBy = r.Username
+
+	if err := modelhelper.SetIdlePolicy(req.Policy); err != nil {
+		return nil, err
+	}
+
+	return &IdlePolicyResponse{Policy: req.Policy}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"koding/api"
+	"koding/kites/kloud/pricing"
 	"koding/remoteapi"
 	"koding/remoteapi/client"
 	stacktemplate "koding/remoteapi/client/j_stack_template"
@@ -26,6 +27,24 @@ type ImportRequest struct {
 	Provider    string              `json:"provider"`
 	Team        string              `json:"team"`
 	Title       string              `json:"title,omitempty"`
+
+	// TemplateID, when non-empty, makes import build the stack from
+	// an existing stack template instead of creating a new one.
+	// The Template field is ignored then.
+	TemplateID string `json:"templateId,omitempty"`
+
+	// DryRun makes import stop right after the stack template
+	// is planned. The response contains the planned machines
+	// along with their estimated cost, no stack is created.
+	//
+	// A stack template created for the plan is removed
+	// afterwards, unless KeepTemplate is true.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// KeepTemplate makes a dry run keep the stack template it
+	// created, so the stack can be built later by passing
+	// its ID as TemplateID.
+	KeepTemplate bool `json:"keepTemplate,omitempty"`
 }
 
 // Valid implements the Validator interface.
@@ -34,14 +53,18 @@ func (r *ImportRequest) Valid() error {
 		return errors.New("empty credentials")
 	}
 
-	if len(r.Template) == 0 {
-		return errors.New("empty template")
-	}
-
 	if r.Team == "" {
 		return errors.New("empty team")
 	}
 
+	if r.TemplateID != "" {
+		return nil
+	}
+
+	if len(r.Template) == 0 {
+		return errors.New("empty template")
+	}
+
 	var raw json.RawMessage
 
 	if err := json.Unmarshal(r.Template, &raw); err != nil {
@@ -58,6 +81,9 @@ type ImportResponse struct {
 	StackID    string `json:"stackId"`
 	Title      string `json:"title"`
 	EventID    string `json:"eventId"`
+
+	Machines []*Machine        `json:"machines,omitempty"`
+	Cost     *pricing.Estimate `json:"cost,omitempty"`
 }
 
 var requiredData = map[string]interface{}{
@@ -74,6 +100,14 @@ var requiredData = map[string]interface{}{
 //
 // The method expects the received credentials to be already verified
 // and bootstrapped.
+//
+// When TemplateID is set, the existing JStackTemplate is used instead
+// of creating a new one.
+//
+// When DryRun is true, the method stops after the plan and responds
+// with the planned machines and their estimated cost. The template
+// created for the plan is deleted, so nothing is persisted, unless
+// KeepTemplate is true.
 func (k *Kloud) Import(r *kite.Request) (interface{}, error) {
 	var req ImportRequest
 
@@ -85,6 +119,23 @@ func (k *Kloud) Import(r *kite.Request) (interface{}, error) {
 		return nil, err
 	}
 
+	sb := &stackBuilder{
+		req:  &req,
+		resp: &ImportResponse{},
+		api: k.RemoteClient.New(&api.User{
+			Username: r.Username,
+			Team:     req.Team,
+		}),
+		timeout: k.RemoteClient.Timeout(),
+		log:     k.Log.New("stackBuilder"),
+	}
+
+	if req.TemplateID != "" {
+		if err := sb.fetchTemplate(); err != nil {
+			return nil, errors.New("failure reading template: " + err.Error())
+		}
+	}
+
 	// TODO(rjeczalik): Refactor stack/provider/apply to make it possible to build
 	// multiple stacks at once.
 	if req.Provider == "" {
@@ -116,21 +167,18 @@ func (k *Kloud) Import(r *kite.Request) (interface{}, error) {
 		Identifier: req.Credentials[req.Provider][0],
 	}
 
-	sb := &stackBuilder{
-		req: &req,
-		resp: &ImportResponse{
-			Title: req.Title,
-		},
-		api: k.RemoteClient.New(&api.User{
-			Username: r.Username,
-			Team:     req.Team,
-		}),
-		timeout: k.RemoteClient.Timeout(),
-		log:     k.Log.New("stackBuilder"),
-	}
+	sb.resp.Title = req.Title
 
-	if err := sb.buildTemplate(); err != nil {
-		return nil, errors.New("failure creating template: " + err.Error())
+	if req.TemplateID != "" {
+		sb.resp.TemplateID = req.TemplateID
+	} else {
+		if err := sb.buildTemplate(); err != nil {
+			return nil, errors.New("failure creating template: " + err.Error())
+		}
+
+		if req.DryRun && !req.KeepTemplate {
+			defer sb.deleteTemplate()
+		}
 	}
 
 	planReq := &PlanRequest{
@@ -139,11 +187,20 @@ func (k *Kloud) Import(r *kite.Request) (interface{}, error) {
 		GroupName:       req.Team,
 	}
 
-	machines, err := k.doPlan(r, p, teamReq, planReq)
+	plan, err := k.doPlan(r, p, teamReq, planReq)
 	if err != nil {
 		return nil, errors.New("failure creating plan: " + err.Error())
 	}
 
+	machines, _ := plan.Machines.([]*Machine)
+
+	sb.resp.Machines = machines
+	sb.resp.Cost = plan.Cost
+
+	if req.DryRun {
+		return sb.resp, nil
+	}
+
 	if err := sb.setVerified(machines); err != nil {
 		return nil, errors.New("failure updating template: " + err.Error())
 	}
@@ -168,7 +225,7 @@ func (k *Kloud) Import(r *kite.Request) (interface{}, error) {
 	return sb.resp, nil
 }
 
-func (k *Kloud) doPlan(r *kite.Request, p Provider, teamReq *TeamRequest, req *PlanRequest) ([]*Machine, error) {
+func (k *Kloud) doPlan(r *kite.Request, p Provider, teamReq *TeamRequest, req *PlanRequest) (*PlanResponse, error) {
 	kiteReq := &kite.Request{
 		Method:   "plan",
 		Username: r.Username,
@@ -188,15 +245,11 @@ func (k *Kloud) doPlan(r *kite.Request, p Provider, teamReq *TeamRequest, req *P
 
 	k.Log.Debug("plan received: %# v", v)
 
-	var machines []*Machine
-
 	if v, ok := v.(*PlanResponse); ok {
-		if m, ok := v.Machines.([]*Machine); ok {
-			machines = m
-		}
+		return v, nil
 	}
 
-	return machines, nil
+	return &PlanResponse{}, nil
 }
 
 func (k *Kloud) doApply(r *kite.Request, p Provider, teamReq *TeamRequest, req *ApplyRequest) (eventID string, err error) {
@@ -267,6 +320,66 @@ func (sb *stackBuilder) buildTemplate() error {
 	return nil
 }
 
+func (sb *stackBuilder) fetchTemplate() error {
+	params := &stacktemplate.PostRemoteAPIJStackTemplateSomeParams{
+		Body: map[string]interface{}{
+			"_id": sb.req.TemplateID,
+		},
+	}
+
+	params.SetTimeout(sb.timeout)
+
+	resp, err := sb.api.JStackTemplate.PostRemoteAPIJStackTemplateSome(params)
+	if err != nil {
+		return err
+	}
+
+	// TODO(rjeczalik): generated model does not have an ID field.
+	var tmpls []struct {
+		ID       string `json:"_id"`
+		Title    string `json:"title"`
+		Template struct {
+			Content string `json:"content"`
+		} `json:"template"`
+	}
+
+	if err := remoteapi.Unmarshal(resp.Payload, &tmpls); err != nil {
+		return err
+	}
+
+	if len(tmpls) == 0 || tmpls[0].ID != sb.req.TemplateID {
+		return fmt.Errorf("template %q not found", sb.req.TemplateID)
+	}
+
+	sb.req.Template = []byte(tmpls[0].Template.Content)
+
+	if sb.req.Title == "" {
+		sb.req.Title = tmpls[0].Title
+	}
+
+	return nil
+}
+
+func (sb *stackBuilder) deleteTemplate() {
+	params := &stacktemplate.PostRemoteAPIJStackTemplateDeleteIDParams{
+		ID: sb.resp.TemplateID,
+	}
+
+	params.SetTimeout(sb.timeout)
+
+	resp, err := sb.api.JStackTemplate.PostRemoteAPIJStackTemplateDeleteID(params)
+	if err == nil {
+		err = remoteapi.Unmarshal(&resp.Payload.DefaultResponse, nil)
+	}
+
+	if err != nil {
+		sb.log.Warning("unable to delete dry-run template %q: %s", sb.resp.TemplateID, err)
+		return
+	}
+
+	sb.resp.TemplateID = ""
+}
+
 func (sb *stackBuilder) setVerified(machines []*Machine) error {
 	body := map[string]interface{}{
 		"config.verified": true,
@@ -1,6 +1,7 @@
 package stack_test
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -50,7 +51,99 @@ func TestImport(t *testing.T) {
 		EventID:    "mocked-event-id",
 	}
 
-	if *got != *want {
+	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("got %#v, want %#v", got, want)
 	}
 }
+
+func TestImportDryRun(t *testing.T) {
+	remoteapi := &apitest.StubHandler{
+		map[string]*models.JStackTemplate{"data": {ID: "mocked-template-id"}},
+		nil, // JStackTemplate.delete
+	}
+
+	s := apitest.Serve(remoteapi)
+	defer s.Close()
+
+	fk := stacktest.NewFakeKloud(s.URL)
+
+	req := &stack.ImportRequest{
+		Credentials: map[string][]string{"test": {"mocked-identifier"}},
+		Template:    []byte(`{"provider":{"test":{}}}`),
+		Team:        "test-team",
+		Title:       "test",
+		DryRun:      true,
+	}
+
+	resp, err := fk.Import(stacktest.NewRequest("import", "test-user", req))
+	if err != nil {
+		t.Fatalf("Import()=%s", err)
+	}
+
+	want := &stack.ImportResponse{
+		Title: "test",
+	}
+
+	if !reflect.DeepEqual(resp, want) {
+		t.Fatalf("got %#v, want %#v", resp, want)
+	}
+
+	if len(fk.Stacker.Plan) != 1 {
+		t.Fatalf("got %d plan requests, want 1", len(fk.Stacker.Plan))
+	}
+
+	if len(fk.Stacker.Apply) != 0 {
+		t.Fatalf("got %d apply requests, want 0", len(fk.Stacker.Apply))
+	}
+
+	if len(*remoteapi) != 0 {
+		t.Fatalf("want dry-run template to be deleted, %d stubs left", len(*remoteapi))
+	}
+}
+
+func TestImportTemplateID(t *testing.T) {
+	remoteapi := &apitest.StubHandler{
+		map[string]interface{}{"data": []interface{}{
+			map[string]interface{}{
+				"_id":   "existing-template-id",
+				"title": "existing",
+				"template": map[string]interface{}{
+					"content": `{"provider":{"test":{}}}`,
+				},
+			},
+		}},
+		nil,
+		map[string]map[string]*models.JComputeStack{"data": {"stack": {ID: "mocked-stack-id"}}},
+	}
+
+	s := apitest.Serve(remoteapi)
+	defer s.Close()
+
+	fk := stacktest.NewFakeKloud(s.URL)
+
+	req := &stack.ImportRequest{
+		Credentials: map[string][]string{"test": {"mocked-identifier"}},
+		TemplateID:  "existing-template-id",
+		Team:        "test-team",
+	}
+
+	resp, err := fk.Import(stacktest.NewRequest("import", "test-user", req))
+	if err != nil {
+		t.Fatalf("Import()=%s", err)
+	}
+
+	want := &stack.ImportResponse{
+		TemplateID: "existing-template-id",
+		StackID:    "mocked-stack-id",
+		Title:      "existing",
+		EventID:    "mocked-event-id",
+	}
+
+	if !reflect.DeepEqual(resp, want) {
+		t.Fatalf("got %#v, want %#v", resp, want)
+	}
+
+	if len(fk.Stacker.Apply) != 1 {
+		t.Fatalf("got %d apply requests, want 1", len(fk.Stacker.Apply))
+	}
+}
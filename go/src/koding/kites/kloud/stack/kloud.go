@@ -61,6 +61,15 @@ type Kloud struct {
 	// mu protects Eventers
 	mu sync.RWMutex
 
+	// subs keeps event subscriptions of each connected client,
+	// a single disconnect handler is registered per client.
+	subs   map[*kite.Client]map[*subscription]struct{}
+	subsMu sync.Mutex
+
+	// EventStore, if non-nil, is used to persist events of each
+	// eventer, so they can be queried after the eventer is gone.
+	EventStore eventer.Store
+
 	// idlock provides multiple locks per id
 	idlock *idlock.IdLock
 
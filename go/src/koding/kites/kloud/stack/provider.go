@@ -1,6 +1,7 @@
 package stack
 
 import (
+	"koding/db/models"
 	"koding/kites/kloud/machinestate"
 
 	"golang.org/x/net/context"
@@ -49,6 +50,8 @@ type Stacker interface {
 	HandleApply(context.Context) (interface{}, error)
 	HandleAuthenticate(context.Context) (interface{}, error)
 	HandleBootstrap(context.Context) (interface{}, error)
+	HandleDestroy(context.Context) (interface{}, error)
+	HandleDrift(context.Context) (interface{}, error)
 	HandlePlan(context.Context) (interface{}, error)
 }
 
@@ -68,3 +71,53 @@ type Machiner interface {
 	HandleStop(context.Context) error
 	HandleInfo(context.Context) (*InfoResponse, error)
 }
+
+// Snapshotter is an optional interface implemented by user machines
+// of providers, which support snapshots.
+type Snapshotter interface {
+	// CreateSnapshot creates a snapshot of the root disk of the machine.
+	// It returns after the snapshot is ready to be restored.
+	//
+	// The returned snapshot is required to have the ID set.
+	CreateSnapshot(ctx context.Context, label string) (*Snapshot, error)
+
+	// DeleteSnapshot deletes the snapshot with the given provider-specific id.
+	DeleteSnapshot(ctx context.Context, snapshotID string) error
+
+	// RestoreSnapshot restores the root disk of the machine from
+	// the snapshot. The machine is stopped when the method is called.
+	//
+	// Providers, which can't restore the disk in place without
+	// making the machine diverge from its Terraform state, prepare
+	// the snapshot to be used by the machine resource instead and
+	// return attributes that replace the ones from the stack template.
+	// The machine is recreated from the snapshot when the stack is
+	// applied next time.
+	//
+	// If the disk was restored in place, nil override is returned.
+	RestoreSnapshot(ctx context.Context, snapshotID string) (*ResourceOverride, error)
+}
+
+// ResourceOverride describes attributes of a single resource of
+// a stack template, which are replaced when the stack is applied.
+type ResourceOverride struct {
+	Type       string                 // resource type, e.g. "aws_instance"
+	Attributes map[string]interface{} // attributes to replace
+}
+
+// SnapshotMachiner is implemented by machines of providers,
+// which implement the Snapshotter interface.
+type SnapshotMachiner interface {
+	Machiner
+	Snapshotter
+
+	// Implemented by *provider.BaseMachine.
+	HandleCreateSnapshot(ctx context.Context, label string) (*Snapshot, error)
+	HandleDeleteSnapshot(ctx context.Context, snapshotID string) error
+
+	// HandleRestoreSnapshot restores the machine from the snapshot.
+	//
+	// If the machine is going to be recreated from the snapshot
+	// on next apply, the stack of the machine is returned.
+	HandleRestoreSnapshot(ctx context.Context, snapshotID string) (*models.ComputeStack, error)
+}
@@ -61,6 +61,12 @@ func (bs *BaseStack) HandleApply(ctx context.Context) (interface{}, error) {
 		return nil, fmt.Errorf("State is currently %s. Please try again later", state)
 	}
 
+	if !arg.Destroy {
+		if err := bs.buildRevision(arg); err != nil {
+			return nil, err
+		}
+	}
+
 	if rt, ok := stack.RequestTraceFromContext(ctx); ok {
 		rt.Hijack()
 	}
@@ -275,6 +281,14 @@ func (bs *BaseStack) applyAsync(ctx context.Context, req *stack.ApplyRequest) er
 		}
 	}
 
+	if overrides, ok := bs.Builder.Stack.Stack.Config["overrides"]; ok {
+		m, _ := toMap(overrides)
+
+		if err := bs.Builder.Template.OverrideResources(m); err != nil {
+			return err
+		}
+	}
+
 	bs.Log.Debug("Stack template before injecting Koding data: %s", bs.Builder.Template)
 
 	t, err := bs.stack.ApplyTemplate(cred)
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+
+	"koding/kites/kloud/pricing"
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/utils/object"
+)
+
+// EstimateCost annotates each of the given machines with an estimated
+// cost and gives the total cost of all the machines.
+//
+// If the provider has no price table, nil is returned. If some of the
+// machines have no price, the total is marked as partial.
+func (bs *BaseStack) EstimateCost(machines stack.Machines, cred *stack.Credential) *pricing.Estimate {
+	if len(machines) == 0 {
+		return nil
+	}
+
+	table, err := pricing.TableFor(bs.Provider.Name)
+	if err != nil {
+		bs.Log.Debug("no price table for %q provider: %s", bs.Provider.Name, err)
+		return nil
+	}
+
+	var credRegion string
+
+	if cred != nil && cred.Credential != nil {
+		fields := object.HCLBuilder.Build(cred.Credential)
+
+		if v, ok := fields[table.RegionCredentialField()]; ok && v != nil {
+			credRegion = fmt.Sprintf("%v", v)
+		}
+	}
+
+	total := &pricing.Estimate{
+		Currency: table.Currency,
+	}
+
+	for label, m := range machines {
+		region := m.Attributes[table.RegionAttribute]
+		if region == "" || IsVariable(region) {
+			region = credRegion
+		}
+
+		estimate, err := table.Lookup(region, m.Attributes[table.TypeAttribute])
+		if err != nil {
+			bs.Log.Debug("no price for %q machine (region=%q): %s", label, region, err)
+
+			total.Partial = true
+			continue
+		}
+
+		m.Cost = estimate
+
+		total.Add(estimate)
+	}
+
+	return total
+}
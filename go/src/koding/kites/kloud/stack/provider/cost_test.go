@@ -0,0 +1,75 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"koding/kites/kloud/pricing"
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/stack/provider"
+
+	"github.com/koding/logging"
+)
+
+type testCred struct {
+	Region string `hcl:"region"`
+}
+
+func TestEstimateCost(t *testing.T) {
+	bs := &provider.BaseStack{
+		Log:      logging.NewCustom("test", false),
+		Provider: &provider.Provider{Name: "aws"},
+	}
+
+	machines := stack.Machines{
+		"web": {
+			Label:      "web",
+			Attributes: map[string]string{"instance_type": "t2.micro"},
+		},
+		"db": {
+			Label:      "db",
+			Attributes: map[string]string{"instance_type": "m4.large"},
+		},
+		"gpu": {
+			Label:      "gpu",
+			Attributes: map[string]string{"instance_type": "p2.xlarge"},
+		},
+	}
+
+	cred := &stack.Credential{
+		Credential: &testCred{Region: "us-east-1"},
+	}
+
+	total := bs.EstimateCost(machines, cred)
+
+	want := pricing.NewEstimate(0.012, "USD")
+	want.Add(pricing.NewEstimate(0.108, "USD"))
+	want.Partial = true
+
+	if !reflect.DeepEqual(total, want) {
+		t.Fatalf("got %+v, want %+v", total, want)
+	}
+
+	if machines["web"].Cost == nil || machines["db"].Cost == nil {
+		t.Fatalf("missing machine cost: web=%+v, db=%+v", machines["web"].Cost, machines["db"].Cost)
+	}
+
+	if machines["gpu"].Cost != nil {
+		t.Fatalf("got %+v, want nil", machines["gpu"].Cost)
+	}
+}
+
+func TestEstimateCostNoTable(t *testing.T) {
+	bs := &provider.BaseStack{
+		Log:      logging.NewCustom("test", false),
+		Provider: &provider.Provider{Name: "vagrant"},
+	}
+
+	machines := stack.Machines{
+		"default": {Label: "default"},
+	}
+
+	if total := bs.EstimateCost(machines, nil); total != nil {
+		t.Fatalf("got %+v, want nil", total)
+	}
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"koding/db/models"
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/stackstate"
+	"koding/kites/kloud/terraformer"
+	tf "koding/kites/terraformer"
+
+	"github.com/hashicorp/terraform/terraform"
+	"golang.org/x/net/context"
+)
+
+// HandleDestroy destroys the stack for the given ID.
+//
+// When dryRun=false, the request is handled like an apply
+// request with destroy=true.
+//
+// When dryRun=true, it asks terraformer for a destroy plan and
+// returns resources that are going to be removed, without
+// touching any of them.
+func (bs *BaseStack) HandleDestroy(ctx context.Context) (interface{}, error) {
+	arg, ok := ctx.Value(stack.DestroyRequestKey).(*stack.DestroyRequest)
+	if !ok {
+		arg = &stack.DestroyRequest{}
+
+		if err := bs.Req.Args.One().Unmarshal(arg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := arg.Valid(); err != nil {
+		return nil, err
+	}
+
+	if arg.DryRun {
+		bs.Arg = arg
+
+		return bs.destroyPlan(ctx, arg)
+	}
+
+	applyReq := &stack.ApplyRequest{
+		Provider:  arg.Provider,
+		StackID:   arg.StackID,
+		GroupName: arg.GroupName,
+		Destroy:   true,
+	}
+
+	return bs.HandleApply(context.WithValue(ctx, stack.ApplyRequestKey, applyReq))
+}
+
+func (bs *BaseStack) destroyPlan(ctx context.Context, req *stack.DestroyRequest) (*stack.DestroyPlanResponse, error) {
+	err := bs.Builder.BuildStack(req.StackID, nil)
+
+	if err != nil && !models.IsNotFound(err, "jStackTemplate") {
+		return nil, err
+	}
+
+	if state := bs.Builder.Stack.Stack.State(); state.InProgress() {
+		return nil, fmt.Errorf("State is currently %s. Please try again later", state)
+	}
+
+	// Ensure the requester is allowed to destroy the stack.
+	if err := bs.Builder.BuildMachines(ctx); err != nil {
+		return nil, err
+	}
+
+	resp := &stack.DestroyPlanResponse{
+		StackID:   req.StackID,
+		Machines:  make([]*stack.DestroyPlanMachine, 0),
+		Resources: make([]string, 0),
+	}
+
+	// Stack was never applied, thus there is no Terraform
+	// state - only the machine documents are going to be removed.
+	if bs.Builder.Stack.Stack.State() == stackstate.NotInitialized {
+		for label, m := range bs.Builder.Machines {
+			resp.Machines = append(resp.Machines, &stack.DestroyPlanMachine{
+				ID:        m.ObjectId.Hex(),
+				Label:     label,
+				Provider:  m.Provider,
+				Resources: []string{},
+			})
+		}
+
+		sortDestroyPlan(resp)
+
+		return resp, nil
+	}
+
+	tfKite, err := terraformer.Connect(bs.Session.Terraformer)
+	if err != nil {
+		return nil, err
+	}
+	defer tfKite.Close()
+
+	tfReq := &tf.TerraformRequest{
+		ContentID: req.GroupName + "-" + req.StackID,
+		TraceID:   bs.TraceID,
+		Destroy:   true,
+	}
+
+	bs.Log.Debug("Calling terraform.plan method for destroy with context: %+v", tfReq)
+
+	plan, err := tfKite.Plan(tfReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bs.buildDestroyPlan(plan, resp); err != nil {
+		return nil, err
+	}
+
+	sortDestroyPlan(resp)
+
+	return resp, nil
+}
+
+func (bs *BaseStack) buildDestroyPlan(plan *terraform.Plan, resp *stack.DestroyPlanResponse) error {
+	if plan.Diff == nil {
+		return errors.New("plan diff is empty")
+	}
+
+	machines := make(map[string]*stack.DestroyPlanMachine)
+
+	for _, d := range plan.Diff.Modules {
+		for providerResource, r := range d.Resources {
+			if r == nil || !(r.Destroy || d.Destroy) {
+				continue
+			}
+
+			resp.Resources = append(resp.Resources, providerResource)
+
+			provider, resourceType, label, err := parseResource(providerResource)
+			if err != nil {
+				return err
+			}
+
+			if resourceType != bs.Planner.ResourceType || provider != bs.Planner.Provider {
+				continue
+			}
+
+			m, ok := machines[label]
+			if !ok {
+				m = &stack.DestroyPlanMachine{
+					Label:    label,
+					Provider: provider,
+				}
+
+				if jm, ok := bs.Builder.Machines[label]; ok {
+					m.ID = jm.ObjectId.Hex()
+				}
+
+				machines[label] = m
+				resp.Machines = append(resp.Machines, m)
+			}
+
+			m.Resources = append(m.Resources, providerResource)
+		}
+	}
+
+	return nil
+}
+
+func sortDestroyPlan(resp *stack.DestroyPlanResponse) {
+	sort.Strings(resp.Resources)
+
+	sort.Sort(machinesByLabel(resp.Machines))
+
+	for _, m := range resp.Machines {
+		sort.Strings(m.Resources)
+	}
+}
+
+type machinesByLabel []*stack.DestroyPlanMachine
+
+func (m machinesByLabel) Len() int           { return len(m) }
+func (m machinesByLabel) Less(i, j int) bool { return m[i].Label < m[j].Label }
+func (m machinesByLabel) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+	"koding/kites/kloud/stack"
+	"koding/kites/kloud/stackstate"
+	"koding/kites/kloud/terraformer"
+	tf "koding/kites/terraformer"
+
+	"github.com/hashicorp/terraform/terraform"
+	"golang.org/x/net/context"
+)
+
+// HandleDrift checks whether resources of the given stack differ
+// from the Terraform state recorded on the last apply.
+func (bs *BaseStack) HandleDrift(ctx context.Context) (interface{}, error) {
+	arg, ok := ctx.Value(stack.DriftRequestKey).(*stack.DriftRequest)
+	if !ok {
+		arg = &stack.DriftRequest{}
+
+		if err := bs.Req.Args.One().Unmarshal(arg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := arg.Valid(); err != nil {
+		return nil, err
+	}
+
+	bs.Arg = arg
+
+	err := bs.Builder.BuildStack(arg.StackID, nil)
+
+	if err != nil && !models.IsNotFound(err, "jStackTemplate") {
+		return nil, err
+	}
+
+	switch state := bs.Builder.Stack.Stack.State(); {
+	case state == stackstate.NotInitialized:
+		return nil, errors.New("stack is not initialized, there is nothing to check")
+	case state.InProgress():
+		return nil, fmt.Errorf("State is currently %s. Please try again later", state)
+	}
+
+	// Ensure the requester is allowed to read the stack.
+	if err := bs.Builder.BuildMachines(ctx); err != nil {
+		return nil, err
+	}
+
+	drift, err := bs.Drift(arg.StackID, arg.GroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stack.DriftResponse{
+		StackID: arg.StackID,
+		Drift:   drift,
+	}, nil
+}
+
+// Drift runs a plan against the stack content and state stored
+// by the last apply and records any differences as the stack drift.
+//
+// Terraform refreshes the state before planning, thus any
+// non-empty diff means the resources were changed out of band.
+//
+// If the plan fails, the error is recorded as well, so periodic
+// checks do not retry the same stack immediately. Stacks which have
+// an apply or destroy in flight are skipped with ErrStackBusy.
+func (bs *BaseStack) Drift(stackID, groupName string) (*models.StackDrift, error) {
+	cs, err := idleStack(stackID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	drift, err := bs.drift(stackID, groupName)
+
+	// An apply or destroy started while the check was running, the result
+	// is stale - the stack is going to be checked again later.
+	if _, e := idleStack(stackID, cs); e != nil {
+		return nil, e
+	}
+
+	if err != nil {
+		drift = &models.StackDrift{
+			Error: err.Error(),
+		}
+	}
+
+	drift.CheckedAt = time.Now().UTC()
+
+	if e := modelhelper.SetStackDrift(stackID, drift); e != nil && err == nil {
+		err = e
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return drift, nil
+}
+
+// ErrStackBusy is returned by Drift when the stack has an apply or destroy
+// in flight, in which case no drift result is recorded.
+var ErrStackBusy = errors.New("stack has an operation in progress")
+
+// idleStack returns ErrStackBusy when the stack is in progress or, if prev
+// is not nil, when the stack state was modified since prev was read.
+// The drift claim is released in that case.
+func idleStack(stackID string, prev *models.ComputeStack) (*models.ComputeStack, error) {
+	cs, err := modelhelper.GetComputeStack(stackID)
+	if err != nil {
+		return nil, err
+	}
+
+	busy := cs.State().InProgress()
+
+	if prev != nil && (cs.Status.State != prev.Status.State || !cs.Status.ModifiedAt.Equal(prev.Status.ModifiedAt)) {
+		busy = true
+	}
+
+	if busy {
+		if err := modelhelper.ReleaseStackDrift(stackID); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrStackBusy
+	}
+
+	return cs, nil
+}
+
+func (bs *BaseStack) drift(stackID, groupName string) (*models.StackDrift, error) {
+	tfKite, err := terraformer.Connect(bs.Session.Terraformer)
+	if err != nil {
+		return nil, err
+	}
+	defer tfKite.Close()
+
+	// The check works on a copy of the stored content, so the refreshed
+	// state is not written back and a running apply is not locked out.
+	tfReq := &tf.TerraformRequest{
+		ContentID: groupName + "-" + stackID,
+		TraceID:   bs.TraceID,
+		ReadOnly:  true,
+	}
+
+	bs.Log.Debug("Calling terraform.plan method for drift with context: %+v", tfReq)
+
+	plan, err := tfKite.Plan(tfReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildDrift(plan)
+}
+
+func buildDrift(plan *terraform.Plan) (*models.StackDrift, error) {
+	if plan.Diff == nil {
+		return nil, errors.New("plan diff is empty")
+	}
+
+	// Resources that were removed out of band are dropped from the
+	// state during refresh, plan wants to create them again.
+	existing := make(map[string]struct{})
+
+	if plan.State != nil {
+		for _, m := range plan.State.Modules {
+			for name := range m.Resources {
+				existing[name] = struct{}{}
+			}
+		}
+	}
+
+	drift := &models.StackDrift{}
+
+	for _, d := range plan.Diff.Modules {
+		for name, r := range d.Resources {
+			if r.Empty() {
+				continue
+			}
+
+			res := &models.DriftedResource{
+				Name:  name,
+				State: "modified",
+			}
+
+			if _, ok := existing[name]; ok {
+				for attr := range r.Attributes {
+					res.Attributes = append(res.Attributes, attr)
+				}
+
+				sort.Strings(res.Attributes)
+			} else {
+				res.State = "deleted"
+			}
+
+			drift.Resources = append(drift.Resources, res)
+		}
+	}
+
+	sort.Sort(resourcesByName(drift.Resources))
+
+	return drift, nil
+}
+
+type resourcesByName []*models.DriftedResource
+
+func (r resourcesByName) Len() int           { return len(r) }
+func (r resourcesByName) Less(i, j int) bool { return r[i].Name < r[j].Name }
+func (r resourcesByName) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
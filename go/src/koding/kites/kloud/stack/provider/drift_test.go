@@ -0,0 +1,68 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"koding/db/models"
+	"koding/kites/kloud/stack/provider"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestBuildDrift(t *testing.T) {
+	plan := &terraform.Plan{
+		Diff: &terraform.Diff{
+			Modules: []*terraform.ModuleDiff{{
+				Path: []string{"root"},
+				Resources: map[string]*terraform.InstanceDiff{
+					"aws_instance.example": {
+						Attributes: map[string]*terraform.ResourceAttrDiff{
+							"tags.Name":     {Old: "example", New: "koding"},
+							"instance_type": {Old: "t2.micro", New: "t2.nano"},
+						},
+					},
+					"aws_eip.example": {
+						Attributes: map[string]*terraform.ResourceAttrDiff{
+							"instance": {New: "i-123"},
+						},
+					},
+					"aws_security_group.example": {},
+				},
+			}},
+		},
+		State: &terraform.State{
+			Modules: []*terraform.ModuleState{{
+				Path: []string{"root"},
+				Resources: map[string]*terraform.ResourceState{
+					"aws_instance.example":       {},
+					"aws_security_group.example": {},
+				},
+			}},
+		},
+	}
+
+	want := &models.StackDrift{
+		Resources: []*models.DriftedResource{{
+			Name:  "aws_eip.example",
+			State: "deleted",
+		}, {
+			Name:       "aws_instance.example",
+			State:      "modified",
+			Attributes: []string{"instance_type", "tags.Name"},
+		}},
+	}
+
+	got, err := provider.BuildDrift(plan)
+	if err != nil {
+		t.Fatalf("BuildDrift()=%s", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if !got.Drifted() {
+		t.Fatal("want drift to be detected")
+	}
+}
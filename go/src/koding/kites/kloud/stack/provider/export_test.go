@@ -4,3 +4,6 @@ package provider
 func Title(s string) string {
 	return title(s)
 }
+
+// BuildDrift exports buildDrift func for test purpose.
+var BuildDrift = buildDrift
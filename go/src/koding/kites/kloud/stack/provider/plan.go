@@ -48,6 +48,10 @@ func (bs *BaseStack) HandlePlan(ctx context.Context) (interface{}, error) {
 		return nil, err
 	}
 
+	if _, err := addRevision(bs.Builder.StackTemplate); err != nil {
+		bs.Log.Warning("unable to record revision of %q stack template: %s", arg.StackTemplateID, err)
+	}
+
 	bs.Log.Debug("Fetched terraform data: koding=%+v, template=%+v", bs.Builder.Koding, bs.Builder.Template)
 
 	contentID := bs.Req.Username + "-" + arg.StackTemplateID
@@ -94,6 +98,7 @@ func (bs *BaseStack) HandlePlan(ctx context.Context) (interface{}, error) {
 
 	return &stack.PlanResponse{
 		Machines: machines.Slice(),
+		Cost:     bs.EstimateCost(machines, cred),
 	}, nil
 }
 
@@ -123,6 +123,12 @@ type Provider struct {
 	//
 	// If nil, DefaultSchema will be used instead.
 	Schema *Schema
+
+	// Config holds provider-specific configuration, it is
+	// passed to machines and stacks of the provider.
+	//
+	// Optional.
+	Config interface{}
 }
 
 // DefaultSchema describes default schema used,
@@ -262,6 +268,7 @@ type BaseMachine struct {
 
 	KlientTimeout time.Duration
 	Provider      string
+	Config        interface{}
 	TraceID       string
 	Debug         bool
 	User          *models.User
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+	"koding/kites/kloud/stack"
+)
+
+// buildRevision records the current content of the stack template
+// as a new revision, or - if the request pins the apply to an older
+// revision - replaces the stack template with its content.
+func (bs *BaseStack) buildRevision(req *stack.ApplyRequest) error {
+	templateID := bs.Builder.Stack.Stack.BaseStackId.Hex()
+
+	if req.TemplateRevision != 0 {
+		rev, err := modelhelper.GetStackTemplateRevision(templateID, req.TemplateRevision)
+		if err != nil {
+			return models.ResError(err, "jStackTemplateRevision")
+		}
+
+		bs.Log.Debug("Using revision %d of %q stack template", rev.Revision, templateID)
+
+		bs.Builder.Stack.Template = rev.Content
+
+		return nil
+	}
+
+	tmpl, err := modelhelper.GetStackTemplate(templateID)
+	if err != nil {
+		return models.ResError(err, "jStackTemplate")
+	}
+
+	// Failing to record a revision is not fatal, the stack
+	// can still be built.
+	if _, err := addRevision(tmpl); err != nil {
+		bs.Log.Warning("unable to record revision of %q stack template: %s", templateID, err)
+	}
+
+	return nil
+}
+
+// addRevision records the content of the given stack template as a new
+// revision, unless it has not changed since the latest one.
+func addRevision(tmpl *models.StackTemplate) (*models.StackTemplateRevision, error) {
+	return modelhelper.AddStackTemplateRevision(tmpl, tmpl.LastUpdaterID())
+}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+	"koding/kites/kloud/machinestate"
+	"koding/kites/kloud/stack"
+
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// HandleCreateSnapshot creates a snapshot of the machine and
+// records it in jSnapshots collection.
+func (bm *BaseMachine) HandleCreateSnapshot(ctx context.Context, label string) (*stack.Snapshot, error) {
+	s, ok := bm.machine.(stack.Snapshotter)
+	if !ok {
+		return nil, stack.NewError(stack.ErrSnapshotNotImplemented)
+	}
+
+	accountID, err := modelhelper.GetAccountID(bm.Req.Username)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find account for %q: %s", bm.Req.Username, err)
+	}
+
+	if label == "" {
+		label = bm.Label + "-" + time.Now().UTC().Format("20060102150405")
+	}
+
+	defer bm.snapshotting("Creating snapshot")()
+
+	snapshot, err := s.CreateSnapshot(ctx, label)
+	if err != nil {
+		return nil, stack.NewEventerError(err)
+	}
+
+	snapshot.MachineID = bm.ObjectId.Hex()
+	snapshot.Label = label
+	snapshot.CreatedAt = time.Now().UTC()
+
+	bm.PushEvent("Saving snapshot", 90, machinestate.Snapshotting)
+
+	doc := &models.Snapshot{
+		Id:          bson.NewObjectId(),
+		OriginId:    accountID,
+		MachineId:   bm.ObjectId,
+		SnapshotId:  snapshot.ID,
+		StorageSize: strconv.Itoa(snapshot.StorageSize),
+		Region:      snapshot.Region,
+		Label:       snapshot.Label,
+		CreatedAt:   snapshot.CreatedAt,
+	}
+
+	if err := modelhelper.CreateSnapshot(doc); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot %q: %s", snapshot.ID, err)
+	}
+
+	return snapshot, nil
+}
+
+// HandleDeleteSnapshot deletes the given snapshot of the machine.
+func (bm *BaseMachine) HandleDeleteSnapshot(ctx context.Context, snapshotID string) error {
+	s, ok := bm.machine.(stack.Snapshotter)
+	if !ok {
+		return stack.NewError(stack.ErrSnapshotNotImplemented)
+	}
+
+	if err := bm.checkSnapshot(snapshotID); err != nil {
+		return err
+	}
+
+	defer bm.snapshotting("Deleting snapshot")()
+
+	if err := s.DeleteSnapshot(ctx, snapshotID); err != nil {
+		return stack.NewEventerError(err)
+	}
+
+	return modelhelper.DeleteSnapshot(snapshotID)
+}
+
+// HandleRestoreSnapshot replaces root disk of the machine with
+// the given snapshot. If the machine is running, it is stopped
+// before the restore.
+//
+// If the provider restored the disk in place, a machine that was
+// running is started again afterwards. Otherwise the resource
+// override given by the provider is recorded in the stack of
+// the machine, which is returned to be applied by the caller.
+func (bm *BaseMachine) HandleRestoreSnapshot(ctx context.Context, snapshotID string) (*models.ComputeStack, error) {
+	s, ok := bm.machine.(stack.Snapshotter)
+	if !ok {
+		return nil, stack.NewError(stack.ErrSnapshotNotImplemented)
+	}
+
+	if err := bm.checkSnapshot(snapshotID); err != nil {
+		return nil, err
+	}
+
+	// Machines created with count share a single resource,
+	// overriding it would restore all of them.
+	if strings.Contains(bm.Label, ".") {
+		return nil, fmt.Errorf("restoring machine %q created with count is not supported", bm.Label)
+	}
+
+	cs, err := modelhelper.GetComputeStackByMachine(bm.ObjectId)
+	if err != nil {
+		return nil, models.ResError(err, "jComputeStack")
+	}
+
+	wasRunning := bm.State() == machinestate.Running
+
+	if wasRunning {
+		if err := bm.HandleStop(ctx); err != nil {
+			return nil, err
+		}
+
+		bm.Status.State = machinestate.Stopped.String()
+	}
+
+	override, err := func() (*stack.ResourceOverride, error) {
+		defer bm.snapshotting("Restoring snapshot")()
+
+		return s.RestoreSnapshot(ctx, snapshotID)
+	}()
+
+	if err != nil {
+		return nil, stack.NewEventerError(err)
+	}
+
+	if override == nil {
+		if wasRunning {
+			return nil, bm.HandleStart(ctx)
+		}
+
+		return nil, nil
+	}
+
+	bm.PushEvent("Updating stack of the machine", 90, machinestate.Snapshotting)
+
+	err = modelhelper.SetStackResourceOverride(cs.Id, override.Type, bm.Label, override.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update stack %q: %s", cs.Id.Hex(), err)
+	}
+
+	return cs, nil
+}
+
+// snapshotting marks the machine as snapshotting. The returned
+// func restores the original state of the machine.
+func (bm *BaseMachine) snapshotting(msg string) func() {
+	origState := bm.State()
+
+	bm.PushEvent(msg, 25, machinestate.Snapshotting)
+
+	err := modelhelper.ChangeMachineState(bm.ObjectId, msg, machinestate.Snapshotting)
+	if err != nil {
+		bm.Log.Warning("failed to update machine state: %s", err)
+	}
+
+	return func() {
+		err := modelhelper.ChangeMachineState(bm.ObjectId, "Machine is marked as "+origState.String(), origState)
+		if err != nil {
+			bm.Log.Warning("failed to update machine state: %s", err)
+		}
+	}
+}
+
+// checkSnapshot ensures the given snapshot belongs to the machine.
+func (bm *BaseMachine) checkSnapshot(snapshotID string) error {
+	snapshot, err := modelhelper.GetSnapshot(snapshotID)
+	if err == mgo.ErrNotFound {
+		return fmt.Errorf("snapshot %q not found", snapshotID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if snapshot.MachineId != bm.ObjectId {
+		return fmt.Errorf("snapshot %q does not belong to the machine", snapshotID)
+	}
+
+	return nil
+}
@@ -143,6 +143,7 @@ func (s *Stacker) BuildBaseMachine(ctx context.Context, m *models.Machine) (*Bas
 		Metadata:   s.Provider.newMetadata(nil),
 		Req:        req,
 		Provider:   s.Provider.Name,
+		Config:     s.Provider.Config,
 		Debug:      s.Debug,
 	}
 
@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/hil"
 	"github.com/hashicorp/terraform/config"
 	"github.com/koding/logging"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // Template represents a HCL template.
@@ -84,6 +85,58 @@ func (t *Template) hclUpdate() error {
 	return t.hclParse(out)
 }
 
+// OverrideResources replaces attributes of the template resources
+// with the given ones. The overrides are keyed by resource type
+// and then by resource name, e.g.:
+//
+//   {"aws_instance": {"example": {"ami": "ami-a1b2c3d4"}}}
+//
+// Overrides of resources not defined in the template are ignored.
+func (t *Template) OverrideResources(overrides map[string]interface{}) error {
+	for typ, v := range overrides {
+		names, ok := toMap(v)
+		if !ok {
+			continue
+		}
+
+		resources, ok := toMap(t.Resource[typ])
+		if !ok {
+			continue
+		}
+
+		for name, v := range names {
+			attrs, ok := toMap(v)
+			if !ok {
+				continue
+			}
+
+			resource, ok := toMap(resources[name])
+			if !ok {
+				continue
+			}
+
+			for k, v := range attrs {
+				t.log.Debug("Overriding %s.%s.%s attribute: %v", typ, name, k, v)
+
+				resource[k] = v
+			}
+		}
+	}
+
+	return t.hclUpdate()
+}
+
+func toMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case bson.M:
+		return m, true
+	default:
+		return nil, false
+	}
+}
+
 // DecodeProvider decodes the provider block to the given out struct
 func (t *Template) DecodeProvider(out interface{}) error {
 	return t.decode("provider", out)
@@ -210,6 +210,52 @@ func TestTerraformTemplate_DecodeProvider(t *testing.T) {
 	equals(t, "${var.aws_region}", provider.Aws.Region)
 }
 
+func TestTerraformTemplate_OverrideResources(t *testing.T) {
+	template, err := provider.ParseTemplate(testTemplate, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overrides := map[string]interface{}{
+		"aws_instance": map[string]interface{}{
+			"example": map[string]interface{}{
+				"ami": "ami-a1b2c3d4",
+			},
+			"nonexisting": map[string]interface{}{
+				"ami": "ami-a1b2c3d4",
+			},
+		},
+		"google_compute_instance": map[string]interface{}{
+			"example": map[string]interface{}{
+				"machine_type": "n1-standard-1",
+			},
+		},
+	}
+
+	if err := template.OverrideResources(overrides); err != nil {
+		t.Fatal(err)
+	}
+
+	var resource struct {
+		AwsInstance map[string]struct {
+			Ami          string `hcl:"ami"`
+			InstanceType string `hcl:"instance_type"`
+		} `hcl:"aws_instance"`
+	}
+
+	if err := template.DecodeResource(&resource); err != nil {
+		t.Fatal(err)
+	}
+
+	equals(t, 1, len(resource.AwsInstance))
+	equals(t, "ami-a1b2c3d4", resource.AwsInstance["example"].Ami)
+	equals(t, "t2.micro", resource.AwsInstance["example"].InstanceType)
+
+	if _, ok := template.Resource["google_compute_instance"]; ok {
+		t.Fatal("want override of undefined resource to be ignored")
+	}
+}
+
 func TestTerraformTemplate_ShadowVariables(t *testing.T) {
 	userTestTemplate := `{
     "provider": {
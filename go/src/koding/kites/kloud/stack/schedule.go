@@ -0,0 +1,167 @@
+package stack
+
+import (
+	"errors"
+	"time"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+
+	"github.com/koding/kite"
+)
+
+// MachineScheduleRequest represents a request for "machine.schedule",
+// "machine.setSchedule" and "machine.clearSchedule" kloud's kite methods.
+type MachineScheduleRequest struct {
+	MachineID string `json:"machineId"`
+
+	// Schedule is required by "machine.setSchedule" only.
+	Schedule *models.MachineSchedule `json:"schedule,omitempty"`
+}
+
+// Valid implements the Validator interface.
+func (req *MachineScheduleRequest) Valid() error {
+	if req.MachineID == "" {
+		return errors.New("machineId is empty")
+	}
+
+	if req.Schedule != nil {
+		return req.Schedule.Valid()
+	}
+
+	return nil
+}
+
+// MachineScheduleResponse represents a response for "machine.schedule",
+// "machine.setSchedule" and "machine.clearSchedule" kloud's kite methods.
+type MachineScheduleResponse struct {
+	MachineID string `json:"machineId"`
+
+	// Schedule is nil when the machine has no schedule.
+	Schedule *models.MachineSchedule `json:"schedule,omitempty"`
+
+	// NextStart and NextStop tell when the machine is going to
+	// be started and stopped next, if ever.
+	NextStart *time.Time `json:"nextStart,omitempty"`
+	NextStop  *time.Time `json:"nextStop,omitempty"`
+}
+
+// MachineSchedule is a kite.Handler for "machine.schedule" kite method.
+//
+// It gives a start/stop schedule of the given machine.
+func (k *Kloud) MachineSchedule(r *kite.Request) (interface{}, error) {
+	req, m, err := scheduleRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sched, err := modelhelper.GetMachineSchedule(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return newScheduleResponse(req.MachineID, sched), nil
+}
+
+// MachineSetSchedule is a kite.Handler for "machine.setSchedule" kite method.
+//
+// It sets a start/stop schedule of the given machine, replacing
+// the previous one, if any. The schedule is executed by kloud
+// queue.
+func (k *Kloud) MachineSetSchedule(r *kite.Request) (interface{}, error) {
+	req, m, err := scheduleRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Schedule == nil {
+		return nil, errors.New("schedule is empty")
+	}
+
+	// Actions scheduled before the schedule was set are not executed.
+	req.Schedule.CheckedAt = time.Now().UTC()
+
+	if err := modelhelper.SetMachineSchedule(m.ObjectId, req.Schedule); err != nil {
+		return nil, err
+	}
+
+	return newScheduleResponse(req.MachineID, req.Schedule), nil
+}
+
+// MachineClearSchedule is a kite.Handler for "machine.clearSchedule" kite method.
+//
+// It removes a start/stop schedule of the given machine.
+func (k *Kloud) MachineClearSchedule(r *kite.Request) (interface{}, error) {
+	req, m, err := scheduleRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := modelhelper.SetMachineSchedule(m.ObjectId, nil); err != nil {
+		return nil, err
+	}
+
+	return newScheduleResponse(req.MachineID, nil), nil
+}
+
+// scheduleRequest reads a schedule request and the requested machine.
+//
+// The machine schedule can be managed by its owner or team admins.
+func scheduleRequest(r *kite.Request) (*MachineScheduleRequest, *models.Machine, error) {
+	var req MachineScheduleRequest
+
+	if r.Args == nil {
+		return nil, nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, nil, err
+	}
+
+	m, err := modelhelper.GetMachine(req.MachineID)
+	if err != nil {
+		return nil, nil, models.ResError(err, "jMachine")
+	}
+
+	if u := m.Owner(); u != nil && u.Username == r.Username {
+		return &req, m, nil
+	}
+
+	for _, g := range m.Groups {
+		group, err := modelhelper.GetGroupById(g.Id.Hex())
+		if err != nil {
+			continue
+		}
+
+		if ok, err := modelhelper.IsAdmin(r.Username, group.Slug); err == nil && ok {
+			return &req, m, nil
+		}
+	}
+
+	return nil, nil, NewError(ErrNotAuthorized)
+}
+
+func newScheduleResponse(machineID string, sched *models.MachineSchedule) *MachineScheduleResponse {
+	resp := &MachineScheduleResponse{
+		MachineID: machineID,
+		Schedule:  sched,
+	}
+
+	if sched != nil {
+		start, stop := sched.Next(time.Now())
+
+		if !start.IsZero() {
+			resp.NextStart = &start
+		}
+
+		if !stop.IsZero() {
+			resp.NextStop = &stop
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,270 @@
+package stack
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+
+	"github.com/koding/kite"
+	"golang.org/x/net/context"
+)
+
+// Snapshot represents a snapshot of a root disk of a machine.
+type Snapshot struct {
+	ID          string    `json:"id"` // provider-specific id
+	MachineID   string    `json:"machineId"`
+	Label       string    `json:"label,omitempty"`
+	Region      string    `json:"region,omitempty"`
+	StorageSize int       `json:"storageSize,omitempty"` // in GB
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// NewSnapshot converts snapshot document to a Snapshot value.
+func NewSnapshot(s *models.Snapshot) *Snapshot {
+	size, _ := strconv.Atoi(s.StorageSize)
+
+	return &Snapshot{
+		ID:          s.SnapshotId,
+		MachineID:   s.MachineId.Hex(),
+		Label:       s.Label,
+		Region:      s.Region,
+		StorageSize: size,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+// SnapshotRequest represents a request for "machine.snapshot.create",
+// "machine.snapshot.delete" and "machine.snapshot.restore" kloud's
+// kite methods.
+type SnapshotRequest struct {
+	MachineId string `json:"machineId"`
+	Provider  string `json:"provider"`
+
+	// Label is an optional name of the created snapshot,
+	// used by "machine.snapshot.create" only.
+	Label string `json:"label,omitempty"`
+
+	// SnapshotID is required by "machine.snapshot.delete" and
+	// "machine.snapshot.restore" methods.
+	SnapshotID string `json:"snapshotId,omitempty"`
+}
+
+// Valid implements the Validator interface.
+func (req *SnapshotRequest) Valid() error {
+	if req.MachineId == "" {
+		return NewError(ErrMachineIdMissing)
+	}
+
+	if req.Provider == "" {
+		return NewError(ErrProviderIsMissing)
+	}
+
+	return nil
+}
+
+// SnapshotListRequest represents a request for "machine.snapshot.list"
+// kloud's kite method.
+type SnapshotListRequest struct {
+	MachineId string `json:"machineId"`
+}
+
+// Valid implements the Validator interface.
+func (req *SnapshotListRequest) Valid() error {
+	if req.MachineId == "" {
+		return NewError(ErrMachineIdMissing)
+	}
+
+	return nil
+}
+
+// SnapshotListResponse represents a response for "machine.snapshot.list"
+// kloud's kite method.
+type SnapshotListResponse struct {
+	Snapshots []*Snapshot `json:"snapshots"`
+}
+
+// SnapshotCreate is a kite.Handler for "machine.snapshot.create" kite method.
+//
+// It creates a snapshot of the root disk of the given machine. The
+// method returns immediately, the progress can be tracked with
+// the returned event id.
+func (k *Kloud) SnapshotCreate(r *kite.Request) (interface{}, error) {
+	req, err := snapshotRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	createFunc := func(ctx context.Context, machine Machiner) error {
+		sm, err := snapshotMachine(machine)
+		if err != nil {
+			return err
+		}
+
+		_, err = sm.HandleCreateSnapshot(ctx, req.Label)
+		return err
+	}
+
+	return k.coreMethods(r, createFunc)
+}
+
+// SnapshotDelete is a kite.Handler for "machine.snapshot.delete" kite method.
+func (k *Kloud) SnapshotDelete(r *kite.Request) (interface{}, error) {
+	req, err := snapshotRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SnapshotID == "" {
+		return nil, NewError(ErrSnapshotIdMissing)
+	}
+
+	deleteFunc := func(ctx context.Context, machine Machiner) error {
+		sm, err := snapshotMachine(machine)
+		if err != nil {
+			return err
+		}
+
+		return sm.HandleDeleteSnapshot(ctx, req.SnapshotID)
+	}
+
+	return k.coreMethods(r, deleteFunc)
+}
+
+// SnapshotRestore is a kite.Handler for "machine.snapshot.restore" kite method.
+//
+// It replaces the root disk of the given machine with the snapshot.
+// A running machine is stopped for the time of the restore.
+//
+// If the provider recreates the machine from the snapshot with
+// Terraform, the stack of the machine is applied afterwards,
+// which can be tracked with "apply-<stackId>" event id.
+func (k *Kloud) SnapshotRestore(r *kite.Request) (interface{}, error) {
+	req, err := snapshotRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SnapshotID == "" {
+		return nil, NewError(ErrSnapshotIdMissing)
+	}
+
+	restoreFunc := func(ctx context.Context, machine Machiner) error {
+		sm, err := snapshotMachine(machine)
+		if err != nil {
+			return err
+		}
+
+		cs, err := sm.HandleRestoreSnapshot(ctx, req.SnapshotID)
+		if err != nil || cs == nil {
+			return err
+		}
+
+		teamReq := &TeamRequest{
+			Provider:  req.Provider,
+			GroupName: cs.Group,
+			StackID:   cs.Id.Hex(),
+		}
+
+		applyReq := &ApplyRequest{
+			Provider:  req.Provider,
+			StackID:   cs.Id.Hex(),
+			GroupName: cs.Group,
+		}
+
+		eventID, err := k.doApply(r, k.providers[req.Provider], teamReq, applyReq)
+		if err != nil {
+			return errors.New("failure applying stack: " + err.Error())
+		}
+
+		k.Log.Debug("[%s] restore applies %q stack: %s", req.MachineId, cs.Id.Hex(), eventID)
+
+		return nil
+	}
+
+	return k.coreMethods(r, restoreFunc)
+}
+
+// SnapshotList is a kite.Handler for "machine.snapshot.list" kite method.
+//
+// It lists snapshots of the given machine, starting from the latest one.
+func (k *Kloud) SnapshotList(r *kite.Request) (interface{}, error) {
+	var req SnapshotListRequest
+
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	m, err := modelhelper.GetMachine(req.MachineId)
+	if err != nil {
+		return nil, models.ResError(err, "jMachine")
+	}
+
+	if !isPermitted(m, r.Username) {
+		return nil, NewError(ErrNotAuthorized)
+	}
+
+	snapshots, err := modelhelper.GetSnapshotsByMachine(m.ObjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SnapshotListResponse{
+		Snapshots: make([]*Snapshot, len(snapshots)),
+	}
+
+	for i, s := range snapshots {
+		resp.Snapshots[i] = NewSnapshot(s)
+	}
+
+	return resp, nil
+}
+
+func snapshotMachine(m Machiner) (SnapshotMachiner, error) {
+	sm, ok := m.(SnapshotMachiner)
+	if !ok {
+		return nil, NewError(ErrSnapshotNotImplemented)
+	}
+
+	return sm, nil
+}
+
+func snapshotRequest(r *kite.Request) (*SnapshotRequest, error) {
+	var req SnapshotRequest
+
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// isPermitted tells whether the user is an owner of the machine
+// or the machine is permanently shared with the user.
+func isPermitted(m *models.Machine, username string) bool {
+	for _, u := range m.Users {
+		if u.Username == username && (u.Owner || (u.Permanent && u.Approved)) {
+			return true
+		}
+	}
+
+	return false
+}
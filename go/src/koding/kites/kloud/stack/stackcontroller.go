@@ -4,6 +4,8 @@ import (
 	"errors"
 	"koding/kites/config"
 
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
 	"koding/kites/kloud/contexthelper/publickeys"
 	"koding/kites/kloud/contexthelper/request"
 	"koding/kites/kloud/eventer"
@@ -21,6 +23,10 @@ type TeamRequest struct {
 	Debug       bool   `json:"debug,omitempty"`
 	Impersonate string `json:"impersonate,omitempty"` // only for kloudctl
 	Identifier  string `json:"identifier"`
+
+	// noEventer is set for requests that do not emit any events,
+	// so they do not replace an eventer of a running process.
+	noEventer bool
 }
 
 func (req *TeamRequest) metricTags() []string {
@@ -56,6 +62,13 @@ func IsKloudSecretAuth(r *kite.Request, key string) bool {
 
 // stackMethod routes the team method call to a requested provider.
 func (k *Kloud) stackMethod(r *kite.Request, fn StackFunc) (interface{}, error) {
+	return k.stackMethodWith(r, fn, nil)
+}
+
+// stackMethodWith works like stackMethod, but calls the prepare
+// function, if non-nil, with the team request before the stack
+// handler is created.
+func (k *Kloud) stackMethodWith(r *kite.Request, fn StackFunc, prepare func(*TeamRequest)) (interface{}, error) {
 	if r.Args == nil {
 		return nil, NewError(ErrNoArguments)
 	}
@@ -67,6 +80,10 @@ func (k *Kloud) stackMethod(r *kite.Request, fn StackFunc) (interface{}, error)
 		return nil, errors.New("invalid request: " + err.Error())
 	}
 
+	if prepare != nil {
+		prepare(&args)
+	}
+
 	// TODO(rjeczalik): compatibility code, remove
 	if args.Provider == "" {
 		args.Provider = "aws"
@@ -118,6 +135,47 @@ func (k *Kloud) stackMethod(r *kite.Request, fn StackFunc) (interface{}, error)
 	return resp, err
 }
 
+// stackProvider looks up a provider of the given stack by reading
+// providers of its machines.
+//
+// If the lookup fails, it returns empty string.
+func (k *Kloud) stackProvider(stackID string) string {
+	computeStack, err := modelhelper.GetComputeStack(stackID)
+	if err != nil {
+		k.Log.Debug("unable to read provider for stack %q: %s", stackID, err)
+		return ""
+	}
+
+	provider, err := ReadStackProvider(computeStack, func(provider string) bool {
+		_, ok := k.providers[provider]
+		return ok
+	})
+	if err != nil {
+		k.Log.Debug("unable to read provider for stack %q: %s", stackID, err)
+		return ""
+	}
+
+	return provider
+}
+
+// ReadStackProvider looks up a provider of the given stack by reading
+// providers of its machines. Providers for which known returns false
+// are skipped.
+func ReadStackProvider(cs *models.ComputeStack, known func(provider string) bool) (string, error) {
+	for _, id := range cs.Machines {
+		m, err := modelhelper.GetMachine(id.Hex())
+		if err != nil {
+			continue
+		}
+
+		if known(m.Provider) {
+			return m.Provider, nil
+		}
+	}
+
+	return "", errors.New("no known provider found for stack machines")
+}
+
 func (k *Kloud) newStack(r *kite.Request, req *TeamRequest) (Stacker, context.Context, error) {
 	if k.NewStack != nil {
 		return k.NewStack(r, req)
@@ -138,7 +196,9 @@ func (k *Kloud) newStack(r *kite.Request, req *TeamRequest) (Stacker, context.Co
 		ctx = k.ContextCreator(ctx)
 	}
 
-	if req.StackID != "" {
+	if req.noEventer {
+		k.Log.Debug("Eventer not created for %q", r.Method)
+	} else if req.StackID != "" {
 		evID := r.Method + "-" + req.StackID
 		ctx = eventer.NewContext(ctx, k.NewEventer(evID))
 
@@ -17,6 +17,7 @@ import (
 	"koding/db/models"
 	"koding/db/mongodb/modelhelper"
 	"koding/kites/kloud/machinestate"
+	"koding/kites/kloud/pricing"
 
 	"github.com/koding/cache"
 	"github.com/koding/kite"
@@ -31,6 +32,8 @@ var (
 	ApplyRequestKey        = contextKey(2)
 	BootstrapRequestKey    = contextKey(3)
 	PlanRequestKey         = contextKey(4)
+	DestroyRequestKey      = contextKey(5)
+	DriftRequestKey        = contextKey(6)
 )
 
 // KiteMap maps resource names to kite IDs they own.
@@ -113,6 +116,10 @@ type Machine struct {
 	Attributes map[string]string `json:"attributes"`
 	Credential *Credential       `json:"-"`
 
+	// Cost is an estimated cost of the machine, set by kloud.plan
+	// when the provider has a price table.
+	Cost *pricing.Estimate `json:"cost,omitempty"`
+
 	// Fields set by kloud.apply:
 	QueryString string             `json:"queryString,omitempty"`
 	RegisterURL string             `json:"registerURL,omitempty"`
@@ -201,6 +208,10 @@ type ApplyRequest struct {
 	// Destroy, when true, destroys the terraform tempalte associated with the
 	// given StackId.
 	Destroy bool
+
+	// TemplateRevision, when non-zero, makes apply use the given revision
+	// of jStackTemplate content instead of the current one.
+	TemplateRevision int `json:"templateRevision,omitempty"`
 }
 
 // Valid implements the Validator interface.
@@ -219,6 +230,114 @@ func (k *Kloud) Apply(r *kite.Request) (interface{}, error) {
 	return k.stackMethod(r, Stacker.HandleApply)
 }
 
+/// DESTROY
+
+// DestroyRequest represents an argument of stack.destroy kite method.
+type DestroyRequest struct {
+	Provider  string `json:"provider"`
+	StackID   string `json:"stackId"`
+	GroupName string `json:"groupName"`
+
+	// DryRun, when true, makes destroy return the Terraform destroy
+	// plan for the stack instead of destroying it.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Valid implements the Validator interface.
+func (req *DestroyRequest) Valid() error {
+	if req.StackID == "" {
+		return errors.New("stackId is empty")
+	}
+	if req.GroupName == "" {
+		return errors.New("groupName is empty")
+	}
+	return nil
+}
+
+// DestroyPlanMachine describes a single machine, which is going to
+// be removed by destroy operation.
+type DestroyPlanMachine struct {
+	ID        string   `json:"id,omitempty"` // jMachine._id
+	Label     string   `json:"label"`
+	Provider  string   `json:"provider"`
+	Resources []string `json:"resources"`
+}
+
+// DestroyPlanResponse represents a response of stack.destroy kite method,
+// when called in dry-run mode.
+type DestroyPlanResponse struct {
+	StackID string `json:"stackId"`
+
+	// Machines lists the machines to be removed.
+	Machines []*DestroyPlanMachine `json:"machines"`
+
+	// Resources lists all Terraform resources to be removed,
+	// including those that do not belong to any machine.
+	Resources []string `json:"resources"`
+}
+
+// Destroy provides stack.destroy as a kite method.
+//
+// If request's DryRun is false, the response is *ControlResult and
+// the stack is destroyed asynchronously. Otherwise the response
+// is *DestroyPlanResponse and no resources are touched.
+//
+// When provider is not set, it is read from the machines
+// of the stack.
+func (k *Kloud) Destroy(r *kite.Request) (interface{}, error) {
+	var req DestroyRequest
+
+	if r.Args != nil {
+		if err := r.Args.One().Unmarshal(&req); err != nil {
+			return nil, errors.New("invalid request: " + err.Error())
+		}
+	}
+
+	return k.stackMethodWith(r, Stacker.HandleDestroy, func(args *TeamRequest) {
+		args.noEventer = req.DryRun
+
+		if args.Provider == "" && args.StackID != "" {
+			args.Provider = k.stackProvider(args.StackID)
+		}
+	})
+}
+
+/// DRIFT
+
+// DriftRequest represents an argument of stack.drift kite method.
+type DriftRequest struct {
+	Provider  string `json:"provider"`
+	StackID   string `json:"stackId"`
+	GroupName string `json:"groupName"`
+}
+
+// Valid implements the Validator interface.
+func (req *DriftRequest) Valid() error {
+	if req.StackID == "" {
+		return errors.New("stackId is empty")
+	}
+	if req.GroupName == "" {
+		return errors.New("groupName is empty")
+	}
+	return nil
+}
+
+// DriftResponse represents a response of stack.drift kite method.
+type DriftResponse struct {
+	StackID string             `json:"stackId"`
+	Drift   *models.StackDrift `json:"drift"`
+}
+
+// Drift provides stack.drift as a kite method.
+//
+// It compares the Terraform state recorded on the last apply
+// with the actual state of stack resources. The result is also
+// stored in the jComputeStack document, so it's available
+// via describeStack method.
+func (k *Kloud) Drift(r *kite.Request) (interface{}, error) {
+	return k.stackMethod(r, Stacker.HandleDrift)
+}
+
 /// AUTHENTICATE
 
 // AuthenticateRequest represents an argument of the authenticate kite method.
@@ -299,6 +418,9 @@ type PlanRequest struct {
 // PlanResponse represents a reponse type of the plan kite method.
 type PlanResponse struct {
 	Machines interface{} `json:"machines"`
+
+	// Cost is an estimated total cost of the planned machines.
+	Cost *pricing.Estimate `json:"cost,omitempty"`
 }
 
 // Valid implements the Validator interface.
@@ -337,6 +459,10 @@ type StatusResponse struct {
 	StackID    string    `json:"stackId"`
 	Status     string    `json:"status"`
 	ModifiedAt time.Time `json:"modifiedAt"`
+
+	// Drift is the result of the most recent drift check,
+	// nil if the stack was not checked yet.
+	Drift *models.StackDrift `json:"drift,omitempty"`
 }
 
 // Status
@@ -368,6 +494,7 @@ func (k *Kloud) Status(r *kite.Request) (interface{}, error) {
 			StackID:    arg.StackID,
 			Status:     computeStack.Status.State,
 			ModifiedAt: computeStack.Status.ModifiedAt,
+			Drift:      computeStack.Drift,
 		}
 
 		k.statusCache.Set(arg.StackID, resp)
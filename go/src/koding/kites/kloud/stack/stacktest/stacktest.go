@@ -51,6 +51,8 @@ type SpyStacker struct {
 	Apply     []*stack.ApplyRequest
 	Auth      []*stack.AuthenticateRequest
 	Bootstrap []*stack.BootstrapRequest
+	Destroy   []*stack.DestroyRequest
+	Drift     []*stack.DriftRequest
 	Plan      []*stack.PlanRequest
 }
 
@@ -98,6 +100,40 @@ func (ss *SpyStacker) HandleBootstrap(ctx context.Context) (interface{}, error)
 	return true, nil
 }
 
+// HandleDestroy implements the stack.Stacker interface.
+func (ss *SpyStacker) HandleDestroy(ctx context.Context) (interface{}, error) {
+	req, ok := ctx.Value(stack.DestroyRequestKey).(*stack.DestroyRequest)
+	if !ok {
+		return nil, nil
+	}
+
+	ss.Destroy = append(ss.Destroy, req)
+
+	if req.DryRun {
+		return &stack.DestroyPlanResponse{
+			StackID: req.StackID,
+		}, nil
+	}
+
+	return &stack.ControlResult{
+		EventId: "mocked-event-id",
+	}, nil
+}
+
+// HandleDrift implements the stack.Stacker interface.
+func (ss *SpyStacker) HandleDrift(ctx context.Context) (interface{}, error) {
+	req, ok := ctx.Value(stack.DriftRequestKey).(*stack.DriftRequest)
+	if !ok {
+		return nil, nil
+	}
+
+	ss.Drift = append(ss.Drift, req)
+
+	return &stack.DriftResponse{
+		StackID: req.StackID,
+	}, nil
+}
+
 // HandlePlan implements the stack.Stacker interface.
 func (ss *SpyStacker) HandlePlan(ctx context.Context) (interface{}, error) {
 	if req, ok := ctx.Value(stack.PlanRequestKey).(*stack.PlanRequest); ok {
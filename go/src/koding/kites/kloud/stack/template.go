@@ -0,0 +1,345 @@
+package stack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"koding/api"
+	"koding/db/models"
+	"koding/db/mongodb/modelhelper"
+	"koding/remoteapi"
+	stacktemplate "koding/remoteapi/client/j_stack_template"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/printer"
+	"github.com/koding/kite"
+	"github.com/kylelemons/godebug/diff"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// TemplateHistoryRequest represents a request for "template.history"
+// kloud's kite method.
+type TemplateHistoryRequest struct {
+	TemplateID string `json:"templateId"`
+}
+
+// Valid implements the Validator interface.
+func (req *TemplateHistoryRequest) Valid() error {
+	if req.TemplateID == "" {
+		return errors.New("templateId is empty")
+	}
+	return nil
+}
+
+// TemplateRevision describes a single revision of a stack template.
+type TemplateRevision struct {
+	Revision  int       `json:"revision"`
+	Sum       string    `json:"sum"`
+	Author    string    `json:"author,omitempty"` // username
+	CreatedAt time.Time `json:"createdAt"`
+	Current   bool      `json:"current,omitempty"`
+}
+
+// TemplateHistoryResponse represents a response for "template.history"
+// kloud's kite method.
+type TemplateHistoryResponse struct {
+	TemplateID string              `json:"templateId"`
+	Revisions  []*TemplateRevision `json:"revisions"`
+}
+
+// TemplateHistory is a kite.Handler for "template.history" kite method.
+//
+// It lists all revisions of the given stack template, starting
+// from the latest one.
+func (k *Kloud) TemplateHistory(r *kite.Request) (interface{}, error) {
+	var req TemplateHistoryRequest
+
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := templateFor(r.Username, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revisions are recorded on each write, this records the
+	// first one for templates created before they were tracked.
+	if _, err := modelhelper.AddStackTemplateRevision(tmpl, tmpl.LastUpdaterID()); err != nil {
+		return nil, err
+	}
+
+	revs, err := modelhelper.GetStackTemplateRevisions(req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TemplateHistoryResponse{
+		TemplateID: req.TemplateID,
+		Revisions:  make([]*TemplateRevision, len(revs)),
+	}
+
+	authors := make(map[bson.ObjectId]string)
+
+	for i, rev := range revs {
+		author, ok := authors[rev.AuthorID]
+		if !ok && rev.AuthorID.Valid() {
+			if account, err := modelhelper.GetAccountById(rev.AuthorID.Hex()); err == nil {
+				author = account.Profile.Nickname
+			}
+
+			authors[rev.AuthorID] = author
+		}
+
+		resp.Revisions[i] = &TemplateRevision{
+			Revision:  rev.Revision,
+			Sum:       rev.Sum,
+			Author:    author,
+			CreatedAt: rev.CreatedAt,
+			Current:   rev.Sum == tmpl.Template.Sum,
+		}
+	}
+
+	return resp, nil
+}
+
+// TemplateDiffRequest represents a request for "template.diff"
+// kloud's kite method.
+type TemplateDiffRequest struct {
+	TemplateID string `json:"templateId"`
+
+	// From is a revision to compare.
+	From int `json:"from"`
+
+	// To is a revision to compare with. If 0, the latest
+	// revision is used.
+	To int `json:"to,omitempty"`
+
+	// Format is either "json" (default) or "hcl".
+	Format string `json:"format,omitempty"`
+}
+
+// Valid implements the Validator interface.
+func (req *TemplateDiffRequest) Valid() error {
+	if req.TemplateID == "" {
+		return errors.New("templateId is empty")
+	}
+	if req.From <= 0 {
+		return errors.New("from revision is invalid")
+	}
+	if req.To < 0 {
+		return errors.New("to revision is invalid")
+	}
+	switch req.Format {
+	case "", "json", "hcl":
+	default:
+		return fmt.Errorf("unsupported format: %q", req.Format)
+	}
+	return nil
+}
+
+// TemplateDiffResponse represents a response for "template.diff"
+// kloud's kite method.
+type TemplateDiffResponse struct {
+	TemplateID string `json:"templateId"`
+	From       int    `json:"from"`
+	To         int    `json:"to"`
+
+	// Diff is a line diff of the two revisions, each line
+	// prefixed with "-", "+" or " ".
+	Diff string `json:"diff"`
+}
+
+// TemplateDiff is a kite.Handler for "template.diff" kite method.
+func (k *Kloud) TemplateDiff(r *kite.Request) (interface{}, error) {
+	var req TemplateDiffRequest
+
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	if _, err := templateFor(r.Username, req.TemplateID); err != nil {
+		return nil, err
+	}
+
+	from, err := modelhelper.GetStackTemplateRevision(req.TemplateID, req.From)
+	if err != nil {
+		return nil, models.ResError(err, "jStackTemplateRevision")
+	}
+
+	to, err := modelhelper.GetStackTemplateRevision(req.TemplateID, req.To)
+	if err != nil {
+		return nil, models.ResError(err, "jStackTemplateRevision")
+	}
+
+	fromContent, err := formatTemplate(from.Content, req.Format)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read revision %d: %s", from.Revision, err)
+	}
+
+	toContent, err := formatTemplate(to.Content, req.Format)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read revision %d: %s", to.Revision, err)
+	}
+
+	return &TemplateDiffResponse{
+		TemplateID: req.TemplateID,
+		From:       from.Revision,
+		To:         to.Revision,
+		Diff:       diff.Diff(fromContent, toContent),
+	}, nil
+}
+
+// TemplateRollbackRequest represents a request for "template.rollback"
+// kloud's kite method.
+type TemplateRollbackRequest struct {
+	TemplateID string `json:"templateId"`
+	Revision   int    `json:"revision"`
+}
+
+// Valid implements the Validator interface.
+func (req *TemplateRollbackRequest) Valid() error {
+	if req.TemplateID == "" {
+		return errors.New("templateId is empty")
+	}
+	if req.Revision <= 0 {
+		return errors.New("revision is invalid")
+	}
+	return nil
+}
+
+// TemplateRollbackResponse represents a response for "template.rollback"
+// kloud's kite method.
+type TemplateRollbackResponse struct {
+	TemplateID string `json:"templateId"`
+
+	// Revision is the new revision, which content is
+	// a copy of the requested one.
+	Revision int `json:"revision"`
+}
+
+// TemplateRollback is a kite.Handler for "template.rollback" kite method.
+//
+// It restores the content of the stack template to the given revision.
+// The content is updated with JStackTemplate.update via remote.api,
+// on behalf of the requester, so the restored content is recorded
+// as a new revision and the rollback itself can be undone.
+//
+// Only template owner or team admin is allowed to roll back the template.
+// Existing stacks are not modified, they need to be reapplied.
+func (k *Kloud) TemplateRollback(r *kite.Request) (interface{}, error) {
+	var req TemplateRollbackRequest
+
+	if r.Args == nil {
+		return nil, NewError(ErrNoArguments)
+	}
+
+	if err := r.Args.One().Unmarshal(&req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := templateFor(r.Username, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := modelhelper.GetStackTemplateRevision(req.TemplateID, req.Revision)
+	if err != nil {
+		return nil, models.ResError(err, "jStackTemplateRevision")
+	}
+
+	params := &stacktemplate.PostRemoteAPIJStackTemplateUpdateIDParams{
+		ID: req.TemplateID,
+		Body: map[string]interface{}{
+			"template":   rev.Content,
+			"rawContent": rev.RawContent,
+		},
+	}
+
+	params.SetTimeout(k.RemoteClient.Timeout())
+
+	remote := k.RemoteClient.New(&api.User{
+		Username: r.Username,
+		Team:     tmpl.Group,
+	})
+
+	resp, err := remote.JStackTemplate.PostRemoteAPIJStackTemplateUpdateID(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := remoteapi.Unmarshal(&resp.Payload.DefaultResponse, nil); err != nil {
+		return nil, err
+	}
+
+	latest, err := modelhelper.GetStackTemplateRevision(req.TemplateID, 0)
+	if err != nil {
+		return nil, models.ResError(err, "jStackTemplateRevision")
+	}
+
+	return &TemplateRollbackResponse{
+		TemplateID: req.TemplateID,
+		Revision:   latest.Revision,
+	}, nil
+}
+
+func templateFor(username, templateID string) (*models.StackTemplate, error) {
+	tmpl, err := modelhelper.GetStackTemplate(templateID)
+	if err != nil {
+		return nil, models.ResError(err, "jStackTemplate")
+	}
+
+	if err := modelhelper.HasTemplateAccess(tmpl, username); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+func formatTemplate(content, format string) (string, error) {
+	if format == "hcl" {
+		tree, err := hcl.Parse(content)
+		if err != nil {
+			return "", err
+		}
+
+		var buf bytes.Buffer
+
+		if err := printer.Fprint(&buf, tree); err != nil {
+			return "", err
+		}
+
+		return buf.String(), nil
+	}
+
+	var buf bytes.Buffer
+
+	if err := json.Indent(&buf, []byte(content), "", "\t"); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
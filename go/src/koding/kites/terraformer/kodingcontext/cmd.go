@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"strings"
+
+	"koding/kites/terraformer/storage"
 
 	"github.com/mitchellh/cli"
 )
@@ -12,7 +15,17 @@ type ArgsFunc func(paths *paths, destroy bool) []string
 
 func (c *KodingContext) run(cmd cli.Command, content io.Reader, destroy bool, argsFunc ArgsFunc) (*paths, error) {
 	// copy all contents from remote to local for operating
-	if err := c.RemoteStorage.Clone(c.ContentID, c.LocalStorage); err != nil {
+	if c.sourceID != "" {
+		local := &renameStorage{
+			Interface: c.LocalStorage,
+			from:      c.sourceID,
+			to:        c.ContentID,
+		}
+
+		if err := c.RemoteStorage.Clone(c.sourceID, local); err != nil {
+			return nil, err
+		}
+	} else if err := c.RemoteStorage.Clone(c.ContentID, c.LocalStorage); err != nil {
 		return nil, err
 	}
 
@@ -35,10 +48,13 @@ func (c *KodingContext) run(cmd cli.Command, content io.Reader, destroy bool, ar
 		err = fmt.Errorf("apply failed with code: %d, output: %s", exitCode, c.Buffer)
 	}
 
-	// copy all contents from local to remote for later operating
-	e := c.LocalStorage.Clone(c.ContentID, c.RemoteStorage)
-	if e != nil && err == nil {
-		err = e
+	// copy all contents from local to remote for later operating,
+	// copies of a content are thrown away
+	if c.sourceID == "" {
+		e := c.LocalStorage.Clone(c.ContentID, c.RemoteStorage)
+		if e != nil && err == nil {
+			err = e
+		}
 	}
 
 	if err != nil {
@@ -73,3 +89,17 @@ func (c *KodingContext) paths() (*paths, error) {
 		planPath:         planFilePath,
 	}, nil
 }
+
+// renameStorage writes files of one content under another content ID.
+type renameStorage struct {
+	storage.Interface
+	from, to string
+}
+
+func (rs *renameStorage) Write(filePath string, r io.Reader) error {
+	if strings.HasPrefix(filePath, rs.from+"/") {
+		filePath = rs.to + strings.TrimPrefix(filePath, rs.from)
+	}
+
+	return rs.Interface.Write(filePath, r)
+}
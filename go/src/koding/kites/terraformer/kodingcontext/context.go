@@ -4,7 +4,9 @@ package kodingcontext
 
 import (
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"koding/kites/terraformer/kodingcontext/pkg"
@@ -28,10 +30,14 @@ var (
 	shutdownChans   map[string]chan struct{}
 	shutdownChansMu sync.Mutex
 	shutdownChansWG sync.WaitGroup
+
+	// copyCount makes content IDs of copies unique
+	copyCount uint64
 )
 
 type Context interface {
 	Get(string, string) (*KodingContext, error)
+	GetCopy(string, string) (*KodingContext, error)
 	Shutdown() error
 }
 
@@ -93,6 +99,28 @@ func (c *context) Get(contentID, traceID string) (*KodingContext, error) {
 	return c.newKodingContext(sc, contentID, traceID), nil
 }
 
+// GetCopy creates a new context which works on a private copy of the
+// given content. The copy is never written back to the remote storage,
+// so the stored content and state are left untouched. The content
+// is not locked, thus the copy can be used alongside a regular context.
+func (c *context) GetCopy(contentID, traceID string) (*KodingContext, error) {
+	if contentID == "" {
+		return nil, errors.New("contentID is not set")
+	}
+
+	copyID := fmt.Sprintf("%s-copy-%d-%d", contentID, time.Now().UnixNano(), atomic.AddUint64(&copyCount, 1))
+
+	sc, err := c.createShutdownChan(copyID)
+	if err != nil {
+		return nil, err
+	}
+
+	kc := c.newKodingContext(sc, copyID, traceID)
+	kc.sourceID = contentID
+
+	return kc, nil
+}
+
 // BroadcastForceShutdown sends a message to the current operations
 func (c *context) BroadcastForceShutdown() {
 	shutdownChansMu.Lock()
@@ -42,6 +42,10 @@ type KodingContext struct {
 	ShutdownChan <-chan struct{}
 	ContentID    string
 
+	// sourceID is the ID of the copied content, it is set
+	// for contexts created with GetCopy only
+	sourceID string
+
 	debug bool
 }
 
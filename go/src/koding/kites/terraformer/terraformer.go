@@ -60,6 +60,15 @@ type TerraformRequest struct {
 	Variables map[string]string
 	ContentID string
 	TraceID   string
+
+	// Destroy, when true, makes plan method create a destroy plan
+	// for the stored content.
+	Destroy bool
+
+	// ReadOnly, when true, makes plan method work on a copy of the
+	// stored content. The refreshed state is not stored and the content
+	// is not locked, so it does not interfere with other operations.
+	ReadOnly bool
 }
 
 // New creates a new terraformer
@@ -169,7 +178,12 @@ func (t *Terraformer) Plan(r *kite.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	c, err := t.Context.Get(args.ContentID, args.TraceID)
+	get := t.Context.Get
+	if args.ReadOnly {
+		get = t.Context.GetCopy
+	}
+
+	c, err := get(args.ContentID, args.TraceID)
 	if err != nil {
 		return nil, err
 	}
@@ -178,8 +192,14 @@ func (t *Terraformer) Plan(r *kite.Request) (interface{}, error) {
 	// set variables if sent
 	c.Variables = args.Variables
 
-	destroy := false
-	return c.Plan(strings.NewReader(args.Content), destroy)
+	// set content if non-empty, otherwise plan is run against
+	// the content stored by the last apply
+	var content io.Reader
+	if args.Content != "" {
+		content = strings.NewReader(args.Content)
+	}
+
+	return c.Plan(content, args.Destroy)
 }
 
 // Apply provides a kite call for apply operation
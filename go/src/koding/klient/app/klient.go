@@ -24,6 +24,7 @@ import (
 	"koding/kites/config/configstore"
 	"koding/kites/kloud/stack"
 	"koding/kites/kloud/team"
+	"koding/klient/audit"
 	"koding/klient/client"
 	"koding/klient/collaboration"
 	"koding/klient/command"
@@ -86,6 +87,9 @@ type Klient struct {
 
 	storage *storage.Storage
 
+	// audit records method calls made by third party users.
+	audit *audit.Log
+
 	// terminal provides wmethods
 	terminal *terminal.Terminal
 
@@ -213,12 +217,18 @@ func NewKlient(conf *KlientConfig) (*Klient, error) {
 		"fs.createDirectory":   true,
 		"fs.move":              true,
 		"fs.copy":              true,
+		"fs.upload.begin":      true,
+		"fs.upload.chunk":      true,
+		"fs.upload.commit":     true,
+		"fs.download":          true,
+		"fs.watch":             true,
 		"webterm.getSessions":  true,
 		"webterm.connect":      true,
 		"webterm.killSession":  true,
 		"webterm.killSessions": true,
 		"webterm.rename":       true,
 		"exec":                 true,
+		"exec.stream":          true,
 		"klient.share":         true,
 		"klient.unshare":       true,
 		"klient.shared":        true,
@@ -362,6 +372,7 @@ func NewKlient(conf *KlientConfig) (*Klient, error) {
 		kite:    k,
 		collab:  collaboration.New(db), // nil is ok, fallbacks to in memory storage
 		storage: storage.New(db),       // nil is ok, fallbacks to in memory storage
+		audit:   audit.New(db, 0),      // nil is ok, fallbacks to in memory storage
 		tunnel:  t,
 		vagrant: vagrant.NewHandlers(vagrantOpts),
 		// docker:   docker.New("unix://var/run/docker.sock", k.Log),
@@ -421,6 +432,18 @@ func NewKlient(conf *KlientConfig) (*Klient, error) {
 		}
 	})
 
+	// Record only calls of third party users, the owner and Koding call us
+	// all the time.
+	kl.audit.Skip = func(r *kite.Request) bool {
+		return r.Auth == nil || userIn(r.Username, kl.kite.Config.Username, "koding")
+	}
+
+	// Close terminal sessions of users whose share has expired.
+	kl.collab.OnExpire = func(user string) {
+		kl.log.Info("Share of user %q has expired", user)
+		kl.terminal.CloseSessions(user)
+	}
+
 	// This is important, don't forget it
 	kl.RegisterMethods()
 
@@ -464,6 +487,10 @@ func (k *Klient) RegisterMethods() {
 
 	k.kite.PreHandleFunc(k.checkAuth)
 
+	// Audit log, records calls regardless of whether they were allowed.
+	k.kite.FinalFunc(k.audit.FinalFunc)
+	k.kite.HandleFunc("audit.list", k.auditList)
+
 	// Metrics, is used by Kloud to get usage so Kloud can stop free VMs
 	k.kite.PreHandleFunc(k.usage.Counter) // we measure every incoming request
 	k.kite.HandleFunc("klient.usage", k.usage.Current)
@@ -510,6 +537,11 @@ func (k *Klient) RegisterMethods() {
 	k.handleWithSub("fs.createDirectory", fs.CreateDirectory)
 	k.handleWithSub("fs.move", fs.Move)
 	k.handleWithSub("fs.copy", fs.Copy)
+	k.handleWithSub("fs.upload.begin", fs.UploadBegin)
+	k.handleWithSub("fs.upload.chunk", fs.UploadChunk)
+	k.handleWithSub("fs.upload.commit", fs.UploadCommit)
+	k.handleWithSub("fs.download", fs.Download)
+	k.handleWithSub("fs.watch", fs.WatchTree)
 	k.handleWithSub("fs.getDiskInfo", fs.GetDiskInfo)
 	k.handleWithSub("fs.getPathSize", fs.GetPathSize)
 
@@ -519,12 +551,21 @@ func (k *Klient) RegisterMethods() {
 	k.kite.HandleFunc("machine.ssh", machinegroup.KiteHandlerSSH(k.machines))
 	k.kite.HandleFunc("machine.mount.head", machinegroup.KiteHandlerHeadMount(k.machines))
 	k.kite.HandleFunc("machine.mount.add", machinegroup.KiteHandlerAddMount(k.machines))
+	k.kite.HandleFunc("machine.mount.conflicts", machinegroup.KiteHandlerConflictsMount(k.machines))
+	k.kite.HandleFunc("machine.mount.resolve", machinegroup.KiteHandlerResolveMount(k.machines))
+	k.kite.HandleFunc("machine.mount.policy", machinegroup.KiteHandlerPolicyMount(k.machines))
+	k.kite.HandleFunc("machine.mount.ignore", machinegroup.KiteHandlerIgnoreMount(k.machines))
+	k.kite.HandleFunc("machine.mount.status", machinegroup.KiteHandlerStatusMount(k.machines))
+	k.kite.HandleFunc("machine.mount.pause", machinegroup.KiteHandlerPauseMount(k.machines))
+	k.kite.HandleFunc("machine.mount.resume", machinegroup.KiteHandlerResumeMount(k.machines))
 	k.kite.HandleFunc("machine.mount.list", machinegroup.KiteHandlerListMount(k.machines))
 	k.kite.HandleFunc("machine.umount", machinegroup.KiteHandlerUmount(k.machines))
 
 	// Machine index handlers.
 	k.handleWithSub("machine.index.head", index.KiteHandlerHead())
 	k.handleWithSub("machine.index.get", index.KiteHandlerGet())
+	k.handleWithSub("machine.index.delta", index.KiteHandlerDelta())
+	k.handleWithSub("machine.index.patch", index.KiteHandlerPatch())
 
 	// Vagrant
 	k.kite.HandleFunc("vagrant.create", k.vagrant.Create)
@@ -553,6 +594,7 @@ func (k *Klient) RegisterMethods() {
 
 	// Execution
 	k.kite.HandleFunc("exec", command.Exec)
+	k.kite.HandleFunc("exec.stream", command.ExecStream)
 
 	// Terminal
 	k.handleWithSub("webterm.getSessions", k.terminal.GetSessions)
@@ -848,6 +890,7 @@ func (k *Klient) Close() {
 	k.collabCloser.Close()
 	k.collab.Close()
 	k.kite.Close()
+	k.audit.Close()
 }
 
 func newKite(kconf *KlientConfig) *kite.Kite {
@@ -889,23 +932,43 @@ func (k *Klient) checkAuth(r *kite.Request) (interface{}, error) {
 	}
 
 	// Allow collaboration users as well
-	sharedUsers, err := k.collab.GetAll()
+	option, err := k.collab.Get(r.Username)
+	if err == collaboration.ErrUserNotFound {
+		return nil, fmt.Errorf("User '%s' is not allowed to make a call to us.", r.Username)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Can't read shared users from the storage. Err: %v", err)
 	}
 
-	sharedUsernames := make([]string, 0, len(sharedUsers))
-	for username := range sharedUsers {
-		sharedUsernames = append(sharedUsernames, username)
+	// Old storage entries have no options, they give full access.
+	if option == nil {
+		option = &collaboration.Option{}
 	}
 
-	if !userIn(r.Username, sharedUsernames...) {
+	if option.Expired(time.Now()) {
+		k.collab.Expire(r.Username)
 		return nil, fmt.Errorf("User '%s' is not allowed to make a call to us.", r.Username)
 	}
 
+	if err := option.Authorize(r); err != nil {
+		return nil, fmt.Errorf("User '%s' is not allowed to call %q: %s", r.Username, r.Method, err)
+	}
+
+	// Let handlers know about the restrictions of the shared user.
+	r.Context.Set(collaboration.ContextKey, option)
+
 	return true, nil
 }
 
+// auditList lists audit records. Shared users are not allowed to read them.
+func (k *Klient) auditList(r *kite.Request) (interface{}, error) {
+	if collaboration.FromRequest(r) != nil {
+		return nil, fmt.Errorf("User '%s' is not allowed to read audit log.", r.Username)
+	}
+
+	return k.audit.ListHandler(r)
+}
+
 func (k *Klient) updateKiteKey(reg *kiteproto.RegisterResult) {
 	if reg.KiteKey == "" {
 		return
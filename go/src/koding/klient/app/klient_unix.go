@@ -12,12 +12,14 @@ func (k *Klient) addRemoteHandlers() {
 	k.handleRemoteFunc("remote.unmountFolder", k.remote.UnmountFolderHandler)
 	k.handleRemoteFunc("remote.sshKeysAdd", k.remote.SSHKeyAddHandler)
 	k.handleRemoteFunc("remote.exec", k.remote.ExecHandler)
+	k.handleRemoteFunc("remote.execStream", k.remote.ExecStreamHandler)
 	k.handleRemoteFunc("remote.status", k.remote.StatusHandler)
 	k.handleRemoteFunc("remote.remount", k.remote.RemountHandler)
 	k.handleRemoteFunc("remote.mountInfo", k.remote.MountInfoHandler)
 	k.handleRemoteFunc("remote.readDirectory", k.remote.ReadDirectoryHandler)
 	k.handleRemoteFunc("remote.currentUsername", k.remote.CurrentUsername)
 	k.handleRemoteFunc("remote.getPathSize", k.remote.GetPathSize)
+	k.handleRemoteFunc("remote.transfer", k.remote.TransferHandler)
 }
 
 // Initializing the remote re-establishes any previously-running remote
@@ -2,7 +2,12 @@ package collaboration
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/koding/kite"
@@ -10,6 +15,20 @@ import (
 
 type Collaboration struct {
 	Storage
+
+	// OnExpire, if set, is called with the name of a user whose share has
+	// expired, after the user was removed from the storage.
+	OnExpire func(username string)
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer // username -> expiry timer
+}
+
+// SharedUser describes a single shared user, it is returned by klient.shared
+// method when details are requested.
+type SharedUser struct {
+	Username string `json:"username"`
+	Option
 }
 
 func New(boltDB *bolt.DB) *Collaboration {
@@ -22,35 +41,67 @@ func New(boltDB *bolt.DB) *Collaboration {
 		db = NewMemoryStorage()
 	}
 
-	return &Collaboration{
+	c := &Collaboration{
 		Storage: db,
+		timers:  make(map[string]*time.Timer),
+	}
+
+	// Expiry timers are not persisted, set them again for stored users.
+	if users, err := c.GetAll(); err == nil {
+		for username, option := range users {
+			c.schedule(username, option)
+		}
 	}
+
+	return c
 }
 
 func (c *Collaboration) Share(r *kite.Request) (interface{}, error) {
 	var params struct {
 		Username  string
 		Permanent bool
+		Role      Role
+		Paths     []string
+		ExpiresAt time.Time
 	}
 
 	if r.Args.One().Unmarshal(&params) != nil || params.Username == "" {
 		return nil, errors.New("Wrong usage.")
 	}
 
-	option, err := c.Get(params.Username)
-	if err == nil {
-		// if the user is already a permanant user just return lazily, we don't
-		// need change anything
-		if option.Permanent {
-			return "shared", nil
+	if err := params.Role.Valid(); err != nil {
+		return nil, err
+	}
+
+	for _, path := range params.Paths {
+		if !filepath.IsAbs(path) {
+			return nil, fmt.Errorf("shared path %q is not absolute", path)
 		}
 	}
 
-	newOption := &Option{Permanent: params.Permanent}
+	if !params.ExpiresAt.IsZero() && !params.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiry time is in the past")
+	}
+
+	newOption := &Option{
+		Permanent: params.Permanent,
+		Role:      params.Role,
+		Paths:     params.Paths,
+		ExpiresAt: params.ExpiresAt,
+	}
+
+	// if the user is already a permanent user keep it that way, only its
+	// access options are updated.
+	if option, err := c.Get(params.Username); err == nil && option != nil && option.Permanent {
+		newOption.Permanent = true
+	}
+
 	if err := c.Set(params.Username, newOption); err != nil {
 		return nil, errors.New("user is already in the shared list.")
 	}
 
+	c.schedule(params.Username, newOption)
+
 	return "shared", nil
 }
 
@@ -81,19 +132,96 @@ func (c *Collaboration) Unshare(r *kite.Request) (interface{}, error) {
 		return nil, errors.New("user is not in the shared list.")
 	}
 
+	c.unschedule(params.Username)
+
 	return "unshared", nil
 }
 
+// Shared returns comma separated names of shared users. When called with
+// details flag set, it returns []*SharedUser instead.
 func (c *Collaboration) Shared(r *kite.Request) (interface{}, error) {
+	var params struct {
+		Details bool
+	}
+
+	if r.Args != nil {
+		if args, err := r.Args.Slice(); err == nil && len(args) != 0 {
+			args[0].Unmarshal(&params)
+		}
+	}
+
 	users, err := c.GetAll()
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	usernames := make([]string, 0)
-	for username := range users {
+	for username, option := range users {
+		if option != nil && option.Expired(now) {
+			continue
+		}
+
 		usernames = append(usernames, username)
 	}
 
-	return strings.Join(usernames, ","), nil
+	if !params.Details {
+		return strings.Join(usernames, ","), nil
+	}
+
+	sort.Strings(usernames)
+
+	shared := make([]*SharedUser, len(usernames))
+	for i, username := range usernames {
+		shared[i] = &SharedUser{Username: username}
+
+		if option := users[username]; option != nil {
+			shared[i].Option = *option
+		}
+	}
+
+	return shared, nil
+}
+
+// Expire removes the user if its share has expired.
+func (c *Collaboration) Expire(username string) {
+	option, err := c.Get(username)
+	if err != nil || option == nil || !option.Expired(time.Now()) {
+		return
+	}
+
+	c.unschedule(username)
+
+	if err := c.Delete(username); err != nil {
+		return
+	}
+
+	if c.OnExpire != nil {
+		c.OnExpire(username)
+	}
+}
+
+// schedule sets expiry timer of the user, replacing the previous one.
+func (c *Collaboration) schedule(username string, option *Option) {
+	c.unschedule(username)
+
+	if option == nil || option.ExpiresAt.IsZero() {
+		return
+	}
+
+	c.mu.Lock()
+	c.timers[username] = time.AfterFunc(option.ExpiresAt.Sub(time.Now()), func() {
+		c.Expire(username)
+	})
+	c.mu.Unlock()
+}
+
+func (c *Collaboration) unschedule(username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t, ok := c.timers[username]; ok {
+		t.Stop()
+		delete(c.timers, username)
+	}
 }
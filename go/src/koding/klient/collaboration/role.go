@@ -0,0 +1,217 @@
+package collaboration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/koding/kite"
+)
+
+// ContextKey is the key under which the Option of a shared user is stored
+// in request context once the request is authorized.
+const ContextKey = "collaboration.option"
+
+// ErrNotAllowed is returned when shared user is not allowed to call a method.
+var ErrNotAllowed = errors.New("method is not allowed for the shared user")
+
+// Role defines what a shared user is allowed to do.
+type Role string
+
+// Available roles.
+const (
+	// RoleFull gives access to all methods. This is the default role.
+	RoleFull Role = ""
+
+	// RoleReadOnly gives read-only access to the file system and allows to
+	// watch, but not to type in, existing terminal sessions.
+	RoleReadOnly Role = "read-only"
+
+	// RoleFSReadOnly gives read-only access to the file system.
+	RoleFSReadOnly Role = "fs-read-only"
+
+	// RoleTerminalReadOnly allows to watch existing terminal sessions.
+	RoleTerminalReadOnly Role = "terminal-read-only"
+)
+
+var (
+	// fsReadMethods are the fs methods which don't modify the file system.
+	fsReadMethods = map[string]bool{
+		"fs.readDirectory": true,
+		"fs.glob":          true,
+		"fs.readFile":      true,
+		"fs.uniquePath":    true,
+		"fs.getInfo":       true,
+		"fs.getDiskInfo":   true,
+		"fs.getPathSize":   true,
+		"fs.download":      true,
+		"fs.watch":         true,
+	}
+
+	// terminalReadMethods are the terminal methods which allow to watch
+	// terminal sessions.
+	terminalReadMethods = map[string]bool{
+		"webterm.getSessions": true,
+		"webterm.connect":     true,
+	}
+
+	// pathArgs are the names of fs.* method arguments which hold paths.
+	pathArgs = []string{"path", "oldPath", "newPath", "srcPath", "dstPath", "pattern"}
+)
+
+// Valid checks if the role is known.
+func (r Role) Valid() error {
+	switch r {
+	case RoleFull, RoleReadOnly, RoleFSReadOnly, RoleTerminalReadOnly:
+		return nil
+	default:
+		return fmt.Errorf("unknown role: %q", r)
+	}
+}
+
+// String returns a string form of the role.
+func (r Role) String() string {
+	if r == RoleFull {
+		return "full"
+	}
+
+	return string(r)
+}
+
+// ReadOnlyTerminal tells whether the user can only watch terminal sessions.
+func (r Role) ReadOnlyTerminal() bool {
+	return r == RoleReadOnly || r == RoleTerminalReadOnly
+}
+
+// Authorize checks whether the shared user is allowed to make the request.
+func (o *Option) Authorize(r *kite.Request) error {
+	// Kite internal methods, like kite.ping, are always allowed.
+	if strings.HasPrefix(r.Method, "kite.") {
+		return nil
+	}
+
+	if !o.allows(r.Method) {
+		return ErrNotAllowed
+	}
+
+	if len(o.Paths) != 0 && strings.HasPrefix(r.Method, "fs.") {
+		return o.authorizePaths(r)
+	}
+
+	return nil
+}
+
+func (o *Option) allows(method string) bool {
+	switch o.Role {
+	case RoleFull:
+		return true
+	case RoleReadOnly:
+		return fsReadMethods[method] || terminalReadMethods[method]
+	case RoleFSReadOnly:
+		return fsReadMethods[method]
+	case RoleTerminalReadOnly:
+		return terminalReadMethods[method]
+	default:
+		return false
+	}
+}
+
+// authorizePaths checks whether all paths given to fs.* method are within
+// the shared ones.
+func (o *Option) authorizePaths(r *kite.Request) error {
+	if r.Args == nil {
+		return nil
+	}
+
+	partials, err := r.Args.Slice()
+	if err != nil || len(partials) == 0 {
+		return nil
+	}
+
+	var args map[string]interface{}
+	if err := json.Unmarshal(partials[0].Raw, &args); err != nil {
+		return fmt.Errorf("invalid arguments: %s", err)
+	}
+
+	for key, value := range args {
+		if !isPathArg(key) {
+			continue
+		}
+
+		path, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		if !o.pathAllowed(path) {
+			return fmt.Errorf("access to %q is not allowed for the shared user", path)
+		}
+	}
+
+	return nil
+}
+
+// pathAllowed checks whether the path is within the shared ones. Relative
+// paths are not allowed, since they are resolved differently by fs methods.
+func (o *Option) pathAllowed(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+
+	path = resolvePath(path)
+
+	for _, shared := range o.Paths {
+		shared = resolvePath(shared)
+
+		if path == shared || strings.HasPrefix(path, strings.TrimSuffix(shared, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FromRequest gives the Option of a shared user stored in request context by
+// authorization. It returns nil if the request was not made by a shared user.
+func FromRequest(r *kite.Request) *Option {
+	if r.Context == nil {
+		return nil
+	}
+
+	v, err := r.Context.Get(ContextKey)
+	if err != nil {
+		return nil
+	}
+
+	option, _ := v.(*Option)
+	return option
+}
+
+// resolvePath cleans the path and evaluates symlinks, so shared paths
+// cannot be escaped with them. Paths which don't exist yet are resolved
+// relative to their parent directory.
+func resolvePath(path string) string {
+	path = filepath.Clean(path)
+
+	if p, err := filepath.EvalSymlinks(path); err == nil {
+		return p
+	}
+
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		return filepath.Join(dir, filepath.Base(path))
+	}
+
+	return path
+}
+
+func isPathArg(key string) bool {
+	for _, arg := range pathArgs {
+		if strings.EqualFold(key, arg) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package collaboration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/koding/kite"
+	"github.com/koding/kite/dnode"
+)
+
+func TestOptionAuthorize(t *testing.T) {
+	tests := map[string]struct {
+		Option  *Option
+		Method  string
+		Args    string
+		Allowed bool
+	}{
+		"full access": {
+			Option:  &Option{},
+			Method:  "fs.remove",
+			Args:    `[{"path":"/etc/passwd"}]`,
+			Allowed: true,
+		},
+		"read-only reads file": {
+			Option:  &Option{Role: RoleReadOnly},
+			Method:  "fs.readFile",
+			Args:    `[{"path":"/tmp/file"}]`,
+			Allowed: true,
+		},
+		"read-only writes file": {
+			Option:  &Option{Role: RoleReadOnly},
+			Method:  "fs.writeFile",
+			Args:    `[{"path":"/tmp/file"}]`,
+			Allowed: false,
+		},
+		"read-only connects to terminal": {
+			Option:  &Option{Role: RoleReadOnly},
+			Method:  "webterm.connect",
+			Allowed: true,
+		},
+		"fs read-only connects to terminal": {
+			Option:  &Option{Role: RoleFSReadOnly},
+			Method:  "webterm.connect",
+			Allowed: false,
+		},
+		"terminal read-only reads file": {
+			Option:  &Option{Role: RoleTerminalReadOnly},
+			Method:  "fs.readFile",
+			Args:    `[{"path":"/tmp/file"}]`,
+			Allowed: false,
+		},
+		"read-only runs command": {
+			Option:  &Option{Role: RoleReadOnly},
+			Method:  "exec",
+			Allowed: false,
+		},
+		"kite methods": {
+			Option:  &Option{Role: RoleTerminalReadOnly},
+			Method:  "kite.ping",
+			Allowed: true,
+		},
+		"path within shared one": {
+			Option:  &Option{Paths: []string{"/home/user/project"}},
+			Method:  "fs.readDirectory",
+			Args:    `[{"path":"/home/user/project/src"}]`,
+			Allowed: true,
+		},
+		"path outside shared one": {
+			Option:  &Option{Paths: []string{"/home/user/project"}},
+			Method:  "fs.readDirectory",
+			Args:    `[{"path":"/home/user/project2"}]`,
+			Allowed: false,
+		},
+		"path escaping shared one": {
+			Option:  &Option{Paths: []string{"/home/user/project"}},
+			Method:  "fs.readFile",
+			Args:    `[{"path":"/home/user/project/../.ssh/id_rsa"}]`,
+			Allowed: false,
+		},
+		"relative path": {
+			Option:  &Option{Paths: []string{"/home/user/project"}},
+			Method:  "fs.readFile",
+			Args:    `[{"path":"project/file"}]`,
+			Allowed: false,
+		},
+		"one of paths outside shared one": {
+			Option:  &Option{Paths: []string{"/home/user/project"}},
+			Method:  "fs.copy",
+			Args:    `[{"srcPath":"/home/user/project/a","dstPath":"/tmp/a"}]`,
+			Allowed: false,
+		},
+	}
+
+	for name, test := range tests {
+		// capture range variable here
+		test := test
+		t.Run(name, func(t *testing.T) {
+			r := &kite.Request{
+				Method: test.Method,
+			}
+
+			if test.Args != "" {
+				r.Args = &dnode.Partial{Raw: []byte(test.Args)}
+			}
+
+			if err := test.Option.Authorize(r); (err == nil) != test.Allowed {
+				t.Errorf("want allowed = %t; got err = %v", test.Allowed, err)
+			}
+		})
+	}
+}
+
+func TestOptionExpired(t *testing.T) {
+	now := time.Now()
+
+	if (&Option{}).Expired(now) {
+		t.Error("want option without expiry time to never expire")
+	}
+
+	if !(&Option{ExpiresAt: now}).Expired(now) {
+		t.Error("want option to expire at its expiry time")
+	}
+
+	if (&Option{ExpiresAt: now.Add(time.Minute)}).Expired(now) {
+		t.Error("want option not to expire before its expiry time")
+	}
+}
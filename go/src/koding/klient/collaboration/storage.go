@@ -2,6 +2,7 @@ package collaboration
 
 import (
 	"errors"
+	"time"
 )
 
 var (
@@ -13,6 +14,22 @@ type Option struct {
 	// Permananet means the user is shared
 	Permanent bool   `json:"permanent"`
 	Test      string `json:"test"`
+
+	// Role limits the methods the user can call. Empty role gives full
+	// access.
+	Role Role `json:"role,omitempty"`
+
+	// Paths, if not empty, limits fs.* methods to the given absolute paths
+	// and their contents.
+	Paths []string `json:"paths,omitempty"`
+
+	// ExpiresAt, if not zero, is the time after which the user is unshared.
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// Expired checks whether the share expired at the given time.
+func (o *Option) Expired(now time.Time) bool {
+	return !o.ExpiresAt.IsZero() && !now.Before(o.ExpiresAt)
 }
 
 type Storage interface {
@@ -0,0 +1,214 @@
+package command
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/koding/kite"
+	"github.com/koding/kite/dnode"
+
+	"koding/klient/kiteerrortypes"
+	"koding/klient/util"
+)
+
+// StreamRequest is the request struct for exec.stream method.
+type StreamRequest struct {
+	Command string        `json:"command"`
+	WorkDir string        `json:"workDir,omitempty"` // Defaults to klient working directory.
+	Env     []string      `json:"env,omitempty"`     // KEY=VALUE pairs added to klient environment.
+	Timeout time.Duration `json:"timeout,omitempty"` // Process is killed after timeout, if set.
+
+	// Stdout and Stderr are called with chunks of process output. Exit is
+	// called with *StreamExit value when the process ends and all its output
+	// was sent.
+	Stdout dnode.Function `json:"stdout"`
+	Stderr dnode.Function `json:"stderr"`
+	Exit   dnode.Function `json:"exit"`
+}
+
+// StreamExit describes the end of streamed process.
+type StreamExit struct {
+	ExitStatus int    `json:"exitStatus"`
+	Killed     bool   `json:"killed,omitempty"`   // Process was killed by caller.
+	TimedOut   bool   `json:"timedOut,omitempty"` // Process was killed after timeout.
+	Error      string `json:"error,omitempty"`    // Set when process could not be waited for.
+}
+
+// Stream is a handle of a running process which is sent back to the caller of
+// exec.stream method. Its exported methods are callable remotely.
+type Stream struct {
+	PID int `json:"pid"`
+
+	// never expose the following fields as we return them back to the client.
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	mu       sync.Mutex
+	killed   bool
+	timedOut bool
+	done     bool
+}
+
+// ExecStream starts the given command and streams its output to provided
+// callbacks. The returned Stream can be used to write process input or to kill
+// it. The process is killed when the caller disconnects.
+func ExecStream(r *kite.Request) (interface{}, error) {
+	var params StreamRequest
+
+	if r.Args == nil || r.Args.One().Unmarshal(&params) != nil || params.Command == "" {
+		return nil, errors.New("{command: [string], stdout: [function], stderr: [function], exit: [function]}")
+	}
+
+	s, err := NewStream(&params)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Client.OnDisconnect(s.kill)
+
+	return s, nil
+}
+
+// NewStream starts a process described by the given request.
+func NewStream(req *StreamRequest) (*Stream, error) {
+	cmd := exec.Command("/bin/bash", "-c", req.Command)
+	cmd.Dir = req.WorkDir
+	cmd.Stdout = callbackWriter(req.Stdout)
+	cmd.Stderr = callbackWriter(req.Stderr)
+
+	// Run the command in its own process group so all its children can be
+	// killed together.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if len(req.Env) != 0 {
+		cmd.Env = append(os.Environ(), req.Env...)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, util.NewKiteError(kiteerrortypes.ProcessError, err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, util.NewKiteError(kiteerrortypes.ProcessError, err)
+	}
+
+	s := &Stream{
+		PID:   cmd.Process.Pid,
+		cmd:   cmd,
+		stdin: stdin,
+	}
+
+	var t *time.Timer
+	if req.Timeout > 0 {
+		t = time.AfterFunc(req.Timeout, s.timeout)
+	}
+
+	go func() {
+		s.wait(req.Exit)
+
+		if t != nil {
+			t.Stop()
+		}
+	}()
+
+	return s, nil
+}
+
+// Input writes data given as the only argument to process standard input.
+func (s *Stream) Input(d *dnode.Partial) {
+	data := d.MustSliceOfLength(1)[0].MustString()
+
+	// There is no need to protect the Write() with a mutex because
+	// Kite Library guarantees that only one message is processed at a time.
+	s.stdin.Write([]byte(data))
+}
+
+// CloseInput closes process standard input.
+func (s *Stream) CloseInput(*dnode.Partial) {
+	s.stdin.Close()
+}
+
+// Kill kills the process and all its children.
+func (s *Stream) Kill(*dnode.Partial) {
+	s.kill()
+}
+
+func (s *Stream) kill() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done || s.killed {
+		return
+	}
+
+	s.killed = true
+	syscall.Kill(-s.PID, syscall.SIGKILL)
+}
+
+func (s *Stream) timeout() {
+	s.mu.Lock()
+	s.timedOut = !s.done
+	s.mu.Unlock()
+
+	s.kill()
+}
+
+// wait waits for the process to end and reports its exit status.
+func (s *Stream) wait(exit dnode.Function) {
+	err := s.cmd.Wait()
+
+	s.mu.Lock()
+	s.done = true
+	res := &StreamExit{
+		Killed:   s.killed && !s.timedOut,
+		TimedOut: s.timedOut,
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			res.ExitStatus = exitErr.Sys().(syscall.WaitStatus).ExitStatus()
+		} else {
+			res.Error = err.Error()
+		}
+	}
+
+	if exit.IsValid() {
+		exit.Call(res)
+	}
+}
+
+// callbackWriter returns a writer which sends written data to the given dnode
+// function. It returns nil writer when the function is not set, which makes
+// exec discard the output.
+func callbackWriter(fn dnode.Function) io.Writer {
+	if !fn.IsValid() {
+		return nil
+	}
+
+	return writerFunc(func(p []byte) (int, error) {
+		if err := fn.Call(string(p)); err != nil {
+			return 0, err
+		}
+
+		return len(p), nil
+	})
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (fn writerFunc) Write(p []byte) (int, error) { return fn(p) }
+
+// StreamHandle is the Stream as seen by the caller of exec.stream method.
+type StreamHandle struct {
+	PID        int            `json:"pid"`
+	Input      dnode.Function `json:"input"`
+	CloseInput dnode.Function `json:"closeInput"`
+	Kill       dnode.Function `json:"kill"`
+}
@@ -0,0 +1,143 @@
+package command
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koding/kite/dnode"
+)
+
+type callerFunc func(...interface{}) error
+
+func (fn callerFunc) Call(args ...interface{}) error { return fn(args...) }
+
+// streamRecorder collects data sent to stream callbacks.
+type streamRecorder struct {
+	mu     sync.Mutex
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	exitC  chan *StreamExit
+}
+
+func newStreamRecorder(command string) (*StreamRequest, *streamRecorder) {
+	rec := &streamRecorder{
+		exitC: make(chan *StreamExit, 1),
+	}
+
+	write := func(buf *bytes.Buffer) dnode.Function {
+		return dnode.Function{Caller: callerFunc(func(args ...interface{}) error {
+			rec.mu.Lock()
+			buf.WriteString(args[0].(string))
+			rec.mu.Unlock()
+			return nil
+		})}
+	}
+
+	req := &StreamRequest{
+		Command: command,
+		Stdout:  write(&rec.stdout),
+		Stderr:  write(&rec.stderr),
+		Exit: dnode.Function{Caller: callerFunc(func(args ...interface{}) error {
+			rec.exitC <- args[0].(*StreamExit)
+			return nil
+		})},
+	}
+
+	return req, rec
+}
+
+func (rec *streamRecorder) wait(t *testing.T) *StreamExit {
+	select {
+	case exit := <-rec.exitC:
+		return exit
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for process exit")
+		return nil
+	}
+}
+
+func TestStreamOutput(t *testing.T) {
+	req, rec := newStreamRecorder("echo out; echo err >&2; exit 3")
+
+	if _, err := NewStream(req); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if exit := rec.wait(t); exit.ExitStatus != 3 {
+		t.Errorf("want exit status = 3; got %d", exit.ExitStatus)
+	}
+
+	if got := rec.stdout.String(); got != "out\n" {
+		t.Errorf("want stdout = %q; got %q", "out\n", got)
+	}
+
+	if got := rec.stderr.String(); got != "err\n" {
+		t.Errorf("want stderr = %q; got %q", "err\n", got)
+	}
+}
+
+func TestStreamInput(t *testing.T) {
+	req, rec := newStreamRecorder("cat")
+
+	s, err := NewStream(req)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	s.Input(&dnode.Partial{Raw: []byte(`["hello"]`)})
+	s.CloseInput(nil)
+
+	if exit := rec.wait(t); exit.ExitStatus != 0 {
+		t.Errorf("want exit status = 0; got %d", exit.ExitStatus)
+	}
+
+	if got := rec.stdout.String(); got != "hello" {
+		t.Errorf("want stdout = %q; got %q", "hello", got)
+	}
+}
+
+func TestStreamKill(t *testing.T) {
+	tests := map[string]struct {
+		Timeout  time.Duration
+		Kill     bool
+		Killed   bool
+		TimedOut bool
+	}{
+		"killed by caller": {
+			Kill:   true,
+			Killed: true,
+		},
+		"timed out": {
+			Timeout:  100 * time.Millisecond,
+			TimedOut: true,
+		},
+	}
+
+	for name, test := range tests {
+		// capture range variable here
+		test := test
+		t.Run(name, func(t *testing.T) {
+			req, rec := newStreamRecorder("sleep 30")
+			req.Timeout = test.Timeout
+
+			s, err := NewStream(req)
+			if err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+
+			if test.Kill {
+				s.Kill(nil)
+			}
+
+			exit := rec.wait(t)
+			if exit.Killed != test.Killed {
+				t.Errorf("want killed = %t; got %t", test.Killed, exit.Killed)
+			}
+			if exit.TimedOut != test.TimedOut {
+				t.Errorf("want timed out = %t; got %t", test.TimedOut, exit.TimedOut)
+			}
+		})
+	}
+}
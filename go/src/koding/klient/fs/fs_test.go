@@ -80,7 +80,17 @@ func TestMain(m *testing.M) {
 	}
 	defer remoteKite2.Close()
 
-	os.Exit(m.Run())
+	code := m.Run()
+
+	// Remove files created by the tests, so they don't end up
+	// in the working tree.
+	os.Remove(testfile1)
+
+	for _, name := range []string{"permissions.txt", "permissions2.txt", "permissions3.txt"} {
+		os.Remove(filepath.Join("testdata", name))
+	}
+
+	os.Exit(code)
 }
 
 func benchmarkReadDirectory(b *testing.B, numberOfFiles int) {
@@ -0,0 +1,535 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/koding/kite"
+)
+
+const (
+	// DefaultChunkSize is the size of transferred chunks used when the caller
+	// doesn't specify it.
+	DefaultChunkSize = 1024 * 1024
+
+	// MaxChunkSize is the upper limit of a single transferred chunk.
+	MaxChunkSize = 8 * 1024 * 1024
+
+	// uploadPrefix is the name prefix of temporary files storing uploaded
+	// data. They are stored next to the destination file, so the final rename
+	// doesn't cross file system boundaries.
+	uploadPrefix = ".kdupload-"
+)
+
+var (
+	// ErrSessionNotFound is returned when upload session with a given ID
+	// doesn't exist. Client should begin the upload again, which resumes it
+	// from already received data.
+	ErrSessionNotFound = errors.New("upload session not found")
+
+	// ErrChecksumMismatch is returned when received data doesn't match its
+	// checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// UploadSessionTTL defines how long an inactive upload session is kept. After
+// that time, both the session and its temporary file are removed, so the
+// upload can no longer be resumed.
+var UploadSessionTTL = 24 * time.Hour
+
+var (
+	sessions   = make(map[string]*uploadSession) // upload ID -> session
+	uploadDirs = make(map[string]struct{})       // directories with temporary files
+	sessionsMu sync.Mutex                        // protects sessions and uploadDirs
+
+	sweepOnce sync.Once // starts sweeping of inactive sessions
+)
+
+// UploadBeginOptions are the arguments of fs.upload.begin method.
+type UploadBeginOptions struct {
+	// Path is the destination path. Relative paths are resolved against
+	// the home directory of klient user.
+	Path string `json:"path"`
+
+	// Size and Hash describe the entire file. Hash is a hex encoded SHA-256
+	// checksum of file content.
+	Size int64  `json:"size"`
+	Hash string `json:"hash"`
+
+	// Mode is the permission of created file, defaults to 0644.
+	Mode os.FileMode `json:"mode,omitempty"`
+
+	// ChunkSize is the preferred size of uploaded chunks.
+	ChunkSize int64 `json:"chunkSize,omitempty"`
+}
+
+// UploadSession describes the state of started upload.
+type UploadSession struct {
+	// ID identifies upload session. Uploads of the same content to the same
+	// path always get the same ID, which allows to resume them.
+	ID string `json:"id"`
+
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"` // Number of bytes already received.
+	ChunkSize int64  `json:"chunkSize"`
+}
+
+// UploadChunkOptions are the arguments of fs.upload.chunk method.
+type UploadChunkOptions struct {
+	ID      string `json:"id"`
+	Offset  int64  `json:"offset"`
+	Content []byte `json:"content"`
+	Hash    string `json:"hash"` // Hex encoded SHA-256 checksum of Content.
+}
+
+// UploadCommitOptions are the arguments of fs.upload.commit method.
+type UploadCommitOptions struct {
+	ID string `json:"id"`
+}
+
+// DownloadOptions are the arguments of fs.download method.
+type DownloadOptions struct {
+	// Path is the source path. Relative paths are resolved against the home
+	// directory of klient user.
+	Path      string `json:"path"`
+	Offset    int64  `json:"offset"`
+	BlockSize int64  `json:"blockSize,omitempty"` // Defaults to DefaultChunkSize.
+}
+
+// DownloadChunk is a part of downloaded file.
+type DownloadChunk struct {
+	Offset  int64       `json:"offset"`
+	Content []byte      `json:"content"`
+	Hash    string      `json:"hash"` // Hex encoded SHA-256 checksum of Content.
+	Size    int64       `json:"size"` // Size of the entire file.
+	Mode    os.FileMode `json:"mode"`
+
+	// FileHash is a hex encoded SHA-256 checksum of the entire file. It is
+	// set only for the last chunk.
+	FileHash string `json:"fileHash,omitempty"`
+}
+
+// uploadSession stores the state of a single upload.
+type uploadSession struct {
+	mu sync.Mutex // serializes writes to tmp file.
+
+	UploadSession
+	hash   string
+	mode   os.FileMode
+	tmp    string    // path of temporary file storing received data.
+	active time.Time // last time the session was used, protected by sessionsMu.
+}
+
+// UploadBegin starts or resumes chunked upload of a file.
+func UploadBegin(r *kite.Request) (interface{}, error) {
+	var params UploadBeginOptions
+	if r.Args == nil || r.Args.One().Unmarshal(&params) != nil || params.Path == "" || params.Hash == "" {
+		return nil, errors.New("{ path: [string], size: [number], hash: [string] }")
+	}
+
+	return uploadBegin(&params)
+}
+
+// UploadChunk writes a single chunk of uploaded file.
+func UploadChunk(r *kite.Request) (interface{}, error) {
+	var params UploadChunkOptions
+	if r.Args == nil || r.Args.One().Unmarshal(&params) != nil || params.ID == "" {
+		return nil, errors.New("{ id: [string], offset: [number], content: [base64], hash: [string] }")
+	}
+
+	return uploadChunk(&params)
+}
+
+// UploadCommit verifies uploaded file and moves it to its destination path.
+func UploadCommit(r *kite.Request) (interface{}, error) {
+	var params UploadCommitOptions
+	if r.Args == nil || r.Args.One().Unmarshal(&params) != nil || params.ID == "" {
+		return nil, errors.New("{ id: [string] }")
+	}
+
+	return uploadCommit(&params)
+}
+
+// Download reads a single chunk of a file.
+func Download(r *kite.Request) (interface{}, error) {
+	var params DownloadOptions
+	if r.Args == nil || r.Args.One().Unmarshal(&params) != nil || params.Path == "" {
+		return nil, errors.New("{ path: [string], offset: [number], blockSize: [number] }")
+	}
+
+	return download(&params)
+}
+
+func uploadBegin(params *UploadBeginOptions) (*UploadSession, error) {
+	if params.Size < 0 {
+		return nil, fmt.Errorf("invalid file size: %d", params.Size)
+	}
+
+	path, err := absPath(params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize, err := chunkSizeOf(params.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := params.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+
+	id := uploadID(path, params.Size, params.Hash)
+
+	sweepOnce.Do(func() { go sweep() })
+
+	sessionsMu.Lock()
+	s, ok := sessions[id]
+	if !ok {
+		s = &uploadSession{
+			UploadSession: UploadSession{
+				ID:   id,
+				Path: path,
+				Size: params.Size,
+			},
+			hash: params.Hash,
+			tmp:  filepath.Join(filepath.Dir(path), uploadPrefix+id),
+		}
+		sessions[id] = s
+	}
+	s.active = time.Now()
+	uploadDirs[filepath.Dir(path)] = struct{}{}
+	sessionsMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Data received by previous sessions is kept in temporary file, resume
+	// from its end.
+	fi, err := os.Stat(s.tmp)
+	switch {
+	case os.IsNotExist(err):
+		f, err := os.OpenFile(s.tmp, os.O_WRONLY|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+		s.Offset = 0
+	case err != nil:
+		return nil, err
+	case fi.Size() > s.Size:
+		// Should not happen, start over.
+		if err := os.Truncate(s.tmp, 0); err != nil {
+			return nil, err
+		}
+		s.Offset = 0
+	default:
+		s.Offset = fi.Size()
+	}
+
+	s.ChunkSize = chunkSize
+	s.mode = mode
+
+	us := s.UploadSession
+	return &us, nil
+}
+
+func uploadChunk(params *UploadChunkOptions) (*UploadSession, error) {
+	s, err := session(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if int64(len(params.Content)) > MaxChunkSize {
+		return nil, fmt.Errorf("chunk size %d exceeds limit of %d bytes", len(params.Content), MaxChunkSize)
+	}
+
+	if params.Offset != s.Offset {
+		return nil, fmt.Errorf("invalid chunk offset %d, expected %d", params.Offset, s.Offset)
+	}
+
+	if s.Offset+int64(len(params.Content)) > s.Size {
+		return nil, fmt.Errorf("chunk exceeds declared file size of %d bytes", s.Size)
+	}
+
+	if hashOf(params.Content) != params.Hash {
+		return nil, ErrChecksumMismatch
+	}
+
+	f, err := os.OpenFile(s.tmp, os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteAt(params.Content, s.Offset); err != nil {
+		return nil, err
+	}
+
+	s.Offset += int64(len(params.Content))
+
+	us := s.UploadSession
+	return &us, nil
+}
+
+func uploadCommit(params *UploadCommitOptions) (*FileEntry, error) {
+	s, err := session(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Offset != s.Size {
+		return nil, fmt.Errorf("upload is incomplete: received %d of %d bytes", s.Offset, s.Size)
+	}
+
+	hash, err := fileHash(s.tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Received data is corrupted, there is no point in keeping it.
+	if hash != s.hash {
+		removeSession(s)
+		return nil, ErrChecksumMismatch
+	}
+
+	if err := os.Chmod(s.tmp, s.mode); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(s.tmp, s.Path); err != nil {
+		return nil, err
+	}
+
+	removeSession(s)
+
+	return getInfo(s.Path)
+}
+
+func download(params *DownloadOptions) (*DownloadChunk, error) {
+	path, err := absPath(params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	blockSize, err := chunkSizeOf(params.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+
+	if params.Offset < 0 || params.Offset > fi.Size() {
+		return nil, fmt.Errorf("invalid offset %d for file of %d bytes", params.Offset, fi.Size())
+	}
+
+	if n := fi.Size() - params.Offset; n < blockSize {
+		blockSize = n
+	}
+
+	buf := make([]byte, blockSize)
+	if _, err := f.ReadAt(buf, params.Offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	chunk := &DownloadChunk{
+		Offset:  params.Offset,
+		Content: buf,
+		Hash:    hashOf(buf),
+		Size:    fi.Size(),
+		Mode:    fi.Mode().Perm(),
+	}
+
+	if params.Offset+blockSize == fi.Size() {
+		if _, err := f.Seek(0, 0); err != nil {
+			return nil, err
+		}
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return nil, err
+		}
+
+		chunk.FileHash = hex.EncodeToString(h.Sum(nil))
+	}
+
+	return chunk, nil
+}
+
+// session looks up upload session with a given ID.
+func session(id string) (*uploadSession, error) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	s, ok := sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	s.active = time.Now()
+	return s, nil
+}
+
+// removeSession removes the session together with its temporary file. Session
+// mutex must be held by the caller.
+func removeSession(s *uploadSession) {
+	sessionsMu.Lock()
+	delete(sessions, s.ID)
+	sessionsMu.Unlock()
+
+	os.Remove(s.tmp)
+}
+
+// sweep periodically removes upload sessions which were not used for longer
+// than UploadSessionTTL.
+func sweep() {
+	for {
+		time.Sleep(UploadSessionTTL / 4)
+		sweepSessions(time.Now())
+	}
+}
+
+// sweepSessions removes upload sessions inactive since UploadSessionTTL before
+// now, together with their temporary files. Temporary files not owned by any
+// session, e.g. left by klient process which was restarted, are removed when
+// they were not modified within the same period.
+func sweepSessions(now time.Time) {
+	var (
+		stale []*uploadSession
+		dirs  []string
+		owned = make(map[string]struct{})
+	)
+
+	sessionsMu.Lock()
+	for id, s := range sessions {
+		if now.Sub(s.active) > UploadSessionTTL {
+			delete(sessions, id)
+			stale = append(stale, s)
+		} else {
+			owned[s.tmp] = struct{}{}
+		}
+	}
+	for dir := range uploadDirs {
+		dirs = append(dirs, dir)
+	}
+	sessionsMu.Unlock()
+
+	for _, s := range stale {
+		s.mu.Lock()
+		os.Remove(s.tmp)
+		s.mu.Unlock()
+	}
+
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, uploadPrefix+"*"))
+		if err != nil {
+			continue
+		}
+
+		left := 0
+		for _, file := range files {
+			if _, ok := owned[file]; ok {
+				left++
+				continue
+			}
+
+			fi, err := os.Stat(file)
+			if err != nil {
+				continue
+			}
+
+			if now.Sub(fi.ModTime()) <= UploadSessionTTL || os.Remove(file) != nil {
+				left++
+			}
+		}
+
+		if left == 0 {
+			sessionsMu.Lock()
+			delete(uploadDirs, dir)
+			sessionsMu.Unlock()
+		}
+	}
+}
+
+// uploadID creates upload session ID from its destination and content.
+func uploadID(path string, size int64, hash string) string {
+	h := sha256.New()
+	io.WriteString(h, path+"\x00"+strconv.FormatInt(size, 10)+"\x00"+hash)
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// chunkSizeOf validates requested chunk size. Zero value means default size.
+func chunkSizeOf(n int64) (int64, error) {
+	switch {
+	case n == 0:
+		return DefaultChunkSize, nil
+	case n < 0 || n > MaxChunkSize:
+		return 0, fmt.Errorf("invalid chunk size: %d", n)
+	default:
+		return n, nil
+	}
+}
+
+// absPath resolves relative paths against user's home directory.
+func absPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(u.HomeDir, path), nil
+}
+
+func hashOf(p []byte) string {
+	sum := sha256.Sum256(p)
+	return hex.EncodeToString(sum[:])
+}
+
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
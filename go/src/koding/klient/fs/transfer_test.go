@@ -0,0 +1,223 @@
+package fs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUploadResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fs.upload")
+	if err != nil {
+		t.Fatalf("TempDir()=%s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := testContent(1000)
+	begin := &UploadBeginOptions{
+		Path:      filepath.Join(dir, "file.txt"),
+		Size:      int64(len(content)),
+		Hash:      hashOf(content),
+		ChunkSize: 300,
+	}
+
+	s, err := uploadBegin(begin)
+	if err != nil {
+		t.Fatalf("uploadBegin()=%s", err)
+	}
+
+	if s.Offset != 0 {
+		t.Fatalf("want offset = 0; got %d", s.Offset)
+	}
+
+	chunk := &UploadChunkOptions{
+		ID:      s.ID,
+		Content: content[:300],
+		Hash:    hashOf(content[:300]),
+	}
+
+	if s, err = uploadChunk(chunk); err != nil {
+		t.Fatalf("uploadChunk()=%s", err)
+	}
+
+	// Corrupted chunk must be rejected.
+	corrupted := &UploadChunkOptions{
+		ID:      s.ID,
+		Offset:  s.Offset,
+		Content: content[300:600],
+		Hash:    hashOf(content[:300]),
+	}
+
+	if _, err := uploadChunk(corrupted); err != ErrChecksumMismatch {
+		t.Fatalf("want err = %v; got %v", ErrChecksumMismatch, err)
+	}
+
+	// Simulate klient restart, the upload should be resumed from data
+	// stored on disk.
+	sessionsMu.Lock()
+	delete(sessions, s.ID)
+	sessionsMu.Unlock()
+
+	if s, err = uploadBegin(begin); err != nil {
+		t.Fatalf("uploadBegin()=%s", err)
+	}
+
+	if s.Offset != 300 {
+		t.Fatalf("want offset = 300; got %d", s.Offset)
+	}
+
+	for s.Offset < s.Size {
+		end := s.Offset + s.ChunkSize
+		if end > s.Size {
+			end = s.Size
+		}
+
+		chunk := &UploadChunkOptions{
+			ID:      s.ID,
+			Offset:  s.Offset,
+			Content: content[s.Offset:end],
+			Hash:    hashOf(content[s.Offset:end]),
+		}
+
+		if s, err = uploadChunk(chunk); err != nil {
+			t.Fatalf("uploadChunk()=%s", err)
+		}
+	}
+
+	entry, err := uploadCommit(&UploadCommitOptions{ID: s.ID})
+	if err != nil {
+		t.Fatalf("uploadCommit()=%s", err)
+	}
+
+	if entry.Size != int64(len(content)) {
+		t.Errorf("want size = %d; got %d", len(content), entry.Size)
+	}
+
+	got, err := ioutil.ReadFile(begin.Path)
+	if err != nil {
+		t.Fatalf("ReadFile()=%s", err)
+	}
+
+	if !bytes.Equal(got, content) {
+		t.Errorf("uploaded file content differs")
+	}
+
+	if fis, _ := ioutil.ReadDir(dir); len(fis) != 1 {
+		t.Errorf("want only uploaded file in %s; got %d files", dir, len(fis))
+	}
+
+	if _, err := uploadCommit(&UploadCommitOptions{ID: s.ID}); err != ErrSessionNotFound {
+		t.Errorf("want err = %v; got %v", ErrSessionNotFound, err)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	f, err := ioutil.TempFile("", "fs.download")
+	if err != nil {
+		t.Fatalf("TempFile()=%s", err)
+	}
+	defer os.Remove(f.Name())
+
+	content := testContent(1000)
+	if _, err := f.Write(content); err != nil {
+		t.Fatalf("Write()=%s", err)
+	}
+	f.Close()
+
+	var (
+		got    []byte
+		offset int64
+	)
+
+	for {
+		chunk, err := download(&DownloadOptions{Path: f.Name(), Offset: offset, BlockSize: 300})
+		if err != nil {
+			t.Fatalf("download()=%s", err)
+		}
+
+		if chunk.Hash != hashOf(chunk.Content) {
+			t.Fatalf("chunk at %d: invalid checksum", offset)
+		}
+
+		got = append(got, chunk.Content...)
+		offset += int64(len(chunk.Content))
+
+		if chunk.FileHash != "" {
+			if chunk.FileHash != hashOf(content) {
+				t.Fatalf("want file hash = %s; got %s", hashOf(content), chunk.FileHash)
+			}
+			break
+		}
+
+		if offset >= chunk.Size {
+			t.Fatalf("file hash was not sent with the last chunk")
+		}
+	}
+
+	if !bytes.Equal(got, content) {
+		t.Errorf("downloaded file content differs")
+	}
+}
+
+// testContent generates n bytes of data with no repeating chunks.
+func testContent(n int) []byte {
+	p := make([]byte, n)
+	for i := range p {
+		p[i] = byte(i % 251)
+	}
+
+	return p
+}
+
+func TestUploadSweep(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fs.upload")
+	if err != nil {
+		t.Fatalf("TempDir()=%s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := testContent(100)
+	s, err := uploadBegin(&UploadBeginOptions{
+		Path: filepath.Join(dir, "file.txt"),
+		Size: int64(len(content)),
+		Hash: hashOf(content),
+	})
+	if err != nil {
+		t.Fatalf("uploadBegin()=%s", err)
+	}
+
+	// Temporary file left by a session which no longer exists.
+	orphan := filepath.Join(dir, uploadPrefix+"orphan")
+	if err := ioutil.WriteFile(orphan, content, 0600); err != nil {
+		t.Fatalf("WriteFile()=%s", err)
+	}
+
+	tmp := filepath.Join(dir, uploadPrefix+s.ID)
+
+	sweepSessions(time.Now())
+
+	if _, err := session(s.ID); err != nil {
+		t.Fatalf("want active session to be kept; got %s", err)
+	}
+
+	for _, file := range []string{tmp, orphan} {
+		if _, err := os.Stat(file); err != nil {
+			t.Fatalf("want %s to be kept; got %s", file, err)
+		}
+	}
+
+	sweepSessions(time.Now().Add(UploadSessionTTL + time.Minute))
+
+	if _, err := session(s.ID); err != ErrSessionNotFound {
+		t.Fatalf("want err = %s; got %v", ErrSessionNotFound, err)
+	}
+
+	for _, file := range []string{tmp, orphan} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Fatalf("want %s to be removed; got %v", file, err)
+		}
+	}
+}
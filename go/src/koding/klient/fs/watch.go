@@ -0,0 +1,356 @@
+package fs
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/koding/kite"
+	"github.com/koding/kite/dnode"
+	"gopkg.in/fsnotify.v1"
+)
+
+// DefaultDebounce is the time events are collected for before they are sent
+// to the caller of fs.watch method.
+const DefaultDebounce = 100 * time.Millisecond
+
+// Types of events sent by fs.watch method.
+const (
+	WatchCreate = "create"
+	WatchModify = "modify"
+	WatchDelete = "delete"
+
+	// WatchRename is sent with the old file name. The new name is reported
+	// with WatchCreate event.
+	WatchRename = "rename"
+)
+
+// WatchOptions are the arguments of fs.watch method.
+type WatchOptions struct {
+	// Path is the root of watched tree. Relative paths are resolved against
+	// the home directory of klient user.
+	Path string `json:"path"`
+
+	// Include and Exclude are glob patterns matched against slash separated
+	// paths relative to watched root. Patterns without a slash match any
+	// path element. When Include is set, only matching files are reported.
+	// Excluded directories are not watched at all.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Debounce is the time events are collected for before they are sent.
+	// Multiple events of the same file are merged. Defaults to
+	// DefaultDebounce.
+	Debounce time.Duration `json:"debounce,omitempty"`
+
+	// OnEvent is called with a slice of collected *WatchEvent values.
+	OnEvent dnode.Function `json:"onEvent"`
+}
+
+// WatchEvent describes a change of a single file.
+type WatchEvent struct {
+	Type  string `json:"type"`
+	Path  string `json:"path"` // Absolute path of changed file.
+	IsDir bool   `json:"isDir,omitempty"`
+}
+
+// Watch is a handle of watched tree which is sent back to the caller of
+// fs.watch method. Its exported methods are callable remotely.
+type Watch struct {
+	Path string `json:"path"`
+
+	// never expose the following fields as we return them back to the client.
+	w        *fsnotify.Watcher
+	opts     *WatchOptions
+	pending  map[string]*WatchEvent
+	order    []string // paths of pending events in arrival order.
+	once     sync.Once
+	unsubC   chan struct{}
+	debounce time.Duration
+}
+
+// WatchTree starts watching a tree recursively and streams its changes to
+// the caller until it unsubscribes or disconnects.
+func WatchTree(r *kite.Request) (interface{}, error) {
+	var params WatchOptions
+	if r.Args == nil || r.Args.One().Unmarshal(&params) != nil || params.Path == "" || !params.OnEvent.IsValid() {
+		return nil, errors.New("{ path: [string], onEvent: [function] }")
+	}
+
+	w, err := NewWatch(&params)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Client.OnDisconnect(w.stop)
+
+	return w, nil
+}
+
+// NewWatch starts watching a tree described by the given options.
+func NewWatch(opts *WatchOptions) (*Watch, error) {
+	root, err := absPath(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return nil, errors.New(root + " is not a directory")
+	}
+
+	w := &Watch{
+		Path:     root,
+		opts:     opts,
+		pending:  make(map[string]*WatchEvent),
+		unsubC:   make(chan struct{}),
+		debounce: opts.Debounce,
+	}
+
+	if w.debounce <= 0 {
+		w.debounce = DefaultDebounce
+	}
+
+	if w.w, err = fsnotify.NewWatcher(); err != nil {
+		return nil, err
+	}
+
+	if err := w.add(root, false); err != nil {
+		w.w.Close()
+		return nil, err
+	}
+
+	go w.loop()
+
+	return w, nil
+}
+
+// Unsubscribe stops watching the tree.
+func (w *Watch) Unsubscribe(*dnode.Partial) {
+	w.stop()
+}
+
+func (w *Watch) stop() {
+	w.once.Do(func() {
+		close(w.unsubC)
+		w.w.Close()
+	})
+}
+
+func (w *Watch) loop() {
+	var (
+		t      *time.Timer
+		flushC <-chan time.Time
+	)
+
+	for {
+		select {
+		case ev, ok := <-w.w.Events:
+			if !ok {
+				return
+			}
+
+			w.handle(ev)
+
+			if t == nil {
+				t = time.NewTimer(w.debounce)
+				flushC = t.C
+			}
+		case err, ok := <-w.w.Errors:
+			if !ok {
+				return
+			}
+
+			log.Println("watch error:", err)
+		case <-flushC:
+			t, flushC = nil, nil
+
+			// Caller is gone, there is no point in watching anymore.
+			if err := w.flush(); err != nil {
+				w.stop()
+			}
+		case <-w.unsubC:
+			if t != nil {
+				t.Stop()
+			}
+			return
+		}
+	}
+}
+
+// handle translates fsnotify event into a pending watch event.
+func (w *Watch) handle(ev fsnotify.Event) {
+	rel, err := filepath.Rel(w.Path, ev.Name)
+	if err != nil || w.excluded(rel) {
+		return
+	}
+
+	var typ string
+	switch {
+	case ev.Op&fsnotify.Create != 0:
+		typ = WatchCreate
+	case ev.Op&fsnotify.Write != 0:
+		typ = WatchModify
+	case ev.Op&fsnotify.Remove != 0:
+		typ = WatchDelete
+	case ev.Op&fsnotify.Rename != 0:
+		typ = WatchRename
+	default:
+		// Chmod events are not reported.
+		return
+	}
+
+	isDir := false
+	if typ == WatchCreate {
+		if fi, err := os.Lstat(ev.Name); err == nil && fi.IsDir() {
+			isDir = true
+
+			// Files could have been created in the new directory before
+			// it was added to the watcher, report them too.
+			if err := w.add(ev.Name, true); err != nil {
+				log.Println("watch add error:", err)
+			}
+		}
+	}
+
+	w.push(&WatchEvent{Type: typ, Path: ev.Name, IsDir: isDir})
+}
+
+// add adds a given directory and all its subdirectories to the watcher. If
+// report is true, create events are generated for walked files.
+func (w *Watch) add(dir string, report bool) error {
+	return filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			// File could have been removed in the meantime.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(w.Path, path)
+		if err != nil {
+			return err
+		}
+
+		if rel != "." && w.excluded(rel) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if report && path != dir {
+			w.push(&WatchEvent{Type: WatchCreate, Path: path, IsDir: fi.IsDir()})
+		}
+
+		if fi.IsDir() {
+			return w.w.Add(path)
+		}
+
+		return nil
+	})
+}
+
+// push adds the event to pending ones merging it with the previous event of
+// the same file.
+func (w *Watch) push(ev *WatchEvent) {
+	if !ev.IsDir && len(w.opts.Include) != 0 {
+		rel, err := filepath.Rel(w.Path, ev.Path)
+		if err != nil || !matchGlobs(w.opts.Include, filepath.ToSlash(rel)) {
+			return
+		}
+	}
+
+	prev, ok := w.pending[ev.Path]
+	if !ok {
+		w.pending[ev.Path] = ev
+		w.order = append(w.order, ev.Path)
+		return
+	}
+
+	switch typ := mergeWatchEvents(prev.Type, ev.Type); typ {
+	case "":
+		// File was created and removed, there is nothing to report.
+		delete(w.pending, ev.Path)
+
+		for i := range w.order {
+			if w.order[i] == ev.Path {
+				w.order = append(w.order[:i], w.order[i+1:]...)
+				break
+			}
+		}
+	default:
+		prev.Type = typ
+		prev.IsDir = prev.IsDir || ev.IsDir
+	}
+}
+
+// flush sends all pending events to the caller.
+func (w *Watch) flush() error {
+	events := make([]*WatchEvent, 0, len(w.order))
+	for _, path := range w.order {
+		events = append(events, w.pending[path])
+	}
+
+	w.pending = make(map[string]*WatchEvent)
+	w.order = nil
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	return w.opts.OnEvent.Call(events)
+}
+
+// excluded checks if a given path, relative to watched root, matches any of
+// exclude patterns.
+func (w *Watch) excluded(rel string) bool {
+	return len(w.opts.Exclude) != 0 && matchGlobs(w.opts.Exclude, filepath.ToSlash(rel))
+}
+
+// mergeWatchEvents gives the type of event which is equivalent to the given
+// two events of the same file. Empty string means the events cancel out.
+func mergeWatchEvents(prev, next string) string {
+	switch {
+	case prev == WatchCreate && next == WatchModify:
+		return WatchCreate
+	case prev == WatchCreate && (next == WatchDelete || next == WatchRename):
+		return ""
+	case (prev == WatchDelete || prev == WatchRename) && next == WatchCreate:
+		return WatchModify
+	default:
+		return next
+	}
+}
+
+// matchGlobs checks if a slash separated path matches any of the given glob
+// patterns. Patterns without a slash are matched against every path element.
+func matchGlobs(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.Trim(pattern, "/")
+
+		if strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(pattern, rel); ok {
+				return true
+			}
+			continue
+		}
+
+		for _, elem := range strings.Split(rel, "/") {
+			if ok, _ := filepath.Match(pattern, elem); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,150 @@
+package fs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/koding/kite/dnode"
+)
+
+type callerFunc func(...interface{}) error
+
+func (fn callerFunc) Call(args ...interface{}) error { return fn(args...) }
+
+func TestWatch(t *testing.T) {
+	root, err := ioutil.TempDir("", "fs.watch")
+	if err != nil {
+		t.Fatalf("TempDir()=%s", err)
+	}
+	defer os.RemoveAll(root)
+
+	if err := os.Mkdir(filepath.Join(root, "node_modules"), 0755); err != nil {
+		t.Fatalf("Mkdir()=%s", err)
+	}
+
+	eventsC := make(chan []*WatchEvent, 16)
+	opts := &WatchOptions{
+		Path:     root,
+		Include:  []string{"*.go"},
+		Exclude:  []string{"node_modules"},
+		Debounce: 200 * time.Millisecond,
+		OnEvent: dnode.Function{Caller: callerFunc(func(args ...interface{}) error {
+			eventsC <- args[0].([]*WatchEvent)
+			return nil
+		})},
+	}
+
+	w, err := NewWatch(opts)
+	if err != nil {
+		t.Fatalf("NewWatch()=%s", err)
+	}
+	defer w.Unsubscribe(nil)
+
+	files := map[string]string{
+		"main.go":             "package main",
+		"README.md":           "readme",
+		"node_modules/lib.go": "package lib",
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile()=%s", err)
+		}
+	}
+
+	// Files created in a new directory must be reported too.
+	if err := os.MkdirAll(filepath.Join(root, "pkg", "sub"), 0755); err != nil {
+		t.Fatalf("MkdirAll()=%s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "pkg", "sub", "sub.go"), nil, 0644); err != nil {
+		t.Fatalf("WriteFile()=%s", err)
+	}
+
+	want := []string{
+		"create main.go",
+		"create pkg",
+		"create pkg/sub",
+		"create pkg/sub/sub.go",
+	}
+
+	if got := collectEvents(t, root, eventsC, len(want)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("want events = %v; got %v", want, got)
+	}
+
+	if err := os.Rename(filepath.Join(root, "main.go"), filepath.Join(root, "app.go")); err != nil {
+		t.Fatalf("Rename()=%s", err)
+	}
+
+	want = []string{
+		"create app.go",
+		"rename main.go",
+	}
+
+	if got := collectEvents(t, root, eventsC, len(want)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("want events = %v; got %v", want, got)
+	}
+
+	w.Unsubscribe(nil)
+
+	if err := ioutil.WriteFile(filepath.Join(root, "app.go"), []byte("package app"), 0644); err != nil {
+		t.Fatalf("WriteFile()=%s", err)
+	}
+
+	select {
+	case events := <-eventsC:
+		t.Fatalf("want no events after unsubscribe; got %d", len(events))
+	case <-time.After(2 * opts.Debounce):
+	}
+}
+
+func TestMergeWatchEvents(t *testing.T) {
+	tests := []struct {
+		prev, next string
+		want       string
+	}{
+		{WatchCreate, WatchModify, WatchCreate},
+		{WatchCreate, WatchDelete, ""},
+		{WatchCreate, WatchRename, ""},
+		{WatchDelete, WatchCreate, WatchModify},
+		{WatchRename, WatchCreate, WatchModify},
+		{WatchModify, WatchDelete, WatchDelete},
+		{WatchModify, WatchModify, WatchModify},
+	}
+
+	for _, test := range tests {
+		if got := mergeWatchEvents(test.prev, test.next); got != test.want {
+			t.Errorf("%s+%s: want %q; got %q", test.prev, test.next, test.want, got)
+		}
+	}
+}
+
+// collectEvents waits for n events and returns them as sorted "type path"
+// strings.
+func collectEvents(t *testing.T, root string, eventsC <-chan []*WatchEvent, n int) []string {
+	var got []string
+
+	for len(got) < n {
+		select {
+		case events := <-eventsC:
+			for _, ev := range events {
+				rel, err := filepath.Rel(root, ev.Path)
+				if err != nil {
+					t.Fatalf("Rel()=%s", err)
+				}
+
+				got = append(got, ev.Type+" "+filepath.ToSlash(rel))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+
+	sort.Strings(got)
+
+	return got
+}
@@ -15,12 +15,26 @@ type Client interface {
 	SSHAddKeys(string, ...string) error
 
 	// MountHeadIndex returns the number and the overall size of files in a
-	// given remote directory.
-	MountHeadIndex(string) (string, int, int64, error)
+	// given remote directory. Files matched by ignore files stored in the
+	// directory or by provided patterns are not counted.
+	MountHeadIndex(string, []string) (string, int, int64, error)
 
 	// MountGetIndex returns an index that describes the current state of remote
-	// directory.
-	MountGetIndex(string) (*index.Index, error)
+	// directory. Files matched by ignore files stored in the directory or by
+	// provided patterns are not indexed.
+	MountGetIndex(string, []string) (*index.Index, error)
+
+	// MountGetDelta returns a delta that transforms a file described by given
+	// chunks into the current state of remote file. The file path is relative
+	// to provided mount root. Nil delta means that remote file does not exist.
+	MountGetDelta(string, string, []index.Chunk) (*index.Delta, error)
+
+	// MountPatch applies provided delta to remote file and returns the entry
+	// of patched file. The file path is relative to provided mount root. Nil
+	// delta removes remote file. Unless forced, the patch
+	// is applied only when remote file matches provided base entry, otherwise
+	// index.ErrConflict is returned together with current remote file entry.
+	MountPatch(string, string, *index.Delta, *index.Entry, bool) (*index.Entry, error)
 
 	// DiskBlocks gets basic information about volume pointed by provided path.
 	DiskBlocks(string) (uint64, uint64, uint64, uint64, error)
@@ -111,13 +111,13 @@ func (c *Client) SSHAddKeys(_ string, _ ...string) error {
 }
 
 // MountHeadIndex gets basic info about the index generated from local path.
-func (c *Client) MountHeadIndex(path string) (string, int, int64, error) {
+func (c *Client) MountHeadIndex(path string, ignore []string) (string, int, int64, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return "", 0, 0, err
 	}
 
-	idx, err := c.MountGetIndex(absPath)
+	idx, err := c.MountGetIndex(absPath, ignore)
 	if err != nil {
 		return "", 0, 0, err
 	}
@@ -127,8 +127,43 @@ func (c *Client) MountHeadIndex(path string) (string, int, int64, error) {
 
 // MountGetIndex creates an index from provided local path. Generated index is
 // not cached.
-func (c *Client) MountGetIndex(path string) (*index.Index, error) {
-	return index.NewIndexFiles(path)
+func (c *Client) MountGetIndex(path string, ignore []string) (*index.Index, error) {
+	ign, err := index.ReadIgnore(path, ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.NewIndexFilesIgnore(path, ign)
+}
+
+// MountGetDelta creates a delta of local file.
+func (c *Client) MountGetDelta(root, path string, chunks []index.Chunk) (*index.Delta, error) {
+	resp, err := index.GetDelta(&index.DeltaRequest{Root: root, Path: path, Chunks: chunks})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Delta, nil
+}
+
+// MountPatch applies provided delta to local file.
+func (c *Client) MountPatch(root, path string, d *index.Delta, base *index.Entry, force bool) (*index.Entry, error) {
+	resp, err := index.Patch(&index.PatchRequest{
+		Root:  root,
+		Path:  path,
+		Delta: d,
+		Base:  base,
+		Force: force,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Conflict {
+		return resp.Entry, index.ErrConflict
+	}
+
+	return resp.Entry, nil
 }
 
 // DiskBlocks gets faked information about file-system.
@@ -57,12 +57,22 @@ func (*Disconnected) SSHAddKeys(_ string, _ ...string) error {
 }
 
 // MountHeadIndex always returns ErrDisconnected error.
-func (*Disconnected) MountHeadIndex(_ string) (string, int, int64, error) {
+func (*Disconnected) MountHeadIndex(_ string, _ []string) (string, int, int64, error) {
 	return "", 0, 0, ErrDisconnected
 }
 
 // MountGetIndex always returns ErrDisconnected error.
-func (*Disconnected) MountGetIndex(_ string) (*index.Index, error) {
+func (*Disconnected) MountGetIndex(_ string, _ []string) (*index.Index, error) {
+	return nil, ErrDisconnected
+}
+
+// MountGetDelta always returns ErrDisconnected error.
+func (*Disconnected) MountGetDelta(_, _ string, _ []index.Chunk) (*index.Delta, error) {
+	return nil, ErrDisconnected
+}
+
+// MountPatch always returns ErrDisconnected error.
+func (*Disconnected) MountPatch(_, _ string, _ *index.Delta, _ *index.Entry, _ bool) (*index.Entry, error) {
 	return nil, ErrDisconnected
 }
 
@@ -54,9 +54,9 @@ func (s *Supervised) SSHAddKeys(username string, keys ...string) (err error) {
 // MountHeadIndex calls registered Client's MountHeadIndex method and returns
 // its result if it's not produced by Disconnected client. If it is, this
 // function will wait until valid client is available or timeout is reached.
-func (s *Supervised) MountHeadIndex(path string) (absPath string, count int, diskSize int64, err error) {
+func (s *Supervised) MountHeadIndex(path string, ignore []string) (absPath string, count int, diskSize int64, err error) {
 	fn := func(c Client) error {
-		absPath, count, diskSize, err = c.MountHeadIndex(path)
+		absPath, count, diskSize, err = c.MountHeadIndex(path, ignore)
 		return err
 	}
 
@@ -67,9 +67,35 @@ func (s *Supervised) MountHeadIndex(path string) (absPath string, count int, dis
 // MountGetIndex calls registered Client's MountGetIndex method and returns its
 // result if it's not produced by Disconnected client. If it is, this function
 // will wait until valid client is available or timeout is reached.
-func (s *Supervised) MountGetIndex(path string) (idx *index.Index, err error) {
+func (s *Supervised) MountGetIndex(path string, ignore []string) (idx *index.Index, err error) {
 	fn := func(c Client) error {
-		idx, err = c.MountGetIndex(path)
+		idx, err = c.MountGetIndex(path, ignore)
+		return err
+	}
+
+	err = s.call(fn)
+	return
+}
+
+// MountGetDelta calls registered Client's MountGetDelta method and returns its
+// result if it's not produced by Disconnected client. If it is, this function
+// will wait until valid client is available or timeout is reached.
+func (s *Supervised) MountGetDelta(root, path string, chunks []index.Chunk) (d *index.Delta, err error) {
+	fn := func(c Client) error {
+		d, err = c.MountGetDelta(root, path, chunks)
+		return err
+	}
+
+	err = s.call(fn)
+	return
+}
+
+// MountPatch calls registered Client's MountPatch method and returns its
+// result if it's not produced by Disconnected client. If it is, this function
+// will wait until valid client is available or timeout is reached.
+func (s *Supervised) MountPatch(root, path string, d *index.Delta, base *index.Entry, force bool) (entry *index.Entry, err error) {
+	fn := func(c Client) error {
+		entry, err = c.MountPatch(root, path, d, base, force)
 		return err
 	}
 
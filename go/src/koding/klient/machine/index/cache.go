@@ -32,25 +32,32 @@ type Cached struct {
 // function stores the resulting index in temporary file in order to not
 // recompute it each time when the index is requested.
 func (c *Cached) GetCachedIndex(root string) (*Index, error) {
+	return c.GetCachedIndexIgnore(root, nil)
+}
+
+// GetCachedIndexIgnore works like GetCachedIndex but files matched by provided
+// ignore are not indexed. Indexes created with different ignore patterns are
+// cached separately.
+func (c *Cached) GetCachedIndexIgnore(root string, ign *Ignore) (*Index, error) {
 	var cs ChangeSlice
 
 	// Load or create index.
-	idx, path, createdAt, err := c.getCachedIndex(root)
+	idx, path, createdAt, err := c.getCachedIndex(root, ign)
 	if err != nil {
 		// Generate new index.
-		if idx, err = NewIndexFiles(root); err != nil {
+		if idx, err = NewIndexFilesIgnore(root, ign); err != nil {
 			return nil, err
 		}
 	} else if createdAt.IsZero() || time.Since(createdAt) > c.Rescan {
 		// Update loaded index.
-		cs = idx.Compare(root)
+		cs = idx.CompareIgnore(root, ign)
 		idx.Apply(root, cs)
 	}
 
 	// If index changed or was generated, save it.
 	if path == "" || len(cs) != 0 {
 		if path == "" {
-			if path, err = c.createTempPath(root); err != nil {
+			if path, err = c.createTempPath(root, ign); err != nil {
 				return nil, err
 			}
 		}
@@ -66,7 +73,13 @@ func (c *Cached) GetCachedIndex(root string) (*Index, error) {
 // HeadCachedIndex gets cached index or creates a new one and returns the number
 // and the overall size of stored files.
 func (c *Cached) HeadCachedIndex(root string) (count int, diskSize int64, err error) {
-	idx, err := c.GetCachedIndex(root)
+	return c.HeadCachedIndexIgnore(root, nil)
+}
+
+// HeadCachedIndexIgnore works like HeadCachedIndex but files matched by
+// provided ignore are not counted.
+func (c *Cached) HeadCachedIndexIgnore(root string, ign *Ignore) (count int, diskSize int64, err error) {
+	idx, err := c.GetCachedIndexIgnore(root, ign)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -76,8 +89,8 @@ func (c *Cached) HeadCachedIndex(root string) (count int, diskSize int64, err er
 
 // getCachedIndex looks up for index stored in one of temporary directories.
 // If provided index is found, it will be loaded to memory and returned.
-func (c *Cached) getCachedIndex(root string) (idx *Index, path string, createdAt time.Time, err error) {
-	name := hashSHA1(root)
+func (c *Cached) getCachedIndex(root string, ign *Ignore) (idx *Index, path string, createdAt time.Time, err error) {
+	name := cacheName(root, ign)
 	// Find index in temporary directories.
 	for _, tempdir := range c.indexTempDirs(-1) {
 		path = filepath.Join(tempdir, name)
@@ -111,7 +124,7 @@ func (c *Cached) getCachedIndex(root string) (idx *Index, path string, createdAt
 }
 
 // createTempPath creates a valid path to index file.
-func (c *Cached) createTempPath(root string) (path string, err error) {
+func (c *Cached) createTempPath(root string, ign *Ignore) (path string, err error) {
 	if dirs := c.indexTempDirs(1); len(dirs) > 0 {
 		path = dirs[0]
 	} else {
@@ -120,7 +133,7 @@ func (c *Cached) createTempPath(root string) (path string, err error) {
 		}
 	}
 
-	return filepath.Join(path, hashSHA1(root)), nil
+	return filepath.Join(path, cacheName(root, ign)), nil
 }
 
 // indexTempDirs reads all directory names that could be created by index cache.
@@ -163,6 +176,15 @@ func (c *Cached) tempDir() string {
 	return c.TempDir()
 }
 
+// cacheName creates index file name for a given root and ignore patterns.
+func cacheName(root string, ign *Ignore) string {
+	if patterns := ign.String(); patterns != "" {
+		return hashSHA1(root + "\x00" + patterns)
+	}
+
+	return hashSHA1(root)
+}
+
 // hashSHA1 converts a string to hex representation of its SHA-1 checksum.
 func hashSHA1(val string) string {
 	h := sha1.Sum([]byte(val))
@@ -0,0 +1,157 @@
+package index
+
+import (
+	"crypto/sha1"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// Chunk size boundaries used by content-defined chunking. The average size
+// must be a power of two.
+const (
+	ChunkMinSize = 16 * 1024
+	ChunkAvgSize = 64 * 1024
+	ChunkMaxSize = 256 * 1024
+
+	chunkMask = ChunkAvgSize - 1
+)
+
+// ChunkThreshold defines the minimal file size for which index entries carry
+// chunk signatures. Smaller files are always transferred in their entirety so
+// there is no need to keep their signatures. Non-positive value disables
+// chunk signatures in index entries.
+var ChunkThreshold int64 = 1024 * 1024
+
+// Chunk describes a single content-defined part of a file.
+type Chunk struct {
+	Offset int64  `json:"o"` // Offset of the chunk in the file.
+	Size   int64  `json:"s"` // Size of the chunk.
+	Hash   []byte `json:"h"` // SHA-1 checksum of chunk content.
+}
+
+// gearTable stores random values used by the rolling hash. The values must be
+// the same on both sides of synchronization, so they are generated from
+// a constant seed.
+var gearTable [256]uint64
+
+func init() {
+	seed := uint64(0x6b6f64696e67) // "koding"
+
+	// SplitMix64 generator.
+	for i := range gearTable {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gearTable[i] = z ^ (z >> 31)
+	}
+}
+
+// chunker is an io.Writer which splits written data into content-defined
+// chunks. Chunk boundaries are found with gear rolling hash, thus inserting
+// or removing data in the middle of the file changes only the chunks around
+// modified region.
+type chunker struct {
+	h    hash.Hash // checksum of current chunk.
+	gear uint64    // rolling hash value.
+	off  int64     // offset of current chunk.
+	n    int64     // size of current chunk.
+
+	chunks []Chunk
+}
+
+func newChunker() *chunker {
+	return &chunker{
+		h: sha1.New(),
+	}
+}
+
+// Write implements io.Writer interface. It never fails.
+func (c *chunker) Write(p []byte) (int, error) {
+	start := 0
+
+	for i, b := range p {
+		c.gear = (c.gear << 1) + gearTable[b]
+		c.n++
+
+		if c.n >= ChunkMaxSize || (c.n >= ChunkMinSize && c.gear&chunkMask == 0) {
+			c.h.Write(p[start : i+1])
+			c.cut()
+			start = i + 1
+		}
+	}
+
+	c.h.Write(p[start:])
+
+	return len(p), nil
+}
+
+// Chunks returns all chunks written so far including the last, partial one.
+func (c *chunker) Chunks() []Chunk {
+	if c.n != 0 {
+		c.cut()
+	}
+
+	return c.chunks
+}
+
+func (c *chunker) cut() {
+	c.chunks = append(c.chunks, Chunk{
+		Offset: c.off,
+		Size:   c.n,
+		Hash:   c.h.Sum(nil),
+	})
+
+	c.off += c.n
+	c.n, c.gear = 0, 0
+	c.h.Reset()
+}
+
+// ReadChunks splits the content of a given file into chunks and computes their
+// signatures.
+func ReadChunks(path string) ([]Chunk, error) {
+	_, chunks, err := readSums(path, true)
+	return chunks, err
+}
+
+// readSums computes CRC-32 checksum of a given file content. If chunked
+// argument is set, file chunk signatures are computed during the same read.
+func readSums(path string, chunked bool) ([]byte, []Chunk, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var (
+		hash = crc32.NewIEEE()
+		w    = io.Writer(hash)
+		c    *chunker
+	)
+
+	if chunked {
+		c = newChunker()
+		w = io.MultiWriter(hash, c)
+	}
+
+	bufp := copyBufPool.Get().(*[]byte)
+	defer copyBufPool.Put(bufp)
+
+	if _, err := io.CopyBuffer(w, file, *bufp); err != nil {
+		return nil, nil, err
+	}
+
+	if c != nil {
+		return hash.Sum(nil), c.Chunks(), nil
+	}
+
+	return hash.Sum(nil), nil, nil
+}
+
+// isChunked tells whether file of the given size should have its chunk
+// signatures stored in index entry.
+func isChunked(size int64) bool {
+	return ChunkThreshold > 0 && size >= ChunkThreshold
+}
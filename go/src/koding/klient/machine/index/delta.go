@@ -0,0 +1,332 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// MaxDeltaSize is the maximum number of data bytes a single delta can carry.
+// It limits the size of messages exchanged during file synchronization.
+const MaxDeltaSize = 16 * 1024 * 1024
+
+// ErrDeltaTooLarge indicates that the delta would carry more than MaxDeltaSize
+// data bytes.
+var ErrDeltaTooLarge = fmt.Errorf("delta exceeds %d bytes", MaxDeltaSize)
+
+// Limiter limits the rate at which delta data bytes are transferred.
+type Limiter interface {
+	// Wait blocks until n data bytes can be transferred.
+	Wait(n int64)
+}
+
+// LimiterFunc is an adapter that allows to use ordinary functions as delta
+// limiters.
+type LimiterFunc func(int64)
+
+// Wait calls f(n).
+func (f LimiterFunc) Wait(n int64) { f(n) }
+
+// DeltaOp describes a single operation used to rebuild a file from its base
+// version. When Data is nil, Size bytes starting at Offset are copied from
+// the base file. Otherwise, Data is written as is.
+type DeltaOp struct {
+	Offset int64  `json:"o,omitempty"`
+	Size   int64  `json:"s,omitempty"`
+	Data   []byte `json:"d,omitempty"`
+}
+
+// Delta describes how to transform base file into its new version.
+type Delta struct {
+	// Entry describes the resulting file. Its size and hash are used to
+	// validate the patched file.
+	Entry *Entry `json:"entry"`
+
+	// Ops stores operations which create the file. Ops are empty for
+	// directories.
+	Ops []DeltaOp `json:"ops,omitempty"`
+}
+
+// Size returns the number of data bytes that need to be transferred in order
+// to apply the delta.
+func (d *Delta) Size() (n int64) {
+	for i := range d.Ops {
+		n += int64(len(d.Ops[i].Data))
+	}
+
+	return n
+}
+
+// NewDelta creates a delta that transforms a file described by base chunks
+// into the current state of a file stored under path argument. Chunks of the
+// file which are present in base are not copied to delta. If base is empty,
+// delta will contain entire file content. ErrDeltaTooLarge is returned when
+// the delta data would exceed MaxDeltaSize.
+func NewDelta(path string, base []Chunk) (*Delta, error) {
+	return NewDeltaLimit(path, base, nil)
+}
+
+// NewDeltaLimit works like NewDelta but, if provided limiter is not nil, it
+// waits for it before each chunk of file data is read into the delta.
+func NewDeltaLimit(path string, base []Chunk, l Limiter) (*Delta, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		CTime: ctime(info),
+		MTime: info.ModTime().UnixNano(),
+		Mode:  info.Mode(),
+		Size:  info.Size(),
+	}
+
+	if info.IsDir() {
+		return &Delta{Entry: entry}, nil
+	}
+
+	sum, chunks, err := readSums(path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Hash = sum
+	if isChunked(entry.Size) {
+		entry.Chunks = chunks
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	known := make(map[string]*Chunk, len(base))
+	for i := range base {
+		known[string(base[i].Hash)] = &base[i]
+	}
+
+	d := &Delta{
+		Entry: entry,
+	}
+
+	var size int64
+	for i := range chunks {
+		var last *DeltaOp
+		if n := len(d.Ops); n != 0 {
+			last = &d.Ops[n-1]
+		}
+
+		if c, ok := known[string(chunks[i].Hash)]; ok && c.Size == chunks[i].Size {
+			// Merge continuous copy operations.
+			if last != nil && last.Data == nil && last.Offset+last.Size == c.Offset {
+				last.Size += c.Size
+			} else {
+				d.Ops = append(d.Ops, DeltaOp{Offset: c.Offset, Size: c.Size})
+			}
+			continue
+		}
+
+		if size += chunks[i].Size; size > MaxDeltaSize {
+			return nil, ErrDeltaTooLarge
+		}
+
+		if l != nil {
+			l.Wait(chunks[i].Size)
+		}
+
+		data := make([]byte, chunks[i].Size)
+		if _, err := f.ReadAt(data, chunks[i].Offset); err != nil {
+			return nil, err
+		}
+
+		// Merge continuous data operations.
+		if last != nil && last.Data != nil {
+			last.Data = append(last.Data, data...)
+		} else {
+			d.Ops = append(d.Ops, DeltaOp{Data: data})
+		}
+	}
+
+	return d, nil
+}
+
+// ApplyDelta rebuilds a file stored under path argument according to provided
+// delta. The existing file is used as a base. The file is replaced atomically
+// and only when its new content matches delta entry checksum. The returned
+// entry describes the patched file.
+func ApplyDelta(path string, d *Delta) (*Entry, error) {
+	return ApplyDeltaLimit(path, d, nil)
+}
+
+// ApplyDeltaLimit works like ApplyDelta but, if provided limiter is not nil,
+// delta data is written in parts and the limiter is waited for before each of
+// them.
+func ApplyDeltaLimit(path string, d *Delta, l Limiter) (*Entry, error) {
+	if d == nil || d.Entry == nil {
+		return nil, errors.New("invalid empty delta")
+	}
+
+	if d.Entry.Mode.IsDir() {
+		if err := os.MkdirAll(path, d.Entry.Mode.Perm()); err != nil {
+			return nil, err
+		}
+
+		return newEntryDelta(path, d)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	base, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if base != nil {
+		defer base.Close()
+	}
+
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".kd")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	hash := crc32.NewIEEE()
+	w := io.MultiWriter(f, hash)
+
+	// Only data carried by the delta is limited, base file chunks are local.
+	dw := w
+	if l != nil {
+		dw = &limitWriter{w: w, l: l}
+	}
+
+	var n int64
+	for i := range d.Ops {
+		var m int64
+		if op := &d.Ops[i]; op.Data != nil {
+			m, err = io.Copy(dw, bytes.NewReader(op.Data))
+		} else if base == nil {
+			err = errors.New("delta refers to missing base file")
+		} else if m, err = io.Copy(w, io.NewSectionReader(base, op.Offset, op.Size)); err == nil && m != op.Size {
+			err = fmt.Errorf("base file is too short: want %d bytes at %d, got %d", op.Size, op.Offset, m)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		n += m
+	}
+
+	if n != d.Entry.Size || !bytes.Equal(hash.Sum(nil), d.Entry.Hash) {
+		err = fmt.Errorf("patched file checksum mismatch for %s", path)
+		return nil, err
+	}
+
+	if err = f.Chmod(d.Entry.Mode.Perm()); err != nil {
+		return nil, err
+	}
+
+	if err = f.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = os.Rename(f.Name(), path); err != nil {
+		return nil, err
+	}
+
+	return newEntryDelta(path, d)
+}
+
+// Diverged checks if a file stored under path argument differs from its known
+// entry. Nil entry means that the file should not exist. File content checksum
+// is computed only when file size matches the entry but its modification time
+// doesn't.
+func Diverged(path string, known *Entry) (bool, error) {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return known != nil, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case known == nil:
+		return true, nil
+	case info.IsDir() || known.Mode.IsDir():
+		return info.IsDir() != known.Mode.IsDir(), nil
+	case info.Size() != known.Size:
+		return true, nil
+	case info.ModTime().UnixNano() == known.MTime:
+		return false, nil
+	}
+
+	sum, _, err := readSums(path, false)
+	if err != nil {
+		return false, err
+	}
+
+	return !bytes.Equal(sum, known.Hash), nil
+}
+
+// limitWriter is a writer which waits for its limiter before writing each part
+// of provided data.
+type limitWriter struct {
+	w io.Writer
+	l Limiter
+}
+
+// Write writes p to underlying writer in parts no larger than ChunkMinSize.
+func (lw *limitWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		part := p
+		if len(part) > ChunkMinSize {
+			part = part[:ChunkMinSize]
+		}
+
+		lw.l.Wait(int64(len(part)))
+
+		m, err := lw.w.Write(part)
+		if n += m; err != nil {
+			return n, err
+		}
+
+		p = p[len(part):]
+	}
+
+	return n, nil
+}
+
+// newEntryDelta creates an entry of a file patched with the given delta.
+// File content checksums are taken from the delta, so the file is not
+// reread.
+func newEntryDelta(path string, d *Delta) (*Entry, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		CTime:  ctime(info),
+		MTime:  info.ModTime().UnixNano(),
+		Mode:   info.Mode(),
+		Size:   info.Size(),
+		Hash:   d.Entry.Hash,
+		Chunks: d.Entry.Chunks,
+	}, nil
+}
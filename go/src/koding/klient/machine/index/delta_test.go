@@ -0,0 +1,273 @@
+package index_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"koding/klient/machine/index"
+)
+
+func TestDelta(t *testing.T) {
+	content := make([]byte, 4*1024*1024)
+	rand.New(rand.NewSource(0xD)).Read(content)
+
+	tests := map[string]struct {
+		Edit    func([]byte) []byte
+		MaxSent int64 // Maximum number of data bytes in delta.
+	}{
+		"unchanged": {
+			Edit:    func(b []byte) []byte { return b },
+			MaxSent: 0,
+		},
+		"overwrite in the middle": {
+			Edit: func(b []byte) []byte {
+				b = append([]byte(nil), b...)
+				copy(b[2*1024*1024:], "koding")
+				return b
+			},
+			MaxSent: 2 * index.ChunkMaxSize,
+		},
+		"insert at the beginning": {
+			Edit: func(b []byte) []byte {
+				return append([]byte("koding"), b...)
+			},
+			MaxSent: 2 * index.ChunkMaxSize,
+		},
+		"truncate": {
+			Edit: func(b []byte) []byte {
+				return b[:3*1024*1024]
+			},
+			MaxSent: index.ChunkMaxSize,
+		},
+	}
+
+	for name, test := range tests {
+		// capture range variable here
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root, err := ioutil.TempDir("", "index.delta")
+			if err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+			defer os.RemoveAll(root)
+
+			var (
+				base    = filepath.Join(root, "base.bin")
+				changed = filepath.Join(root, "changed.bin")
+				edited  = test.Edit(content)
+			)
+
+			if err := ioutil.WriteFile(base, content, 0644); err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+			if err := ioutil.WriteFile(changed, edited, 0644); err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+
+			chunks, err := index.ReadChunks(base)
+			if err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+
+			d, err := index.NewDelta(changed, chunks)
+			if err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+
+			if n := d.Size(); n > test.MaxSent {
+				t.Errorf("want delta size <= %d; got %d", test.MaxSent, n)
+			}
+
+			entry, err := index.ApplyDelta(base, d)
+			if err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+
+			if entry.Size != int64(len(edited)) {
+				t.Errorf("want entry size = %d; got %d", len(edited), entry.Size)
+			}
+
+			patched, err := ioutil.ReadFile(base)
+			if err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+
+			if !bytes.Equal(patched, edited) {
+				t.Fatal("patched file content differs from the changed one")
+			}
+		})
+	}
+}
+
+func TestDeltaBaseMismatch(t *testing.T) {
+	root, err := ioutil.TempDir("", "index.delta")
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	content := make([]byte, 512*1024)
+	rand.New(rand.NewSource(0xD)).Read(content)
+
+	var (
+		base    = filepath.Join(root, "base.bin")
+		changed = filepath.Join(root, "changed.bin")
+	)
+
+	if err := ioutil.WriteFile(changed, content, 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	chunks, err := index.ReadChunks(changed)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	d, err := index.NewDelta(changed, chunks)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	// Base file content doesn't match chunks used to create the delta.
+	if err := ioutil.WriteFile(base, make([]byte, len(content)), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if _, err := index.ApplyDelta(base, d); err == nil {
+		t.Fatal("want err != nil; got nil")
+	}
+
+	// Base file must not be modified.
+	if b, err := ioutil.ReadFile(base); err != nil || bytes.Equal(b, content) {
+		t.Fatalf("want base file to stay intact; err = %v", err)
+	}
+}
+
+func TestDeltaTooLarge(t *testing.T) {
+	f, err := ioutil.TempFile("", "index.delta")
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	err = f.Truncate(index.MaxDeltaSize + 1)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if _, err := index.NewDelta(f.Name(), nil); err != index.ErrDeltaTooLarge {
+		t.Fatalf("want err = %v; got %v", index.ErrDeltaTooLarge, err)
+	}
+
+	// Delta which reuses known chunks is not limited by file size.
+	chunks, err := index.ReadChunks(f.Name())
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if _, err := index.NewDelta(f.Name(), chunks); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+}
+
+func TestEntryChunks(t *testing.T) {
+	root, clean, err := generateTree()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	idx, err := index.NewIndexFiles(root)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	tests := map[string]bool{
+		"b.bin":    false, // Below chunk threshold.
+		"c/cb.bin": true,
+	}
+
+	for name, chunked := range tests {
+		nd, ok := idx.Lookup(name)
+		if !ok {
+			t.Fatalf("want %s to be present in index", name)
+		}
+
+		if got := len(nd.Entry.Chunks) != 0; got != chunked {
+			t.Errorf("%s: want chunked = %t; got %t", name, chunked, got)
+		}
+
+		var size int64
+		for _, c := range nd.Entry.Chunks {
+			if c.Offset != size {
+				t.Errorf("%s: want chunk offset = %d; got %d", name, size, c.Offset)
+			}
+			size += c.Size
+		}
+
+		if chunked && size != nd.Entry.Size {
+			t.Errorf("%s: want chunks size = %d; got %d", name, nd.Entry.Size, size)
+		}
+	}
+}
+
+func TestDeltaLimit(t *testing.T) {
+	root, err := ioutil.TempDir("", "index.delta")
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	content := make([]byte, 3*index.ChunkMinSize+1)
+	rand.New(rand.NewSource(0xD)).Read(content)
+
+	var (
+		src = filepath.Join(root, "src")
+		dst = filepath.Join(root, "dst")
+	)
+
+	if err := ioutil.WriteFile(src, content, 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	var read, written int64
+	d, err := index.NewDeltaLimit(src, nil, index.LimiterFunc(func(n int64) { read += n }))
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if read != d.Size() {
+		t.Errorf("want %d bytes to be limited on read; got %d", d.Size(), read)
+	}
+
+	if _, err := index.ApplyDeltaLimit(dst, d, index.LimiterFunc(func(n int64) {
+		if n > index.ChunkMinSize {
+			t.Errorf("want parts of at most %d bytes; got %d", index.ChunkMinSize, n)
+		}
+		written += n
+	})); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if written != d.Size() {
+		t.Errorf("want %d bytes to be limited on write; got %d", d.Size(), written)
+	}
+
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if !bytes.Equal(got, content) {
+		t.Error("want patched file to match the source")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,10 @@ import (
 type Request struct {
 	Rescan time.Duration `json:"rescan"`     // Rescan directory if index is older than Rescan.
 	Path   string        `json:"remotePath"` // Path to the folder we want to mount.
+
+	// Ignore stores additional ignore patterns. They are applied after the
+	// patterns read from ignore files stored in requested folder.
+	Ignore []string `json:"ignore,omitempty"`
 }
 
 // HeadResponse contains the basic info about requested index.
@@ -33,7 +38,12 @@ func Head(req *Request) (*HeadResponse, error) {
 		return nil, err
 	}
 
-	count, diskSize, err := (&Cached{}).HeadCachedIndex(absPath)
+	ign, err := ReadIgnore(absPath, req.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	count, diskSize, err := (&Cached{}).HeadCachedIndexIgnore(absPath, ign)
 	if err != nil {
 		return nil, fmt.Errorf("remote path index error: %s", err)
 	}
@@ -61,7 +71,12 @@ func Get(req *Request) (*GetResponse, error) {
 		return nil, err
 	}
 
-	idx, err := (&Cached{}).GetCachedIndex(absPath)
+	ign, err := ReadIgnore(absPath, req.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := (&Cached{}).GetCachedIndexIgnore(absPath, ign)
 	if err != nil {
 		return nil, fmt.Errorf("remote path index error: %s", err)
 	}
@@ -92,3 +107,180 @@ func preparePath(path string) (string, error) {
 
 	return absPath, nil
 }
+
+// DeltaRequest defines a request for a delta of remote file.
+type DeltaRequest struct {
+	Root   string  `json:"root"`   // Absolute path to the mounted directory.
+	Path   string  `json:"path"`   // Path to the remote file relative to Root.
+	Chunks []Chunk `json:"chunks"` // Chunks of the file version known to caller.
+}
+
+// DeltaResponse stores a delta of requested file.
+type DeltaResponse struct {
+	Delta *Delta `json:"delta"` // Nil when requested file does not exist.
+}
+
+// GetDelta creates a delta that transforms a file described by requested
+// chunks into the current state of requested file.
+func GetDelta(req *DeltaRequest) (*DeltaResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid empty request")
+	}
+
+	path, err := resolvePath(req.Root, req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := NewDelta(path, req.Chunks)
+	if os.IsNotExist(err) {
+		return &DeltaResponse{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("remote file delta error: %s", err)
+	}
+
+	return &DeltaResponse{
+		Delta: d,
+	}, nil
+}
+
+// ErrConflict indicates that remote file was changed since its entry was
+// obtained by the caller.
+var ErrConflict = errors.New("remote file was changed since last synchronization")
+
+// PatchRequest defines a request that updates remote file with provided delta.
+type PatchRequest struct {
+	Root  string `json:"root"`  // Absolute path to the mounted directory.
+	Path  string `json:"path"`  // Path to the remote file relative to Root.
+	Delta *Delta `json:"delta"` // Delta to apply. If nil, the file is removed.
+
+	// Base is the remote file entry known to the caller. If the remote file
+	// diverged from it, the patch is not applied. Nil Base means that the
+	// remote file should not exist.
+	Base *Entry `json:"base,omitempty"`
+
+	// Force applies the patch regardless of remote file state.
+	Force bool `json:"force,omitempty"`
+}
+
+// PatchResponse describes the patched file.
+type PatchResponse struct {
+	// Entry describes the patched file. It is nil when the file was removed.
+	// In case of conflict, Entry describes the current remote file.
+	Entry *Entry `json:"entry,omitempty"`
+
+	// Conflict is set when the remote file diverged from requested base
+	// entry. The patch is not applied then.
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// Patch applies requested delta to a remote file.
+func Patch(req *PatchRequest) (*PatchResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid empty request")
+	}
+
+	path, err := resolvePath(req.Root, req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Delta != nil && req.Delta.Size() > MaxDeltaSize {
+		return nil, fmt.Errorf("remote file patch error: %s", ErrDeltaTooLarge)
+	}
+
+	if !req.Force {
+		diverged, err := Diverged(path, req.Base)
+		if err != nil {
+			return nil, fmt.Errorf("remote file stat error: %s", err)
+		}
+
+		if diverged {
+			_, entry, err := NewEntryFile(filepath.Dir(path), path, nil)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("remote file stat error: %s", err)
+			}
+
+			return &PatchResponse{
+				Entry:    entry,
+				Conflict: true,
+			}, nil
+		}
+	}
+
+	if req.Delta == nil {
+		if err := os.RemoveAll(path); err != nil {
+			return nil, fmt.Errorf("remote file remove error: %s", err)
+		}
+
+		return &PatchResponse{}, nil
+	}
+
+	entry, err := ApplyDelta(path, req.Delta)
+	if err != nil {
+		return nil, fmt.Errorf("remote file patch error: %s", err)
+	}
+
+	return &PatchResponse{
+		Entry: entry,
+	}, nil
+}
+
+// resolvePath joins a file name with mount root and ensures that the resulting
+// path, with symbolic links of its existing parents evaluated, doesn't point
+// outside the root. The root itself cannot be requested.
+func resolvePath(root, name string) (string, error) {
+	if !filepath.IsAbs(root) {
+		return "", fmt.Errorf("remote root %s is not absolute", root)
+	}
+
+	root, err := preparePath(root)
+	if err != nil {
+		return "", err
+	}
+
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", fmt.Errorf("cannot resolve remote root: %s", err)
+	}
+
+	if filepath.IsAbs(name) {
+		return "", fmt.Errorf("remote path %s is not relative to mount root", name)
+	}
+
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if !within(root, path) || path == root {
+		return "", fmt.Errorf("remote path %s is outside of mount root", name)
+	}
+
+	// Find the deepest existing parent and make sure that it doesn't point
+	// outside the root. Missing directories are created by patch.
+	dir := filepath.Dir(path)
+	for {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			if !within(root, resolved) {
+				return "", fmt.Errorf("remote path %s is outside of mount root", name)
+			}
+
+			return path, nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("cannot resolve remote path: %s", err)
+		}
+
+		dir = filepath.Dir(dir)
+	}
+}
+
+// within checks if a given path is equal to root or is stored under it. Both
+// paths must be clean.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
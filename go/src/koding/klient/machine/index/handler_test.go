@@ -0,0 +1,74 @@
+package index_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"koding/klient/machine/index"
+)
+
+func TestPatchOutsideRoot(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "index.handler")
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		root    = filepath.Join(tmp, "root")
+		outside = filepath.Join(tmp, "outside")
+	)
+
+	for _, dir := range []string{root, outside} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("want err = nil; got %v", err)
+		}
+	}
+
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	tests := map[string]struct {
+		Path  string
+		Valid bool
+	}{
+		"file in root":         {Path: "file", Valid: true},
+		"file in missing dir":  {Path: "dir/file", Valid: true},
+		"root itself":          {Path: "."},
+		"absolute path":        {Path: filepath.Join(outside, "file")},
+		"parent directory":     {Path: "../outside/file"},
+		"nested parent":        {Path: "dir/../../outside/file"},
+		"symlink outside root": {Path: "link/file"},
+		"missing dir in link":  {Path: "link/dir/file"},
+	}
+
+	for name, test := range tests {
+		// capture range variable here
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, err := index.Patch(&index.PatchRequest{
+				Root:  root,
+				Path:  test.Path,
+				Force: true,
+			})
+
+			if test.Valid && err != nil {
+				t.Fatalf("want err = nil; got %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Fatal("want err != nil; got nil")
+			}
+
+			if _, err := index.GetDelta(&index.DeltaRequest{Root: root, Path: test.Path}); (err == nil) != test.Valid {
+				t.Fatalf("want delta error = %t; got %v", !test.Valid, err)
+			}
+		})
+	}
+
+	if fis, err := ioutil.ReadDir(outside); err != nil || len(fis) != 0 {
+		t.Fatalf("want outside directory to stay empty; got %d files (err = %v)", len(fis), err)
+	}
+}
@@ -0,0 +1,301 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Names of files which store ignore patterns. Like in git, they can be stored
+// in any directory of indexed tree and their patterns are relative to that
+// directory.
+const (
+	GitIgnoreName = ".gitignore"
+	KDIgnoreName  = ".kdignore"
+)
+
+// IgnoreFiles lists ignore files in the order their patterns are applied.
+// Patterns from files which come later take precedence. Patterns from nested
+// directories take precedence over the ones from their parents.
+var IgnoreFiles = []string{GitIgnoreName, KDIgnoreName}
+
+// Ignore matches file names against gitignore compatible patterns. Nil Ignore
+// doesn't match any file.
+type Ignore struct {
+	patterns []*ignorePattern
+}
+
+// ignorePattern is a single compiled ignore pattern.
+type ignorePattern struct {
+	raw     string         // original pattern line.
+	base    string         // directory of ignore file, empty for root.
+	re      *regexp.Regexp // pattern converted to regular expression.
+	negate  bool           // pattern starts with '!'.
+	dirOnly bool           // pattern ends with '/'.
+}
+
+// NewIgnore creates an ignore matcher from provided pattern lines. Empty lines
+// and comments are skipped. Patterns given later take precedence.
+func NewIgnore(lines ...string) *Ignore {
+	ig := &Ignore{}
+	ig.add("", lines)
+
+	return ig
+}
+
+// ReadIgnore creates an ignore matcher from ignore files stored in root
+// directory and its subdirectories. Directories excluded by already read
+// patterns are not searched. Provided patterns are applied after the ones read
+// from files, so they can override them. Missing ignore files are skipped.
+func ReadIgnore(root string, patterns []string) (*Ignore, error) {
+	ig := &Ignore{}
+
+	walkFn := func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+
+		base, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+
+		if base = filepath.ToSlash(base); base == "." {
+			base = ""
+		} else if ig.Match(base, true) {
+			return filepath.SkipDir
+		}
+
+		for _, name := range IgnoreFiles {
+			lines, err := readLines(filepath.Join(path, name))
+			if err != nil {
+				return err
+			}
+
+			ig.add(base, lines)
+		}
+
+		return nil
+	}
+
+	if err := filepath.Walk(root, walkFn); err != nil {
+		return nil, err
+	}
+
+	ig.add("", patterns)
+
+	return ig, nil
+}
+
+// IsIgnoreFile checks if a given file name, relative to indexed root, is one of
+// ignore files.
+func IsIgnoreFile(name string) bool {
+	name = path.Base(name)
+	for _, ignoreName := range IgnoreFiles {
+		if name == ignoreName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match checks if a given slash separated file name, relative to indexed root,
+// should be ignored. Files stored in ignored directories are ignored too.
+func (ig *Ignore) Match(name string, isDir bool) bool {
+	if ig == nil || len(ig.patterns) == 0 {
+		return false
+	}
+
+	name = strings.Trim(name, "/")
+
+	// Like git, do not look into excluded directories.
+	for i := strings.IndexByte(name, '/'); i != -1; {
+		if ig.match(name[:i], true) {
+			return true
+		}
+
+		j := strings.IndexByte(name[i+1:], '/')
+		if j == -1 {
+			break
+		}
+		i += j + 1
+	}
+
+	return ig.match(name, isDir)
+}
+
+// String returns all patterns separated by new lines. Patterns read from
+// nested ignore files are prefixed with their directory and a colon.
+func (ig *Ignore) String() string {
+	if ig == nil {
+		return ""
+	}
+
+	raw := make([]string, len(ig.patterns))
+	for i, p := range ig.patterns {
+		if raw[i] = p.raw; p.base != "" {
+			raw[i] = p.base + ":" + p.raw
+		}
+	}
+
+	return strings.Join(raw, "\n")
+}
+
+// add appends patterns from a single ignore file stored in base directory.
+func (ig *Ignore) add(base string, lines []string) {
+	for _, line := range lines {
+		if p := newIgnorePattern(line); p != nil {
+			p.base = base
+			ig.patterns = append(ig.patterns, p)
+		}
+	}
+}
+
+// match checks a single path without looking at its parent directories. The
+// last matching pattern decides.
+func (ig *Ignore) match(name string, isDir bool) (ignored bool) {
+	for _, p := range ig.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		rel := name
+		if p.base != "" {
+			if !strings.HasPrefix(name, p.base+"/") {
+				continue
+			}
+
+			rel = name[len(p.base)+1:]
+		}
+
+		if p.re.MatchString(rel) {
+			ignored = !p.negate
+		}
+	}
+
+	return ignored
+}
+
+// readLines reads all lines of a given file. Missing file has no lines.
+func readLines(path string) ([]string, error) {
+	p, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	s := bufio.NewScanner(bytes.NewReader(p))
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+
+	return lines, s.Err()
+}
+
+// newIgnorePattern parses a single line of ignore file. Nil is returned for
+// empty lines and comments.
+func newIgnorePattern(line string) *ignorePattern {
+	line = strings.TrimRight(line, "\r")
+
+	// Trailing spaces are ignored unless they are escaped.
+	if trimmed := strings.TrimRight(line, " "); strings.HasSuffix(trimmed, `\`) && trimmed != line {
+		line = trimmed + " "
+	} else {
+		line = trimmed
+	}
+
+	if line == "" || line[0] == '#' {
+		return nil
+	}
+
+	p := &ignorePattern{raw: line}
+
+	switch {
+	case line[0] == '!':
+		p.negate, line = true, line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly, line = true, strings.TrimRight(line, "/")
+	}
+
+	if line == "" {
+		return nil
+	}
+
+	// Patterns without a slash match file names at any level. Other ones are
+	// relative to the root directory.
+	prefix := "^"
+	if !strings.Contains(line, "/") {
+		prefix = "^(?:.*/)?"
+	}
+
+	re, err := regexp.Compile(prefix + globToRegexp(strings.TrimPrefix(line, "/")) + "$")
+	if err != nil {
+		return nil
+	}
+
+	p.re = re
+	return p
+}
+
+// globToRegexp converts gitignore glob to regular expression.
+func globToRegexp(glob string) string {
+	var buf bytes.Buffer
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				if i+2 < len(glob) && glob[i+2] == '/' {
+					// "**/" matches zero or more directories.
+					buf.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					// Trailing "/**" matches everything inside.
+					buf.WriteString(".*")
+					i++
+				}
+				continue
+			}
+
+			buf.WriteString("[^/]*")
+		case '?':
+			buf.WriteString("[^/]")
+		case '[':
+			j := strings.IndexByte(glob[i+1:], ']')
+			if j == -1 {
+				buf.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+
+			class := glob[i+1 : i+1+j]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			buf.WriteString("[" + class + "]")
+			i += j + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				buf.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			}
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,199 @@
+package index_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"koding/klient/machine/index"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	tests := map[string]struct {
+		Patterns []string
+		Name     string
+		IsDir    bool
+		Ignored  bool
+	}{
+		"empty patterns": {
+			Name:    "a.txt",
+			Ignored: false,
+		},
+		"comment": {
+			Patterns: []string{"# a.txt"},
+			Name:     "a.txt",
+			Ignored:  false,
+		},
+		"base name at any level": {
+			Patterns: []string{"*.bin"},
+			Name:     "c/cb.bin",
+			Ignored:  true,
+		},
+		"directory only": {
+			Patterns: []string{"node_modules/"},
+			Name:     "node_modules",
+			IsDir:    false,
+			Ignored:  false,
+		},
+		"file inside ignored directory": {
+			Patterns: []string{"node_modules/"},
+			Name:     "web/node_modules/lib/index.js",
+			Ignored:  true,
+		},
+		"anchored to root": {
+			Patterns: []string{"/build"},
+			Name:     "src/build",
+			IsDir:    true,
+			Ignored:  false,
+		},
+		"pattern with slash": {
+			Patterns: []string{"d/dc/*.txt"},
+			Name:     "d/dc/dca.txt",
+			Ignored:  true,
+		},
+		"single star does not cross directories": {
+			Patterns: []string{"d/*.txt"},
+			Name:     "d/dc/dca.txt",
+			Ignored:  false,
+		},
+		"double star directories": {
+			Patterns: []string{"d/**/*.txt"},
+			Name:     "d/dc/dca.txt",
+			Ignored:  true,
+		},
+		"trailing double star": {
+			Patterns: []string{"d/**"},
+			Name:     "d/dc/dca.txt",
+			Ignored:  true,
+		},
+		"negation": {
+			Patterns: []string{"*.txt", "!a.txt"},
+			Name:     "a.txt",
+			Ignored:  false,
+		},
+		"negation inside ignored directory": {
+			Patterns: []string{"d/", "!d/da.txt"},
+			Name:     "d/da.txt",
+			Ignored:  true,
+		},
+		"character class": {
+			Patterns: []string{"[ab].txt"},
+			Name:     "b.txt",
+			Ignored:  true,
+		},
+		"escaped hash": {
+			Patterns: []string{`\#tmp#`},
+			Name:     "#tmp#",
+			Ignored:  true,
+		},
+	}
+
+	for name, test := range tests {
+		// capture range variable here
+		test := test
+		t.Run(name, func(t *testing.T) {
+			ign := index.NewIgnore(test.Patterns...)
+
+			if ignored := ign.Match(test.Name, test.IsDir); ignored != test.Ignored {
+				t.Errorf("want ignored = %t; got %t", test.Ignored, ignored)
+			}
+		})
+	}
+}
+
+func TestIndexIgnore(t *testing.T) {
+	root, clean, err := generateTree()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	if err := ioutil.WriteFile(filepath.Join(root, index.GitIgnoreName), []byte("*.bin\nd/\n"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, index.KDIgnoreName), []byte("!b.bin\n"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	// Mount patterns override the ones from ignore files.
+	ign, err := index.ReadIgnore(root, []string{"a.txt"})
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	idx, err := index.NewIndexFilesIgnore(root, ign)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	tests := map[string]bool{
+		"a.txt":        false,
+		"b.bin":        true,
+		"c/ca.txt":     true,
+		"c/cb.bin":     false,
+		"d":            false,
+		"d/dc/dca.txt": false,
+	}
+
+	for name, indexed := range tests {
+		if _, ok := idx.Lookup(name); ok != indexed {
+			t.Errorf("%s: want indexed = %t; got %t", name, indexed, ok)
+		}
+	}
+
+	// Ignored files must not be reported as changes.
+	if err := writeFile("d/test.txt", 1024)(root); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if cs := idx.CompareIgnore(root, ign); len(cs) != 0 {
+		t.Errorf("want no changes; got %v", cs)
+	}
+}
+
+func TestIndexIgnoreNested(t *testing.T) {
+	root, clean, err := generateTree()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	files := map[string]string{
+		index.GitIgnoreName:                     "*.bin\n",
+		filepath.Join("c", index.GitIgnoreName): "ca.txt\n!cb.bin\n",
+		filepath.Join("d", index.KDIgnoreName):  "/da.txt\ndc/\n",
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("want err = nil; got %v", err)
+		}
+	}
+
+	ign, err := index.ReadIgnore(root, nil)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	tests := map[string]bool{
+		"a.txt":        false,
+		"b.bin":        true,
+		"c/ca.txt":     true,
+		"c/cb.bin":     false,
+		"d/da.txt":     true,
+		"d/dc":         true,
+		"d/dc/dca.txt": true,
+		"da.txt":       false,
+		"dc/dca.txt":   false,
+	}
+
+	for name, ignored := range tests {
+		if got := ign.Match(name, name == "d/dc"); got != ignored {
+			t.Errorf("%s: want ignored = %t; got %t", name, ignored, got)
+		}
+	}
+
+	if !index.IsIgnoreFile("c/" + index.GitIgnoreName) {
+		t.Errorf("want %s to be an ignore file", "c/"+index.GitIgnoreName)
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"compress/gzip"
 	"encoding/base64"
 	"encoding/json"
-	"hash/crc32"
-	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -25,6 +23,10 @@ type Entry struct {
 	Mode  os.FileMode `json:"o"` // File mode and permission bits.
 	Size  int64       `json:"s"` // Size of the file.
 	Hash  []byte      `json:"h"` // Hash of file content.
+
+	// Chunks stores content-defined chunk signatures of the file. They are
+	// present only for files which size is not less than ChunkThreshold.
+	Chunks []Chunk `json:"k,omitempty"`
 }
 
 // NewEntryFile creates new Entry from a file stored under path argument.
@@ -42,20 +44,24 @@ func NewEntryFile(root, path string, info os.FileInfo) (name string, e *Entry, e
 		return "", nil, err
 	}
 
-	// Compute file's hash sum.
-	var sum []byte
+	// Compute file's hash sum and, for large files, chunk signatures.
+	var (
+		sum    []byte
+		chunks []Chunk
+	)
 	if !info.IsDir() {
-		if sum, err = readCRC32(path); err != nil {
+		if sum, chunks, err = readSums(path, isChunked(info.Size())); err != nil {
 			return "", nil, err
 		}
 	}
 
 	return filepath.ToSlash(name), &Entry{
-		CTime: ctime(info),
-		MTime: info.ModTime().UnixNano(),
-		Mode:  info.Mode(),
-		Size:  info.Size(),
-		Hash:  sum,
+		CTime:  ctime(info),
+		MTime:  info.ModTime().UnixNano(),
+		Mode:   info.Mode(),
+		Size:   info.Size(),
+		Hash:   sum,
+		Chunks: chunks,
 	}, nil
 }
 
@@ -66,26 +72,6 @@ var copyBufPool = sync.Pool{
 	},
 }
 
-// readCRC32 computes CRC-32 checksum of a given file content.
-func readCRC32(path string) ([]byte, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	hash := crc32.NewIEEE()
-
-	bufp := copyBufPool.Get().(*[]byte)
-	defer copyBufPool.Put(bufp)
-
-	if _, err := io.CopyBuffer(hash, file, *bufp); err != nil {
-		return nil, err
-	}
-
-	return hash.Sum(nil), nil
-}
-
 // Index stores a virtual working tree state. It recursively records objects in
 // a given root path and allows to efficiently detect changes on it.
 type Index struct {
@@ -113,6 +99,12 @@ type fileDesc struct {
 // NewIndexFiles walks the given file tree roted at root and records file
 // states to resulting Index object.
 func NewIndexFiles(root string) (*Index, error) {
+	return NewIndexFilesIgnore(root, nil)
+}
+
+// NewIndexFilesIgnore works like NewIndexFiles but files matched by provided
+// ignore are not recorded. Nil ignore doesn't match any file.
+func NewIndexFilesIgnore(root string, ign *Ignore) (*Index, error) {
 	idx := NewIndex()
 
 	// Start worker pool.
@@ -134,10 +126,15 @@ func NewIndexFiles(root string) (*Index, error) {
 		}
 
 		// Skip root path.
-		if name, err := filepath.Rel(root, path); err != nil || name == "." {
+		name, err := filepath.Rel(root, path)
+		if err != nil || name == "." {
 			return nil
 		}
 
+		if ign.Match(filepath.ToSlash(name), info.IsDir()) {
+			return skipIgnored(info)
+		}
+
 		fC <- &fileDesc{path: path, info: info}
 		return nil
 	}
@@ -185,6 +182,22 @@ func (idx *Index) DiskSize(maxsize int64) int64 {
 	return idx.root.DiskSize(maxsize)
 }
 
+// Add adds or replaces the entry stored under a given name.
+func (idx *Index) Add(name string, entry *Entry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.root.Add(name, entry)
+}
+
+// Del removes the entry stored under a given name.
+func (idx *Index) Del(name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.root.Del(name)
+}
+
 func (idx *Index) Lookup(name string) (*Node, bool) {
 	idx.mu.RLock()
 	defer idx.mu.RUnlock()
@@ -195,7 +208,13 @@ func (idx *Index) Lookup(name string) (*Node, bool) {
 // Compare rereads the given file tree roted at root and compares its entries
 // to previous state of the index. All detected changes will be stored in
 // returned Change slice.
-func (idx *Index) Compare(root string) (cs ChangeSlice) {
+func (idx *Index) Compare(root string) ChangeSlice {
+	return idx.CompareIgnore(root, nil)
+}
+
+// CompareIgnore works like Compare but files matched by provided ignore are
+// skipped. Nil ignore doesn't match any file.
+func (idx *Index) CompareIgnore(root string, ign *Ignore) (cs ChangeSlice) {
 	visited := make(map[string]struct{})
 
 	// Walk over current root path and check it files.
@@ -210,6 +229,10 @@ func (idx *Index) Compare(root string) (cs ChangeSlice) {
 		}
 		name = filepath.ToSlash(name)
 
+		if ign.Match(name, info.IsDir()) {
+			return skipIgnored(info)
+		}
+
 		idx.mu.RLock()
 		nd, ok := idx.root.Lookup(name)
 		idx.mu.RUnlock()
@@ -238,7 +261,7 @@ func (idx *Index) Compare(root string) (cs ChangeSlice) {
 	// Check for removes.
 	idx.mu.RLock()
 	idx.root.ForEach(func(name string, entry *Entry) {
-		if _, ok := visited[name]; !ok {
+		if _, ok := visited[name]; !ok && !ign.Match(name, entry.Mode.IsDir()) {
 			path := filepath.Join(root, filepath.FromSlash(name))
 
 			if _, err := os.Lstat(path); os.IsNotExist(err) {
@@ -251,6 +274,61 @@ func (idx *Index) Compare(root string) (cs ChangeSlice) {
 	return cs
 }
 
+// Diff compares two indexes and returns changes which transform the called
+// index into the other one. Nodes created only as parents of other entries
+// are not compared.
+func (idx *Index) Diff(other *Index) (cs ChangeSlice) {
+	other.mu.RLock()
+	other.root.ForEach(func(name string, entry *Entry) {
+		if entry == nil || entry.MTime == 0 {
+			return
+		}
+
+		name = filepath.ToSlash(name)
+		idx.mu.RLock()
+		nd, ok := idx.root.Lookup(name)
+		idx.mu.RUnlock()
+
+		switch {
+		case !ok || nd.Entry == nil || nd.Entry.MTime == 0:
+			cs = append(cs, NewChange(name, ChangeMetaAdd|markLargeMeta(entry.Size)))
+		case nd.Entry.MTime != entry.MTime ||
+			nd.Entry.Size != entry.Size ||
+			nd.Entry.Mode != entry.Mode:
+			cs = append(cs, NewChange(name, ChangeMetaUpdate|markLargeMeta(entry.Size)))
+		}
+	})
+	other.mu.RUnlock()
+
+	idx.mu.RLock()
+	idx.root.ForEach(func(name string, entry *Entry) {
+		if entry == nil || entry.MTime == 0 {
+			return
+		}
+
+		name = filepath.ToSlash(name)
+		other.mu.RLock()
+		nd, ok := other.root.Lookup(name)
+		other.mu.RUnlock()
+
+		if !ok || nd.Entry == nil || nd.Entry.MTime == 0 {
+			cs = append(cs, NewChange(name, ChangeMetaRemove|markLargeMeta(entry.Size)))
+		}
+	})
+	idx.mu.RUnlock()
+
+	return cs
+}
+
+// skipIgnored tells filepath.Walk to not descend into ignored directories.
+func skipIgnored(info os.FileInfo) error {
+	if info.IsDir() {
+		return filepath.SkipDir
+	}
+
+	return nil
+}
+
 // markLargeMeta adds large file flag for files which size is over 4GiB.
 func markLargeMeta(n int64) ChangeMeta {
 	if n < 0 || (n>>32) == 0 {
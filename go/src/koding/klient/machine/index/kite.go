@@ -53,3 +53,51 @@ func KiteHandlerGet() kite.HandlerFunc {
 		return res, nil
 	}
 }
+
+// KiteHandlerDelta creates a kite handler function that, when called, invokes
+// index package GetDelta method.
+func KiteHandlerDelta() kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &DeltaRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := GetDelta(req)
+		if err != nil {
+			return nil, &kite.Error{
+				Type:    "indexError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// KiteHandlerPatch creates a kite handler function that, when called, invokes
+// index package Patch method.
+func KiteHandlerPatch() kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &PatchRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := Patch(req)
+		if err != nil {
+			return nil, &kite.Error{
+				Type:    "indexError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,138 @@
+package machinegroup
+
+import (
+	"errors"
+
+	"koding/klient/machine/mount"
+)
+
+// ConflictsRequest defines machine group mount conflicts request.
+type ConflictsRequest struct {
+	// Identifier is a string that identifiers requested mount. It can be either
+	// mount ID or local path of the mount.
+	Identifier string `json:"identifier"`
+}
+
+// ConflictsResponse defines machine group mount conflicts response.
+type ConflictsResponse struct {
+	// MountID is a unique identifier of requested mount.
+	MountID mount.ID `json:"mountID"`
+
+	// Conflicts stores unresolved conflicts sorted by file name.
+	Conflicts []*mount.Conflict `json:"conflicts"`
+}
+
+// Conflicts lists files that were changed both locally and remotely since
+// their last synchronization.
+func (g *Group) Conflicts(req *ConflictsRequest) (*ConflictsResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := g.supervisor.Conflicts(mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConflictsResponse{
+		MountID:   mountID,
+		Conflicts: cs,
+	}, nil
+}
+
+// ResolveRequest defines machine group mount conflict resolve request.
+type ResolveRequest struct {
+	// Identifier is a string that identifiers requested mount. It can be either
+	// mount ID or local path of the mount.
+	Identifier string `json:"identifier"`
+
+	// Name is the conflicting file name relative to mount root.
+	Name string `json:"name"`
+
+	// Policy defines how the conflict should be resolved.
+	Policy mount.ConflictPolicy `json:"policy"`
+}
+
+// ResolveResponse defines machine group mount conflict resolve response.
+type ResolveResponse struct {
+	// MountID is a unique identifier of requested mount.
+	MountID mount.ID `json:"mountID"`
+}
+
+// Resolve resolves a single conflict using provided policy.
+func (g *Group) Resolve(req *ResolveRequest) (*ResolveResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.supervisor.Resolve(mountID, req.Name, req.Policy); err != nil {
+		g.log.Error("Cannot resolve conflict of %s in mount %s: %s", req.Name, mountID, err)
+		return nil, err
+	}
+
+	g.log.Info("Resolved conflict of %s in mount %s using %s policy", req.Name, mountID, req.Policy)
+
+	return &ResolveResponse{
+		MountID: mountID,
+	}, nil
+}
+
+// PolicyRequest defines machine group mount conflict policy request.
+type PolicyRequest struct {
+	// Identifier is a string that identifiers requested mount. It can be either
+	// mount ID or local path of the mount.
+	Identifier string `json:"identifier"`
+
+	// Policy defines how new conflicts of the mount are resolved.
+	Policy mount.ConflictPolicy `json:"policy"`
+}
+
+// PolicyResponse defines machine group mount conflict policy response.
+type PolicyResponse struct {
+	// MountID is a unique identifier of requested mount.
+	MountID mount.ID `json:"mountID"`
+}
+
+// Policy changes conflict resolution policy of the mount. The policy is stored
+// together with the mount, so it is kept after klient restarts.
+func (g *Group) Policy(req *PolicyRequest) (*PolicyResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := g.storedMount(mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.supervisor.SetConflictPolicy(mountID, req.Policy); err != nil {
+		return nil, err
+	}
+
+	m.ConflictPolicy = req.Policy
+	if err := g.mount.Update(mountID, m); err != nil {
+		g.log.Error("Cannot store conflict policy of mount %s: %s", mountID, err)
+		return nil, err
+	}
+
+	g.log.Info("Changed conflict policy of mount %s to %s", mountID, req.Policy)
+
+	return &PolicyResponse{
+		MountID: mountID,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package machinegroup
+
+import (
+	"errors"
+
+	"koding/klient/machine/mount"
+)
+
+// IgnoreRequest defines machine group mount ignore request.
+type IgnoreRequest struct {
+	// Identifier is a string that identifiers requested mount. It can be either
+	// mount ID or local path of the mount.
+	Identifier string `json:"identifier"`
+
+	// Ignore, if not nil, replaces ignore patterns of the mount. Patterns are
+	// separated by new lines; empty string removes all of them.
+	Ignore *string `json:"ignore,omitempty"`
+}
+
+// IgnoreResponse defines machine group mount ignore response.
+type IgnoreResponse struct {
+	// MountID is a unique identifier of requested mount.
+	MountID mount.ID `json:"mountID"`
+
+	// Ignore stores current ignore patterns of the mount.
+	Ignore string `json:"ignore"`
+}
+
+// Ignore gets or replaces ignore patterns of the mount. New patterns are stored
+// together with the mount, so they are kept after klient restarts.
+func (g *Group) Ignore(req *IgnoreRequest) (*IgnoreResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := g.storedMount(mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Ignore != nil {
+		if err := g.supervisor.SetIgnore(mountID, *req.Ignore); err != nil {
+			return nil, err
+		}
+
+		m.Ignore = *req.Ignore
+		if err := g.mount.Update(mountID, m); err != nil {
+			g.log.Error("Cannot store ignore patterns of mount %s: %s", mountID, err)
+			return nil, err
+		}
+
+		g.log.Info("Changed ignore patterns of mount %s", mountID)
+	}
+
+	return &IgnoreResponse{
+		MountID: mountID,
+		Ignore:  m.Ignore,
+	}, nil
+}
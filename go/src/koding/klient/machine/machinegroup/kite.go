@@ -1,6 +1,8 @@
 package machinegroup
 
 import (
+	"koding/klient/machine/mount"
+
 	"github.com/koding/kite"
 )
 
@@ -178,3 +180,194 @@ func KiteHandlerUmount(g *Group) kite.HandlerFunc {
 		return res, nil
 	}
 }
+
+// KiteHandlerConflictsMount creates a kite handler function that, when called,
+// invokes machine group Conflicts method.
+func KiteHandlerConflictsMount(g *Group) kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &ConflictsRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := g.Conflicts(req)
+		if err != nil {
+			// TODO(ppknap): create errors file similar to kloud/stack/errors.
+			return nil, &kite.Error{
+				Type:    "machinesError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// KiteHandlerResolveMount creates a kite handler function that, when called,
+// invokes machine group Resolve method.
+func KiteHandlerResolveMount(g *Group) kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &ResolveRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := g.Resolve(req)
+		if err != nil {
+			// TODO(ppknap): create errors file similar to kloud/stack/errors.
+			return nil, &kite.Error{
+				Type:    "machinesError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// KiteHandlerPolicyMount creates a kite handler function that, when called,
+// invokes machine group Policy method.
+func KiteHandlerPolicyMount(g *Group) kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &PolicyRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := g.Policy(req)
+		if err != nil {
+			// TODO(ppknap): create errors file similar to kloud/stack/errors.
+			return nil, &kite.Error{
+				Type:    "machinesError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// KiteHandlerIgnoreMount creates a kite handler function that, when called,
+// invokes machine group Ignore method.
+func KiteHandlerIgnoreMount(g *Group) kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &IgnoreRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := g.Ignore(req)
+		if err != nil {
+			// TODO(ppknap): create errors file similar to kloud/stack/errors.
+			return nil, &kite.Error{
+				Type:    "machinesError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// KiteHandlerStatusMount creates a kite handler function that, when called,
+// invokes machine group Status method.
+func KiteHandlerStatusMount(g *Group) kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &StatusRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		if req.OnChange.IsValid() {
+			fn := func(st *mount.Status) error {
+				return req.OnChange.Call(st)
+			}
+
+			stop, err := g.WatchStatus(req, fn)
+			if err != nil {
+				return nil, &kite.Error{
+					Type:    "machinesError",
+					Message: err.Error(),
+				}
+			}
+
+			r.Client.OnDisconnect(stop)
+		}
+
+		res, err := g.Status(req)
+		if err != nil {
+			// TODO(ppknap): create errors file similar to kloud/stack/errors.
+			return nil, &kite.Error{
+				Type:    "machinesError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// KiteHandlerPauseMount creates a kite handler function that, when called,
+// invokes machine group Pause method.
+func KiteHandlerPauseMount(g *Group) kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &PauseRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := g.Pause(req)
+		if err != nil {
+			// TODO(ppknap): create errors file similar to kloud/stack/errors.
+			return nil, &kite.Error{
+				Type:    "machinesError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// KiteHandlerResumeMount creates a kite handler function that, when called,
+// invokes machine group Resume method.
+func KiteHandlerResumeMount(g *Group) kite.HandlerFunc {
+	return func(r *kite.Request) (interface{}, error) {
+		req := &ResumeRequest{}
+
+		if r.Args != nil {
+			if err := r.Args.One().Unmarshal(req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := g.Resume(req)
+		if err != nil {
+			// TODO(ppknap): create errors file similar to kloud/stack/errors.
+			return nil, &kite.Error{
+				Type:    "machinesError",
+				Message: err.Error(),
+			}
+		}
+
+		return res, nil
+	}
+}
@@ -60,7 +60,7 @@ func (g *Group) HeadMount(req *HeadMountRequest) (*HeadMountResponse, error) {
 		return nil, err
 	}
 
-	absRemotePath, count, diskSize, err := c.MountHeadIndex(req.Mount.RemotePath)
+	absRemotePath, count, diskSize, err := c.MountHeadIndex(req.Mount.RemotePath, req.Mount.IgnorePatterns())
 	if err != nil {
 		return nil, err
 	}
@@ -294,13 +294,9 @@ func (g *Group) Umount(req *UmountRequest) (res *UmountResponse, err error) {
 	}
 
 	// Get mount ID from identifier.
-	mountID, err := mount.IDFromString(req.Identifier)
+	mountID, err := g.mountID(req.Identifier)
 	if err != nil {
-		absPath, e := filepath.Abs(req.Identifier)
-		if mountID, err = g.mount.Path(absPath); e != nil || err != nil {
-			g.log.Error("Cannot found mount with identifier: %s", req.Identifier)
-			return nil, fmt.Errorf("unknown mount: %q", req.Identifier)
-		}
+		return nil, err
 	}
 
 	// Stop mount synchronization routine.
@@ -334,3 +330,38 @@ func (g *Group) Umount(req *UmountRequest) (res *UmountResponse, err error) {
 		Mount:   m,
 	}, nil
 }
+
+// mountID gets mount ID from identifier which can be either mount ID or local
+// path of the mount.
+func (g *Group) mountID(identifier string) (mount.ID, error) {
+	mountID, err := mount.IDFromString(identifier)
+	if err != nil {
+		absPath, e := filepath.Abs(identifier)
+		if mountID, err = g.mount.Path(absPath); e != nil || err != nil {
+			g.log.Error("Cannot found mount with identifier: %s", identifier)
+			return "", fmt.Errorf("unknown mount: %q", identifier)
+		}
+	}
+
+	return mountID, nil
+}
+
+// storedMount gets the mount with provided ID from mount storage.
+func (g *Group) storedMount(mountID mount.ID) (mount.Mount, error) {
+	id, err := g.mount.MachineID(mountID)
+	if err != nil {
+		return mount.Mount{}, err
+	}
+
+	mounts, err := g.mount.All(id)
+	if err != nil {
+		return mount.Mount{}, err
+	}
+
+	m, ok := mounts[mountID]
+	if !ok {
+		return mount.Mount{}, mount.ErrMountNotFound
+	}
+
+	return m, nil
+}
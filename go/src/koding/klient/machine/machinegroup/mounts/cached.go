@@ -52,6 +52,18 @@ func (c *Cached) Add(id machine.ID, mountID mount.ID, m mount.Mount) error {
 	return c.st.SetValue(storageKey, c.mounts.all())
 }
 
+// Update replaces the options of existing mount and updates the cache.
+func (c *Cached) Update(mountID mount.ID, m mount.Mount) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.mounts.Update(mountID, m); err != nil {
+		return err
+	}
+
+	return c.st.SetValue(storageKey, c.mounts.all())
+}
+
 // Remove removes a given mount from cache ad updates it.
 func (c *Cached) Remove(mountID mount.ID) error {
 	c.mu.Lock()
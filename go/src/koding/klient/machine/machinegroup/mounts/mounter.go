@@ -10,6 +10,9 @@ type Mounter interface {
 	// Add adds provided mount to a given machine.
 	Add(machine.ID, mount.ID, mount.Mount) error
 
+	// Update replaces the options of existing mount.
+	Update(mount.ID, mount.Mount) error
+
 	// Remove removes a given mount from cache.
 	Remove(mount.ID) error
 
@@ -50,6 +50,25 @@ func (ms *Mounts) Add(id machine.ID, mountID mount.ID, m mount.Mount) error {
 	return nil
 }
 
+// Update replaces the options of existing mount. Mount paths cannot be
+// changed.
+func (ms *Mounts) Update(mountID mount.ID, m mount.Mount) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	id, err := ms.machineID(mountID)
+	if err != nil {
+		return err
+	}
+
+	mb := ms.m[id]
+	if old := mb.All()[mountID]; old.Path != m.Path || old.RemotePath != m.RemotePath {
+		return fmt.Errorf("paths of mount %s cannot be changed", mountID)
+	}
+
+	return mb.Update(mountID, m)
+}
+
 // Remove removes a given mount from the cache.
 func (ms *Mounts) Remove(mountID mount.ID) error {
 	ms.mu.Lock()
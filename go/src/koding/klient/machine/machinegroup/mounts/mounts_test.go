@@ -176,6 +176,40 @@ func TestMountsRemove(t *testing.T) {
 	}
 }
 
+func TestMountsUpdate(t *testing.T) {
+	ms, err := mountsObject()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := mount.Mount{
+		Path:           "/home/koding/a",
+		RemotePath:     "/home/koding/remote/a",
+		ConflictPolicy: mount.ConflictLocalWins,
+	}
+
+	if err := ms.Update("mountAA", m); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	all, err := ms.All("machineA")
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	if !reflect.DeepEqual(all["mountAA"], m) {
+		t.Errorf("want mount = %#v; got %#v", m, all["mountAA"])
+	}
+
+	m.Path = "/home/koding/X"
+	if err := ms.Update("mountAA", m); err == nil {
+		t.Errorf("want err != nil; got nil")
+	}
+
+	if err := ms.Update("unknown", m); err != mount.ErrMountNotFound {
+		t.Errorf("want err = %v; got %v", mount.ErrMountNotFound, err)
+	}
+}
+
 func TestMountsAddValidate(t *testing.T) {
 	tests := map[string]struct {
 		ID      machine.ID
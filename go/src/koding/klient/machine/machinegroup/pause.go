@@ -0,0 +1,112 @@
+package machinegroup
+
+import (
+	"errors"
+
+	"koding/klient/machine/mount"
+)
+
+// PauseRequest defines machine group mount pause request.
+type PauseRequest struct {
+	// Identifier is a string that identifiers requested mount. It can be either
+	// mount ID or local path of the mount.
+	Identifier string `json:"identifier"`
+}
+
+// PauseResponse defines machine group mount pause response.
+type PauseResponse struct {
+	// MountID is a unique identifier of paused mount.
+	MountID mount.ID `json:"mountID"`
+}
+
+// Pause temporarily stops synchronization of the mount. Local and remote
+// changes are still collected and they will be synchronized after resume.
+// Paused state is stored together with the mount, so it is kept after klient
+// restarts.
+func (g *Group) Pause(req *PauseRequest) (*PauseResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := g.storedMount(mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.supervisor.Pause(mountID); err != nil {
+		return nil, err
+	}
+
+	m.Paused = true
+	if err := g.mount.Update(mountID, m); err != nil {
+		g.log.Error("Cannot store paused state of mount %s: %s", mountID, err)
+		return nil, err
+	}
+
+	g.log.Info("Paused synchronization of mount %s", mountID)
+
+	return &PauseResponse{
+		MountID: mountID,
+	}, nil
+}
+
+// ResumeRequest defines machine group mount resume request.
+type ResumeRequest struct {
+	// Identifier is a string that identifiers requested mount. It can be either
+	// mount ID or local path of the mount.
+	Identifier string `json:"identifier"`
+
+	// BandwidthLimit, if not nil, replaces the bandwidth limit of the mount.
+	// It is given in bytes per second; zero disables the limit.
+	BandwidthLimit *int64 `json:"bandwidthLimit,omitempty"`
+}
+
+// ResumeResponse defines machine group mount resume response.
+type ResumeResponse struct {
+	// MountID is a unique identifier of resumed mount.
+	MountID mount.ID `json:"mountID"`
+}
+
+// Resume restarts synchronization of the mount. It can be also used to change
+// the bandwidth limit of already running mount. Both the state and the limit
+// are stored together with the mount.
+func (g *Group) Resume(req *ResumeRequest) (*ResumeResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := g.storedMount(mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.supervisor.Resume(mountID, req.BandwidthLimit); err != nil {
+		return nil, err
+	}
+
+	m.Paused = false
+	if req.BandwidthLimit != nil {
+		m.BandwidthLimit = *req.BandwidthLimit
+	}
+
+	if err := g.mount.Update(mountID, m); err != nil {
+		g.log.Error("Cannot store state of mount %s: %s", mountID, err)
+		return nil, err
+	}
+
+	g.log.Info("Resumed synchronization of mount %s", mountID)
+
+	return &ResumeResponse{
+		MountID: mountID,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package machinegroup
+
+import (
+	"errors"
+	"reflect"
+
+	"koding/klient/machine/mount"
+
+	"github.com/koding/kite/dnode"
+)
+
+// StatusRequest defines machine group mount status request.
+type StatusRequest struct {
+	// Identifier is a string that identifiers requested mount. It can be either
+	// mount ID or local path of the mount.
+	Identifier string `json:"identifier"`
+
+	// OnChange, if valid, is called with *mount.Status each time the status
+	// of the mount changes. It is called until the caller disconnects or the
+	// mount is removed.
+	OnChange dnode.Function `json:"onChange,omitempty"`
+}
+
+// StatusResponse defines machine group mount status response.
+type StatusResponse struct {
+	// MountID is a unique identifier of requested mount.
+	MountID mount.ID `json:"mountID"`
+
+	// Status describes mount synchronization progress.
+	Status *mount.Status `json:"status"`
+}
+
+// Status gets synchronization progress of the mount.
+func (g *Group) Status(req *StatusRequest) (*StatusResponse, error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := g.supervisor.Status(mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatusResponse{
+		MountID: mountID,
+		Status:  st,
+	}, nil
+}
+
+// WatchStatus calls fn with synchronization progress of the mount each time it
+// changes. Watching stops when fn returns an error, when the mount is removed
+// or when returned function is called.
+func (g *Group) WatchStatus(req *StatusRequest, fn func(*mount.Status) error) (func(), error) {
+	if req == nil {
+		return nil, errors.New("invalid nil request")
+	}
+
+	mountID, err := g.mountID(req.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	c, stop, err := g.supervisor.WatchStatus(mountID)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer stop()
+
+		var last *mount.Status
+		for range c {
+			st, err := g.supervisor.Status(mountID)
+			if err != nil {
+				return
+			}
+
+			if reflect.DeepEqual(st, last) {
+				continue
+			}
+
+			if err := fn(st); err != nil {
+				g.log.Debug("Stopped watching status of mount %s: %s", mountID, err)
+				return
+			}
+
+			last = st
+		}
+	}()
+
+	return stop, nil
+}
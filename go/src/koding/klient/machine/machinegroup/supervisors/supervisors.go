@@ -144,3 +144,121 @@ func (s *Supervisors) Drop(mountID mount.ID) (err error) {
 
 	return err
 }
+
+// Conflicts returns unresolved conflicts of mount with provided ID.
+func (s *Supervisors) Conflicts(mountID mount.ID) ([]*mount.Conflict, error) {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, mount.ErrMountNotFound
+	}
+
+	return spv.Conflicts(), nil
+}
+
+// Resolve resolves a conflicting file of mount with provided ID.
+func (s *Supervisors) Resolve(mountID mount.ID, name string, policy mount.ConflictPolicy) error {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return mount.ErrMountNotFound
+	}
+
+	return spv.Resolve(name, policy)
+}
+
+// SetConflictPolicy changes conflict resolution policy of mount with provided
+// ID.
+func (s *Supervisors) SetConflictPolicy(mountID mount.ID, policy mount.ConflictPolicy) error {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return mount.ErrMountNotFound
+	}
+
+	return spv.SetConflictPolicy(policy)
+}
+
+// SetIgnore replaces ignore patterns of mount with provided ID.
+func (s *Supervisors) SetIgnore(mountID mount.ID, patterns string) error {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return mount.ErrMountNotFound
+	}
+
+	return spv.SetIgnore(patterns)
+}
+
+// Status returns synchronization progress of mount with provided ID.
+func (s *Supervisors) Status(mountID mount.ID) (*mount.Status, error) {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, mount.ErrMountNotFound
+	}
+
+	return spv.Status(), nil
+}
+
+// WatchStatus returns a channel which is notified each time the status of
+// mount with provided ID may have changed. Returned function stops watching.
+func (s *Supervisors) WatchStatus(mountID mount.ID) (<-chan struct{}, func(), error) {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, nil, mount.ErrMountNotFound
+	}
+
+	c, stop := spv.WatchStatus()
+	return c, stop, nil
+}
+
+// Pause stops synchronization of mount with provided ID.
+func (s *Supervisors) Pause(mountID mount.ID) error {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return mount.ErrMountNotFound
+	}
+
+	spv.Pause()
+	return nil
+}
+
+// Resume restarts synchronization of mount with provided ID. If limit is not
+// nil, it replaces the bandwidth limit of the mount.
+func (s *Supervisors) Resume(mountID mount.ID, limit *int64) error {
+	s.mu.RLock()
+	spv, ok := s.spvs[mountID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return mount.ErrMountNotFound
+	}
+
+	if limit != nil {
+		if *limit < 0 {
+			return fmt.Errorf("invalid bandwidth limit: %d", *limit)
+		}
+
+		spv.SetBandwidthLimit(*limit)
+	}
+
+	spv.Resume()
+	return nil
+}
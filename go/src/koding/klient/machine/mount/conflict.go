@@ -0,0 +1,308 @@
+package mount
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"koding/klient/machine/index"
+)
+
+// ErrConflictNotFound indicates that there is no conflict for a given file.
+var ErrConflictNotFound = errors.New("conflict not found")
+
+// ConflictPolicy defines how conflicting changes are resolved.
+type ConflictPolicy string
+
+// Available conflict resolution policies.
+const (
+	// ConflictManual keeps conflicting files intact until they are resolved
+	// explicitly. This is the default policy.
+	ConflictManual ConflictPolicy = ""
+
+	// ConflictLocalWins overwrites remote file with the local one.
+	ConflictLocalWins ConflictPolicy = "local-wins"
+
+	// ConflictRemoteWins overwrites local file with the remote one.
+	ConflictRemoteWins ConflictPolicy = "remote-wins"
+
+	// ConflictKeepBoth keeps remote file under its original name and stores
+	// the local one as a .conflict copy next to it.
+	ConflictKeepBoth ConflictPolicy = "keep-both"
+)
+
+// Valid checks if conflict policy is known.
+func (cp ConflictPolicy) Valid() error {
+	switch cp {
+	case ConflictManual, ConflictLocalWins, ConflictRemoteWins, ConflictKeepBoth:
+		return nil
+	default:
+		return fmt.Errorf("unknown conflict policy: %q", cp)
+	}
+}
+
+// ParseConflictPolicy converts a string form of conflict policy to its value.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	if s == ConflictManual.String() {
+		return ConflictManual, nil
+	}
+
+	cp := ConflictPolicy(s)
+	if err := cp.Valid(); err != nil {
+		return "", err
+	}
+
+	return cp, nil
+}
+
+// String returns a string form of conflict policy.
+func (cp ConflictPolicy) String() string {
+	if cp == ConflictManual {
+		return "manual"
+	}
+
+	return string(cp)
+}
+
+// Conflict describes a file that was modified both locally and remotely
+// since its last synchronization.
+type Conflict struct {
+	Name       string       `json:"name"`             // File name relative to mount root.
+	Local      *index.Entry `json:"local,omitempty"`  // Local file, nil when removed.
+	Remote     *index.Entry `json:"remote,omitempty"` // Remote file, nil when removed.
+	DetectedAt time.Time    `json:"detectedAt"`
+}
+
+// Conflicts returns all unresolved conflicts sorted by file name.
+func (s *Supervisor) Conflicts() []*Conflict {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cs := make([]*Conflict, 0, len(s.conflicts))
+	for _, c := range s.conflicts {
+		cs = append(cs, c)
+	}
+
+	sort.Sort(conflictsByName(cs))
+
+	return cs
+}
+
+// Resolve resolves conflict of a given file using provided policy.
+func (s *Supervisor) Resolve(name string, policy ConflictPolicy) error {
+	if err := policy.Valid(); err != nil {
+		return err
+	}
+
+	if policy == ConflictManual {
+		return errors.New("conflict resolution policy is not set")
+	}
+
+	s.mu.Lock()
+	_, ok := s.conflicts[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrConflictNotFound
+	}
+
+	if err := s.resolve(name, policy); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.conflicts, name)
+	s.mu.Unlock()
+
+	s.changed()
+
+	return s.saveConflicts()
+}
+
+// ConflictPolicy returns the policy used to resolve new conflicts.
+func (s *Supervisor) ConflictPolicy() ConflictPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.m.ConflictPolicy
+}
+
+// SetConflictPolicy changes the policy used to resolve new conflicts. Already
+// detected conflicts are not resolved.
+func (s *Supervisor) SetConflictPolicy(policy ConflictPolicy) error {
+	if err := policy.Valid(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.m.ConflictPolicy = policy
+	s.mu.Unlock()
+
+	return nil
+}
+
+// conflict registers a conflict of a given file and resolves it if mount
+// has conflict policy set.
+func (s *Supervisor) conflict(name string, remote *index.Entry) error {
+	local, err := s.localEntry(name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.conflicts[name] = &Conflict{
+		Name:       name,
+		Local:      local,
+		Remote:     remote,
+		DetectedAt: time.Now(),
+	}
+	s.mu.Unlock()
+
+	if err := s.saveConflicts(); err != nil {
+		s.log.Error("Cannot save conflicts: %v", err)
+	}
+
+	s.changed()
+
+	policy := s.ConflictPolicy()
+	s.log.Warning("Conflicting changes of %s, resolution policy: %s", name, policy)
+
+	if policy == ConflictManual {
+		return nil
+	}
+
+	return s.Resolve(name, policy)
+}
+
+// loadConflicts reads unresolved conflicts stored in supervisor working
+// directory. Missing file means that there are no conflicts.
+func (s *Supervisor) loadConflicts() error {
+	f, err := os.Open(filepath.Join(s.opts.WorkDir, ConflictsName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var cs []*Conflict
+	if err := json.NewDecoder(f).Decode(&cs); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for _, c := range cs {
+		s.conflicts[c.Name] = c
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// saveConflicts atomically writes unresolved conflicts to supervisor working
+// directory, so they survive supervisor restarts.
+func (s *Supervisor) saveConflicts() (err error) {
+	cs := s.Conflicts()
+	path := filepath.Join(s.opts.WorkDir, ConflictsName)
+
+	f, err := ioutil.TempFile(filepath.Split(path))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if err = json.NewEncoder(f).Encode(cs); err != nil {
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// resolve applies conflict policy to a given file.
+func (s *Supervisor) resolve(name string, policy ConflictPolicy) error {
+	local := s.localPath(name)
+
+	switch policy {
+	case ConflictLocalWins:
+		return s.push(name, true)
+	case ConflictRemoteWins:
+		return s.fetch(name, true)
+	}
+
+	// Keep both. If local file was removed, there is nothing to keep.
+	if _, err := os.Lstat(local); os.IsNotExist(err) {
+		return s.fetch(name, true)
+	}
+
+	copyName := s.conflictName(name)
+	if err := os.Rename(local, s.localPath(copyName)); err != nil {
+		return err
+	}
+
+	s.lidx.Del(name)
+
+	if err := s.push(copyName, true); err != nil {
+		return err
+	}
+
+	return s.fetch(name, true)
+}
+
+// conflictName finds a free name for the local copy of conflicting file.
+func (s *Supervisor) conflictName(name string) string {
+	for i := 0; ; i++ {
+		copyName := name + ".conflict"
+		if i != 0 {
+			copyName += "." + strconv.Itoa(i)
+		}
+
+		if _, err := os.Lstat(s.localPath(copyName)); !os.IsNotExist(err) {
+			continue
+		}
+
+		if _, ok := s.ridx.Lookup(copyName); ok {
+			continue
+		}
+
+		return copyName
+	}
+}
+
+// localEntry creates an entry of a given file stored in mount cache. It
+// returns nil entry when the file does not exist.
+func (s *Supervisor) localEntry(name string) (*index.Entry, error) {
+	_, entry, err := index.NewEntryFile(filepath.Join(s.opts.WorkDir, "data"), s.localPath(name), nil)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return entry, err
+}
+
+// localPath returns the path of a given file in mount cache.
+func (s *Supervisor) localPath(name string) string {
+	return filepath.Join(s.opts.WorkDir, "data", filepath.FromSlash(name))
+}
+
+type conflictsByName []*Conflict
+
+func (cs conflictsByName) Len() int           { return len(cs) }
+func (cs conflictsByName) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
+func (cs conflictsByName) Less(i, j int) bool { return cs[i].Name < cs[j].Name }
@@ -0,0 +1,156 @@
+package mount_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"koding/klient/machine/client"
+	"koding/klient/machine/client/clienttest"
+	"koding/klient/machine/index"
+	"koding/klient/machine/mount"
+	"koding/klient/machine/mount/mounttest"
+)
+
+func TestSupervisorConflict(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	remote, err := mounttest.TempFile(m.RemotePath)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	name := filepath.Base(remote)
+	local := filepath.Join(wd, "data", name)
+
+	commit := func(meta index.ChangeMeta) {
+		ctx := s.Commit(index.NewChange(name, meta))
+		if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+			t.Fatalf("want err = nil; got %v", err)
+		}
+	}
+
+	commit(index.ChangeMetaRemote | index.ChangeMetaAdd)
+
+	// Modify both copies of the file.
+	if err := ioutil.WriteFile(local, []byte("local change"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	if err := ioutil.WriteFile(remote, []byte("remote"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	// Remote change must not overwrite local one.
+	commit(index.ChangeMetaRemote | index.ChangeMetaUpdate)
+
+	cs := s.Conflicts()
+	if len(cs) != 1 || cs[0].Name != name {
+		t.Fatalf("want one conflict of %s; got %v", name, cs)
+	}
+
+	if b, err := ioutil.ReadFile(local); err != nil || string(b) != "local change" {
+		t.Fatalf("want cached content = %q; got %q (err = %v)", "local change", b, err)
+	}
+
+	// Local change must not overwrite remote one.
+	commit(index.ChangeMetaLocal | index.ChangeMetaUpdate)
+
+	if b, err := ioutil.ReadFile(remote); err != nil || string(b) != "remote" {
+		t.Fatalf("want remote content = %q; got %q (err = %v)", "remote", b, err)
+	}
+
+	if err := s.Resolve("unknown", mount.ConflictKeepBoth); err != mount.ErrConflictNotFound {
+		t.Fatalf("want err = %v; got %v", mount.ErrConflictNotFound, err)
+	}
+
+	if err := s.Resolve(name, mount.ConflictKeepBoth); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if cs := s.Conflicts(); len(cs) != 0 {
+		t.Fatalf("want no conflicts; got %v", cs)
+	}
+
+	tests := map[string]string{
+		local:               "remote",
+		remote:              "remote",
+		local + ".conflict": "local change",
+		filepath.Join(m.RemotePath, name) + ".conflict": "local change",
+	}
+
+	for path, content := range tests {
+		if b, err := ioutil.ReadFile(path); err != nil || string(b) != content {
+			t.Errorf("want %s content = %q; got %q (err = %v)", path, content, b, err)
+		}
+	}
+}
+
+func TestSupervisorConflictRestore(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	mountID := mount.MakeID()
+	s, err := mount.NewSupervisor(mountID, m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	if err := s.SetConflictPolicy("unknown"); err == nil {
+		t.Fatal("want err != nil; got nil")
+	}
+
+	remote, err := mounttest.TempFile(m.RemotePath)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	name := filepath.Base(remote)
+
+	// Local file which is not known to supervisor conflicts with remote one.
+	if err := ioutil.WriteFile(filepath.Join(wd, "data", name), []byte("local"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	ctx := s.Commit(index.NewChange(name, index.ChangeMetaRemote|index.ChangeMetaAdd))
+	if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if cs := s.Conflicts(); len(cs) != 1 || cs[0].Name != name {
+		t.Fatalf("want one conflict of %s; got %v", name, cs)
+	}
+
+	// Conflicts are kept when the mount is supervised again.
+	if s, err = mount.NewSupervisor(mountID, m, opts); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if cs := s.Conflicts(); len(cs) != 1 || cs[0].Name != name {
+		t.Fatalf("want one conflict of %s; got %v", name, cs)
+	}
+}
@@ -0,0 +1,62 @@
+package mount
+
+import (
+	"os"
+	"path/filepath"
+
+	"koding/klient/machine/index"
+)
+
+// ignore returns the current ignore matcher of the mount.
+func (s *Supervisor) ignore() *index.Ignore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ign
+}
+
+// ignored checks if a given file is excluded from synchronization.
+func (s *Supervisor) ignored(name string) bool {
+	var isDir bool
+	if info, err := os.Lstat(s.localPath(name)); err == nil {
+		isDir = info.IsDir()
+	} else if e := entryOf(s.ridx, name); e != nil {
+		isDir = e.Mode.IsDir()
+	}
+
+	return s.ignore().Match(name, isDir)
+}
+
+// SetIgnore replaces ignore patterns of the mount. Patterns are separated by
+// new lines. Files which are already synchronized are not removed.
+func (s *Supervisor) SetIgnore(patterns string) error {
+	s.mu.Lock()
+	s.m.Ignore = patterns
+	s.mu.Unlock()
+
+	return s.reloadIgnore()
+}
+
+// ignorePatterns returns ignore patterns of the mount.
+func (s *Supervisor) ignorePatterns() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.m.IgnorePatterns()
+}
+
+// reloadIgnore reads ignore files stored in mount cache and merges them with
+// mount ignore patterns. Since mount cache mirrors remote directory, this
+// gives the same result as on remote machine.
+func (s *Supervisor) reloadIgnore() error {
+	ign, err := index.ReadIgnore(filepath.Join(s.opts.WorkDir, "data"), s.ignorePatterns())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.ign = ign
+	s.mu.Unlock()
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	uuid "github.com/satori/go.uuid"
@@ -48,6 +49,33 @@ func (ids IDSlice) Less(i, j int) bool { return ids[i] < ids[j] }
 type Mount struct {
 	Path       string `json:"path"`       // Mount point.
 	RemotePath string `json:"remotePath"` // Remote directory path.
+
+	// ConflictPolicy defines how files changed both locally and remotely
+	// are synchronized. By default, conflicts need to be resolved manually.
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// Ignore stores gitignore compatible patterns, separated by new lines,
+	// of files which are not synchronized. They are applied after patterns
+	// from .gitignore and .kdignore files stored in mounted directory, so
+	// they can override them.
+	Ignore string `json:"ignore,omitempty"`
+
+	// BandwidthLimit is the maximum number of data bytes per second that can
+	// be transferred by mount synchronization. Zero means no limit.
+	BandwidthLimit int64 `json:"bandwidthLimit,omitempty"`
+
+	// Paused is set when synchronization of the mount was paused by the
+	// user. Paused mounts stay paused after klient restarts.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// IgnorePatterns returns ignore patterns of the mount.
+func (m Mount) IgnorePatterns() []string {
+	if m.Ignore == "" {
+		return nil
+	}
+
+	return strings.Split(m.Ignore, "\n")
 }
 
 // String return a string form of stored mount.
@@ -96,6 +124,20 @@ func (mb *MountBook) Add(id ID, mount Mount) error {
 	return nil
 }
 
+// Update replaces the mount with provided ID. ErrMountNotFound is returned
+// when the mount book doesn't contain the mount.
+func (mb *MountBook) Update(id ID, mount Mount) error {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if _, ok := mb.mounts[id]; !ok {
+		return ErrMountNotFound
+	}
+
+	mb.mounts[id] = mount
+	return nil
+}
+
 // Remove removes the mount with provided ID from mount book.
 func (mb *MountBook) Remove(id ID) {
 	mb.mu.Lock()
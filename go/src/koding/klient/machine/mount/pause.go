@@ -0,0 +1,114 @@
+package mount
+
+import (
+	"time"
+
+	"koding/klient/machine/index"
+
+	"github.com/juju/ratelimit"
+)
+
+// Pause stops dispatching queued changes. Changes committed while the mount is
+// paused are coalesced and synchronized after resume. Change which is being
+// synchronized when Pause is called is not interrupted.
+func (s *Supervisor) Pause() {
+	s.mu.Lock()
+	if s.resumeC == nil {
+		s.resumeC = make(chan struct{})
+	}
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+// Resume restarts synchronization of queued changes.
+func (s *Supervisor) Resume() {
+	s.mu.Lock()
+	if s.resumeC != nil {
+		close(s.resumeC)
+		s.resumeC = nil
+	}
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+// Paused checks if mount synchronization is paused.
+func (s *Supervisor) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.resumeC != nil
+}
+
+// SetBandwidthLimit changes the maximum number of data bytes per second that
+// can be transferred by mount synchronization. Zero disables the limit.
+func (s *Supervisor) SetBandwidthLimit(limit int64) {
+	bucket := newBucket(limit)
+
+	s.mu.Lock()
+	s.bucket = bucket
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+// waitResumed blocks while synchronization is paused. It returns false when
+// the supervisor was dropped.
+func (s *Supervisor) waitResumed() bool {
+	s.mu.Lock()
+	resumeC := s.resumeC
+	s.mu.Unlock()
+
+	if resumeC == nil {
+		return true
+	}
+
+	select {
+	case <-resumeC:
+		return true
+	case <-s.dropC:
+		return false
+	}
+}
+
+// limiter returns a delta limiter which keeps mount bandwidth limit. Nil is
+// returned when bandwidth is not limited.
+func (s *Supervisor) limiter() index.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bucket == nil {
+		return nil
+	}
+
+	return index.LimiterFunc(s.throttle)
+}
+
+// throttle delays transfer of n data bytes in order to keep bandwidth limit.
+func (s *Supervisor) throttle(n int64) {
+	s.mu.Lock()
+	bucket := s.bucket
+	s.mu.Unlock()
+
+	if bucket == nil || n <= 0 {
+		return
+	}
+
+	if d := bucket.Take(n); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-s.dropC:
+		}
+	}
+}
+
+// newBucket creates token bucket which limits bandwidth to a given number of
+// bytes per second. Nil is returned when limit is not set.
+func newBucket(limit int64) *ratelimit.Bucket {
+	if limit <= 0 {
+		return nil
+	}
+
+	return ratelimit.NewBucketWithRate(float64(limit), limit)
+}
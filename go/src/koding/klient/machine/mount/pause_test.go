@@ -0,0 +1,139 @@
+package mount_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"koding/klient/machine/client"
+	"koding/klient/machine/client/clienttest"
+	"koding/klient/machine/index"
+	"koding/klient/machine/mount"
+	"koding/klient/machine/mount/mounttest"
+)
+
+func TestSupervisorPause(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	s.Pause()
+
+	if !s.Paused() || !s.Status().Paused {
+		t.Fatal("want mount to be paused")
+	}
+
+	remote, err := mounttest.TempFile(m.RemotePath)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	name := filepath.Base(remote)
+
+	ctx := s.Commit(index.NewChange(name, index.ChangeMetaRemote|index.ChangeMetaAdd))
+	if err := mounttest.WaitForContextClose(ctx, 100*time.Millisecond); err == nil {
+		t.Fatal("want change to wait until mount is resumed")
+	}
+
+	if _, err := os.Stat(filepath.Join(wd, "data", name)); !os.IsNotExist(err) {
+		t.Fatalf("want err = os.ErrNotExist; got %v", err)
+	}
+
+	s.Resume()
+
+	if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(wd, "data", name)); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+}
+
+func TestSupervisorBandwidthLimit(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	m.BandwidthLimit = 10 * 1024
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	if err := ioutil.WriteFile(filepath.Join(m.RemotePath, "file.bin"), make([]byte, 15*1024), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	// The first 10KiB are available immediately, the rest must wait.
+	start := time.Now()
+	ctx := s.Commit(index.NewChange("file.bin", index.ChangeMetaRemote|index.ChangeMetaAdd))
+	if err := mounttest.WaitForContextClose(ctx, 5*time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("want sync to be throttled; took %s", elapsed)
+	}
+
+	if limit := s.Status().BandwidthLimit; limit != m.BandwidthLimit {
+		t.Errorf("want bandwidth limit = %d; got %d", m.BandwidthLimit, limit)
+	}
+}
+
+func TestSupervisorPausedMount(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	m.Paused = true
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	if !s.Paused() {
+		t.Fatal("want mount to be restored as paused")
+	}
+
+	s.Resume()
+
+	if s.Paused() {
+		t.Fatal("want mount to be resumed")
+	}
+}
@@ -0,0 +1,81 @@
+package mount
+
+import (
+	"path/filepath"
+	"time"
+
+	"koding/klient/machine/index"
+)
+
+// DefaultScanInterval defines how often mount cache and remote directory are
+// compared with their indexes when SupervisorOpts.ScanInterval is not set.
+const DefaultScanInterval = 10 * time.Second
+
+// Scan compares mount cache and remote directory with their known states and
+// commits all detected changes for synchronization.
+func (s *Supervisor) Scan() error {
+	s.scanLocal()
+	return s.scanRemote()
+}
+
+// scan periodically looks for changes made to both sides of the mount. It
+// returns when the supervisor is dropped.
+func (s *Supervisor) scan() {
+	interval := s.opts.ScanInterval
+	if interval <= 0 {
+		interval = DefaultScanInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if s.Paused() {
+				continue
+			}
+
+			if err := s.Scan(); err != nil {
+				s.log.Warning("Cannot scan remote directory: %v", err)
+			}
+		case <-s.dropC:
+			return
+		}
+	}
+}
+
+// scanLocal commits changes made to mount cache since the last
+// synchronization.
+func (s *Supervisor) scanLocal() {
+	cs := s.lidx.CompareIgnore(filepath.Join(s.opts.WorkDir, "data"), s.ignore())
+	s.commitAll(cs, index.ChangeMetaLocal)
+}
+
+// scanRemote downloads remote index and commits changes made to remote
+// directory since the last synchronization.
+func (s *Supervisor) scanRemote() error {
+	idx, err := s.fetchRemoteIdx()
+	if err != nil {
+		return err
+	}
+
+	s.commitAll(s.ridx.Diff(idx), index.ChangeMetaRemote)
+	return nil
+}
+
+// commitAll commits provided changes in a given direction. Changes of files
+// with unresolved conflicts are skipped since they wait for user decision.
+func (s *Supervisor) commitAll(cs index.ChangeSlice, dir index.ChangeMeta) {
+	for _, c := range cs {
+		s.mu.Lock()
+		_, ok := s.conflicts[c.Name()]
+		s.mu.Unlock()
+
+		if ok {
+			continue
+		}
+
+		s.Commit(index.NewChange(c.Name(), c.Meta()|dir))
+	}
+}
@@ -0,0 +1,171 @@
+package mount
+
+import (
+	"sort"
+	"time"
+)
+
+// FileError describes the last failed synchronization of a file.
+type FileError struct {
+	Name  string    `json:"name"`  // File name relative to mount root.
+	Error string    `json:"error"` // Synchronization error message.
+	Time  time.Time `json:"time"`  // Time of the failed synchronization.
+}
+
+// Status describes the progress of mount synchronization.
+type Status struct {
+	Queued    int `json:"queued"`    // Changes waiting in the queue.
+	InFlight  int `json:"inFlight"`  // Changes being synchronized.
+	Failed    int `json:"failed"`    // Files which synchronization failed.
+	Conflicts int `json:"conflicts"` // Unresolved conflicts.
+
+	Paused         bool  `json:"paused"`                   // Synchronization is paused.
+	BandwidthLimit int64 `json:"bandwidthLimit,omitempty"` // Bytes per second, zero if unlimited.
+
+	SentBytes     int64 `json:"sentBytes"`     // Data bytes uploaded to remote.
+	ReceivedBytes int64 `json:"receivedBytes"` // Data bytes downloaded from remote.
+
+	// LastSync is the time of the last successful synchronization. It is zero
+	// when no file was synchronized yet.
+	LastSync time.Time `json:"lastSync"`
+
+	// Errors stores the last errors of files that failed to synchronize,
+	// sorted by file name.
+	Errors []*FileError `json:"errors,omitempty"`
+}
+
+// Status returns the current synchronization progress of the mount.
+func (s *Supervisor) Status() *Status {
+	items, queued := s.a.Status()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := &Status{
+		Queued:        queued,
+		InFlight:      items - queued,
+		Failed:        len(s.errs),
+		Conflicts:     len(s.conflicts),
+		SentBytes:     s.sent,
+		ReceivedBytes: s.received,
+		LastSync:      s.lastSync,
+		Paused:        s.resumeC != nil,
+		Errors:        make([]*FileError, 0, len(s.errs)),
+	}
+
+	if s.bucket != nil {
+		st.BandwidthLimit = int64(s.bucket.Rate())
+	}
+
+	// Popped events stay in anteroom until they are done.
+	if st.InFlight < 0 {
+		st.InFlight = 0
+	}
+
+	for _, fe := range s.errs {
+		st.Errors = append(st.Errors, fe)
+	}
+
+	sort.Sort(fileErrorsByName(st.Errors))
+
+	return st
+}
+
+// WatchStatus returns a channel which receives a value each time the status
+// of the mount may have changed. Notifications are coalesced, so the current
+// status should be read with Status method. The channel is closed when
+// returned stop function is called or when the supervisor is dropped.
+func (s *Supervisor) WatchStatus() (<-chan struct{}, func()) {
+	c := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subs == nil {
+		close(c)
+		return c, func() {}
+	}
+
+	s.subs[c] = struct{}{}
+
+	stop := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, ok := s.subs[c]; ok {
+			delete(s.subs, c)
+			close(c)
+		}
+	}
+
+	return c, stop
+}
+
+// changed notifies status watchers. It never blocks.
+func (s *Supervisor) changed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.subs {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// unwatchAll closes all status watcher channels and prevents new ones from
+// being registered.
+func (s *Supervisor) unwatchAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.subs {
+		close(c)
+	}
+
+	s.subs = nil
+}
+
+// synced records successful synchronization of a given file. Files that are
+// in conflict are not considered synchronized.
+func (s *Supervisor) synced(name string) {
+	s.mu.Lock()
+	delete(s.errs, name)
+	if _, ok := s.conflicts[name]; !ok {
+		s.lastSync = time.Now()
+	}
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+// failed records failed synchronization of a given file.
+func (s *Supervisor) failed(name string, err error) {
+	s.mu.Lock()
+	s.errs[name] = &FileError{
+		Name:  name,
+		Error: err.Error(),
+		Time:  time.Now(),
+	}
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+// transferred adds the number of uploaded and downloaded data bytes to mount
+// statistics.
+func (s *Supervisor) transferred(sent, received int64) {
+	s.mu.Lock()
+	s.sent += sent
+	s.received += received
+	s.mu.Unlock()
+
+	s.changed()
+}
+
+type fileErrorsByName []*FileError
+
+func (fes fileErrorsByName) Len() int           { return len(fes) }
+func (fes fileErrorsByName) Swap(i, j int)      { fes[i], fes[j] = fes[j], fes[i] }
+func (fes fileErrorsByName) Less(i, j int) bool { return fes[i].Name < fes[j].Name }
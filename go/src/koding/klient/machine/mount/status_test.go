@@ -0,0 +1,132 @@
+package mount_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"koding/klient/machine/client"
+	"koding/klient/machine/client/clienttest"
+	"koding/klient/machine/index"
+	"koding/klient/machine/mount"
+	"koding/klient/machine/mount/mounttest"
+)
+
+func TestSupervisorStatus(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	if st := s.Status(); !st.LastSync.IsZero() || st.ReceivedBytes != 0 {
+		t.Fatalf("want empty status; got %#v", st)
+	}
+
+	remote, err := mounttest.TempFile(m.RemotePath)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	ctx := s.Commit(index.NewChange(filepath.Base(remote), index.ChangeMetaRemote|index.ChangeMetaAdd))
+	if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	st := s.Status()
+	if st.LastSync.IsZero() {
+		t.Errorf("want last sync time to be set")
+	}
+	if st.ReceivedBytes != int64(len("sample")) {
+		t.Errorf("want received bytes = %d; got %d", len("sample"), st.ReceivedBytes)
+	}
+
+	// Local file blocks creation of remote directory.
+	if err := os.MkdirAll(filepath.Join(m.RemotePath, "dir"), 0755); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(m.RemotePath, "dir", "file"), []byte("sample"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(wd, "data", "dir"), []byte("sample"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	ctx = s.Commit(index.NewChange("dir/file", index.ChangeMetaRemote|index.ChangeMetaAdd))
+	if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	st = s.Status()
+	if st.Failed != 1 || len(st.Errors) != 1 || st.Errors[0].Name != "dir/file" {
+		t.Fatalf("want one failed file; got %#v", st)
+	}
+	if st.Queued != 0 || st.InFlight != 0 {
+		t.Errorf("want no pending changes; got queued = %d, in flight = %d", st.Queued, st.InFlight)
+	}
+}
+
+func TestSupervisorWatchStatus(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	c, stop := s.WatchStatus()
+	defer stop()
+
+	s.Pause()
+
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for status change")
+	}
+
+	if st := s.Status(); !st.Paused {
+		t.Fatalf("want mount to be paused; got %#v", st)
+	}
+
+	// Watchers are closed when supervisor is dropped.
+	if err := s.Drop(); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-c:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for watcher to be closed")
+		}
+	}
+}
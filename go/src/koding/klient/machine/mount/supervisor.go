@@ -3,20 +3,24 @@ package mount
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"koding/klient/machine"
 	"koding/klient/machine/client"
 	"koding/klient/machine/index"
 
+	"github.com/juju/ratelimit"
 	"github.com/koding/logging"
 )
 
 const (
 	LocalIndexName  = "index.local"  // file name of local directory index.
 	RemoteIndexName = "index.remote" // file name of remote directory index.
+	ConflictsName   = "conflicts"    // file name of unresolved conflicts.
 )
 
 // Info stores information about current mount status.
@@ -43,10 +47,15 @@ type SupervisorOpts struct {
 	//   |-data
 	//   | +-... // mounted directory cache.
 	//   |-index.remote
-	//   +-index.local
+	//   |-index.local
+	//   +-conflicts
 	//
 	WorkDir string
 
+	// ScanInterval defines how often mount cache and remote directory are
+	// checked for changes. If zero, DefaultScanInterval is used.
+	ScanInterval time.Duration
+
 	// Log is used for logging. If nil, default logger will be created.
 	Log logging.Logger
 }
@@ -78,6 +87,22 @@ type Supervisor struct {
 
 	ridx *index.Index // known state of remote index.
 	lidx *index.Index // known state of local index.
+
+	a *Anteroom // changes waiting for synchronization.
+
+	once  sync.Once
+	dropC chan struct{} // closed when supervisor is dropped.
+
+	mu        sync.Mutex
+	conflicts map[string]*Conflict       // unresolved conflicts by file name.
+	ign       *index.Ignore              // files excluded from synchronization.
+	errs      map[string]*FileError      // last synchronization errors by file name.
+	sent      int64                      // number of uploaded data bytes.
+	received  int64                      // number of downloaded data bytes.
+	lastSync  time.Time                  // time of the last successful sync.
+	resumeC   chan struct{}              // non-nil when synchronization is paused.
+	bucket    *ratelimit.Bucket          // nil when bandwidth is not limited.
+	subs      map[chan struct{}]struct{} // status watchers, nil when dropped.
 }
 
 // NewSupervisor creates a new supervisor instance for a given mount. It ensures
@@ -87,10 +112,27 @@ func NewSupervisor(mountID ID, m Mount, opts SupervisorOpts) (*Supervisor, error
 		return nil, err
 	}
 
+	if err := m.ConflictPolicy.Valid(); err != nil {
+		return nil, err
+	}
+
+	if m.BandwidthLimit < 0 {
+		return nil, fmt.Errorf("invalid bandwidth limit: %d", m.BandwidthLimit)
+	}
+
 	s := &Supervisor{
-		opts:    opts,
-		mountID: mountID,
-		m:       m,
+		opts:      opts,
+		mountID:   mountID,
+		m:         m,
+		conflicts: make(map[string]*Conflict),
+		errs:      make(map[string]*FileError),
+		dropC:     make(chan struct{}),
+		subs:      make(map[chan struct{}]struct{}),
+		bucket:    newBucket(m.BandwidthLimit),
+	}
+
+	if m.Paused {
+		s.resumeC = make(chan struct{})
 	}
 
 	if opts.Log != nil {
@@ -104,6 +146,16 @@ func NewSupervisor(mountID ID, m Mount, opts SupervisorOpts) (*Supervisor, error
 		return nil, err
 	}
 
+	// Restore conflicts which were not resolved before.
+	if err := s.loadConflicts(); err != nil {
+		return nil, err
+	}
+
+	// Read ignore patterns from mount cache.
+	if err := s.reloadIgnore(); err != nil {
+		return nil, err
+	}
+
 	// Fetch or load remote index.
 	var err error
 	if s.ridx, err = s.loadIdx(RemoteIndexName, s.fetchRemoteIdx); err != nil {
@@ -116,18 +168,31 @@ func NewSupervisor(mountID ID, m Mount, opts SupervisorOpts) (*Supervisor, error
 	}
 
 	// Update local index if needed.
-	if err := s.updateLocal(); err != nil {
+	cs, err := s.updateLocal()
+	if err != nil {
 		return nil, err
 	}
 
+	// Start synchronizing committed changes. Files changed in mount cache
+	// while it was not supervised are synchronized first.
+	s.a = NewAnteroom()
+	go s.sync()
+
+	s.commitAll(cs, index.ChangeMetaLocal)
+	go s.scan()
+
 	return s, nil
 }
 
 // Info returns the current status of supervised indexes.
 func (s *Supervisor) Info() *Info {
+	s.mu.Lock()
+	m := s.m
+	s.mu.Unlock()
+
 	return &Info{
 		ID:           s.mountID,
-		Mount:        s.m,
+		Mount:        m,
 		SyncCount:    s.lidx.Count(-1),
 		AllCount:     s.ridx.Count(-1),
 		SyncDiskSize: s.lidx.DiskSize(-1),
@@ -137,6 +202,10 @@ func (s *Supervisor) Info() *Info {
 
 // Drop closes synced mount and cleans up all resources acquired by it.
 func (s *Supervisor) Drop() error {
+	s.once.Do(func() { close(s.dropC) })
+	s.a.Close()
+	s.unwatchAll()
+
 	return os.RemoveAll(s.opts.WorkDir)
 }
 
@@ -167,23 +236,24 @@ type idxFunc func() (*index.Index, error)
 // fetchRemoteIdx downloads remote index.
 func (s *Supervisor) fetchRemoteIdx() (*index.Index, error) {
 	spv := client.NewSupervised(s.opts.ClientFunc, 30*time.Second)
-	return spv.MountGetIndex(s.m.RemotePath)
+	return spv.MountGetIndex(s.m.RemotePath, s.ignorePatterns())
 }
 
 // fetchLocalIdx always scans mount cache directory and creates new index.
 func (s *Supervisor) fetchLocalIdx() (*index.Index, error) {
-	return index.NewIndexFiles(filepath.Join(s.opts.WorkDir, "data"))
+	return index.NewIndexFilesIgnore(filepath.Join(s.opts.WorkDir, "data"), s.ignore())
 }
 
-// updateLocal updates local index and saves it to cache directory.
-func (s *Supervisor) updateLocal() error {
+// updateLocal updates local index and saves it to cache directory. It returns
+// changes applied to the index.
+func (s *Supervisor) updateLocal() (index.ChangeSlice, error) {
 	dataPath := filepath.Join(s.opts.WorkDir, "data")
-	cs := s.lidx.Compare(dataPath)
+	cs := s.lidx.CompareIgnore(dataPath, s.ignore())
 
 	if len(cs) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	s.lidx.Apply(dataPath, cs)
-	return index.SaveIndex(s.lidx, filepath.Join(s.opts.WorkDir, LocalIndexName))
+	return cs, index.SaveIndex(s.lidx, filepath.Join(s.opts.WorkDir, LocalIndexName))
 }
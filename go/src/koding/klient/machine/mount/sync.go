@@ -0,0 +1,207 @@
+package mount
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"koding/klient/machine/client"
+	"koding/klient/machine/index"
+)
+
+// Commit adds a change to the queue of files waiting for synchronization. The
+// returned context is closed when the change is synchronized.
+func (s *Supervisor) Commit(c *index.Change) context.Context {
+	defer s.changed()
+	return s.a.Commit(c)
+}
+
+// sync dispatches events from anteroom and synchronizes their changes. It
+// returns when anteroom is closed. Events are not dispatched while
+// synchronization is paused.
+func (s *Supervisor) sync() {
+	for s.waitResumed() {
+		ev, ok := <-s.a.Events()
+		if !ok {
+			return
+		}
+
+		s.changed()
+
+		if ev.Valid() {
+			if err := s.syncChange(ev.Change()); err != nil {
+				s.log.Error("Cannot synchronize %s: %v", ev.Change().Name(), err)
+				s.failed(ev.Change().Name(), err)
+			} else {
+				s.synced(ev.Change().Name())
+			}
+		}
+
+		ev.Done()
+		s.changed()
+	}
+}
+
+// syncChange synchronizes a single change. Changes without direction are
+// treated as local ones. Changes of ignored files are skipped.
+func (s *Supervisor) syncChange(c *index.Change) (err error) {
+	name := c.Name()
+	if s.ignored(name) {
+		s.log.Debug("Skipping ignored %s", name)
+		return nil
+	}
+
+	if c.Meta()&index.ChangeMetaRemote != 0 {
+		err = s.fetch(name, false)
+	} else {
+		err = s.push(name, false)
+	}
+
+	if err == nil && index.IsIgnoreFile(name) {
+		err = s.reloadIgnore()
+	}
+
+	return err
+}
+
+// fetch synchronizes a remote file with its copy in mount cache. Only chunks
+// that are not present in the cached file are downloaded. Unless forced, the
+// cached file is not overwritten if it was changed since last synchronization.
+func (s *Supervisor) fetch(name string, force bool) error {
+	var (
+		local = s.localPath(name)
+		known = entryOf(s.lidx, name)
+		spv   = client.NewSupervised(s.opts.ClientFunc, 30*time.Second)
+	)
+
+	d, err := spv.MountGetDelta(s.m.RemotePath, name, chunksOf(known))
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		diverged, err := index.Diverged(local, known)
+		if err != nil {
+			return err
+		}
+
+		if diverged {
+			var remote *index.Entry
+			if d != nil {
+				remote = d.Entry
+			}
+
+			return s.conflict(name, remote)
+		}
+	}
+
+	// Remote file was removed.
+	if d == nil {
+		if err := os.RemoveAll(local); err != nil {
+			return err
+		}
+
+		s.lidx.Del(name)
+		s.ridx.Del(name)
+		return nil
+	}
+
+	entry, err := index.ApplyDeltaLimit(local, d, s.limiter())
+	if err != nil && known != nil {
+		// Cached file could have been changed since it was indexed. Try
+		// again with entire file content.
+		if d, err = spv.MountGetDelta(s.m.RemotePath, name, nil); err == nil && d != nil {
+			entry, err = index.ApplyDeltaLimit(local, d, s.limiter())
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if d == nil {
+		// Remote file was removed in the meantime, next change will
+		// handle this.
+		return nil
+	}
+
+	s.log.Debug("Fetched %s: %d of %d bytes transferred", name, d.Size(), d.Entry.Size)
+	s.transferred(0, d.Size())
+
+	s.lidx.Add(name, entry)
+	s.ridx.Add(name, d.Entry)
+	return nil
+}
+
+// push synchronizes a file from mount cache with its remote counterpart. Only
+// chunks that are not present in the remote file are uploaded. Unless forced,
+// the remote file is not overwritten if it was changed since last
+// synchronization.
+func (s *Supervisor) push(name string, force bool) error {
+	var (
+		local = s.localPath(name)
+		base  = entryOf(s.ridx, name)
+		spv   = client.NewSupervised(s.opts.ClientFunc, 30*time.Second)
+		d     *index.Delta
+	)
+
+	// Nil delta removes remote file.
+	if _, err := os.Lstat(local); err == nil {
+		if d, err = index.NewDeltaLimit(local, chunksOf(base), s.limiter()); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	entry, err := spv.MountPatch(s.m.RemotePath, name, d, base, force)
+	if err == index.ErrConflict {
+		return s.conflict(name, entry)
+	}
+
+	if err != nil && d != nil && base != nil {
+		// Remote file could have been changed since it was indexed. Try
+		// again with entire file content.
+		if d, err = index.NewDeltaLimit(local, nil, s.limiter()); err == nil {
+			entry, err = spv.MountPatch(s.m.RemotePath, name, d, base, force)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if d == nil {
+		s.lidx.Del(name)
+		s.ridx.Del(name)
+		return nil
+	}
+
+	s.log.Debug("Pushed %s: %d of %d bytes transferred", name, d.Size(), d.Entry.Size)
+	s.transferred(d.Size(), 0)
+
+	s.lidx.Add(name, d.Entry)
+	s.ridx.Add(name, entry)
+	return nil
+}
+
+// entryOf looks up the entry of a given file in provided index. Nil is returned
+// when the entry is not present or it was created only as a parent of other
+// entries.
+func entryOf(idx *index.Index, name string) *index.Entry {
+	nd, ok := idx.Lookup(name)
+	if !ok || nd.Entry == nil || nd.Entry.MTime == 0 {
+		return nil
+	}
+
+	return nd.Entry
+}
+
+// chunksOf returns chunk signatures of a given entry.
+func chunksOf(e *index.Entry) []index.Chunk {
+	if e == nil {
+		return nil
+	}
+
+	return e.Chunks
+}
@@ -0,0 +1,198 @@
+package mount_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"koding/klient/machine/client"
+	"koding/klient/machine/client/clienttest"
+	"koding/klient/machine/index"
+	"koding/klient/machine/mount"
+	"koding/klient/machine/mount/mounttest"
+)
+
+func TestSupervisorSync(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	// Remote file is downloaded to mount cache.
+	remote, err := mounttest.TempFile(m.RemotePath)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	name := filepath.Base(remote)
+
+	ctx := s.Commit(index.NewChange(name, index.ChangeMetaRemote|index.ChangeMetaAdd))
+	if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	local := filepath.Join(wd, "data", name)
+	if b, err := ioutil.ReadFile(local); err != nil || string(b) != "sample" {
+		t.Fatalf("want cached content = %q; got %q (err = %v)", "sample", b, err)
+	}
+
+	// Cached file update is uploaded to remote.
+	if err := ioutil.WriteFile(local, []byte("updated"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	ctx = s.Commit(index.NewChange(name, index.ChangeMetaLocal|index.ChangeMetaUpdate))
+	if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if b, err := ioutil.ReadFile(remote); err != nil || string(b) != "updated" {
+		t.Fatalf("want remote content = %q; got %q (err = %v)", "updated", b, err)
+	}
+
+	// Cached file removal is propagated to remote.
+	if err := os.Remove(local); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	ctx = s.Commit(index.NewChange(name, index.ChangeMetaLocal|index.ChangeMetaRemove))
+	if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if _, err := os.Stat(remote); !os.IsNotExist(err) {
+		t.Errorf("want err = os.ErrNotExist; got %v", err)
+	}
+}
+
+func TestSupervisorSyncIgnore(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	m.Ignore = "*.log"
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir: wd,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	for _, name := range []string{"debug.log", "trace.out"} {
+		if err := ioutil.WriteFile(filepath.Join(m.RemotePath, name), []byte("sample"), 0644); err != nil {
+			t.Fatalf("want err = nil; got %v", err)
+		}
+	}
+
+	// Files can be ignored also by ignore files stored in mount root.
+	if err := ioutil.WriteFile(filepath.Join(m.RemotePath, index.KDIgnoreName), []byte("*.out\n"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	for _, name := range []string{index.KDIgnoreName, "debug.log", "trace.out"} {
+		ctx := s.Commit(index.NewChange(name, index.ChangeMetaRemote|index.ChangeMetaAdd))
+		if err := mounttest.WaitForContextClose(ctx, time.Second); err != nil {
+			t.Fatalf("want err = nil; got %v", err)
+		}
+	}
+
+	tests := map[string]bool{
+		index.KDIgnoreName: true,
+		"debug.log":        false,
+		"trace.out":        false,
+	}
+
+	for name, synced := range tests {
+		if _, err := os.Stat(filepath.Join(wd, "data", name)); (err == nil) != synced {
+			t.Errorf("%s: want synced = %t; got err = %v", name, synced, err)
+		}
+	}
+}
+
+func TestSupervisorScan(t *testing.T) {
+	wd, m, clean, err := mounttest.MountDirs()
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer clean()
+
+	opts := mount.SupervisorOpts{
+		ClientFunc: func() (client.Client, error) {
+			return clienttest.NewClient(), nil
+		},
+		WorkDir:      wd,
+		ScanInterval: time.Hour,
+	}
+	s, err := mount.NewSupervisor(mount.MakeID(), m, opts)
+	if err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+	defer s.Drop()
+
+	// File created in mount cache is uploaded to remote.
+	local := filepath.Join(wd, "data", "local.txt")
+	if err := ioutil.WriteFile(local, []byte("local"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if err := s.Scan(); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if err := waitForContent(filepath.Join(m.RemotePath, "local.txt"), "local", time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	// File created in remote directory is downloaded to mount cache.
+	if err := ioutil.WriteFile(filepath.Join(m.RemotePath, "remote.txt"), []byte("remote"), 0644); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if err := s.Scan(); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+
+	if err := waitForContent(filepath.Join(wd, "data", "remote.txt"), "remote", time.Second); err != nil {
+		t.Fatalf("want err = nil; got %v", err)
+	}
+}
+
+// waitForContent waits until a given file has the provided content.
+func waitForContent(path, content string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		b, err := ioutil.ReadFile(path)
+		if err == nil && string(b) == content {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s content: got %q (err = %v)", path, b, err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
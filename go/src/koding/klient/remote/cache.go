@@ -137,6 +137,7 @@ func (r *Remote) CacheFolderHandler(kreq *kite.Request) (interface{}, error) {
 			LocalToRemote:     params.LocalToRemote,
 			IgnoreFile:        params.IgnoreFile,
 			IncludePath:       params.IncludePath,
+			BandwidthLimit:    params.BandwidthLimit,
 		},
 		Interval: params.Interval,
 	}
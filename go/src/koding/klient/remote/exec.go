@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/koding/kite"
+	"github.com/koding/kite/dnode"
 
+	"koding/klient/command"
 	"koding/klient/remote/req"
 )
 
@@ -56,3 +58,110 @@ func (r *Remote) ExecHandler(kreq *kite.Request) (interface{}, error) {
 
 	return remoteMachine.Tell("exec", execReq)
 }
+
+// ExecStreamHandler runs the given command on the given remote klient and
+// streams its output back to the caller. The response is a handle which
+// forwards input and kill requests to the remote process.
+func (r *Remote) ExecStreamHandler(kreq *kite.Request) (interface{}, error) {
+	log := r.log.New("remote.execStream")
+
+	var params req.ExecStream
+
+	if kreq.Args == nil {
+		return nil, errors.New("Required arguments were not passed.")
+	}
+
+	if err := kreq.Args.One().Unmarshal(&params); err != nil {
+		err = fmt.Errorf(
+			"remote.execStream: Error '%s' while unmarshalling request '%s'\n",
+			err, kreq.Args.One(),
+		)
+
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	switch {
+	case params.Machine == "":
+		return nil, errors.New("Missing required argument `machine`.")
+	case params.Command == "":
+		return nil, errors.New("Missing required argument `command`.")
+	}
+
+	log = log.New(
+		"machineName", params.Machine,
+	)
+
+	remoteMachine, err := r.GetDialedMachine(params.Machine)
+	if err != nil {
+		log.Error("Error getting dialed, valid machine. err:%s", err)
+		return nil, err
+	}
+
+	streamReq := command.StreamRequest{
+		Command: params.Command,
+		WorkDir: params.Path,
+		Env:     params.Env,
+		Timeout: params.Timeout,
+		Stdout:  forwardCallback(params.Stdout),
+		Stderr:  forwardCallback(params.Stderr),
+		Exit:    forwardCallback(params.Exit),
+	}
+
+	raw, err := remoteMachine.Tell("exec.stream", streamReq)
+	if err != nil {
+		log.Error("Error starting remote command. err:%s", err)
+		return nil, err
+	}
+
+	s := &execStream{}
+	if err := raw.Unmarshal(&s.remote); err != nil {
+		return nil, err
+	}
+
+	s.PID = s.remote.PID
+
+	// Kill the remote process when the caller goes away.
+	kreq.Client.OnDisconnect(func() { s.remote.Kill.Call() })
+
+	return s, nil
+}
+
+// execStream is a handle of remote process returned to the caller of
+// remote.execStream method.
+type execStream struct {
+	PID int `json:"pid"`
+
+	// never expose the remote handle as we return this value to the client.
+	remote command.StreamHandle
+}
+
+// Input writes data to remote process standard input.
+func (s *execStream) Input(d *dnode.Partial) {
+	s.remote.Input.Call(d.MustSliceOfLength(1)[0].MustString())
+}
+
+// CloseInput closes remote process standard input.
+func (s *execStream) CloseInput(*dnode.Partial) {
+	s.remote.CloseInput.Call()
+}
+
+// Kill kills remote process.
+func (s *execStream) Kill(*dnode.Partial) {
+	s.remote.Kill.Call()
+}
+
+// forwardCallback creates a callback which passes its first argument to the
+// given function. It returns invalid function when fn is not set.
+func forwardCallback(fn dnode.Function) dnode.Function {
+	if !fn.IsValid() {
+		return dnode.Function{}
+	}
+
+	return dnode.Callback(func(p *dnode.Partial) {
+		var arg interface{}
+		if err := p.One().Unmarshal(&arg); err == nil {
+			fn.Call(arg)
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"koding/klient/fs"
 	"koding/klient/remote/rsync"
 	"time"
+
+	"github.com/koding/kite/dnode"
 )
 
 type StatusItem int
@@ -43,6 +45,21 @@ type Exec struct {
 	Path    string
 }
 
+// ExecStream is the request struct for remote.execStream method.
+type ExecStream struct {
+	Machine string        `json:"machine"`
+	Command string        `json:"command"`
+	Path    string        `json:"path,omitempty"`    // Remote working directory.
+	Env     []string      `json:"env,omitempty"`     // KEY=VALUE pairs set for the command.
+	Timeout time.Duration `json:"timeout,omitempty"` // Command is killed after timeout, if set.
+
+	// Callbacks receiving command output and exit status, see
+	// command.StreamRequest for details.
+	Stdout dnode.Function `json:"stdout"`
+	Stderr dnode.Function `json:"stderr"`
+	Exit   dnode.Function `json:"exit"`
+}
+
 // SSHAuthSock is the request struct for remote.sshKeysAdd method.
 type SSHKeyAdd struct {
 	Debug bool
@@ -98,6 +115,10 @@ type Cache struct {
 	// This must be true for copying files, or the remote will attempt to copy
 	// `your/path/` (a directory) instead of `your/path` (a directory, *or file*)
 	IncludePath bool `json:"includePath"`
+
+	// BandwidthLimit is the maximum number of bytes per second transferred
+	// by rsync. Zero means no limit.
+	BandwidthLimit int64 `json:"bandwidthLimit"`
 }
 
 type Status struct {
@@ -144,6 +165,19 @@ type GetPathSizeOptions struct {
 	RemotePath string
 }
 
+// Transfer is the request struct for remote.transfer method. It calls one of
+// the file transfer methods on the remote machine.
+type Transfer struct {
+	Machine string `json:"machine"`
+
+	// Method is one of fs.upload.begin, fs.upload.chunk, fs.upload.commit,
+	// fs.download or fs.getInfo.
+	Method string `json:"method"`
+
+	// Args are passed to the remote method as is.
+	Args interface{} `json:"args"`
+}
+
 // CurrentUsernameOptions
 type CurrentUsernameOptions struct {
 	Debug bool
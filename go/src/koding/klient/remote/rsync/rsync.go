@@ -42,6 +42,10 @@ type SyncOpts struct {
 	LocalToRemote     bool   `json:"localToRemote"`
 	IgnoreFile        string `json:"ignoreFile"`
 	IncludePath       bool   `json:"includePath"`
+
+	// BandwidthLimit is the maximum number of bytes per second transferred
+	// by rsync. Zero means no limit.
+	BandwidthLimit int64 `json:"bandwidthLimit,omitempty"`
 }
 
 type SyncIntervalOpts struct {
@@ -70,6 +74,8 @@ func (o SyncOpts) IsZero() bool {
 		return false
 	case o.IncludePath != false:
 		return false
+	case o.BandwidthLimit != 0:
+		return false
 	default:
 		return true
 	}
@@ -158,6 +164,11 @@ func (rs *Client) sync(progCh chan Progress, opts SyncOpts) {
 		args = append(args, fmt.Sprintf("--filter=:- %s", opts.IgnoreFile))
 	}
 
+	if opts.BandwidthLimit > 0 {
+		// rsync takes the limit in KiB per second.
+		args = append(args, fmt.Sprintf("--bwlimit=%d", (opts.BandwidthLimit+1023)/1024))
+	}
+
 	args = append(args, []string{
 		"--progress", "--delete", "-zave",
 		fmt.Sprintf("ssh -i %s -oStrictHostKeyChecking=no", opts.SSHPrivateKeyPath),
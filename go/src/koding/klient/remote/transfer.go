@@ -0,0 +1,74 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"koding/klient/remote/req"
+
+	"github.com/koding/kite"
+	"github.com/koding/logging"
+)
+
+// transferMethods are the remote methods which can be called with
+// remote.transfer.
+var transferMethods = map[string]bool{
+	"fs.upload.begin":  true,
+	"fs.upload.chunk":  true,
+	"fs.upload.commit": true,
+	"fs.download":      true,
+	"fs.getInfo":       true,
+}
+
+// TransferHandler calls one of the file transfer methods on a remote machine
+// with the given args.
+func (r *Remote) TransferHandler(kreq *kite.Request) (interface{}, error) {
+	log := logging.NewLogger("remote").New("remote.transfer")
+
+	var params req.Transfer
+	if kreq.Args == nil {
+		return nil, errors.New("arguments are not passed")
+	}
+
+	if err := kreq.Args.One().Unmarshal(&params); err != nil {
+		err = fmt.Errorf(
+			"remote.transfer: Error '%s' while unmarshalling request '%s'\n",
+			err, kreq.Args.One(),
+		)
+		r.log.Error(err.Error())
+		return nil, err
+	}
+
+	switch {
+	case params.Machine == "":
+		return nil, errors.New("Missing required argument `machine`.")
+	case !transferMethods[params.Method]:
+		return nil, fmt.Errorf("Invalid transfer method %q.", params.Method)
+	}
+
+	log = log.New(
+		"machineName", params.Machine,
+		"method", params.Method,
+	)
+
+	remoteMachine, err := r.GetDialedMachine(params.Machine)
+	if err != nil {
+		log.Error("Error getting dialed, valid machine. err:%s", err)
+		return nil, err
+	}
+
+	// Relative paths are resolved against remote home directory, like with
+	// other remote.* methods.
+	if args, ok := params.Args.(map[string]interface{}); ok {
+		if p, ok := args["path"].(string); ok && p != "" && !path.IsAbs(p) {
+			home, err := remoteMachine.HomeWithDefault()
+			if err != nil {
+				return nil, err
+			}
+			args["path"] = path.Join(home, p)
+		}
+	}
+
+	return remoteMachine.Tell(params.Method, params.Args)
+}
@@ -26,6 +26,10 @@ type Server struct {
 
 	// inputHook is called whenever an input is received
 	inputHook func()
+
+	// readOnly discards all input, resizes and close requests, it is set
+	// for shared users which are only allowed to watch the session.
+	readOnly bool
 }
 
 type Remote struct {
@@ -35,6 +39,10 @@ type Remote struct {
 
 // Input is called when some text is written to the terminal.
 func (s *Server) Input(d *dnode.Partial) {
+	if s.readOnly {
+		return
+	}
+
 	data := d.MustSliceOfLength(1)[0].MustString()
 
 	if s.inputHook != nil {
@@ -48,11 +56,19 @@ func (s *Server) Input(d *dnode.Partial) {
 
 // ControlSequence is called when a non-printable key is pressed on the terminal.
 func (s *Server) ControlSequence(d *dnode.Partial) {
+	if s.readOnly {
+		return
+	}
+
 	data := d.MustSliceOfLength(1)[0].MustString()
 	s.pty.MasterEncoded.Write([]byte(data))
 }
 
 func (s *Server) SetSize(d *dnode.Partial) {
+	if s.readOnly {
+		return
+	}
+
 	args := d.MustSliceOfLength(2)
 	x := args[0].MustFloat64()
 	y := args[1].MustFloat64()
@@ -64,9 +80,17 @@ func (s *Server) setSize(x, y float64) {
 }
 
 func (s *Server) Close(d *dnode.Partial) {
+	if s.readOnly {
+		return
+	}
+
 	s.pty.Signal(syscall.SIGHUP)
 }
 
 func (s *Server) Terminate(d *dnode.Partial) {
+	if s.readOnly {
+		return
+	}
+
 	s.Close(nil)
 }
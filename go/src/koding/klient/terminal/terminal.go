@@ -14,6 +14,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"koding/klient/collaboration"
 	"koding/klient/terminal/pty"
 
 	"github.com/koding/kite"
@@ -208,6 +209,16 @@ func (t *Terminal) Connect(r *kite.Request) (interface{}, error) {
 		return nil, fmt.Errorf("Could not get home dir: %s", err)
 	}
 
+	// Read-only shared users can only watch existing sessions.
+	readOnly := false
+	if option := collaboration.FromRequest(r); option != nil && option.Role.ReadOnlyTerminal() {
+		if params.Mode != "shared" {
+			return nil, errors.New("read-only users can only connect to shared sessions")
+		}
+
+		readOnly = true
+	}
+
 	if params.Mode == "create" && t.HasLimit(r.Username) {
 		return nil, errors.New("session limit has reached")
 	}
@@ -231,8 +242,13 @@ func (t *Terminal) Connect(r *kite.Request) (interface{}, error) {
 		remote:    params.Remote,
 		pty:       p,
 		inputHook: t.InputHook,
+		readOnly:  readOnly,
+	}
+
+	// Read-only users watch the session with the size set by its owner.
+	if !readOnly {
+		server.setSize(float64(params.SizeX), float64(params.SizeY))
 	}
-	server.setSize(float64(params.SizeX), float64(params.SizeY))
 
 	t.AddUserSession(r.Username, command.Session, server)
 
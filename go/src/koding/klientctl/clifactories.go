@@ -47,6 +47,7 @@ func MountCommandFactory(c *cli.Context, log logging.Logger, cmdName string) ctl
 		OneWayInterval:   c.Int("oneway-interval"),
 		Debug:            c.Bool("debug") || config.Konfig.Debug,
 		Fuse:             c.Bool("fuse"),
+		BandwidthLimit:   c.String("bwlimit"),
 
 		// Used for prefetch
 		SSHDefaultKeyDir:  config.SSHDefaultKeyDir,
@@ -245,12 +246,15 @@ func LogCommandFactory(c *cli.Context, log logging.Logger, cmdName string) ctlcl
 		KdLogLocation:     c.String("kd-log-file"),
 		KlientLogLocation: c.String("klient-log-file"),
 		Lines:             c.Int("lines"),
+		Audit:             c.Bool("audit"),
+		AuditUser:         c.String("user"),
 	}
 
 	init := logcmd.Init{
-		Stdout: os.Stdout,
-		Log:    log,
-		Helper: ctlcli.CommandHelper(c, cmdName),
+		Stdout:        os.Stdout,
+		Log:           log,
+		Helper:        ctlcli.CommandHelper(c, cmdName),
+		KlientOptions: klient.NewKlientOptions(),
 	}
 
 	cmd, err := logcmd.NewCommand(init, opts)
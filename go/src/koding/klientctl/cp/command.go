@@ -304,6 +304,19 @@ func (c *Command) runCp() error {
 		machineName, localPath, remotePath, localToRemote,
 	)
 
+	// Files are copied in chunks, so an interrupted copy can be resumed.
+	// Directories are still copied with rsync.
+	switch err := c.transfer(machineName, localPath, remotePath, localToRemote); err {
+	case nil:
+		c.Stdout.Printlnf("Copy complete.")
+		return nil
+	case errIsDir:
+		c.Log.Debug("Source is a directory, copying with rsync.")
+	default:
+		c.Stdout.Printlnf(errormessages.FailedCopyFile, err)
+		return fmt.Errorf("Failed to copy file. err:%s", err)
+	}
+
 	// This provides UX!
 	sshKey, err := c.getSSHKey()
 	if err != nil {
@@ -0,0 +1,290 @@
+package cp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"koding/klient/fs"
+	"koding/klient/remote/req"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// transferRetries is the number of attempts made to transfer a single chunk.
+const transferRetries = 3
+
+// partSuffix is the suffix of local files storing partially downloaded data.
+const partSuffix = ".kdpart"
+
+// errIsDir is returned by transfer when the source is a directory. Directories
+// are copied with rsync.
+var errIsDir = errors.New("source is a directory")
+
+// transfer copies a single file between local and remote machine in chunks.
+// Interrupted transfers are resumed from already copied data.
+func (c *Command) transfer(machineName, localPath, remotePath string, localToRemote bool) error {
+	// Empty remote path refers to home directory.
+	if remotePath == "" {
+		remotePath = "."
+	}
+
+	if localToRemote {
+		return c.upload(machineName, localPath, remotePath)
+	}
+
+	return c.download(machineName, localPath, remotePath)
+}
+
+func (c *Command) upload(machineName, localPath, remotePath string) error {
+	fi, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+
+	if fi.IsDir() {
+		return errIsDir
+	}
+
+	// Copying into existing directory keeps the source name.
+	var info fs.FileEntry
+	if err := c.tellTransfer(machineName, "fs.getInfo", fs.GetInfoOptions{Path: remotePath}, &info); err != nil {
+		return err
+	}
+
+	if info.Exists && info.IsDir {
+		remotePath = path.Join(remotePath, filepath.Base(localPath))
+	}
+
+	hash, err := hashFile(localPath)
+	if err != nil {
+		return err
+	}
+
+	begin := fs.UploadBeginOptions{
+		Path: remotePath,
+		Size: fi.Size(),
+		Hash: hash,
+		Mode: fi.Mode().Perm(),
+	}
+
+	var session fs.UploadSession
+	if err := c.tellTransfer(machineName, "fs.upload.begin", begin, &session); err != nil {
+		return err
+	}
+
+	if session.Offset != 0 {
+		c.Stdout.Printlnf("Resuming upload from %s.", humanize.IBytes(uint64(session.Offset)))
+	}
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, session.ChunkSize)
+	for attempt := 0; session.Offset < session.Size; {
+		n, err := f.ReadAt(buf, session.Offset)
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if n == 0 {
+			return fmt.Errorf("%s was truncated while being copied", localPath)
+		}
+
+		chunk := fs.UploadChunkOptions{
+			ID:      session.ID,
+			Offset:  session.Offset,
+			Content: buf[:n],
+			Hash:    hashOf(buf[:n]),
+		}
+
+		if err := c.tellTransfer(machineName, "fs.upload.chunk", chunk, &session); err != nil {
+			if attempt++; attempt == transferRetries {
+				return err
+			}
+
+			c.Log.Warning("Failed to upload chunk at offset %d, retrying. err:%s", chunk.Offset, err)
+
+			// Begin the upload again to find out how much data was received.
+			if err := c.tellTransfer(machineName, "fs.upload.begin", begin, &session); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		attempt = 0
+		c.progress(session.Offset, session.Size)
+	}
+
+	var entry fs.FileEntry
+	if err := c.tellTransfer(machineName, "fs.upload.commit", fs.UploadCommitOptions{ID: session.ID}, &entry); err != nil {
+		return err
+	}
+
+	c.Log.Debug("Uploaded %s to %s", localPath, entry.FullPath)
+
+	return nil
+}
+
+func (c *Command) download(machineName, localPath, remotePath string) error {
+	var info fs.FileEntry
+	if err := c.tellTransfer(machineName, "fs.getInfo", fs.GetInfoOptions{Path: remotePath}, &info); err != nil {
+		return err
+	}
+
+	switch {
+	case !info.Exists:
+		return fmt.Errorf("remote path %s does not exist", remotePath)
+	case info.IsDir:
+		return errIsDir
+	}
+
+	// Copying into existing directory keeps the source name.
+	if fi, err := os.Stat(localPath); err == nil && fi.IsDir() {
+		localPath = filepath.Join(localPath, path.Base(remotePath))
+	}
+
+	// Data is downloaded to a part file which is renamed when the transfer is
+	// complete. Existing part file is a leftover of interrupted transfer.
+	part := localPath + partSuffix
+
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	offset, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+
+	if offset > info.Size {
+		// Remote file has changed since the transfer was interrupted.
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+
+		h.Reset()
+		offset = 0
+	}
+
+	if offset != 0 {
+		c.Stdout.Printlnf("Resuming download from %s.", humanize.IBytes(uint64(offset)))
+	}
+
+	var chunk fs.DownloadChunk
+	for attempt := 0; ; {
+		opts := fs.DownloadOptions{
+			Path:      remotePath,
+			Offset:    offset,
+			BlockSize: fs.DefaultChunkSize,
+		}
+
+		chunk = fs.DownloadChunk{}
+		err := c.tellTransfer(machineName, "fs.download", opts, &chunk)
+		if err == nil && hashOf(chunk.Content) != chunk.Hash {
+			err = errors.New("chunk checksum mismatch")
+		}
+
+		if err != nil {
+			if attempt++; attempt == transferRetries {
+				return err
+			}
+
+			c.Log.Warning("Failed to download chunk at offset %d, retrying. err:%s", offset, err)
+			continue
+		}
+
+		attempt = 0
+
+		if _, err := f.WriteAt(chunk.Content, offset); err != nil {
+			return err
+		}
+
+		h.Write(chunk.Content)
+		offset += int64(len(chunk.Content))
+		c.progress(offset, chunk.Size)
+
+		if chunk.FileHash != "" {
+			break
+		}
+	}
+
+	// Remote file was modified during the transfer, the data is useless.
+	if hex.EncodeToString(h.Sum(nil)) != chunk.FileHash {
+		f.Close()
+		os.Remove(part)
+		return errors.New("file checksum mismatch, remote file has changed during the transfer")
+	}
+
+	if err := f.Chmod(chunk.Mode); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(part, localPath)
+}
+
+// tellTransfer calls the given file transfer method on remote machine.
+func (c *Command) tellTransfer(machineName, method string, args, res interface{}) error {
+	r := req.Transfer{
+		Machine: machineName,
+		Method:  method,
+		Args:    args,
+	}
+
+	resp, err := c.Klient.Tell("remote.transfer", r)
+	if err != nil {
+		return err
+	}
+
+	return resp.Unmarshal(res)
+}
+
+// progress prints the number of already transferred bytes.
+func (c *Command) progress(n, total int64) {
+	percent := 100
+	if total != 0 {
+		percent = int(n * 100 / total)
+	}
+
+	c.Stdout.Printf("\r%s / %s (%d%%)", humanize.IBytes(uint64(n)), humanize.IBytes(uint64(total)), percent)
+
+	if n == total {
+		c.Stdout.Printf("\n")
+	}
+}
+
+func hashOf(p []byte) string {
+	sum := sha256.Sum256(p)
+	return hex.EncodeToString(sum[:])
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
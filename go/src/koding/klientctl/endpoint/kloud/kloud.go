@@ -25,6 +25,12 @@ type Transport interface {
 	Call(method string, arg, reply interface{}) error
 }
 
+// Disconnecter is an optional interface of a Transport, which
+// is able to notify about a lost connection.
+type Disconnecter interface {
+	OnDisconnect(func())
+}
+
 // DefaultLog is a logger used by Client with nil Log.
 var DefaultLog logging.Logger = logging.NewCustom("endpoint-kloud", false)
 
@@ -66,6 +72,15 @@ func (c *Client) Call(method string, arg, reply interface{}) error {
 	return c.Transport.Call(method, arg, reply)
 }
 
+// OnDisconnect registers fn to be called when connection to Kloud
+// is lost. If the transport does not implement Disconnecter,
+// the method is a nop.
+func (c *Client) OnDisconnect(fn func()) {
+	if d, ok := c.Transport.(Disconnecter); ok {
+		d.OnDisconnect(fn)
+	}
+}
+
 // KiteTransport is a default transport that uses github.com/koding/kite
 // for underlying communication.
 //
@@ -101,6 +116,7 @@ type KiteTransport struct {
 
 var (
 	_ Transport       = (*KiteTransport)(nil)
+	_ Disconnecter    = (*KiteTransport)(nil)
 	_ stack.Validator = (*KiteTransport)(nil)
 )
 
@@ -122,6 +138,16 @@ func (kt *KiteTransport) Call(method string, arg, reply interface{}) error {
 	return nil
 }
 
+// OnDisconnect implements the Disconnecter interface.
+//
+// If connection to Kloud was not established yet, fn
+// is never called.
+func (kt *KiteTransport) OnDisconnect(fn func()) {
+	if kt.kClient != nil {
+		kt.kClient.OnDisconnect(fn)
+	}
+}
+
 func (kt *KiteTransport) Connect(url string) (Transport, error) {
 	k, err := kt.newClient(url)
 	if err != nil {
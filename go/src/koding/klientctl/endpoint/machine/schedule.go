@@ -0,0 +1,65 @@
+package machine
+
+import (
+	"koding/db/models"
+	"koding/kites/kloud/stack"
+	"koding/klientctl/endpoint/kloud"
+
+	"github.com/koding/logging"
+)
+
+// ScheduleOptions stores options for `machine schedule` calls.
+type ScheduleOptions struct {
+	Identifier string // Machine identifier.
+	Start      string // Crontab expression for starting the machine.
+	Stop       string // Crontab expression for stopping the machine.
+	Timezone   string // IANA time zone name of the expressions.
+	Log        logging.Logger
+}
+
+// ShowSchedule gives a start/stop schedule of the machine.
+func ShowSchedule(options *ScheduleOptions) (*stack.MachineScheduleResponse, error) {
+	return callSchedule("machine.schedule", options, nil)
+}
+
+// SetSchedule sets a start/stop schedule of the machine.
+func SetSchedule(options *ScheduleOptions) (*stack.MachineScheduleResponse, error) {
+	sched := &models.MachineSchedule{
+		Start:    options.Start,
+		Stop:     options.Stop,
+		Timezone: options.Timezone,
+	}
+
+	if err := sched.Valid(); err != nil {
+		return nil, err
+	}
+
+	return callSchedule("machine.setSchedule", options, sched)
+}
+
+// ClearSchedule removes a start/stop schedule of the machine.
+func ClearSchedule(options *ScheduleOptions) error {
+	_, err := callSchedule("machine.clearSchedule", options, nil)
+	return err
+}
+
+func callSchedule(method string, options *ScheduleOptions, sched *models.MachineSchedule) (*stack.MachineScheduleResponse, error) {
+	id, _, err := machineID(options.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		req = &stack.MachineScheduleRequest{
+			MachineID: id,
+			Schedule:  sched,
+		}
+		resp stack.MachineScheduleResponse
+	)
+
+	if err := kloud.Call(method, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
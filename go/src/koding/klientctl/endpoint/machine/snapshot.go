@@ -0,0 +1,119 @@
+package machine
+
+import (
+	"errors"
+	"fmt"
+
+	"koding/kites/kloud/stack"
+	"koding/klientctl/endpoint/kloud"
+
+	"github.com/koding/logging"
+)
+
+// SnapshotOptions stores options for `machine snapshot` calls.
+type SnapshotOptions struct {
+	Identifier string // Machine identifier.
+	Label      string // Optional label of the created snapshot.
+	SnapshotID string // Snapshot ID, required by delete and restore.
+	Log        logging.Logger
+}
+
+// SnapshotResult describes an asynchronous snapshot operation started
+// on kloud. Its progress can be tracked with Type and MachineID as
+// an event type and an event id respectively.
+type SnapshotResult struct {
+	Type      string
+	MachineID string
+}
+
+// CreateSnapshot requests a snapshot of the machine root disk.
+func CreateSnapshot(options *SnapshotOptions) (*SnapshotResult, error) {
+	return callSnapshot("machine.snapshot.create", options)
+}
+
+// DeleteSnapshot requests the given snapshot to be deleted.
+func DeleteSnapshot(options *SnapshotOptions) (*SnapshotResult, error) {
+	if options.SnapshotID == "" {
+		return nil, errors.New("snapshot ID is missing")
+	}
+
+	return callSnapshot("machine.snapshot.delete", options)
+}
+
+// RestoreSnapshot requests the machine root disk to be restored
+// from the given snapshot.
+func RestoreSnapshot(options *SnapshotOptions) (*SnapshotResult, error) {
+	if options.SnapshotID == "" {
+		return nil, errors.New("snapshot ID is missing")
+	}
+
+	return callSnapshot("machine.snapshot.restore", options)
+}
+
+// ListSnapshots gives snapshots of the machine, starting from
+// the latest one.
+func ListSnapshots(options *SnapshotOptions) ([]*stack.Snapshot, error) {
+	id, _, err := machineID(options.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		req  = &stack.SnapshotListRequest{MachineId: id}
+		resp stack.SnapshotListResponse
+	)
+
+	if err := kloud.Call("machine.snapshot.list", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Snapshots, nil
+}
+
+func callSnapshot(method string, options *SnapshotOptions) (*SnapshotResult, error) {
+	id, _, err := machineID(options.Identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := machineProvider(id)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &stack.SnapshotRequest{
+		MachineId:  id,
+		Provider:   provider,
+		Label:      options.Label,
+		SnapshotID: options.SnapshotID,
+	}
+
+	if err := kloud.Call(method, req, nil); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotResult{
+		Type:      method,
+		MachineID: id,
+	}, nil
+}
+
+// machineProvider looks up the provider name of the given machine.
+func machineProvider(id string) (string, error) {
+	var (
+		listReq = stack.MachineListRequest{}
+		listRes = stack.MachineListResponse{}
+	)
+
+	if err := kloud.Call("machine.list", &listReq, &listRes); err != nil {
+		return "", err
+	}
+
+	for _, m := range listRes.Machines {
+		if m.ID == id {
+			return m.Provider, nil
+		}
+	}
+
+	return "", fmt.Errorf("machine %q not found", id)
+}
@@ -7,10 +7,12 @@ import (
 	"strconv"
 	"strings"
 
+	"koding/klient/machine"
 	"koding/klient/machine/machinegroup"
 	"koding/klientctl/klient"
 	"koding/klientctl/ssh"
 
+	"github.com/koding/kite"
 	"github.com/koding/logging"
 )
 
@@ -21,33 +23,43 @@ type SSHOptions struct {
 	Log        logging.Logger
 }
 
-// SSH connects to remote machine using SSH protocol.
-func SSH(options *SSHOptions) error {
-	// Translate identifier to machine ID.
-	//
-	// TODO(ppknap): this is copied from klientctl old list and will be reworked.
+// machineID translates the given identifier to machine ID. It also
+// returns the klient client that was used for the translation.
+//
+// TODO(ppknap): this is copied from klientctl old list and will be reworked.
+func machineID(identifier string) (string, *kite.Client, error) {
 	k, err := klient.CreateKlientWithDefaultOpts()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error creating klient:", err)
-		return err
+		return "", nil, err
 	}
 
 	if err := k.Dial(); err != nil {
 		fmt.Fprintln(os.Stderr, "Error dialing klient:", err)
-		return err
+		return "", nil, err
 	}
 
 	idReq := machinegroup.IDRequest{
-		Identifier: options.Identifier,
+		Identifier: identifier,
 	}
 
 	idRaw, err := k.Tell("machine.id", idReq)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	idRes := machinegroup.IDResponse{}
 	if err := idRaw.Unmarshal(&idRes); err != nil {
+		return "", nil, err
+	}
+
+	return string(idRes.ID), k, nil
+}
+
+// SSH connects to remote machine using SSH protocol.
+func SSH(options *SSHOptions) error {
+	id, k, err := machineID(options.Identifier)
+	if err != nil {
 		return err
 	}
 
@@ -76,7 +88,7 @@ func SSH(options *SSHOptions) error {
 
 	// Add created key to authorized hosts on remote machine.
 	sshReq := machinegroup.SSHRequest{
-		ID:        idRes.ID,
+		ID:        machine.ID(id),
 		Username:  options.Username,
 		PublicKey: pubkey,
 	}
@@ -0,0 +1,231 @@
+package machine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"koding/klient/machine/machinegroup"
+	"koding/klient/machine/mount"
+	"koding/klientctl/klient"
+
+	"github.com/koding/kite/dnode"
+	"github.com/koding/logging"
+)
+
+// SyncOptions stores options for `sync` calls.
+type SyncOptions struct {
+	Identifier string // Mount ID or local path of the mount.
+	Log        logging.Logger
+}
+
+// Conflicts lists unresolved conflicts of the mount.
+func Conflicts(options *SyncOptions) ([]*mount.Conflict, error) {
+	req := &machinegroup.ConflictsRequest{
+		Identifier: options.Identifier,
+	}
+	var resp machinegroup.ConflictsResponse
+
+	if err := tellKlient("machine.mount.conflicts", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Conflicts, nil
+}
+
+// Status gets synchronization progress of the mount.
+func Status(options *SyncOptions) (*mount.Status, error) {
+	req := &machinegroup.StatusRequest{
+		Identifier: options.Identifier,
+	}
+	var resp machinegroup.StatusResponse
+
+	if err := tellKlient("machine.mount.status", req, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Status == nil {
+		return nil, errors.New("retrieved mount status is nil")
+	}
+
+	return resp.Status, nil
+}
+
+// WatchStatus calls fn with synchronization progress of the mount and then
+// each time it changes. Status changes are pushed by klient. It returns when
+// the connection to klient is lost.
+func WatchStatus(options *SyncOptions, fn func(*mount.Status)) error {
+	k, err := klient.CreateKlientWithDefaultOpts()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error creating klient:", err)
+		return err
+	}
+
+	if err := k.Dial(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error dialing klient:", err)
+		return err
+	}
+	defer k.Close()
+
+	var (
+		statusC = make(chan *mount.Status, 16)
+		disC    = make(chan struct{})
+		once    sync.Once
+	)
+
+	k.OnDisconnect(func() { once.Do(func() { close(disC) }) })
+
+	onChange := func(p *dnode.Partial) {
+		var st mount.Status
+		if err := p.One().Unmarshal(&st); err != nil {
+			return
+		}
+
+		select {
+		case statusC <- &st:
+		case <-disC:
+		}
+	}
+
+	req := &machinegroup.StatusRequest{
+		Identifier: options.Identifier,
+		OnChange:   dnode.Callback(onChange),
+	}
+
+	raw, err := k.Tell("machine.mount.status", req)
+	if err != nil {
+		return err
+	}
+
+	var resp machinegroup.StatusResponse
+	if err := raw.Unmarshal(&resp); err != nil {
+		return err
+	}
+
+	if resp.Status == nil {
+		return errors.New("retrieved mount status is nil")
+	}
+
+	fn(resp.Status)
+
+	for {
+		select {
+		case st := <-statusC:
+			fn(st)
+		case <-disC:
+			return errors.New("connection to klient was lost")
+		}
+	}
+}
+
+// Pause temporarily stops synchronization of the mount.
+func Pause(options *SyncOptions) error {
+	req := &machinegroup.PauseRequest{
+		Identifier: options.Identifier,
+	}
+
+	return tellKlient("machine.mount.pause", req, &machinegroup.PauseResponse{})
+}
+
+// ResumeOptions stores options for `sync resume` call.
+type ResumeOptions struct {
+	SyncOptions
+	BandwidthLimit *int64 // New bandwidth limit in bytes per second, if not nil.
+}
+
+// Resume restarts synchronization of the mount.
+func Resume(options *ResumeOptions) error {
+	req := &machinegroup.ResumeRequest{
+		Identifier:     options.Identifier,
+		BandwidthLimit: options.BandwidthLimit,
+	}
+
+	return tellKlient("machine.mount.resume", req, &machinegroup.ResumeResponse{})
+}
+
+// ResolveOptions stores options for `sync conflicts --resolve` call.
+type ResolveOptions struct {
+	SyncOptions
+	Name   string               // Conflicting file relative to mount root.
+	Policy mount.ConflictPolicy // Resolution policy.
+}
+
+// Resolve resolves the conflict of a single file.
+func Resolve(options *ResolveOptions) error {
+	if err := options.Policy.Valid(); err != nil {
+		return err
+	}
+
+	req := &machinegroup.ResolveRequest{
+		Identifier: options.Identifier,
+		Name:       options.Name,
+		Policy:     options.Policy,
+	}
+
+	return tellKlient("machine.mount.resolve", req, &machinegroup.ResolveResponse{})
+}
+
+// PolicyOptions stores options for `sync conflicts --policy` call.
+type PolicyOptions struct {
+	SyncOptions
+	Policy mount.ConflictPolicy // Policy used to resolve new conflicts.
+}
+
+// SetPolicy changes conflict resolution policy of the mount.
+func SetPolicy(options *PolicyOptions) error {
+	if err := options.Policy.Valid(); err != nil {
+		return err
+	}
+
+	req := &machinegroup.PolicyRequest{
+		Identifier: options.Identifier,
+		Policy:     options.Policy,
+	}
+
+	return tellKlient("machine.mount.policy", req, &machinegroup.PolicyResponse{})
+}
+
+// IgnoreOptions stores options for `sync ignore` call.
+type IgnoreOptions struct {
+	SyncOptions
+	Ignore *string // New ignore patterns separated by new lines, if not nil.
+}
+
+// Ignore gets or replaces ignore patterns of the mount. It returns current
+// patterns of the mount.
+func Ignore(options *IgnoreOptions) (string, error) {
+	req := &machinegroup.IgnoreRequest{
+		Identifier: options.Identifier,
+		Ignore:     options.Ignore,
+	}
+	var resp machinegroup.IgnoreResponse
+
+	if err := tellKlient("machine.mount.ignore", req, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.Ignore, nil
+}
+
+// tellKlient calls the given klient method and decodes its response into
+// resp value.
+func tellKlient(method string, req, resp interface{}) error {
+	k, err := klient.CreateKlientWithDefaultOpts()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error creating klient:", err)
+		return err
+	}
+
+	if err := k.Dial(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error dialing klient:", err)
+		return err
+	}
+
+	raw, err := k.Tell(method, req)
+	if err != nil {
+		return err
+	}
+
+	return raw.Unmarshal(resp)
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"koding/kites/kloud/eventer"
 	"koding/kites/kloud/stack"
 	kloudstack "koding/kites/kloud/stack"
 	"koding/klientctl/endpoint/credential"
@@ -13,6 +16,7 @@ import (
 	"koding/klientctl/endpoint/team"
 
 	"github.com/hashicorp/hcl"
+	"github.com/koding/kite/dnode"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -21,6 +25,9 @@ type CreateOptions struct {
 	Title       string
 	Credentials []string
 	Template    []byte
+	TemplateID   string // if non-empty, builds the stack from an existing template
+	DryRun       bool   // if true, only plans the stack
+	KeepTemplate bool   // if true, dry run keeps the created template
 }
 
 func (opts *CreateOptions) Valid() error {
@@ -35,6 +42,50 @@ func (opts *CreateOptions) Valid() error {
 	return nil
 }
 
+// DestroyOptions represents options for the Destroy and DestroyPlan
+// methods.
+type DestroyOptions struct {
+	ID       string // stack ID
+	Team     string // if empty, currently used team
+	Provider string // if empty, kloud reads the provider of stack machines
+}
+
+func (opts *DestroyOptions) Valid() error {
+	if opts == nil {
+		return errors.New("stack: arguments are missing")
+	}
+
+	if opts.ID == "" {
+		return errors.New("stack: stack ID is missing")
+	}
+
+	return nil
+}
+
+// WaitOptions represents options for the Wait method.
+type WaitOptions struct {
+	Type    string               // event type, e.g. "apply"
+	EventID string               // event id, e.g. stack ID
+	Timeout time.Duration        // if zero, 30m is used
+	OnEvent func(*eventer.Event) // called for each received event
+}
+
+func (opts *WaitOptions) Valid() error {
+	if opts == nil {
+		return errors.New("stack: arguments are missing")
+	}
+
+	if opts.Type == "" {
+		return errors.New("stack: event type is missing")
+	}
+
+	if opts.EventID == "" {
+		return errors.New("stack: event ID is missing")
+	}
+
+	return nil
+}
+
 var DefaultClient = &Client{}
 
 type Client struct {
@@ -66,6 +117,9 @@ func (c *Client) Create(opts *CreateOptions) (*stack.ImportResponse, error) {
 		Team:        opts.Team,
 		Title:       opts.Title,
 		Credentials: make(map[string][]string),
+		TemplateID:   opts.TemplateID,
+		DryRun:       opts.DryRun,
+		KeepTemplate: opts.KeepTemplate,
 	}
 
 	if req.Team == "" {
@@ -105,6 +159,150 @@ func (c *Client) Create(opts *CreateOptions) (*stack.ImportResponse, error) {
 	return &resp, nil
 }
 
+// DestroyPlan gives a list of resources, which are going to be removed
+// when the stack is destroyed. It does not modify the stack.
+func (c *Client) DestroyPlan(opts *DestroyOptions) (*stack.DestroyPlanResponse, error) {
+	var resp stack.DestroyPlanResponse
+
+	if err := c.destroy(opts, true, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Destroy requests the stack to be destroyed. The operation is performed
+// asynchronously, its progress can be tracked with Wait method
+// and "stack.destroy" event type.
+func (c *Client) Destroy(opts *DestroyOptions) (*stack.ControlResult, error) {
+	var resp stack.ControlResult
+
+	if err := c.destroy(opts, false, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (c *Client) destroy(opts *DestroyOptions, dryRun bool, resp interface{}) error {
+	if err := opts.Valid(); err != nil {
+		return err
+	}
+
+	req := &stack.DestroyRequest{
+		Provider:  opts.Provider,
+		StackID:   opts.ID,
+		GroupName: opts.Team,
+		DryRun:    dryRun,
+	}
+
+	if req.GroupName == "" {
+		req.GroupName = team.Used().Name
+	}
+
+	if err := c.kloud().Call("stack.destroy", req, resp); err != nil {
+		return fmt.Errorf("stack: unable to communicate with Kloud: %s", err)
+	}
+
+	return nil
+}
+
+// Describe gives status of the given stack, including the result
+// of the most recent drift check.
+func (c *Client) Describe(id string) (*stack.StatusResponse, error) {
+	if id == "" {
+		return nil, errors.New("stack: stack ID is missing")
+	}
+
+	req := &stack.StatusRequest{
+		StackID: id,
+	}
+
+	var resp stack.StatusResponse
+
+	if err := c.kloud().Call("describeStack", req, &resp); err != nil {
+		return nil, fmt.Errorf("stack: unable to communicate with Kloud: %s", err)
+	}
+
+	return &resp, nil
+}
+
+// Wait subscribes to the events of the given kloud process and
+// blocks until the final event is received.
+//
+// If the process failed, Wait returns non-nil error
+// with the message of the final event.
+func (c *Client) Wait(opts *WaitOptions) error {
+	if err := opts.Valid(); err != nil {
+		return err
+	}
+
+	events := make(chan *eventer.Event, 16)
+	done := make(chan struct{})
+	defer close(done)
+
+	fn := func(r *dnode.Partial) {
+		var ev eventer.Event
+
+		if err := r.One().Unmarshal(&ev); err != nil {
+			return
+		}
+
+		select {
+		case events <- &ev:
+		case <-done:
+		}
+	}
+
+	req := &stack.EventSubscribeRequest{
+		Type:    opts.Type,
+		EventId: opts.EventID,
+		OnEvent: dnode.Callback(fn),
+	}
+
+	if err := c.kloud().Call("event.subscribe", req, nil); err != nil {
+		return fmt.Errorf("stack: unable to subscribe to events: %s", err)
+	}
+
+	// Subscription is gone together with the connection,
+	// so there is nothing to wait for after it was lost.
+	var once sync.Once
+	disconnected := make(chan struct{})
+
+	c.kloud().OnDisconnect(func() {
+		once.Do(func() { close(disconnected) })
+	})
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Minute
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	for {
+		select {
+		case ev := <-events:
+			if opts.OnEvent != nil {
+				opts.OnEvent(ev)
+			}
+
+			if ev.Error != "" {
+				return errors.New(ev.Error)
+			}
+
+			if ev.Final() {
+				return nil
+			}
+		case <-disconnected:
+			return fmt.Errorf("stack: lost connection while waiting for %s-%s to finish", opts.Type, opts.EventID)
+		case <-t.C:
+			return fmt.Errorf("stack: timed out waiting for %s-%s to finish", opts.Type, opts.EventID)
+		}
+	}
+}
+
 func (c *Client) kloud() *kloud.Client {
 	if c.Kloud != nil {
 		return c.Kloud
@@ -149,6 +347,22 @@ func Create(opts *CreateOptions) (*stack.ImportResponse, error) {
 	return DefaultClient.Create(opts)
 }
 
+func DestroyPlan(opts *DestroyOptions) (*stack.DestroyPlanResponse, error) {
+	return DefaultClient.DestroyPlan(opts)
+}
+
+func Destroy(opts *DestroyOptions) (*stack.ControlResult, error) {
+	return DefaultClient.Destroy(opts)
+}
+
+func Describe(id string) (*stack.StatusResponse, error) {
+	return DefaultClient.Describe(id)
+}
+
+func Wait(opts *WaitOptions) error {
+	return DefaultClient.Wait(opts)
+}
+
 func jsonMarshal(v interface{}) ([]byte, error) {
 	var buf bytes.Buffer
 
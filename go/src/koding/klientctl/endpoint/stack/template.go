@@ -0,0 +1,108 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+
+	"koding/kites/kloud/stack"
+)
+
+// TemplateDiffOptions represents options for the TemplateDiff method.
+type TemplateDiffOptions struct {
+	ID     string // stack template ID
+	From   int    // revision to compare
+	To     int    // revision to compare with; if 0, the latest one
+	Format string // "json" or "hcl"; if empty, "json"
+}
+
+func (opts *TemplateDiffOptions) Valid() error {
+	if opts == nil {
+		return errors.New("stack: arguments are missing")
+	}
+
+	if opts.ID == "" {
+		return errors.New("stack: template ID is missing")
+	}
+
+	if opts.From <= 0 {
+		return errors.New("stack: revision to compare is missing")
+	}
+
+	return nil
+}
+
+// TemplateHistory gives all revisions of the given stack template,
+// starting from the latest one.
+func (c *Client) TemplateHistory(id string) (*stack.TemplateHistoryResponse, error) {
+	if id == "" {
+		return nil, errors.New("stack: template ID is missing")
+	}
+
+	req := &stack.TemplateHistoryRequest{
+		TemplateID: id,
+	}
+
+	var resp stack.TemplateHistoryResponse
+
+	if err := c.kloud().Call("template.history", req, &resp); err != nil {
+		return nil, fmt.Errorf("stack: unable to communicate with Kloud: %s", err)
+	}
+
+	return &resp, nil
+}
+
+// TemplateDiff gives a diff between two revisions of the given
+// stack template.
+func (c *Client) TemplateDiff(opts *TemplateDiffOptions) (*stack.TemplateDiffResponse, error) {
+	if err := opts.Valid(); err != nil {
+		return nil, err
+	}
+
+	req := &stack.TemplateDiffRequest{
+		TemplateID: opts.ID,
+		From:       opts.From,
+		To:         opts.To,
+		Format:     opts.Format,
+	}
+
+	var resp stack.TemplateDiffResponse
+
+	if err := c.kloud().Call("template.diff", req, &resp); err != nil {
+		return nil, fmt.Errorf("stack: unable to communicate with Kloud: %s", err)
+	}
+
+	return &resp, nil
+}
+
+// TemplateRollback restores content of the given stack template
+// to the given revision.
+func (c *Client) TemplateRollback(id string, revision int) (*stack.TemplateRollbackResponse, error) {
+	if id == "" {
+		return nil, errors.New("stack: template ID is missing")
+	}
+
+	req := &stack.TemplateRollbackRequest{
+		TemplateID: id,
+		Revision:   revision,
+	}
+
+	var resp stack.TemplateRollbackResponse
+
+	if err := c.kloud().Call("template.rollback", req, &resp); err != nil {
+		return nil, fmt.Errorf("stack: unable to communicate with Kloud: %s", err)
+	}
+
+	return &resp, nil
+}
+
+func TemplateHistory(id string) (*stack.TemplateHistoryResponse, error) {
+	return DefaultClient.TemplateHistory(id)
+}
+
+func TemplateDiff(opts *TemplateDiffOptions) (*stack.TemplateDiffResponse, error) {
+	return DefaultClient.TemplateDiff(opts)
+}
+
+func TemplateRollback(id string, revision int) (*stack.TemplateRollbackResponse, error) {
+	return DefaultClient.TemplateRollback(id, revision)
+}
@@ -128,4 +128,9 @@ Please provide a remote and a local path.`
 	//
 	// Note that Help is also printed after this.
 	DestinationRequired = `Error: Copy Destination is a required argument.`
+
+	// FailedCopyFile is used when chunked transfer of a file fails during kd cp.
+	// Running the command again resumes the transfer.
+	FailedCopyFile = `Error: Failed to copy the file: %s
+Run the command again to resume copying.`
 )
@@ -93,6 +93,8 @@ machineName:path/to/file syntax. Example:
   kd cp ./sourceFile apple:destinationFile
   kd cp apple:sourceFile ./destinationFile
 
+Files are copied in chunks. If copying is interrupted, running the same
+command again resumes it.
 `,
 	),
 	"open": fmtDesc(
@@ -19,6 +19,11 @@ func Ask(format string, args ...interface{}) (string, error) {
 	return strings.TrimSpace(s), nil
 }
 
+// IsTerminal tells whether the standard input is a terminal
+func IsTerminal() bool {
+	return terminal.IsTerminal(int(os.Stdin.Fd()))
+}
+
 // AskSecret asks the user to enter a password
 func AskSecret(format string, args ...interface{}) (string, error) {
 	fmt.Printf(format, args...)
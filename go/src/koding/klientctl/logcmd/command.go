@@ -4,12 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"koding/klient/audit"
 	"koding/klient/logfetcher"
 	"koding/klientctl/config"
 	"koding/klientctl/ctlcli"
+	"koding/klientctl/klient"
 	"koding/klientctl/util"
 	"os"
+	"text/tabwriter"
+	"time"
 
+	"github.com/koding/kite/dnode"
 	"github.com/koding/logging"
 )
 
@@ -22,6 +27,11 @@ type Options struct {
 	KlientLog         bool
 	KdLogLocation     string
 	KlientLogLocation string
+
+	// Audit prints audit log of method calls made by shared users instead
+	// of log files. AuditUser limits it to calls of a single user.
+	Audit     bool
+	AuditUser string
 }
 
 // Init contains various instances required for a Command instance to be initialized.
@@ -31,6 +41,17 @@ type Init struct {
 
 	// The ctlcli Helper. See the type docs for a better understanding of this.
 	Helper ctlcli.Helper
+
+	// The options to use if this struct needs to dial Klient.
+	//
+	// Note! These will be ignored if c.Klient is already defined before Run() is
+	// called.
+	KlientOptions klient.KlientOptions
+
+	// The klient instance this struct will use to read audit log.
+	Klient interface {
+		Tell(string, ...interface{}) (*dnode.Partial, error)
+	}
 }
 
 func (i Init) CheckValid() error {
@@ -86,6 +107,15 @@ func (c *Command) Run() (int, error) {
 		return 1, err
 	}
 
+	if c.Options.Audit {
+		if err := c.printAudit(); err != nil {
+			c.Stdout.Printlnf("Error: Failed to read audit log: %s", err)
+			return 2, err
+		}
+
+		return 0, nil
+	}
+
 	if err := c.tailLogs(); err != nil {
 		return 2, err
 	}
@@ -161,3 +191,58 @@ func (c *Command) tailLogs() (err error) {
 
 	return err
 }
+
+// setupKlient creates and dials our Kite interface *only* if it is nil.
+func (c *Command) setupKlient() error {
+	if c.Klient != nil {
+		return nil
+	}
+
+	k, err := klient.NewDialedKlient(c.KlientOptions)
+	if err != nil {
+		return fmt.Errorf("Failed to get working Klient instance")
+	}
+
+	c.Klient = k
+
+	return nil
+}
+
+// printAudit prints the last audit records, oldest first.
+func (c *Command) printAudit() error {
+	if err := c.setupKlient(); err != nil {
+		return err
+	}
+
+	q := &audit.Query{
+		Username: c.Options.AuditUser,
+		Limit:    c.Options.Lines,
+	}
+
+	resp, err := c.Klient.Tell("audit.list", q)
+	if err != nil {
+		return err
+	}
+
+	var records []*audit.Record
+	if err := resp.Unmarshal(&records); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.Stdout, 2, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tUSER\tMETHOD\tRESULT\tARGS")
+
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+
+		result := "ok"
+		if rec.Error != "" {
+			result = "error: " + rec.Error
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", rec.Time.Local().Format(time.RFC3339),
+			rec.Username, rec.Method, result, rec.Args)
+	}
+
+	return w.Flush()
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,7 +10,10 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"koding/kites/kloud/stack"
 	"koding/klientctl/endpoint/machine"
+	epstack "koding/klientctl/endpoint/stack"
+	"koding/klientctl/helper"
 
 	"github.com/codegangsta/cli"
 	"github.com/koding/logging"
@@ -71,6 +75,239 @@ func MachineSSHCommand(c *cli.Context, log logging.Logger, _ string) (int, error
 	return 0, nil
 }
 
+// MachineScheduleShowCommand shows start/stop schedule of remote machine.
+func MachineScheduleShowCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := scheduleOptions(c, log.New("machine:schedule:show"))
+	if err != nil {
+		return 1, err
+	}
+
+	resp, err := machine.ShowSchedule(opts)
+	if err != nil {
+		return 1, err
+	}
+
+	printSchedule(c, resp)
+	return 0, nil
+}
+
+// MachineScheduleSetCommand sets start/stop schedule of remote machine.
+func MachineScheduleSetCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := scheduleOptions(c, log.New("machine:schedule:set"))
+	if err != nil {
+		return 1, err
+	}
+
+	opts.Start = c.String("start")
+	opts.Stop = c.String("stop")
+	opts.Timezone = c.String("timezone")
+
+	if opts.Start == "" && opts.Stop == "" {
+		return 1, errors.New("at least one of --start or --stop flags is required")
+	}
+
+	resp, err := machine.SetSchedule(opts)
+	if err != nil {
+		return 1, err
+	}
+
+	printSchedule(c, resp)
+	return 0, nil
+}
+
+// MachineScheduleClearCommand removes start/stop schedule of remote machine.
+func MachineScheduleClearCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := scheduleOptions(c, log.New("machine:schedule:clear"))
+	if err != nil {
+		return 1, err
+	}
+
+	if err := machine.ClearSchedule(opts); err != nil {
+		return 1, err
+	}
+
+	fmt.Printf("Schedule of %q machine was removed.\n", opts.Identifier)
+	return 0, nil
+}
+
+func scheduleOptions(c *cli.Context, log logging.Logger) (*machine.ScheduleOptions, error) {
+	// Schedule commands must have only one identifier.
+	idents, err := getIdentifiers(c)
+	if err != nil {
+		return nil, err
+	}
+	if err := identifiersLimit(idents, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return &machine.ScheduleOptions{
+		Identifier: idents[0],
+		Log:        log,
+	}, nil
+}
+
+func printSchedule(c *cli.Context, resp *stack.MachineScheduleResponse) {
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(resp)
+		return
+	}
+
+	if resp.Schedule == nil {
+		fmt.Println("Machine has no schedule.")
+		return
+	}
+
+	tz := resp.Schedule.Timezone
+	if tz == "" {
+		tz = "UTC"
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "ACTION\tSCHEDULE\tTIMEZONE\tNEXT\n")
+	fmt.Fprintf(tw, "start\t%s\t%s\t%s\n", orDash(resp.Schedule.Start), tz, timeOrDash(resp.NextStart))
+	fmt.Fprintf(tw, "stop\t%s\t%s\t%s\n", orDash(resp.Schedule.Stop), tz, timeOrDash(resp.NextStop))
+
+	tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func timeOrDash(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC1123)
+}
+
+// MachineSnapshotCreateCommand creates a snapshot of remote machine.
+func MachineSnapshotCreateCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := snapshotOptions(c, log.New("machine:snapshot:create"))
+	if err != nil {
+		return 1, err
+	}
+
+	opts.Label = c.String("label")
+
+	return waitSnapshot(c, machine.CreateSnapshot, opts, "Creating snapshot of %q machine...")
+}
+
+// MachineSnapshotListCommand lists snapshots of remote machine.
+func MachineSnapshotListCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := snapshotOptions(c, log.New("machine:snapshot:list"))
+	if err != nil {
+		return 1, err
+	}
+
+	snapshots, err := machine.ListSnapshots(opts)
+	if err != nil {
+		return 1, err
+	}
+
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(snapshots)
+		return 0, nil
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Println("Machine has no snapshots.")
+		return 0, nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "ID\tLABEL\tREGION\tSIZE\tCREATED\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%dGB\t%s\n", s.ID, orDash(s.Label), orDash(s.Region), s.StorageSize, s.CreatedAt.Format(time.RFC1123))
+	}
+
+	tw.Flush()
+	return 0, nil
+}
+
+// MachineSnapshotDeleteCommand deletes a snapshot of remote machine.
+func MachineSnapshotDeleteCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := snapshotOptions(c, log.New("machine:snapshot:delete"))
+	if err != nil {
+		return 1, err
+	}
+
+	opts.SnapshotID = c.String("id")
+
+	return waitSnapshot(c, machine.DeleteSnapshot, opts, "Deleting snapshot of %q machine...")
+}
+
+// MachineSnapshotRestoreCommand restores remote machine from a snapshot.
+func MachineSnapshotRestoreCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := snapshotOptions(c, log.New("machine:snapshot:restore"))
+	if err != nil {
+		return 1, err
+	}
+
+	opts.SnapshotID = c.String("id")
+
+	if !c.Bool("force") {
+		fmt.Fprintf(os.Stderr, "Restoring replaces the disk of %q machine, a running machine is going to be restarted.\n\n", opts.Identifier)
+
+		s, err := helper.Ask(`Please type "yes" to confirm you want to restore the machine []: `)
+		if err != nil {
+			return 1, err
+		}
+
+		if s != "yes" {
+			return 1, errors.New("confirmation failed, aborting")
+		}
+	}
+
+	return waitSnapshot(c, machine.RestoreSnapshot, opts, "Restoring %q machine from snapshot...")
+}
+
+func snapshotOptions(c *cli.Context, log logging.Logger) (*machine.SnapshotOptions, error) {
+	// Snapshot commands must have only one identifier.
+	idents, err := getIdentifiers(c)
+	if err != nil {
+		return nil, err
+	}
+	if err := identifiersLimit(idents, 1, 1); err != nil {
+		return nil, err
+	}
+
+	return &machine.SnapshotOptions{
+		Identifier: idents[0],
+		Log:        log,
+	}, nil
+}
+
+func waitSnapshot(c *cli.Context, fn func(*machine.SnapshotOptions) (*machine.SnapshotResult, error), opts *machine.SnapshotOptions, msg string) (int, error) {
+	res, err := fn(opts)
+	if err != nil {
+		return 1, err
+	}
+
+	fmt.Fprintf(os.Stderr, msg+"\n\n", opts.Identifier)
+
+	waitOpts := &epstack.WaitOptions{
+		Type:    res.Type,
+		EventID: res.MachineID,
+		OnEvent: printEvent,
+	}
+
+	if err := epstack.Wait(waitOpts); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
 // getIdentifiers extracts identifiers and validate provided arguments.
 // TODO(ppknap): other CLI libraries like Cobra have this out of the box.
 func getIdentifiers(c *cli.Context) (idents []string, err error) {
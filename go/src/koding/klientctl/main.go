@@ -206,6 +206,10 @@ func run(args []string) {
 					Name:  "trace, t",
 					Usage: "Turn on trace logs.",
 				},
+				cli.StringFlag{
+					Name:  "bwlimit",
+					Usage: "For oneway sync and prefetch: Limit transfer bandwidth per second, e.g. \"512KB\".",
+				},
 			},
 			Action: ctlcli.FactoryAction(MountCommandFactory, log, "mount"),
 			BashComplete: ctlcli.FactoryCompletion(
@@ -388,6 +392,14 @@ func run(args []string) {
 				cli.StringFlag{Name: "kd-log-file"},
 				cli.StringFlag{Name: "klient-log-file"},
 				cli.IntFlag{Name: "lines, n"},
+				cli.BoolFlag{
+					Name:  "audit",
+					Usage: "Display method calls made by shared users.",
+				},
+				cli.StringFlag{
+					Name:  "user",
+					Usage: "Display audit records of the given user only.",
+				},
 			},
 			Action: ctlcli.FactoryAction(LogCommandFactory, log, "log"),
 		},
@@ -585,6 +597,163 @@ func run(args []string) {
 							Usage: "Remote machine username.",
 						},
 					},
+				}, {
+					Name:  "schedule",
+					Usage: "Manage start/stop schedule of remote machine.",
+					Subcommands: []cli.Command{{
+						Name:   "show",
+						Usage:  "Show schedule of provided remote machine.",
+						Action: ctlcli.ExitErrAction(MachineScheduleShowCommand, log, "show"),
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "json",
+								Usage: "Output in JSON format.",
+							},
+						},
+					}, {
+						Name:   "set",
+						Usage:  "Set schedule of provided remote machine.",
+						Action: ctlcli.ExitErrAction(MachineScheduleSetCommand, log, "set"),
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "start",
+								Usage: "Crontab expression telling when to start the machine, e.g. \"0 8 * * 1-5\".",
+							},
+							cli.StringFlag{
+								Name:  "stop",
+								Usage: "Crontab expression telling when to stop the machine, e.g. \"0 20 * * 1-5\".",
+							},
+							cli.StringFlag{
+								Name:  "timezone, tz",
+								Usage: "Time zone of the expressions, e.g. \"Europe/Warsaw\". Defaults to UTC.",
+							},
+							cli.BoolFlag{
+								Name:  "json",
+								Usage: "Output in JSON format.",
+							},
+						},
+					}, {
+						Name:   "clear",
+						Usage:  "Remove schedule of provided remote machine.",
+						Action: ctlcli.ExitErrAction(MachineScheduleClearCommand, log, "clear"),
+					}},
+				}, {
+					Name:  "snapshot",
+					Usage: "Manage snapshots of remote machine.",
+					Subcommands: []cli.Command{{
+						Name:   "create",
+						Usage:  "Create a snapshot of provided remote machine.",
+						Action: ctlcli.ExitErrAction(MachineSnapshotCreateCommand, log, "create"),
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "label",
+								Usage: "Name of the snapshot. Defaults to machine label and current time.",
+							},
+						},
+					}, {
+						Name:   "list",
+						Usage:  "List snapshots of provided remote machine.",
+						Action: ctlcli.ExitErrAction(MachineSnapshotListCommand, log, "list"),
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "json",
+								Usage: "Output in JSON format.",
+							},
+						},
+					}, {
+						Name:   "delete",
+						Usage:  "Delete a snapshot of provided remote machine.",
+						Action: ctlcli.ExitErrAction(MachineSnapshotDeleteCommand, log, "delete"),
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "id",
+								Usage: "ID of the snapshot to delete.",
+							},
+						},
+					}, {
+						Name:   "restore",
+						Usage:  "Restore provided remote machine from a snapshot.",
+						Action: ctlcli.ExitErrAction(MachineSnapshotRestoreCommand, log, "restore"),
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "id",
+								Usage: "ID of the snapshot to restore.",
+							},
+							cli.BoolFlag{
+								Name:  "force",
+								Usage: "Restore the machine without asking for confirmation.",
+							},
+						},
+					}},
+				}},
+			},
+			cli.Command{
+				Name:  "sync",
+				Usage: "Manage file synchronization of mounts.",
+				Subcommands: []cli.Command{{
+					Name:   "status",
+					Usage:  "Show file synchronization progress of provided mount.",
+					Action: ctlcli.ExitErrAction(SyncStatusCommand, log, "status"),
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "watch, w",
+							Usage: "Keep printing the status each time it changes.",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output in JSON format.",
+						},
+					},
+				}, {
+					Name:   "pause",
+					Usage:  "Temporarily stop file synchronization of provided mount.",
+					Action: ctlcli.ExitErrAction(SyncPauseCommand, log, "pause"),
+				}, {
+					Name:   "resume",
+					Usage:  "Resume file synchronization of provided mount.",
+					Action: ctlcli.ExitErrAction(SyncResumeCommand, log, "resume"),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "bwlimit",
+							Usage: "Limit synchronization bandwidth per second, e.g. \"512KB\". Use 0 to remove the limit.",
+						},
+					},
+				}, {
+					Name:   "ignore",
+					Usage:  "Show or replace ignore patterns of provided mount.",
+					Action: ctlcli.ExitErrAction(SyncIgnoreCommand, log, "ignore"),
+					Flags: []cli.Flag{
+						cli.StringSliceFlag{
+							Name:  "pattern, p",
+							Usage: "Replace ignore patterns with gitignore compatible ones. Can be repeated.",
+						},
+						cli.BoolFlag{
+							Name:  "clear",
+							Usage: "Remove all ignore patterns of the mount.",
+						},
+					},
+				}, {
+					Name:   "conflicts",
+					Usage:  "List or resolve conflicting changes of provided mount.",
+					Action: ctlcli.ExitErrAction(SyncConflictsCommand, log, "conflicts"),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "resolve",
+							Usage: "Resolve conflict using one of: local-wins, remote-wins, keep-both.",
+						},
+						cli.StringFlag{
+							Name:  "policy",
+							Usage: "Resolve new conflicts using one of: manual, local-wins, remote-wins, keep-both.",
+						},
+						cli.StringFlag{
+							Name:  "file",
+							Usage: "Conflicting file, relative to mount root, to resolve.",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output in JSON format.",
+						},
+					},
 				}},
 			},
 			cli.Command{
@@ -616,6 +785,54 @@ func run(args []string) {
 							Name:  "json",
 							Usage: "Output in JSON format.",
 						},
+						cli.BoolFlag{
+							Name:  "force",
+							Usage: "Build the stack without asking for confirmation.",
+						},
+					},
+				}, {
+					Name:   "destroy",
+					Usage:  "Destroy a stack.",
+					Action: ctlcli.ExitErrAction(StackDestroy, log, "destroy"),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Specify ID of the stack to destroy.",
+						},
+						cli.StringFlag{
+							Name:  "provider, p",
+							Usage: "Specify stack provider.",
+						},
+						cli.StringFlag{
+							Name:  "team",
+							Usage: "Specify team which the stack belongs to.",
+						},
+						cli.BoolFlag{
+							Name:  "dry-run",
+							Usage: "Only show resources that are going to be destroyed.",
+						},
+						cli.BoolFlag{
+							Name:  "force",
+							Usage: "Do not ask for confirmation.",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output destroy plan in JSON format.",
+						},
+					},
+				}, {
+					Name:   "describe",
+					Usage:  "Show status of a stack.",
+					Action: ctlcli.ExitErrAction(StackDescribe, log, "describe"),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Specify ID of the stack to describe.",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output in JSON format.",
+						},
 					},
 				}},
 			},
@@ -677,6 +894,72 @@ func run(args []string) {
 							Usage: "Do not ask form confirmation.",
 						},
 					},
+				}, {
+					Name:   "history",
+					Usage:  "List revisions of a stack template.",
+					Action: ctlcli.ExitErrAction(TemplateHistory, log, "history"),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "template, t",
+							Usage: "Show template with a given name.",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Limit to a template that matches the ID.",
+						},
+						cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output in JSON format.",
+						},
+					},
+				}, {
+					Name:   "diff",
+					Usage:  "Show differences between revisions of a stack template.",
+					Action: ctlcli.ExitErrAction(TemplateDiff, log, "diff"),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "template, t",
+							Usage: "Show template with a given name.",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Limit to a template that matches the ID.",
+						},
+						cli.IntFlag{
+							Name:  "from",
+							Usage: "Revision to compare.",
+						},
+						cli.IntFlag{
+							Name:  "to",
+							Usage: "Revision to compare with. Defaults to the latest one.",
+						},
+						cli.BoolFlag{
+							Name:  "hcl",
+							Usage: "Show differences in HCL format.",
+						},
+					},
+				}, {
+					Name:   "rollback",
+					Usage:  "Restore a stack template to the given revision.",
+					Action: ctlcli.ExitErrAction(TemplateRollback, log, "rollback"),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "template, t",
+							Usage: "Show template with a given name.",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "Limit to a template that matches the ID.",
+						},
+						cli.IntFlag{
+							Name:  "revision, r",
+							Usage: "Revision to restore.",
+						},
+						cli.BoolFlag{
+							Name:  "force",
+							Usage: "Do not ask for confirmation.",
+						},
+					},
 				}},
 			},
 
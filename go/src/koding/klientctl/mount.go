@@ -27,6 +27,7 @@ import (
 	"koding/klientctl/util"
 
 	"github.com/cheggaaa/pb"
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/structs"
 	"github.com/koding/kite"
 	"github.com/koding/kite/dnode"
@@ -40,6 +41,7 @@ const (
 )
 
 var IgnoreFiles = []string{
+	".kdignore",
 	".gitignore",
 	".hgignore",
 }
@@ -59,6 +61,9 @@ type MountOptions struct {
 	OneWayInterval   int
 	Fuse             bool
 
+	// BandwidthLimit limits bandwidth used by rsync transfers, e.g. "512KB".
+	BandwidthLimit string
+
 	// Used for Prefetching via RSync (SSH)
 	SSHDefaultKeyDir  string
 	SSHDefaultKeyName string
@@ -331,6 +336,8 @@ func (c *MountCommand) hasFlaggedOpts() bool {
 	noPosOpts.RemotePath = ""
 	// Ignore debug like the others, so that we can --debug smart mounting.
 	noPosOpts.Debug = false
+	// Bandwidth limit doesn't decide how to mount either.
+	noPosOpts.BandwidthLimit = ""
 
 	// If these options are using the default config values, then zero them as well.
 	// The user did not supply them.
@@ -377,6 +384,18 @@ func (c *MountCommand) handleOptions() (int, error) {
 		}
 	}
 
+	if c.Options.BandwidthLimit != "" {
+		if _, err := c.bandwidthLimit(); err != nil {
+			c.printfln("Invalid bandwidth limit %q.", c.Options.BandwidthLimit)
+			return 1, err
+		}
+
+		if !c.Options.OneWaySync && !c.Options.PrefetchAll {
+			c.printfln("Invalid Option: --bwlimit can be used only with --oneway-sync or --prefetch-all")
+			return 1, errors.New("Invalid CLI Option.")
+		}
+	}
+
 	if c.Options.PrefetchInterval == 0 {
 		c.Options.PrefetchInterval = 10
 		c.Log.Info("Setting interval to default, %d", c.Options.PrefetchInterval)
@@ -520,6 +539,10 @@ func (c *MountCommand) useSync() error {
 		SSHPrivateKeyPath: sshKey.PrivateKeyPath(),
 	}
 
+	if cacheReq.BandwidthLimit, err = c.bandwidthLimit(); err != nil {
+		return err
+	}
+
 	if err := c.cacheWithProgress(cacheReq); err != nil {
 		return err
 	}
@@ -569,9 +592,28 @@ func (c *MountCommand) prefetchAll() error {
 		SSHPrivateKeyPath: sshKey.PrivateKeyPath(),
 	}
 
+	if cacheReq.BandwidthLimit, err = c.bandwidthLimit(); err != nil {
+		return err
+	}
+
 	return c.cacheWithProgress(cacheReq)
 }
 
+// bandwidthLimit parses bandwidth limit option into the number of bytes per
+// second. Zero is returned when the option is not set.
+func (c *MountCommand) bandwidthLimit() (int64, error) {
+	if c.Options.BandwidthLimit == "" {
+		return 0, nil
+	}
+
+	limit, err := humanize.ParseBytes(c.Options.BandwidthLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(limit), nil
+}
+
 func (c *MountCommand) cacheWithProgress(cacheReq req.Cache) (err error) {
 	// doneErr is used to wait until the cache progress is done, and also send
 	// any error encountered. We simply send nil if there is no error.
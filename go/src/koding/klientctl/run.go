@@ -1,19 +1,24 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"syscall"
 
+	"koding/klient/command"
 	"koding/klient/remote/req"
 	"koding/klientctl/config"
 	"koding/klientctl/klient"
 	"koding/klientctl/metrics"
 	"koding/mountcli"
 
+	"github.com/koding/kite/dnode"
 	"github.com/koding/logging"
 
 	"github.com/codegangsta/cli"
@@ -46,40 +51,32 @@ func RunCommandFactory(c *cli.Context, log logging.Logger, _ string) int {
 		cmdWithArgsStr = strings.Join(cmdWithArgs, " ")
 	)
 
-	res, err := r.runOnRemote(localPath, cmdWithArgsStr)
-	if err != nil && err != mountcli.ErrNoMountPath {
-		log.Error("Error running command. err:", err)
-		// Note that we're printing the error here to the user. This seems reasonable
-		// since their own command may have failed, and that information is meaningful
-		// to them.
-		//
-		// Eg, the binary is not executable, or not formatted for their os, or etc.
-		fmt.Println("Error running command: '%s'\n", err)
-		return 1
-	}
-
+	machine, fullCmdPath, err := r.remotePath(localPath)
 	if err == mountcli.ErrNoMountPath {
 		fmt.Println("Running on local:", cmdWithArgsStr)
 		return r.runOnLocal(cmdWithArgs)
 	}
 
-	fmt.Println("Running on remote:", cmdWithArgsStr)
+	if err == nil {
+		// track metrics
+		metrics.TrackRun(machine, config.VersionNum())
 
-	// write to standard out stream
-	// this stream can contain values even if exit status is not 0.
-	if res.Stdout != "" {
-		os.Stdout.WriteString(res.Stdout)
-	}
+		fmt.Println("Running on remote:", cmdWithArgsStr)
 
-	if res.Stderr != "" {
-		os.Stderr.WriteString(res.Stderr)
-	}
-
-	if res.ExitStatus != 0 {
-		return res.ExitStatus
+		var exitStatus int
+		if exitStatus, err = r.runOnMachine(machine, fullCmdPath, cmdWithArgsStr); err == nil {
+			return exitStatus
+		}
 	}
 
-	return 0
+	log.Error("Error running command. err:", err)
+	// Note that we're printing the error here to the user. This seems reasonable
+	// since their own command may have failed, and that information is meaningful
+	// to them.
+	//
+	// Eg, the binary is not executable, or not formatted for their os, or etc.
+	fmt.Printf("Error running command: '%s'\n", err)
+	return 1
 }
 
 // RunCommand is the cli command that lets users run a command a remote
@@ -109,40 +106,97 @@ func NewRunCommand() (*RunCommand, error) {
 	return &RunCommand{Transport: klientKite}, nil
 }
 
-func (r *RunCommand) runOnRemote(localPath string, cmdWithArgsStr string) (*ExecRes, error) {
-	machine, err := mountcli.NewMountcli().FindMountNameByPath(localPath)
+// remotePath finds the mount which contains local path and returns its
+// machine name together with the corresponding path on remote machine.
+func (r *RunCommand) remotePath(localPath string) (machine, fullCmdPath string, err error) {
+	machine, err = mountcli.NewMountcli().FindMountNameByPath(localPath)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
 
-	fullCmdPath, err := r.getCmdRemotePath(machine, localPath)
+	fullCmdPath, err = r.getCmdRemotePath(machine, localPath)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
 
-	// track metrics
-	metrics.TrackRun(machine, config.VersionNum())
-
-	return r.runOnMachine(machine, fullCmdPath, cmdWithArgsStr)
+	return machine, fullCmdPath, nil
 }
 
-func (r *RunCommand) runOnMachine(machine, fullCmdPath string, cmdWithArgsStr string) (*ExecRes, error) {
-	req := req.Exec{
+// runOnMachine runs the command on remote machine and streams its output to
+// standard output and error. Standard input is forwarded to remote command.
+// Interrupting kd kills the remote command. It returns exit status of the
+// remote command.
+func (r *RunCommand) runOnMachine(machine, fullCmdPath string, cmdWithArgsStr string) (int, error) {
+	exitC := make(chan *command.StreamExit, 1)
+
+	req := req.ExecStream{
 		Machine: machine,
 		Command: cmdWithArgsStr,
 		Path:    fullCmdPath,
+		Stdout: dnode.Callback(func(p *dnode.Partial) {
+			os.Stdout.WriteString(p.One().MustString())
+		}),
+		Stderr: dnode.Callback(func(p *dnode.Partial) {
+			os.Stderr.WriteString(p.One().MustString())
+		}),
+		Exit: dnode.Callback(func(p *dnode.Partial) {
+			var exit command.StreamExit
+			p.One().Unmarshal(&exit)
+			exitC <- &exit
+		}),
 	}
-	raw, err := r.Tell("remote.exec", req)
+
+	sigC := make(chan os.Signal, 1)
+	signal.Notify(sigC, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigC)
+
+	raw, err := r.Tell("remote.execStream", req)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	var res ExecRes
-	if err := raw.Unmarshal(&res); err != nil {
-		return nil, err
+	var stream command.StreamHandle
+	if err := raw.Unmarshal(&stream); err != nil {
+		return 0, err
 	}
 
-	return &res, nil
+	go forwardInput(os.Stdin, &stream)
+
+	for {
+		select {
+		case exit := <-exitC:
+			if exit.Error != "" {
+				return 0, errors.New(exit.Error)
+			}
+
+			if exit.Killed || exit.TimedOut {
+				return 1, nil
+			}
+
+			return exit.ExitStatus, nil
+		case <-sigC:
+			stream.Kill.Call()
+		}
+	}
+}
+
+// forwardInput writes data read from r to the standard input of remote
+// process. Remote standard input is closed when r is drained.
+func forwardInput(r io.Reader, stream *command.StreamHandle) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if stream.Input.Call(string(buf[:n])) != nil {
+				return
+			}
+		}
+
+		if err != nil {
+			stream.CloseInput.Call()
+			return
+		}
+	}
 }
 
 // getCmdRemotePath return the path on remote machine where the command should
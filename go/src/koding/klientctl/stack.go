@@ -6,8 +6,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"koding/kites/kloud/eventer"
+	kloudstack "koding/kites/kloud/stack"
+	"koding/klientctl/endpoint/remoteapi"
 	"koding/klientctl/endpoint/stack"
+	"koding/klientctl/helper"
 
 	"github.com/codegangsta/cli"
 	"github.com/koding/logging"
@@ -30,8 +37,6 @@ func StackCreate(c *cli.Context, log logging.Logger, _ string) (int, error) {
 		return 1, errors.New("error reading template file: " + err.Error())
 	}
 
-	fmt.Fprintln(os.Stderr, "Creating stack... ")
-
 	opts := &stack.CreateOptions{
 		Team:        c.String("team"),
 		Title:       c.String("title"),
@@ -39,6 +44,45 @@ func StackCreate(c *cli.Context, log logging.Logger, _ string) (int, error) {
 		Template:    p,
 	}
 
+	if !c.Bool("json") {
+		fmt.Fprintln(os.Stderr, "Planning stack... ")
+
+		opts.DryRun = true
+		opts.KeepTemplate = true
+
+		plan, err := stack.Create(opts)
+		if err != nil {
+			return 1, errors.New("error planning stack: " + err.Error())
+		}
+
+		printCreatePlan(plan)
+
+		// Confirmation can be asked only when stdin is a terminal
+		// and the template was not read from it.
+		if !c.Bool("force") && c.String("file") != "-" && helper.IsTerminal() {
+			s, err := helper.Ask(`Please type "yes" to confirm you want to build the stack []: `)
+			if err == nil && s != "yes" {
+				err = errors.New("stack was not built")
+			}
+
+			if err != nil {
+				if e := remoteapi.DeleteTemplate(plan.TemplateID); e != nil {
+					log.Warning("unable to delete %q template: %s", plan.TemplateID, e)
+				}
+
+				return 1, err
+			}
+		}
+
+		// The stack is built from the template created for the plan.
+		opts.DryRun = false
+		opts.KeepTemplate = false
+		opts.TemplateID = plan.TemplateID
+		opts.Title = plan.Title
+	}
+
+	fmt.Fprintln(os.Stderr, "Creating stack... ")
+
 	resp, err := stack.Create(opts)
 	if err != nil {
 		return 1, errors.New("error creating stack: " + err.Error())
@@ -52,7 +96,206 @@ func StackCreate(c *cli.Context, log logging.Logger, _ string) (int, error) {
 		return 0, nil
 	}
 
-	fmt.Fprintf(os.Stderr, "Creatad %q stack with %s ID.\n", resp.Title, resp.StackID)
+	fmt.Fprintf(os.Stderr, "Creatad %q stack with %s ID.\nWaiting for the stack to finish building...\n\n", resp.Title, resp.StackID)
+
+	waitOpts := &stack.WaitOptions{
+		Type:    "apply",
+		EventID: resp.StackID,
+		OnEvent: printEvent,
+	}
+
+	if err := stack.Wait(waitOpts); err != nil {
+		return 1, errors.New("error building stack: " + err.Error())
+	}
+
+	return 0, nil
+}
+
+func StackDestroy(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts := &stack.DestroyOptions{
+		ID:       c.String("id"),
+		Team:     c.String("team"),
+		Provider: c.String("provider"),
+	}
+
+	if opts.ID == "" {
+		return 1, errors.New("error destroying stack - missing stack ID")
+	}
+
+	plan, err := stack.DestroyPlan(opts)
+	if err != nil {
+		return 1, errors.New("error reading destroy plan: " + err.Error())
+	}
+
+	if c.Bool("json") && c.Bool("dry-run") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(plan)
+
+		return 0, nil
+	}
+
+	printDestroyPlan(plan)
+
+	if c.Bool("dry-run") {
+		return 0, nil
+	}
+
+	if !c.Bool("force") {
+		s, err := helper.Ask(`Please type "yes" to confirm you want to destroy the stack []: `)
+		if err != nil {
+			return 1, err
+		}
+
+		if s != "yes" {
+			return 1, errors.New("confirmation failed, aborting")
+		}
+	}
+
+	resp, err := stack.Destroy(opts)
+	if err != nil {
+		return 1, errors.New("error destroying stack: " + err.Error())
+	}
+
+	log.Debug("stack.destroy response: %+v", resp)
+
+	fmt.Fprintf(os.Stderr, "Destroying %s stack...\n\n", opts.ID)
+
+	waitOpts := &stack.WaitOptions{
+		Type:    "stack.destroy",
+		EventID: opts.ID,
+		OnEvent: printEvent,
+	}
+
+	if err := stack.Wait(waitOpts); err != nil {
+		return 1, errors.New("error destroying stack: " + err.Error())
+	}
+
+	return 0, nil
+}
+
+func printCreatePlan(plan *kloudstack.ImportResponse) {
+	if len(plan.Machines) == 0 {
+		fmt.Fprint(os.Stderr, "Stack template has no machines.\n\n")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "MACHINE\tPROVIDER\tHOURLY\tMONTHLY")
+
+	for _, m := range plan.Machines {
+		if m.Cost != nil {
+			fmt.Fprintf(w, "%s\t%s\t%.4f %s\t%.2f %s\n", m.Label, m.Provider, m.Cost.Hourly, m.Cost.Currency, m.Cost.Monthly, m.Cost.Currency)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\n", m.Label, m.Provider)
+		}
+	}
+
+	w.Flush()
+
+	if plan.Cost != nil {
+		fmt.Printf("\nEstimated cost of the stack: %s\n", plan.Cost)
+
+		if plan.Cost.Partial {
+			fmt.Println("Some of the machines have no price, the actual cost may be higher.")
+		}
+	}
+
+	fmt.Println()
+}
+
+func printDestroyPlan(plan *kloudstack.DestroyPlanResponse) {
+	if len(plan.Machines) == 0 && len(plan.Resources) == 0 {
+		fmt.Fprintf(os.Stderr, "Stack %s has no resources to destroy.\n\n", plan.StackID)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "MACHINE\tID\tPROVIDER\tRESOURCES")
+
+	for _, m := range plan.Machines {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.Label, m.ID, m.Provider, strings.Join(m.Resources, ", "))
+	}
+
+	w.Flush()
+
+	if len(plan.Resources) != 0 {
+		fmt.Printf("\nThe following resources are going to be destroyed:\n\n")
+
+		for _, r := range plan.Resources {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	fmt.Println()
+}
+
+func StackDescribe(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	id := c.String("id")
+
+	if id == "" {
+		return 1, errors.New("error describing stack - missing stack ID")
+	}
+
+	resp, err := stack.Describe(id)
+	if err != nil {
+		return 1, errors.New("error describing stack: " + err.Error())
+	}
+
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(resp)
+
+		return 0, nil
+	}
+
+	printStatus(resp)
 
 	return 0, nil
 }
+
+func printStatus(resp *kloudstack.StatusResponse) {
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "ID\t%s\n", resp.StackID)
+	fmt.Fprintf(w, "STATUS\t%s\n", resp.Status)
+	fmt.Fprintf(w, "MODIFIED\t%s\n", resp.ModifiedAt.Format(time.RFC3339))
+
+	switch d := resp.Drift; {
+	case !d.Checked():
+		fmt.Fprintf(w, "DRIFT\tnot checked yet\n")
+	case d.Error != "":
+		fmt.Fprintf(w, "DRIFT\tcheck failed at %s: %s\n", d.CheckedAt.Format(time.RFC3339), d.Error)
+	case d.Drifted():
+		fmt.Fprintf(w, "DRIFT\t%d resources drifted (checked at %s)\n", len(d.Resources), d.CheckedAt.Format(time.RFC3339))
+	default:
+		fmt.Fprintf(w, "DRIFT\tnone (checked at %s)\n", d.CheckedAt.Format(time.RFC3339))
+	}
+
+	w.Flush()
+
+	if resp.Drift.Drifted() {
+		fmt.Printf("\nThe following resources differ from the last applied state:\n\n")
+
+		for _, r := range resp.Drift.Resources {
+			if len(r.Attributes) != 0 {
+				fmt.Printf("  - %s (%s: %s)\n", r.Name, r.State, strings.Join(r.Attributes, ", "))
+			} else {
+				fmt.Printf("  - %s (%s)\n", r.Name, r.State)
+			}
+		}
+	}
+
+	fmt.Println()
+}
+
+func printEvent(ev *eventer.Event) {
+	if ev.Message == "" {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "[%3d%%] %s\n", ev.Percentage, ev.Message)
+}
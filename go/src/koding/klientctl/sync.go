@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"koding/klient/machine/index"
+	"koding/klient/machine/mount"
+	"koding/klientctl/endpoint/machine"
+
+	"github.com/codegangsta/cli"
+	"github.com/dustin/go-humanize"
+	"github.com/koding/logging"
+)
+
+// SyncConflictsCommand lists conflicting files of a mount or resolves one of
+// them when --resolve flag is provided. The --policy flag changes how new
+// conflicts of the mount are resolved.
+func SyncConflictsCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := syncOptions(c, log.New("sync:conflicts"))
+	if err != nil {
+		return 1, err
+	}
+
+	if c.IsSet("policy") {
+		policy, err := mount.ParseConflictPolicy(c.String("policy"))
+		if err != nil {
+			return 1, err
+		}
+
+		popts := &machine.PolicyOptions{
+			SyncOptions: *opts,
+			Policy:      policy,
+		}
+
+		if err := machine.SetPolicy(popts); err != nil {
+			return 1, err
+		}
+
+		fmt.Printf("New conflicts will be resolved using %s policy.\n", policy)
+		return 0, nil
+	}
+
+	if policy := c.String("resolve"); policy != "" {
+		if c.String("file") == "" {
+			return 1, errors.New("--file flag is required when resolving a conflict")
+		}
+
+		ropts := &machine.ResolveOptions{
+			SyncOptions: *opts,
+			Name:        c.String("file"),
+			Policy:      mount.ConflictPolicy(policy),
+		}
+
+		if err := machine.Resolve(ropts); err != nil {
+			return 1, err
+		}
+
+		fmt.Printf("Conflict of %q was resolved using %s policy.\n", ropts.Name, ropts.Policy)
+		return 0, nil
+	}
+
+	conflicts, err := machine.Conflicts(opts)
+	if err != nil {
+		return 1, err
+	}
+
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(conflicts)
+		return 0, nil
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Println("Mount has no conflicts.")
+		return 0, nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "FILE\tLOCAL\tREMOTE\tDETECTED\n")
+	for _, cf := range conflicts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", cf.Name, entryState(cf.Local), entryState(cf.Remote), cf.DetectedAt.Format(time.RFC1123))
+	}
+
+	tw.Flush()
+	return 0, nil
+}
+
+// SyncIgnoreCommand prints ignore patterns of a mount. Patterns are replaced
+// when --pattern or --clear flag is provided.
+func SyncIgnoreCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := syncOptions(c, log.New("sync:ignore"))
+	if err != nil {
+		return 1, err
+	}
+
+	iopts := &machine.IgnoreOptions{
+		SyncOptions: *opts,
+	}
+
+	switch patterns := c.StringSlice("pattern"); {
+	case c.Bool("clear") && len(patterns) != 0:
+		return 1, errors.New("--clear and --pattern flags cannot be used together")
+	case c.Bool("clear"), len(patterns) != 0:
+		ignore := strings.Join(patterns, "\n")
+		iopts.Ignore = &ignore
+	}
+
+	ignore, err := machine.Ignore(iopts)
+	if err != nil {
+		return 1, err
+	}
+
+	if ignore == "" {
+		fmt.Println("Mount has no ignore patterns.")
+		return 0, nil
+	}
+
+	fmt.Println(ignore)
+	return 0, nil
+}
+
+// SyncStatusCommand shows synchronization progress of a mount. With --watch
+// flag, the status is printed each time klient reports its change.
+func SyncStatusCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := syncOptions(c, log.New("sync:status"))
+	if err != nil {
+		return 1, err
+	}
+
+	if !c.Bool("watch") {
+		st, err := machine.Status(opts)
+		if err != nil {
+			return 1, err
+		}
+
+		printSyncStatus(c, st)
+		return 0, nil
+	}
+
+	err = machine.WatchStatus(opts, func(st *mount.Status) {
+		printSyncStatus(c, st)
+	})
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func printSyncStatus(c *cli.Context, st *mount.Status) {
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		if !c.Bool("watch") {
+			enc.SetIndent("", "\t")
+		}
+		enc.Encode(st)
+		return
+	}
+
+	lastSync := "never"
+	if !st.LastSync.IsZero() {
+		lastSync = st.LastSync.Format(time.RFC1123)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	state := "syncing"
+	if st.Paused {
+		state = "paused"
+	}
+
+	bwlimit := "-"
+	if st.BandwidthLimit != 0 {
+		bwlimit = humanize.Bytes(uint64(st.BandwidthLimit)) + "/s"
+	}
+
+	fmt.Fprintf(tw, "STATE\tQUEUED\tIN FLIGHT\tFAILED\tCONFLICTS\tSENT\tRECEIVED\tBWLIMIT\tLAST SYNC\n")
+	fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t%s\n", state, st.Queued, st.InFlight, st.Failed, st.Conflicts,
+		humanize.Bytes(uint64(st.SentBytes)), humanize.Bytes(uint64(st.ReceivedBytes)), bwlimit, lastSync)
+
+	if len(st.Errors) != 0 {
+		fmt.Fprintf(tw, "\nFILE\tERROR\tTIME\n")
+		for _, fe := range st.Errors {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", fe.Name, fe.Error, fe.Time.Format(time.RFC1123))
+		}
+	}
+
+	tw.Flush()
+}
+
+// SyncPauseCommand temporarily stops file synchronization of a mount.
+func SyncPauseCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := syncOptions(c, log.New("sync:pause"))
+	if err != nil {
+		return 1, err
+	}
+
+	if err := machine.Pause(opts); err != nil {
+		return 1, err
+	}
+
+	fmt.Println("Synchronization was paused. Changes will be synchronized after resume.")
+	return 0, nil
+}
+
+// SyncResumeCommand restarts file synchronization of a mount and optionally
+// changes its bandwidth limit.
+func SyncResumeCommand(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	opts, err := syncOptions(c, log.New("sync:resume"))
+	if err != nil {
+		return 1, err
+	}
+
+	ropts := &machine.ResumeOptions{
+		SyncOptions: *opts,
+	}
+
+	if bwlimit := c.String("bwlimit"); bwlimit != "" {
+		limit, err := humanize.ParseBytes(bwlimit)
+		if err != nil {
+			return 1, fmt.Errorf("invalid bandwidth limit %q: %s", bwlimit, err)
+		}
+
+		n := int64(limit)
+		ropts.BandwidthLimit = &n
+	}
+
+	if err := machine.Resume(ropts); err != nil {
+		return 1, err
+	}
+
+	switch {
+	case ropts.BandwidthLimit == nil:
+		fmt.Println("Synchronization was resumed.")
+	case *ropts.BandwidthLimit == 0:
+		fmt.Println("Synchronization was resumed without bandwidth limit.")
+	default:
+		fmt.Printf("Synchronization was resumed with bandwidth limit of %s/s.\n", humanize.Bytes(uint64(*ropts.BandwidthLimit)))
+	}
+
+	return 0, nil
+}
+
+// syncOptions creates sync options from mount identifier provided as command
+// argument. Current working directory is used when no identifier is given.
+func syncOptions(c *cli.Context, log logging.Logger) (*machine.SyncOptions, error) {
+	idents, err := getIdentifiers(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(idents) > 1 {
+		return nil, fmt.Errorf("too many mounts: %s", strings.Join(idents, ", "))
+	}
+
+	if len(idents) == 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		idents = append(idents, wd)
+	}
+
+	return &machine.SyncOptions{
+		Identifier: idents[0],
+		Log:        log,
+	}, nil
+}
+
+// entryState describes the state of a conflicting file.
+func entryState(e *index.Entry) string {
+	if e == nil {
+		return "removed"
+	}
+
+	return fmt.Sprintf("%d bytes, %s", e.Size, time.Unix(0, e.MTime).Format(time.RFC1123))
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"text/tabwriter"
+	"time"
 
 	"koding/klientctl/endpoint/kloud"
 	"koding/klientctl/endpoint/remoteapi"
+	"koding/klientctl/endpoint/stack"
 	"koding/klientctl/helper"
 	"koding/remoteapi/models"
 
@@ -144,6 +147,130 @@ func TemplateDelete(c *cli.Context, log logging.Logger, _ string) (int, error) {
 	return 0, nil
 }
 
+func TemplateHistory(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	id, err := templateID(c, "requesting template history")
+	if err != nil {
+		return 1, err
+	}
+
+	resp, err := stack.TemplateHistory(id)
+	if err != nil {
+		return 1, errors.New("error requesting template history: " + err.Error())
+	}
+
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(resp)
+
+		return 0, nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "REVISION\tAUTHOR\tCREATED\tSUM")
+
+	for _, rev := range resp.Revisions {
+		revision := strconv.Itoa(rev.Revision)
+		if rev.Current {
+			revision += " (current)"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", revision, rev.Author, rev.CreatedAt.Format(time.RFC3339), rev.Sum)
+	}
+
+	return 0, nil
+}
+
+func TemplateDiff(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	id, err := templateID(c, "requesting template diff")
+	if err != nil {
+		return 1, err
+	}
+
+	opts := &stack.TemplateDiffOptions{
+		ID:   id,
+		From: c.Int("from"),
+		To:   c.Int("to"),
+	}
+
+	if c.Bool("hcl") {
+		opts.Format = "hcl"
+	}
+
+	resp, err := stack.TemplateDiff(opts)
+	if err != nil {
+		return 1, errors.New("error requesting template diff: " + err.Error())
+	}
+
+	fmt.Printf("--- revision %d\n+++ revision %d\n%s\n", resp.From, resp.To, resp.Diff)
+
+	return 0, nil
+}
+
+func TemplateRollback(c *cli.Context, log logging.Logger, _ string) (int, error) {
+	id, err := templateID(c, "rolling back template")
+	if err != nil {
+		return 1, err
+	}
+
+	revision := c.Int("revision")
+
+	if revision <= 0 {
+		return 1, errors.New("error rolling back template - missing revision")
+	}
+
+	if !c.Bool("force") {
+		s, err := helper.Ask(`Please type "yes" to confirm you want to roll back the template []: `)
+		if err != nil {
+			return 1, err
+		}
+
+		if s != "yes" {
+			return 1, errors.New("confirmation failed, aborting")
+		}
+	}
+
+	resp, err := stack.TemplateRollback(id, revision)
+	if err != nil {
+		return 1, errors.New("error rolling back template: " + err.Error())
+	}
+
+	fmt.Printf("Stack template with %q ID rolled back to revision %d as revision %d.\n", id, revision, resp.Revision)
+	fmt.Println("Existing stacks are not modified, they need to be reapplied.")
+
+	return 0, nil
+}
+
+// templateID gives an ID of the template requested with --id or --template
+// flags. If the latter is used, the ID is looked up.
+func templateID(c *cli.Context, action string) (string, error) {
+	tf := &remoteapi.TemplateFilter{
+		ID:   c.String("id"),
+		Slug: c.String("template"),
+	}
+
+	if tf.ID != "" {
+		return tf.ID, nil
+	}
+
+	if tf.Slug == "" {
+		return "", fmt.Errorf("error %s - missing slug name", action)
+	}
+
+	tmpls, err := remoteapi.ListTemplates(tf)
+	if err != nil {
+		return "", err
+	}
+
+	if len(tmpls) != 1 {
+		return "", fmt.Errorf("error %s - got %d templates, expecting only one", action, len(tmpls))
+	}
+
+	return tmpls[0].ID, nil
+}
+
 func printTemplates(templates []*models.JStackTemplate) {
 	w := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
 	defer w.Flush()
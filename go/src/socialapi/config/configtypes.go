@@ -98,9 +98,15 @@ type (
 	}
 
 	GateKeeper struct {
-		Host   string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_HOST"`
-		Port   string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_PORT"`
-		Pubnub Pubnub
+		Host string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_HOST"`
+		Port string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_PORT"`
+
+		// Provider is the realtime service, either "pubnub" or "gateway".
+		// PubNub is used when it is not set.
+		Provider string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_PROVIDER"`
+
+		Pubnub  Pubnub
+		Gateway Gateway
 	}
 
 	Pubnub struct {
@@ -112,6 +118,14 @@ type (
 		Origin        string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_PUBNUB_ORIGIN"`
 	}
 
+	// Gateway holds the address of the self-hosted realtime gateway and the
+	// secret which internal services use for its private endpoints
+	Gateway struct {
+		Host   string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_GATEWAY_HOST"`
+		Port   string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_GATEWAY_PORT"`
+		Secret string `env:"key=KONFIG_SOCIALAPI_GATEKEEPER_GATEWAY_SECRET"`
+	}
+
 	CustomDomain struct {
 		Public string `env:"key=KONFIG_SOCIALAPI_CUSTOMDOMAIN_PUBLIC"`
 		Local  string `env:"key=KONFIG_SOCIALAPI_CUSTOMDOMAIN_LOCAL"`
@@ -0,0 +1,8 @@
+--
+-- drop read receipt columns
+ALTER TABLE "api"."channel_participant" DROP COLUMN IF EXISTS "last_read_message_id";
+ALTER TABLE "api"."channel_participant" DROP COLUMN IF EXISTS "last_read_at";
+
+DROP TABLE IF EXISTS "api"."message_reaction";
+
+DROP SEQUENCE IF EXISTS "api"."message_reaction_id_seq";
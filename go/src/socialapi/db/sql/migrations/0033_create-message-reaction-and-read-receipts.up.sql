@@ -0,0 +1,54 @@
+--
+-- create the sequence
+--
+DO $$
+  BEGIN
+    BEGIN
+      CREATE SEQUENCE "api"."message_reaction_id_seq" INCREMENT 1 START 1 MAXVALUE 9223372036854775807 MINVALUE 1 CACHE 1;
+    EXCEPTION WHEN duplicate_table THEN
+    END;
+  END;
+$$;
+
+GRANT USAGE ON SEQUENCE "api"."message_reaction_id_seq" TO "social";
+
+--
+-- create message_reaction table for storing emoji reactions of accounts
+--
+CREATE TABLE IF NOT EXISTS "api"."message_reaction" (
+    "id" BIGINT NOT NULL DEFAULT nextval('api.message_reaction_id_seq'::regclass),
+    "message_id" BIGINT NOT NULL,
+    "account_id" BIGINT NOT NULL,
+    "emoji" VARCHAR (64) NOT NULL CHECK ("emoji" <> ''),
+    "created_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    -- create constraints along with table creation
+    PRIMARY KEY ("id") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "message_reaction_message_id_account_id_emoji_key" UNIQUE ("message_id", "account_id", "emoji") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "message_reaction_message_id_fkey" FOREIGN KEY ("message_id") REFERENCES api.channel_message (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "message_reaction_account_id_fkey" FOREIGN KEY ("account_id") REFERENCES api.account (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE
+) WITH (OIDS = FALSE);
+GRANT SELECT, INSERT, DELETE ON "api"."message_reaction" TO "social";
+
+--
+-- add read receipt columns into channel_participant
+--
+DO $$
+  BEGIN
+    BEGIN
+      ALTER TABLE "api"."channel_participant" ADD COLUMN "last_read_message_id" BIGINT NOT NULL DEFAULT 0;
+    EXCEPTION
+      WHEN duplicate_column THEN RAISE NOTICE 'last_read_message_id column already exists';
+    END;
+  END;
+$$;
+
+DO $$
+  BEGIN
+    BEGIN
+      ALTER TABLE "api"."channel_participant" ADD COLUMN "last_read_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now();
+    EXCEPTION
+      WHEN duplicate_column THEN RAISE NOTICE 'last_read_at column already exists';
+    END;
+  END;
+$$;
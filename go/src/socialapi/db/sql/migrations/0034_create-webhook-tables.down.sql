@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "api"."webhook_delivery_status_next_attempt_at_idx";
+DROP INDEX IF EXISTS "api"."webhook_delivery_subscription_id_created_at_idx";
+DROP TABLE IF EXISTS "api"."webhook_delivery";
+
+DROP INDEX IF EXISTS "api"."webhook_subscription_channel_id_idx";
+DROP TABLE IF EXISTS "api"."webhook_subscription";
+
+DROP SEQUENCE IF EXISTS "api"."webhook_delivery_id_seq";
+DROP SEQUENCE IF EXISTS "api"."webhook_subscription_id_seq";
@@ -0,0 +1,92 @@
+--
+-- create the sequences
+--
+DO $$
+  BEGIN
+    BEGIN
+      CREATE SEQUENCE "api"."webhook_subscription_id_seq" INCREMENT 1 START 1 MAXVALUE 9223372036854775807 MINVALUE 1 CACHE 1;
+    EXCEPTION WHEN duplicate_table THEN
+    END;
+  END;
+$$;
+
+GRANT USAGE ON SEQUENCE "api"."webhook_subscription_id_seq" TO "social";
+
+DO $$
+  BEGIN
+    BEGIN
+      CREATE SEQUENCE "api"."webhook_delivery_id_seq" INCREMENT 1 START 1 MAXVALUE 9223372036854775807 MINVALUE 1 CACHE 1;
+    EXCEPTION WHEN duplicate_table THEN
+    END;
+  END;
+$$;
+
+GRANT USAGE ON SEQUENCE "api"."webhook_delivery_id_seq" TO "social";
+
+--
+-- create webhook_subscription table for storing outgoing webhooks of channels
+--
+CREATE TABLE IF NOT EXISTS "api"."webhook_subscription" (
+    "id" BIGINT NOT NULL DEFAULT nextval('api.webhook_subscription_id_seq'::regclass),
+    "channel_id" BIGINT NOT NULL,
+    "creator_id" BIGINT NOT NULL,
+    "url" VARCHAR (2000) NOT NULL CHECK ("url" <> ''),
+    "secret" VARCHAR (200) NOT NULL CHECK ("secret" <> ''),
+    "events" VARCHAR (500) NOT NULL CHECK ("events" <> ''),
+    "created_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    -- create constraints along with table creation
+    PRIMARY KEY ("id") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "webhook_subscription_channel_id_fkey" FOREIGN KEY ("channel_id") REFERENCES api.channel (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "webhook_subscription_creator_id_fkey" FOREIGN KEY ("creator_id") REFERENCES api.account (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE
+) WITH (OIDS = FALSE);
+GRANT SELECT, INSERT, UPDATE, DELETE ON "api"."webhook_subscription" TO "social";
+
+DO $$
+  BEGIN
+    CREATE INDEX "webhook_subscription_channel_id_idx" ON api.webhook_subscription USING btree(channel_id DESC);
+  EXCEPTION WHEN duplicate_table THEN
+    RAISE NOTICE 'webhook_subscription_channel_id_idx already exists';
+  END;
+$$;
+
+--
+-- create webhook_delivery table for storing delivery attempts of webhooks
+--
+CREATE TABLE IF NOT EXISTS "api"."webhook_delivery" (
+    "id" BIGINT NOT NULL DEFAULT nextval('api.webhook_delivery_id_seq'::regclass),
+    "subscription_id" BIGINT NOT NULL,
+    "delivery_id" VARCHAR (100) NOT NULL,
+    "event" VARCHAR (100) NOT NULL,
+    "attempt" INTEGER NOT NULL DEFAULT 0,
+    "status" VARCHAR (20) NOT NULL DEFAULT 'pending',
+    "payload" TEXT NOT NULL,
+    "status_code" INTEGER NOT NULL DEFAULT 0,
+    "error" TEXT,
+    "duration" BIGINT NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+    "created_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    -- create constraints along with table creation
+    PRIMARY KEY ("id") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "webhook_delivery_subscription_id_fkey" FOREIGN KEY ("subscription_id") REFERENCES api.webhook_subscription (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE
+) WITH (OIDS = FALSE);
+GRANT SELECT, INSERT, UPDATE, DELETE ON "api"."webhook_delivery" TO "social";
+
+DO $$
+  BEGIN
+    CREATE INDEX "webhook_delivery_subscription_id_created_at_idx" ON api.webhook_delivery USING btree(subscription_id DESC, created_at DESC);
+  EXCEPTION WHEN duplicate_table THEN
+    RAISE NOTICE 'webhook_delivery_subscription_id_created_at_idx already exists';
+  END;
+$$;
+
+DO $$
+  BEGIN
+    CREATE INDEX "webhook_delivery_status_next_attempt_at_idx" ON api.webhook_delivery USING btree(status, next_attempt_at);
+  EXCEPTION WHEN duplicate_table THEN
+    RAISE NOTICE 'webhook_delivery_status_next_attempt_at_idx already exists';
+  END;
+$$;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "api"."incoming_webhook_channel_id_idx";
+DROP TABLE IF EXISTS "api"."incoming_webhook";
+
+DROP SEQUENCE IF EXISTS "api"."incoming_webhook_id_seq";
@@ -0,0 +1,42 @@
+--
+-- create the sequence
+--
+DO $$
+  BEGIN
+    BEGIN
+      CREATE SEQUENCE "api"."incoming_webhook_id_seq" INCREMENT 1 START 1 MAXVALUE 9223372036854775807 MINVALUE 1 CACHE 1;
+    EXCEPTION WHEN duplicate_table THEN
+    END;
+  END;
+$$;
+
+GRANT USAGE ON SEQUENCE "api"."incoming_webhook_id_seq" TO "social";
+
+--
+-- create incoming_webhook table for storing tokens which external systems
+-- use for posting bot messages into channels
+--
+CREATE TABLE IF NOT EXISTS "api"."incoming_webhook" (
+    "id" BIGINT NOT NULL DEFAULT nextval('api.incoming_webhook_id_seq'::regclass),
+    "channel_id" BIGINT NOT NULL,
+    "creator_id" BIGINT NOT NULL,
+    "name" VARCHAR (200) NOT NULL CHECK ("name" <> ''),
+    "token" VARCHAR (100) NOT NULL CHECK ("token" <> ''),
+    "created_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+    "updated_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    -- create constraints along with table creation
+    PRIMARY KEY ("id") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "incoming_webhook_token_key" UNIQUE ("token") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "incoming_webhook_channel_id_fkey" FOREIGN KEY ("channel_id") REFERENCES api.channel (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "incoming_webhook_creator_id_fkey" FOREIGN KEY ("creator_id") REFERENCES api.account (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE
+) WITH (OIDS = FALSE);
+GRANT SELECT, INSERT, UPDATE, DELETE ON "api"."incoming_webhook" TO "social";
+
+DO $$
+  BEGIN
+    CREATE INDEX "incoming_webhook_channel_id_idx" ON api.incoming_webhook USING btree(channel_id DESC);
+  EXCEPTION WHEN duplicate_table THEN
+    RAISE NOTICE 'incoming_webhook_channel_id_idx already exists';
+  END;
+$$;
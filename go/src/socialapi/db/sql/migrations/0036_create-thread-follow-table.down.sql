@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "api"."thread_follow_account_id_created_at_idx";
+DROP TABLE IF EXISTS "api"."thread_follow";
+
+DROP SEQUENCE IF EXISTS "api"."thread_follow_id_seq";
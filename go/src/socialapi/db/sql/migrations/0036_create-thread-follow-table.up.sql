@@ -0,0 +1,40 @@
+--
+-- create the sequence
+--
+DO $$
+  BEGIN
+    BEGIN
+      CREATE SEQUENCE "api"."thread_follow_id_seq" INCREMENT 1 START 1 MAXVALUE 9223372036854775807 MINVALUE 1 CACHE 1;
+    EXCEPTION WHEN duplicate_table THEN
+    END;
+  END;
+$$;
+
+GRANT USAGE ON SEQUENCE "api"."thread_follow_id_seq" TO "social";
+
+--
+-- create thread_follow table for storing accounts following replies of
+-- messages
+--
+CREATE TABLE IF NOT EXISTS "api"."thread_follow" (
+    "id" BIGINT NOT NULL DEFAULT nextval('api.thread_follow_id_seq'::regclass),
+    "message_id" BIGINT NOT NULL,
+    "account_id" BIGINT NOT NULL,
+    "last_seen_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+    "created_at" timestamp(6) WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    -- create constraints along with table creation
+    PRIMARY KEY ("id") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "thread_follow_message_id_account_id_key" UNIQUE ("message_id", "account_id") NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "thread_follow_message_id_fkey" FOREIGN KEY ("message_id") REFERENCES api.channel_message (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE,
+    CONSTRAINT "thread_follow_account_id_fkey" FOREIGN KEY ("account_id") REFERENCES api.account (id) ON UPDATE NO ACTION ON DELETE NO ACTION NOT DEFERRABLE INITIALLY IMMEDIATE
+) WITH (OIDS = FALSE);
+GRANT SELECT, INSERT, UPDATE, DELETE ON "api"."thread_follow" TO "social";
+
+DO $$
+  BEGIN
+    CREATE INDEX "thread_follow_account_id_created_at_idx" ON api.thread_follow USING btree(account_id DESC, created_at DESC);
+  EXCEPTION WHEN duplicate_table THEN
+    RAISE NOTICE 'thread_follow_account_id_created_at_idx already exists';
+  END;
+$$;
@@ -449,7 +449,8 @@ func (c *ChannelMessage) isInChannel(query *request.Query, channelName string) (
 }
 
 // DeleteMessageDependencies deletes all records from the database that are
-// dependencies of a given message. This includes replies, and channel message lists.
+// dependencies of a given message. This includes replies, reactions and channel
+// message lists.
 func (c *ChannelMessage) DeleteMessageAndDependencies(deleteReplies bool) error {
 	if deleteReplies {
 		if err := c.DeleteReplies(); err != nil {
@@ -466,6 +467,17 @@ func (c *ChannelMessage) DeleteMessageAndDependencies(deleteReplies bool) error
 	if err != nil {
 		return err
 	}
+
+	// delete reactions of the message
+	if err := NewMessageReaction().DeleteByMessageId(c.Id); err != nil {
+		return err
+	}
+
+	// delete followers of the thread
+	if err := NewThreadFollow().DeleteByMessageId(c.Id); err != nil {
+		return err
+	}
+
 	// delete channel message itself
 	return c.Delete()
 }
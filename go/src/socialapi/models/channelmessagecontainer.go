@@ -1,6 +1,10 @@
 package models
 
-import "socialapi/request"
+import (
+	"socialapi/request"
+
+	"github.com/koding/bongo"
+)
 
 type ChannelMessageContainer struct {
 	Message      *ChannelMessage `json:"message"`
@@ -10,6 +14,7 @@ type ChannelMessageContainer struct {
 	Replies            ChannelMessageContainers `json:"replies"`
 	AccountOldId       string                   `json:"accountOldId"`
 	IsFollowed         bool                     `json:"isFollowed"`
+	IsThreadFollowed   bool                     `json:"isThreadFollowed"`
 	UnreadRepliesCount int                      `json:"unreadRepliesCount,omitempty"`
 	ParentID           int64                    `json:"parentId,omitempty,string"`
 	Err                error                    `json:"-"`
@@ -168,9 +173,43 @@ func (cc *ChannelMessageContainer) AddIsFollowed(query *request.Query) *ChannelM
 	})
 }
 
-func (cc *ChannelMessageContainer) AddUnreadRepliesCount() *ChannelMessageContainer {
+// AddIsThreadFollowed sets whether the requester follows the replies of the
+// message
+func (cc *ChannelMessageContainer) AddIsThreadFollowed(query *request.Query) *ChannelMessageContainer {
+	return withChannelMessageContainerChecks(cc, func(c *ChannelMessageContainer) error {
+		if query.AccountId == 0 {
+			return nil
+		}
+
+		tf := NewThreadFollow()
+		tf.MessageId = c.Message.Id
+		tf.AccountId = query.AccountId
+
+		isFollowed, err := tf.IsFollowed()
+		c.IsThreadFollowed = isFollowed
+		return err
+	})
+}
+
+// AddUnreadRepliesCount sets the count of the replies which the requester
+// hasn't seen yet, it is only set for the followed messages
+func (cc *ChannelMessageContainer) AddUnreadRepliesCount(query *request.Query) *ChannelMessageContainer {
 	return withChannelMessageContainerChecks(cc, func(c *ChannelMessageContainer) error {
-		panic("method not implemented")
+		if query.AccountId == 0 {
+			return nil
+		}
+
+		tf := NewThreadFollow()
+		tf.MessageId = c.Message.Id
+		tf.AccountId = query.AccountId
+
+		count, err := tf.UnreadCount(query.ShowExempt)
+		if err == bongo.RecordNotFound {
+			return nil
+		}
+
+		c.UnreadRepliesCount = count
+		return err
 	})
 }
 
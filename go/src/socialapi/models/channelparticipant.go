@@ -33,6 +33,12 @@ type ChannelParticipant struct {
 	// date of the user's last access to regarding channel
 	LastSeenAt time.Time `json:"lastSeenAt"        sql:"NOT NULL"`
 
+	// Id of the last message the user has read in the channel
+	LastReadMessageId int64 `json:"lastReadMessageId,string"`
+
+	// date of the user's last read receipt in the channel
+	LastReadAt time.Time `json:"lastReadAt"`
+
 	// Creation date of the channel channel participant
 	CreatedAt time.Time `json:"createdAt"          sql:"NOT NULL"`
 
@@ -618,6 +624,41 @@ func (c *ChannelParticipant) RawUpdateLastSeenAt(t time.Time) error {
 	return nil
 }
 
+// MarkAsRead moves the read cursor of the participant to the given message.
+// Message ids are increasing, so the cursor never moves backwards; the
+// returned bool tells whether it has moved. The cursor is compared in the
+// update itself, so concurrent calls can not move it backwards either.
+func (c *ChannelParticipant) MarkAsRead(messageId int64) (bool, error) {
+	if messageId == 0 {
+		return false, ErrMessageIdIsNotSet
+	}
+
+	if c.Id == 0 {
+		return false, ErrIdIsNotSet
+	}
+
+	now := time.Now().UTC()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET last_read_message_id = ?, last_read_at = ? WHERE id = ? AND last_read_message_id < ?",
+		c.BongoName(),
+	)
+
+	res := bongo.B.DB.Exec(query, messageId, now, c.Id, messageId)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	c.LastReadMessageId = messageId
+	c.LastReadAt = now
+
+	return true, nil
+}
+
 func (c *ChannelParticipant) Glance() error {
 	c.LastSeenAt = time.Now().UTC()
 
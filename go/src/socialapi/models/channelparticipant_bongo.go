@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/koding/bongo"
@@ -17,6 +18,11 @@ func (c ChannelParticipant) BongoName() string {
 func (c *ChannelParticipant) BeforeCreate() error {
 	c.LastSeenAt = time.Now().UTC()
 
+	// zero time would be written instead of the column default
+	if c.LastReadAt.IsZero() {
+		c.LastReadAt = c.LastSeenAt
+	}
+
 	return c.MarkIfExempt()
 }
 
@@ -38,8 +44,37 @@ func (c ChannelParticipant) AfterDelete() {
 	bongo.B.AfterDelete(c)
 }
 
+// Update updates all fields of the participant except the read cursor, which
+// is moved only by MarkAsRead, so updating a participant fetched before the
+// cursor has moved does not move it back.
 func (c *ChannelParticipant) Update() error {
-	return bongo.B.Update(c)
+	if c.Id == 0 {
+		return bongo.IdIsNotSet
+	}
+
+	if err := c.BeforeUpdate(); err != nil {
+		return err
+	}
+
+	c.UpdatedAt = time.Now().UTC()
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET channel_id = ?, account_id = ?, status_constant = ?, meta_bits = ?, "+
+			"last_seen_at = ?, created_at = ?, updated_at = ? WHERE id = ?",
+		c.BongoName(),
+	)
+
+	err := bongo.B.DB.Exec(query,
+		c.ChannelId, c.AccountId, c.StatusConstant, c.MetaBits,
+		c.LastSeenAt, c.CreatedAt, c.UpdatedAt, c.Id,
+	).Error
+	if err != nil {
+		return err
+	}
+
+	c.AfterUpdate()
+
+	return nil
 }
 
 func (c *ChannelParticipant) DeleteForce() error {
@@ -477,3 +477,60 @@ func TestChannelParticipantisExempt(t *testing.T) {
 		})
 	})
 }
+
+func TestChannelParticipantMarkAsRead(t *testing.T) {
+	tests.WithRunner(t, func(r *runner.Runner) {
+		Convey("While marking a channel as read", t, func() {
+			Convey("it should have message id", func() {
+				cp := NewChannelParticipant()
+
+				moved, err := cp.MarkAsRead(0)
+				So(err, ShouldEqual, ErrMessageIdIsNotSet)
+				So(moved, ShouldBeFalse)
+			})
+
+			Convey("it should only move read cursor forward", func() {
+				acc := CreateAccountWithTest()
+				c := CreateChannelWithTest(acc.Id)
+
+				cp, err := c.AddParticipant(acc.Id)
+				So(err, ShouldBeNil)
+				So(cp, ShouldNotBeNil)
+
+				moved, err := cp.MarkAsRead(10)
+				So(err, ShouldBeNil)
+				So(moved, ShouldBeTrue)
+				So(cp.LastReadAt.IsZero(), ShouldBeFalse)
+
+				moved, err = cp.MarkAsRead(5)
+				So(err, ShouldBeNil)
+				So(moved, ShouldBeFalse)
+
+				// fetch the updated participant
+				So(cp.FetchParticipant(), ShouldBeNil)
+				So(cp.LastReadMessageId, ShouldEqual, 10)
+			})
+
+			Convey("it should not be moved back by other updates", func() {
+				acc := CreateAccountWithTest()
+				c := CreateChannelWithTest(acc.Id)
+
+				cp, err := c.AddParticipant(acc.Id)
+				So(err, ShouldBeNil)
+				So(cp.LastReadAt.IsZero(), ShouldBeFalse)
+
+				stale := *cp
+
+				moved, err := cp.MarkAsRead(10)
+				So(err, ShouldBeNil)
+				So(moved, ShouldBeTrue)
+
+				So(stale.Glance(), ShouldBeNil)
+
+				// fetch the updated participant
+				So(cp.FetchParticipant(), ShouldBeNil)
+				So(cp.LastReadMessageId, ShouldEqual, 10)
+			})
+		})
+	})
+}
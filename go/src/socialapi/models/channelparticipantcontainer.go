@@ -3,10 +3,12 @@ package models
 import "time"
 
 type ChannelParticipantContainer struct {
-	AccountId    int64     `json:"accountId"`
-	AccountOldId string    `json:"accountOldId"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	AccountId         int64     `json:"accountId"`
+	AccountOldId      string    `json:"accountOldId"`
+	LastReadMessageId int64     `json:"lastReadMessageId,string"`
+	LastReadAt        time.Time `json:"lastReadAt"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
 }
 
 func NewChannelParticipantContainer(cp ChannelParticipant) (*ChannelParticipantContainer, error) {
@@ -16,9 +18,11 @@ func NewChannelParticipantContainer(cp ChannelParticipant) (*ChannelParticipantC
 	}
 
 	return &ChannelParticipantContainer{
-		AccountId:    cp.AccountId,
-		AccountOldId: acc.OldId,
-		CreatedAt:    cp.CreatedAt,
-		UpdatedAt:    cp.UpdatedAt,
+		AccountId:         cp.AccountId,
+		AccountOldId:      acc.OldId,
+		LastReadMessageId: cp.LastReadMessageId,
+		LastReadAt:        cp.LastReadAt,
+		CreatedAt:         cp.CreatedAt,
+		UpdatedAt:         cp.UpdatedAt,
 	}, nil
 }
@@ -17,6 +17,8 @@ var (
 	ErrMessageIsNotSet         = errors.New("message is not set")
 	ErrParentMessageIsNotSet   = errors.New("parent message is not set")
 	ErrParentMessageIdIsNotSet = errors.New("parent message id is not set")
+	ErrReplyCursorNotFound     = errors.New("cursor is not a reply of the message")
+	ErrReplyCanNotBeFollowed   = errors.New("replies can not be followed")
 	ErrCreatorIdIsNotSet       = errors.New("creator id is not set")
 	ErrSystemTypeIsNotSet      = errors.New("systemType is not set in payload")
 
@@ -66,6 +68,20 @@ var (
 	ErrLeafIsRootToo         = errors.New("leaf channel is root of another channel")
 	ErrLinkingProcessNotDone = errors.New("channel linking process is not finished")
 
+	// reactions
+	ErrEmojiIsNotSet  = errors.New("emoji is not set")
+	ErrEmojiIsTooLong = errors.New("emoji is too long")
+
+	// webhooks
+	ErrWebhookURLIsNotSet     = errors.New("webhook url is not set")
+	ErrWebhookURLIsNotValid   = errors.New("webhook url must be an absolute http or https url")
+	ErrWebhookEventsAreNotSet = errors.New("webhook events are not set")
+	ErrWebhookHostIsNotPublic = errors.New("webhook url must not point to a private address")
+	ErrWebhookTokenIsNotSet   = errors.New("webhook token is not set")
+	ErrWebhookMessageIsNotSet = errors.New("webhook message body is not set")
+	ErrAttachmentsNotValid    = errors.New("attachments must be a json array")
+	ErrAttachmentsTooLong     = errors.New("attachments are too long")
+
 	ErrLoggerNotExist = errors.New("logger does not exist")
 	ErrRedisNotExist  = errors.New("redis connection is not established")
 )
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+// IncomingWebhook holds an incoming webhook of a channel, external systems
+// post bot messages into the channel with its token
+type IncomingWebhook struct {
+	// unique identifier of the webhook
+	Id int64 `json:"id,string"`
+
+	// Id of the channel which messages are posted to
+	ChannelId int64 `json:"channelId,string"   sql:"NOT NULL"`
+
+	// Id of the account who created the webhook
+	CreatorId int64 `json:"creatorId,string"   sql:"NOT NULL"`
+
+	// Name is shown as the author of posted messages
+	Name string `json:"name"                   sql:"NOT NULL;TYPE:VARCHAR(200);"`
+
+	// Token identifies the webhook in its url, it is generated while
+	// creating the webhook
+	Token string `json:"token"                 sql:"NOT NULL;TYPE:VARCHAR(100);"`
+
+	// Creation date of the webhook
+	CreatedAt time.Time `json:"createdAt"       sql:"NOT NULL"`
+
+	// Modification date of the webhook
+	UpdatedAt time.Time `json:"updatedAt"       sql:"NOT NULL"`
+}
+
+func (w *IncomingWebhook) Create() error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	token, err := generateWebhookSecret()
+	if err != nil {
+		return err
+	}
+
+	w.Token = token
+
+	return bongo.B.Create(w)
+}
+
+func (w *IncomingWebhook) Update() error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	return bongo.B.Update(w)
+}
+
+func (w *IncomingWebhook) Delete() error {
+	if w.Id == 0 {
+		return ErrIdIsNotSet
+	}
+
+	return bongo.B.Delete(w)
+}
+
+// RegenerateToken replaces the token of the webhook, the old url stops
+// working
+func (w *IncomingWebhook) RegenerateToken() error {
+	token, err := generateWebhookSecret()
+	if err != nil {
+		return err
+	}
+
+	w.Token = token
+
+	return w.Update()
+}
+
+// ByToken fetches the webhook with the given token
+func (w *IncomingWebhook) ByToken(token string) error {
+	if token == "" {
+		return ErrWebhookTokenIsNotSet
+	}
+
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"token": token,
+		},
+	}
+
+	return w.One(query)
+}
+
+// ListByChannelId returns incoming webhooks of the given channel
+func (w *IncomingWebhook) ListByChannelId(channelId int64) ([]IncomingWebhook, error) {
+	if channelId == 0 {
+		return nil, ErrChannelIdIsNotSet
+	}
+
+	var webhooks []IncomingWebhook
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"channel_id": channelId,
+		},
+		Sort: map[string]string{
+			"created_at": "ASC",
+		},
+	}
+
+	if err := w.Some(&webhooks, query); err != nil && err != bongo.RecordNotFound {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (w *IncomingWebhook) validate() error {
+	if w.ChannelId == 0 {
+		return ErrChannelIdIsNotSet
+	}
+
+	if w.CreatorId == 0 {
+		return ErrCreatorIdIsNotSet
+	}
+
+	if w.Name == "" {
+		return ErrNameIsNotSet
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+func (w IncomingWebhook) GetId() int64 {
+	return w.Id
+}
+
+func (w IncomingWebhook) BongoName() string {
+	return "api.incoming_webhook"
+}
+
+func NewIncomingWebhook() *IncomingWebhook {
+	return &IncomingWebhook{}
+}
+
+func (w *IncomingWebhook) BeforeCreate() error {
+	w.CreatedAt = time.Now().UTC()
+	w.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (w *IncomingWebhook) BeforeUpdate() error {
+	w.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (w *IncomingWebhook) ById(id int64) error {
+	return bongo.B.ById(w, id)
+}
+
+func (w *IncomingWebhook) One(q *bongo.Query) error {
+	return bongo.B.One(w, w, q)
+}
+
+func (w *IncomingWebhook) Some(data interface{}, q *bongo.Query) error {
+	return bongo.B.Some(w, data, q)
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/koding/bongo"
+)
+
+// MessageReactionMaxEmojiLength is the maximum length of a reaction emoji in
+// characters, it is enough for shortcodes like ":thumbsup:" and for unicode
+// emoji sequences.
+const MessageReactionMaxEmojiLength = 64
+
+type MessageReaction struct {
+	// unique identifier of the MessageReaction
+	Id int64 `json:"id,string"`
+
+	// Id of the reacted message
+	MessageId int64 `json:"messageId,string"   sql:"NOT NULL"`
+
+	// Id of the reacting account
+	AccountId int64 `json:"accountId,string"   sql:"NOT NULL"`
+
+	// Emoji of the reaction, either an emoji shortcode or the emoji itself
+	Emoji string `json:"emoji"                 sql:"NOT NULL;TYPE:VARCHAR(64);"`
+
+	// Creation date of the reaction
+	CreatedAt time.Time `json:"createdAt"      sql:"NOT NULL"`
+}
+
+// MessageReactionSummary holds the reactions of a message grouped by their
+// emoji
+type MessageReactionSummary struct {
+	Emoji      string   `json:"emoji"`
+	Count      int      `json:"count"`
+	AccountIds []string `json:"accountIds"`
+}
+
+// Create adds the reaction of the account to the message, adding the same
+// reaction again does nothing
+func (m *MessageReaction) Create() error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	err := m.fetch()
+	if err == nil {
+		return nil
+	}
+
+	if err != bongo.RecordNotFound {
+		return err
+	}
+
+	return bongo.B.Create(m)
+}
+
+// Delete removes the reaction of the account from the message
+func (m *MessageReaction) Delete() error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	if err := m.fetch(); err != nil {
+		return err
+	}
+
+	return bongo.B.Delete(m)
+}
+
+// DeleteByMessageId removes all reactions of the given message
+func (m *MessageReaction) DeleteByMessageId(messageId int64) error {
+	if messageId == 0 {
+		return ErrMessageIdIsNotSet
+	}
+
+	var reactions []MessageReaction
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"message_id": messageId,
+		},
+	}
+
+	if err := m.Some(&reactions, query); err != nil && err != bongo.RecordNotFound {
+		return err
+	}
+
+	for i := range reactions {
+		if err := bongo.B.Delete(&reactions[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List returns reactions of the message, oldest first
+func (m *MessageReaction) List() ([]MessageReaction, error) {
+	if m.MessageId == 0 {
+		return nil, ErrMessageIdIsNotSet
+	}
+
+	var reactions []MessageReaction
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"message_id": m.MessageId,
+		},
+		Sort: map[string]string{
+			"created_at": "ASC",
+		},
+	}
+
+	if err := m.Some(&reactions, query); err != nil && err != bongo.RecordNotFound {
+		return nil, err
+	}
+
+	return reactions, nil
+}
+
+// Summarize groups the reactions of the message by their emoji, emojis are
+// ordered by their first usage
+func (m *MessageReaction) Summarize() ([]*MessageReactionSummary, error) {
+	reactions, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return SummarizeReactions(reactions), nil
+}
+
+// SummarizeReactions groups the given reactions by their emoji, preserving
+// the order of reactions
+func SummarizeReactions(reactions []MessageReaction) []*MessageReactionSummary {
+	summaries := make([]*MessageReactionSummary, 0)
+	byEmoji := make(map[string]*MessageReactionSummary)
+
+	for _, reaction := range reactions {
+		summary, ok := byEmoji[reaction.Emoji]
+		if !ok {
+			summary = &MessageReactionSummary{
+				Emoji:      reaction.Emoji,
+				AccountIds: make([]string, 0),
+			}
+
+			byEmoji[reaction.Emoji] = summary
+			summaries = append(summaries, summary)
+		}
+
+		summary.Count++
+		summary.AccountIds = append(summary.AccountIds, strconv.FormatInt(reaction.AccountId, 10))
+	}
+
+	return summaries
+}
+
+func (m *MessageReaction) fetch() error {
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"message_id": m.MessageId,
+			"account_id": m.AccountId,
+			"emoji":      m.Emoji,
+		},
+	}
+
+	return m.One(query)
+}
+
+func (m *MessageReaction) validate() error {
+	if m.MessageId == 0 {
+		return ErrMessageIdIsNotSet
+	}
+
+	if m.AccountId == 0 {
+		return ErrAccountIdIsNotSet
+	}
+
+	if m.Emoji == "" {
+		return ErrEmojiIsNotSet
+	}
+
+	if utf8.RuneCountInString(m.Emoji) > MessageReactionMaxEmojiLength {
+		return ErrEmojiIsTooLong
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import "github.com/koding/bongo"
+
+func (m MessageReaction) GetId() int64 {
+	return m.Id
+}
+
+func (m MessageReaction) BongoName() string {
+	return "api.message_reaction"
+}
+
+func NewMessageReaction() *MessageReaction {
+	return &MessageReaction{}
+}
+
+func (m *MessageReaction) AfterCreate() {
+	bongo.B.AfterCreate(m)
+}
+
+func (m *MessageReaction) AfterUpdate() {
+	bongo.B.AfterUpdate(m)
+}
+
+func (m *MessageReaction) AfterDelete() {
+	bongo.B.AfterDelete(m)
+}
+
+func (m *MessageReaction) ById(id int64) error {
+	return bongo.B.ById(m, id)
+}
+
+func (m *MessageReaction) Some(data interface{}, q *bongo.Query) error {
+	return bongo.B.Some(m, data, q)
+}
+
+func (m *MessageReaction) One(q *bongo.Query) error {
+	return bongo.B.One(m, m, q)
+}
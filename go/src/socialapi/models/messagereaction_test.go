@@ -0,0 +1,134 @@
+package models
+
+import (
+	"socialapi/workers/common/tests"
+	"strings"
+	"testing"
+
+	"github.com/koding/bongo"
+	"github.com/koding/runner"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessageReactionBongoName(t *testing.T) {
+	Convey("While getting table name", t, func() {
+		Convey("table names should match", func() {
+			m := MessageReaction{}
+			So(m.BongoName(), ShouldEqual, "api.message_reaction")
+		})
+	})
+}
+
+func TestMessageReactionCreate(t *testing.T) {
+	tests.WithRunner(t, func(r *runner.Runner) {
+		Convey("While creating a reaction", t, func() {
+			Convey("it should have message id", func() {
+				m := NewMessageReaction()
+				m.AccountId = 1
+				m.Emoji = ":+1:"
+				So(m.Create(), ShouldEqual, ErrMessageIdIsNotSet)
+			})
+
+			Convey("it should have emoji", func() {
+				m := NewMessageReaction()
+				m.MessageId = 1
+				m.AccountId = 1
+				So(m.Create(), ShouldEqual, ErrEmojiIsNotSet)
+			})
+
+			Convey("it should not have a long emoji", func() {
+				m := NewMessageReaction()
+				m.MessageId = 1
+				m.AccountId = 1
+				m.Emoji = strings.Repeat("x", MessageReactionMaxEmojiLength+1)
+				So(m.Create(), ShouldEqual, ErrEmojiIsTooLong)
+			})
+
+			Convey("it should not be duplicated", func() {
+				cm := CreateMessageWithTest()
+				So(cm.Create(), ShouldBeNil)
+
+				m := NewMessageReaction()
+				m.MessageId = cm.Id
+				m.AccountId = cm.AccountId
+				m.Emoji = ":+1:"
+				So(m.Create(), ShouldBeNil)
+				So(m.Id, ShouldNotEqual, 0)
+
+				m2 := NewMessageReaction()
+				m2.MessageId = cm.Id
+				m2.AccountId = cm.AccountId
+				m2.Emoji = ":+1:"
+				So(m2.Create(), ShouldBeNil)
+				So(m2.Id, ShouldEqual, m.Id)
+
+				reactions, err := m.List()
+				So(err, ShouldBeNil)
+				So(len(reactions), ShouldEqual, 1)
+
+				Convey("it should be removed with the message", func() {
+					So(cm.DeleteMessageAndDependencies(false), ShouldBeNil)
+
+					reactions, err := m.List()
+					So(err, ShouldBeNil)
+					So(len(reactions), ShouldEqual, 0)
+				})
+			})
+		})
+	})
+}
+
+func TestMessageReactionDelete(t *testing.T) {
+	tests.WithRunner(t, func(r *runner.Runner) {
+		Convey("While deleting a reaction", t, func() {
+			cm := CreateMessageWithTest()
+			So(cm.Create(), ShouldBeNil)
+
+			m := NewMessageReaction()
+			m.MessageId = cm.Id
+			m.AccountId = cm.AccountId
+			m.Emoji = ":tada:"
+
+			Convey("it should return not found for missing reaction", func() {
+				So(m.Delete(), ShouldEqual, bongo.RecordNotFound)
+			})
+
+			Convey("it should delete existing reaction", func() {
+				So(m.Create(), ShouldBeNil)
+				So(m.Delete(), ShouldBeNil)
+
+				reactions, err := m.List()
+				So(err, ShouldBeNil)
+				So(len(reactions), ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestMessageReactionSummarizeReactions(t *testing.T) {
+	Convey("While summarizing reactions", t, func() {
+		Convey("empty reactions should result in empty summary", func() {
+			So(SummarizeReactions(nil), ShouldBeEmpty)
+		})
+
+		Convey("reactions should be grouped by emoji in order", func() {
+			reactions := []MessageReaction{
+				{AccountId: 1, Emoji: ":+1:"},
+				{AccountId: 2, Emoji: ":tada:"},
+				{AccountId: 3, Emoji: ":+1:"},
+			}
+
+			summaries := SummarizeReactions(reactions)
+			So(len(summaries), ShouldEqual, 2)
+
+			So(summaries[0].Emoji, ShouldEqual, ":+1:")
+			So(summaries[0].Count, ShouldEqual, 2)
+			So(summaries[0].AccountIds, ShouldResemble, []string{"1", "3"})
+
+			So(summaries[1].Emoji, ShouldEqual, ":tada:")
+			So(summaries[1].Count, ShouldEqual, 1)
+			So(summaries[1].AccountIds, ShouldResemble, []string{"2"})
+		})
+	})
+}
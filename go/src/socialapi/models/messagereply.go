@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"socialapi/request"
 	"time"
 
@@ -112,22 +113,54 @@ func (m *MessageReply) fetchMessages(query *request.Query) ([]ChannelMessage, er
 		return nil, ErrMessageIdIsNotSet
 	}
 
+	// replies after the cursor are fetched oldest first, so the ones right
+	// after the cursor are returned, they are reversed below
+	sortOrder := "DESC"
+	if query.Before == 0 && query.After != 0 {
+		sortOrder = "ASC"
+	}
+
 	q := &bongo.Query{
 		Selector: map[string]interface{}{
 			"message_id": m.MessageId,
 		},
 		Pluck:      "reply_id",
 		Pagination: *bongo.NewPagination(query.Limit, query.Skip),
-		Sort:       map[string]string{"created_at": "DESC"},
 	}
 
 	q.AddScope(RemoveTrollContent(m, query.ShowExempt))
 
-	bongoQuery := bongo.B.BuildQuery(m, q)
+	// replies created at the same time are ordered by their ids, so the
+	// cursor conditions below neither skip nor repeat them
+	bongoQuery := bongo.B.BuildQuery(m, q).
+		Order(fmt.Sprintf("created_at %s, reply_id %s", sortOrder, sortOrder))
 	if !query.From.IsZero() {
 		bongoQuery = bongoQuery.Where("created_at < ?", query.From)
 	}
 
+	switch {
+	case query.Before != 0:
+		cursor, err := m.fetchCursor(query.Before)
+		if err != nil {
+			return nil, err
+		}
+
+		bongoQuery = bongoQuery.Where(
+			"(created_at < ? OR (created_at = ? AND reply_id < ?))",
+			cursor.CreatedAt, cursor.CreatedAt, cursor.ReplyId,
+		)
+	case query.After != 0:
+		cursor, err := m.fetchCursor(query.After)
+		if err != nil {
+			return nil, err
+		}
+
+		bongoQuery = bongoQuery.Where(
+			"(created_at > ? OR (created_at = ? AND reply_id > ?))",
+			cursor.CreatedAt, cursor.CreatedAt, cursor.ReplyId,
+		)
+	}
+
 	var replies []int64
 	if err := bongo.CheckErr(
 		bongoQuery.Pluck(q.Pluck, &replies),
@@ -135,6 +168,12 @@ func (m *MessageReply) fetchMessages(query *request.Query) ([]ChannelMessage, er
 		return nil, err
 	}
 
+	if sortOrder == "ASC" {
+		for i, j := 0, len(replies)-1; i < j; i, j = i+1, j-1 {
+			replies[i], replies[j] = replies[j], replies[i]
+		}
+	}
+
 	parent := NewChannelMessage()
 	channelMessageReplies, err := parent.FetchByIds(replies)
 	if err != nil {
@@ -144,6 +183,28 @@ func (m *MessageReply) fetchMessages(query *request.Query) ([]ChannelMessage, er
 	return channelMessageReplies, nil
 }
 
+// fetchCursor fetches the given reply of the message, it is used as a
+// pagination cursor
+func (m *MessageReply) fetchCursor(replyId int64) (*MessageReply, error) {
+	cursor := NewMessageReply()
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"message_id": m.MessageId,
+			"reply_id":   replyId,
+		},
+	}
+
+	if err := cursor.One(query); err != nil {
+		if err == bongo.RecordNotFound {
+			return nil, ErrReplyCursorNotFound
+		}
+
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
 func (m *MessageReply) UnreadCount(messageId int64, addedAt time.Time, showExempt bool) (int, error) {
 	if messageId == 0 {
 		return 0, ErrMessageIdIsNotSet
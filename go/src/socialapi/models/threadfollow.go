@@ -0,0 +1,205 @@
+package models
+
+import (
+	"socialapi/request"
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+// ThreadFollow holds an account following the replies of a message, replies
+// which are added after LastSeenAt are counted as unread
+type ThreadFollow struct {
+	// unique identifier of the ThreadFollow
+	Id int64 `json:"id,string"`
+
+	// Id of the followed message
+	MessageId int64 `json:"messageId,string"   sql:"NOT NULL"`
+
+	// Id of the following account
+	AccountId int64 `json:"accountId,string"   sql:"NOT NULL"`
+
+	// Date of the last reply the account has seen
+	LastSeenAt time.Time `json:"lastSeenAt"    sql:"NOT NULL"`
+
+	// Creation date of the follow
+	CreatedAt time.Time `json:"createdAt"      sql:"NOT NULL"`
+}
+
+// Create makes the account follow the message, following the same message
+// again does nothing
+func (t *ThreadFollow) Create() error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	err := t.fetch()
+	if err == nil {
+		return nil
+	}
+
+	if err != bongo.RecordNotFound {
+		return err
+	}
+
+	return bongo.B.Create(t)
+}
+
+// Delete makes the account unfollow the message
+func (t *ThreadFollow) Delete() error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	if err := t.fetch(); err != nil {
+		return err
+	}
+
+	return bongo.B.Delete(t)
+}
+
+// DeleteByMessageId removes all followers of the given message
+func (t *ThreadFollow) DeleteByMessageId(messageId int64) error {
+	if messageId == 0 {
+		return ErrMessageIdIsNotSet
+	}
+
+	var follows []ThreadFollow
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"message_id": messageId,
+		},
+	}
+
+	if err := t.Some(&follows, query); err != nil && err != bongo.RecordNotFound {
+		return err
+	}
+
+	for i := range follows {
+		if err := bongo.B.Delete(&follows[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsFollowed checks if the account follows the message
+func (t *ThreadFollow) IsFollowed() (bool, error) {
+	if err := t.validate(); err != nil {
+		return false, err
+	}
+
+	err := t.fetch()
+	if err == nil {
+		return true, nil
+	}
+
+	if err == bongo.RecordNotFound {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// MarkAsSeen marks the replies of the message added until the given time as
+// seen, the account must be following the message
+func (t *ThreadFollow) MarkAsSeen(seenAt time.Time) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	if err := t.fetch(); err != nil {
+		return err
+	}
+
+	// replies are always seen in order, never go back
+	if !seenAt.After(t.LastSeenAt) {
+		return nil
+	}
+
+	t.LastSeenAt = seenAt.UTC()
+
+	return bongo.B.Update(t)
+}
+
+// MarkAsSeenUntil marks the replies of the message until the given reply,
+// including itself, as seen
+func (t *ThreadFollow) MarkAsSeenUntil(replyId int64) error {
+	mr := NewMessageReply()
+	mr.MessageId = t.MessageId
+
+	cursor, err := mr.fetchCursor(replyId)
+	if err != nil {
+		return err
+	}
+
+	return t.MarkAsSeen(cursor.CreatedAt)
+}
+
+// UnreadCount returns the count of the replies which are added after the
+// account has seen the thread last time
+func (t *ThreadFollow) UnreadCount(showExempt bool) (int, error) {
+	if err := t.validate(); err != nil {
+		return 0, err
+	}
+
+	if err := t.fetch(); err != nil {
+		return 0, err
+	}
+
+	return NewMessageReply().UnreadCount(t.MessageId, t.LastSeenAt, showExempt)
+}
+
+// ListMessageIds returns ids of the messages followed by the account,
+// recently followed first
+func (t *ThreadFollow) ListMessageIds(q *request.Query) ([]int64, error) {
+	if t.AccountId == 0 {
+		return nil, ErrAccountIdIsNotSet
+	}
+
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"account_id": t.AccountId,
+		},
+		Pluck:      "message_id",
+		Pagination: *bongo.NewPagination(q.Limit, q.Skip),
+		Sort: map[string]string{
+			"created_at": "DESC",
+		},
+	}
+
+	var messageIds []int64
+	if err := t.Some(&messageIds, query); err != nil && err != bongo.RecordNotFound {
+		return nil, err
+	}
+
+	if messageIds == nil {
+		return make([]int64, 0), nil
+	}
+
+	return messageIds, nil
+}
+
+func (t *ThreadFollow) fetch() error {
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"message_id": t.MessageId,
+			"account_id": t.AccountId,
+		},
+	}
+
+	return t.One(query)
+}
+
+func (t *ThreadFollow) validate() error {
+	if t.MessageId == 0 {
+		return ErrMessageIdIsNotSet
+	}
+
+	if t.AccountId == 0 {
+		return ErrAccountIdIsNotSet
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+func (t ThreadFollow) GetId() int64 {
+	return t.Id
+}
+
+func (t ThreadFollow) BongoName() string {
+	return "api.thread_follow"
+}
+
+func NewThreadFollow() *ThreadFollow {
+	return &ThreadFollow{}
+}
+
+func (t *ThreadFollow) BeforeCreate() error {
+	now := time.Now().UTC()
+	t.CreatedAt = now
+
+	// replies which are added before following are not unread
+	if t.LastSeenAt.IsZero() {
+		t.LastSeenAt = now
+	}
+
+	return nil
+}
+
+func (t *ThreadFollow) AfterCreate() {
+	bongo.B.AfterCreate(t)
+}
+
+func (t *ThreadFollow) AfterUpdate() {
+	bongo.B.AfterUpdate(t)
+}
+
+func (t *ThreadFollow) AfterDelete() {
+	bongo.B.AfterDelete(t)
+}
+
+func (t *ThreadFollow) ById(id int64) error {
+	return bongo.B.ById(t, id)
+}
+
+func (t *ThreadFollow) Some(data interface{}, q *bongo.Query) error {
+	return bongo.B.Some(t, data, q)
+}
+
+func (t *ThreadFollow) One(q *bongo.Query) error {
+	return bongo.B.One(t, t, q)
+}
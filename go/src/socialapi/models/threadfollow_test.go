@@ -0,0 +1,118 @@
+package models
+
+import (
+	"socialapi/workers/common/tests"
+	"testing"
+	"time"
+
+	"github.com/koding/bongo"
+	"github.com/koding/runner"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestThreadFollowBongoName(t *testing.T) {
+	Convey("While getting table name", t, func() {
+		Convey("table names should match", func() {
+			tf := ThreadFollow{}
+			So(tf.BongoName(), ShouldEqual, "api.thread_follow")
+		})
+	})
+}
+
+func TestThreadFollowCreate(t *testing.T) {
+	tests.WithRunner(t, func(r *runner.Runner) {
+		Convey("While following a thread", t, func() {
+			Convey("it should have message id", func() {
+				tf := NewThreadFollow()
+				tf.AccountId = 1
+				So(tf.Create(), ShouldEqual, ErrMessageIdIsNotSet)
+			})
+
+			Convey("it should have account id", func() {
+				tf := NewThreadFollow()
+				tf.MessageId = 1
+				So(tf.Create(), ShouldEqual, ErrAccountIdIsNotSet)
+			})
+
+			Convey("it should not be duplicated", func() {
+				cm := CreateMessageWithTest()
+				So(cm.Create(), ShouldBeNil)
+
+				tf := NewThreadFollow()
+				tf.MessageId = cm.Id
+				tf.AccountId = cm.AccountId
+				So(tf.Create(), ShouldBeNil)
+				So(tf.Id, ShouldNotEqual, 0)
+
+				tf2 := NewThreadFollow()
+				tf2.MessageId = cm.Id
+				tf2.AccountId = cm.AccountId
+				So(tf2.Create(), ShouldBeNil)
+				So(tf2.Id, ShouldEqual, tf.Id)
+
+				isFollowed, err := tf.IsFollowed()
+				So(err, ShouldBeNil)
+				So(isFollowed, ShouldBeTrue)
+
+				Convey("it should be removed with unfollow", func() {
+					So(tf.Delete(), ShouldBeNil)
+
+					isFollowed, err := tf2.IsFollowed()
+					So(err, ShouldBeNil)
+					So(isFollowed, ShouldBeFalse)
+
+					So(tf2.Delete(), ShouldEqual, bongo.RecordNotFound)
+				})
+			})
+		})
+	})
+}
+
+func TestThreadFollowUnreadCount(t *testing.T) {
+	tests.WithRunner(t, func(r *runner.Runner) {
+		Convey("While counting unread replies of a thread", t, func() {
+			cm := CreateMessageWithTest()
+			So(cm.Create(), ShouldBeNil)
+
+			tf := NewThreadFollow()
+			tf.MessageId = cm.Id
+			tf.AccountId = cm.AccountId
+			So(tf.Create(), ShouldBeNil)
+
+			var last *ChannelMessage
+			for i := 0; i < 3; i++ {
+				reply := CreateMessageWithTest()
+				reply.TypeConstant = ChannelMessage_TYPE_REPLY
+				So(reply.Create(), ShouldBeNil)
+
+				_, err := cm.AddReply(reply)
+				So(err, ShouldBeNil)
+
+				last = reply
+			}
+
+			Convey("replies added after following should be unread", func() {
+				count, err := tf.UnreadCount(false)
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 3)
+			})
+
+			Convey("seen replies should not be unread", func() {
+				So(tf.MarkAsSeenUntil(last.Id), ShouldBeNil)
+
+				count, err := tf.UnreadCount(false)
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 0)
+
+				Convey("seen date should not go back", func() {
+					So(tf.MarkAsSeen(time.Now().Add(-time.Hour)), ShouldBeNil)
+
+					count, err := tf.UnreadCount(false)
+					So(err, ShouldBeNil)
+					So(count, ShouldEqual, 0)
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"fmt"
+	"socialapi/request"
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+const (
+	// WebhookDelivery_STATUS_PENDING is set while the event waits for being
+	// delivered, including retries
+	WebhookDelivery_STATUS_PENDING = "pending"
+
+	// WebhookDelivery_STATUS_DELIVERED is set after the event was delivered
+	WebhookDelivery_STATUS_DELIVERED = "delivered"
+
+	// WebhookDelivery_STATUS_FAILED is set after the delivery was given up
+	WebhookDelivery_STATUS_FAILED = "failed"
+)
+
+// WebhookDelivery holds the delivery of a webhook event to a subscription,
+// they form the delivery log of the subscription. Pending deliveries are the
+// queue of the webhook worker, so they survive its restarts
+type WebhookDelivery struct {
+	// unique identifier of the delivery
+	Id int64 `json:"id,string"`
+
+	// Id of the subscription which the event is sent to
+	SubscriptionId int64 `json:"subscriptionId,string" sql:"NOT NULL"`
+
+	// Unique identifier of the event, it is the same for all attempts of an
+	// event and sent in the request headers
+	DeliveryId string `json:"deliveryId"                sql:"NOT NULL;TYPE:VARCHAR(100);"`
+
+	// Name of the sent event
+	Event string `json:"event"                          sql:"NOT NULL;TYPE:VARCHAR(100);"`
+
+	// Number of attempts made so far
+	Attempt int `json:"attempt"                         sql:"NOT NULL"`
+
+	// Status of the delivery, one of pending, delivered or failed
+	Status string `json:"status"                        sql:"NOT NULL;TYPE:VARCHAR(20);"`
+
+	// Sent request body
+	Payload string `json:"payload"                      sql:"NOT NULL;TYPE:TEXT;"`
+
+	// Status code of the last response, 0 if no response was received
+	StatusCode int `json:"statusCode"`
+
+	// Error of the last attempt, empty if the event was delivered
+	Error string `json:"error,omitempty"                sql:"TYPE:TEXT;"`
+
+	// Duration of the last request in milliseconds
+	Duration int64 `json:"duration"`
+
+	// Date of the next attempt of a pending delivery
+	NextAttemptAt time.Time `json:"nextAttemptAt"       sql:"NOT NULL"`
+
+	// Creation date of the delivery
+	CreatedAt time.Time `json:"createdAt"               sql:"NOT NULL"`
+
+	// Modification date of the delivery
+	UpdatedAt time.Time `json:"updatedAt"               sql:"NOT NULL"`
+}
+
+// IsDelivered checks whether the last attempt was successful
+func (w *WebhookDelivery) IsDelivered() bool {
+	return w.Error == "" && w.StatusCode >= 200 && w.StatusCode < 300
+}
+
+// IsPending checks whether the delivery waits for its next attempt
+func (w *WebhookDelivery) IsPending() bool {
+	return w.Status == WebhookDelivery_STATUS_PENDING
+}
+
+// ListDue returns pending deliveries whose next attempt is due at the given
+// time, oldest first
+func (w *WebhookDelivery) ListDue(now time.Time, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := bongo.B.DB.
+		Table(w.BongoName()).
+		Where("status = ? AND next_attempt_at <= ?", WebhookDelivery_STATUS_PENDING, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil && err != bongo.RecordNotFound {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Claim postpones the next attempt of the pending delivery to the given time,
+// so other workers do not pick it up while it is being delivered. It returns
+// false if the delivery was already claimed by another worker
+func (w *WebhookDelivery) Claim(until time.Time) (bool, error) {
+	if w.Id == 0 {
+		return false, ErrIdIsNotSet
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at = ?",
+		w.BongoName(),
+	)
+
+	res := bongo.B.DB.Exec(query, until, w.Id, WebhookDelivery_STATUS_PENDING, w.NextAttemptAt)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	w.NextAttemptAt = until
+
+	return true, nil
+}
+
+// DeleteFinishedBefore deletes delivered and failed deliveries created
+// before the given time
+func (w *WebhookDelivery) DeleteFinishedBefore(t time.Time) error {
+	return bongo.B.DB.
+		Table(w.BongoName()).
+		Where("status <> ? AND created_at < ?", WebhookDelivery_STATUS_PENDING, t).
+		Delete(w).Error
+}
+
+// ListBySubscriptionId returns the delivery log of the subscription, newest
+// first
+func (w *WebhookDelivery) ListBySubscriptionId(subscriptionId int64, q *request.Query) ([]WebhookDelivery, error) {
+	if subscriptionId == 0 {
+		return nil, ErrIdIsNotSet
+	}
+
+	var deliveries []WebhookDelivery
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"subscription_id": subscriptionId,
+		},
+		Sort: map[string]string{
+			"created_at": "DESC",
+		},
+		Pagination: *bongo.NewPagination(q.Limit, q.Skip),
+	}
+
+	if err := w.Some(&deliveries, query); err != nil && err != bongo.RecordNotFound {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// DeleteBySubscriptionId deletes the delivery log of the subscription
+func (w *WebhookDelivery) DeleteBySubscriptionId(subscriptionId int64) error {
+	if subscriptionId == 0 {
+		return ErrIdIsNotSet
+	}
+
+	return bongo.B.DB.
+		Table(w.BongoName()).
+		Where("subscription_id = ?", subscriptionId).
+		Delete(w).Error
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+func (w WebhookDelivery) GetId() int64 {
+	return w.Id
+}
+
+func (w WebhookDelivery) BongoName() string {
+	return "api.webhook_delivery"
+}
+
+func NewWebhookDelivery() *WebhookDelivery {
+	return &WebhookDelivery{}
+}
+
+func (w *WebhookDelivery) BeforeCreate() error {
+	w.CreatedAt = time.Now().UTC()
+	w.UpdatedAt = time.Now().UTC()
+
+	if w.Status == "" {
+		w.Status = WebhookDelivery_STATUS_PENDING
+	}
+
+	if w.NextAttemptAt.IsZero() {
+		w.NextAttemptAt = w.CreatedAt
+	}
+
+	return nil
+}
+
+func (w *WebhookDelivery) BeforeUpdate() error {
+	w.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (w *WebhookDelivery) Create() error {
+	return bongo.B.Create(w)
+}
+
+func (w *WebhookDelivery) Update() error {
+	return bongo.B.Update(w)
+}
+
+func (w *WebhookDelivery) Some(data interface{}, q *bongo.Query) error {
+	return bongo.B.Some(w, data, q)
+}
@@ -0,0 +1,322 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+// here is why i did this not-so-good constants
+// https://code.google.com/p/go/issues/detail?id=359
+const (
+	Webhook_EVENT_MESSAGE_CREATED     = "message_created"
+	Webhook_EVENT_MESSAGE_UPDATED     = "message_updated"
+	Webhook_EVENT_PARTICIPANT_ADDED   = "participant_added"
+	Webhook_EVENT_PARTICIPANT_REMOVED = "participant_removed"
+	Webhook_EVENT_CHANNEL_UPDATED     = "channel_updated"
+)
+
+// WebhookEvents holds all events a webhook can subscribe to
+var WebhookEvents = []string{
+	Webhook_EVENT_MESSAGE_CREATED,
+	Webhook_EVENT_MESSAGE_UPDATED,
+	Webhook_EVENT_PARTICIPANT_ADDED,
+	Webhook_EVENT_PARTICIPANT_REMOVED,
+	Webhook_EVENT_CHANNEL_UPDATED,
+}
+
+// privateWebhookNets holds address ranges webhooks can not be sent to, so
+// they can not be used for reaching internal services
+var privateWebhookNets = parseCIDRs(
+	"0.0.0.0/8",      // current network
+	"10.0.0.0/8",     // private
+	"100.64.0.0/10",  // carrier-grade NAT
+	"127.0.0.0/8",    // loopback
+	"169.254.0.0/16", // link-local
+	"172.16.0.0/12",  // private
+	"192.168.0.0/16", // private
+	"::1/128",        // loopback
+	"fc00::/7",       // unique local
+	"fe80::/10",      // link-local
+)
+
+// lookupWebhookIP resolves webhook hosts, it is replaced in tests
+var lookupWebhookIP = net.LookupIP
+
+// WebhookEventList is a list of event names, it is stored as a comma
+// separated string
+type WebhookEventList []string
+
+// Value implements driver.Valuer interface
+func (w WebhookEventList) Value() (driver.Value, error) {
+	return strings.Join(w, ","), nil
+}
+
+// Scan implements sql.Scanner interface
+func (w *WebhookEventList) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("unsupported type for webhook events: %T", value)
+	}
+
+	*w = nil
+	if s != "" {
+		*w = strings.Split(s, ",")
+	}
+
+	return nil
+}
+
+// Has checks if the list contains the given event
+func (w WebhookEventList) Has(event string) bool {
+	for _, e := range w {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookSubscription holds an outgoing webhook of a channel, events of the
+// channel are posted to its url
+type WebhookSubscription struct {
+	// unique identifier of the subscription
+	Id int64 `json:"id,string"`
+
+	// Id of the channel which events are sent
+	ChannelId int64 `json:"channelId,string"       sql:"NOT NULL"`
+
+	// Id of the account who created the subscription
+	CreatorId int64 `json:"creatorId,string"       sql:"NOT NULL"`
+
+	// Url which events are posted to
+	URL string `json:"url"                         sql:"NOT NULL;TYPE:VARCHAR(2000);"`
+
+	// Secret is used for signing the request bodies, it is generated if it
+	// is not given while creating the subscription
+	Secret string `json:"secret"                   sql:"NOT NULL;TYPE:VARCHAR(200);"`
+
+	// Events which are sent to the url
+	Events WebhookEventList `json:"events"          sql:"NOT NULL;TYPE:VARCHAR(500);"`
+
+	// Creation date of the subscription
+	CreatedAt time.Time `json:"createdAt"           sql:"NOT NULL"`
+
+	// Modification date of the subscription
+	UpdatedAt time.Time `json:"updatedAt"           sql:"NOT NULL"`
+}
+
+func (w *WebhookSubscription) Create() error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	if err := w.checkHost(); err != nil {
+		return err
+	}
+
+	if w.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+
+		w.Secret = secret
+	}
+
+	return bongo.B.Create(w)
+}
+
+func (w *WebhookSubscription) Update() error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	if err := w.checkHost(); err != nil {
+		return err
+	}
+
+	return bongo.B.Update(w)
+}
+
+// Delete deletes the subscription with its delivery log
+func (w *WebhookSubscription) Delete() error {
+	if w.Id == 0 {
+		return ErrIdIsNotSet
+	}
+
+	if err := NewWebhookDelivery().DeleteBySubscriptionId(w.Id); err != nil {
+		return err
+	}
+
+	return bongo.B.Delete(w)
+}
+
+// ListByChannelId returns subscriptions of the given channel
+func (w *WebhookSubscription) ListByChannelId(channelId int64) ([]WebhookSubscription, error) {
+	if channelId == 0 {
+		return nil, ErrChannelIdIsNotSet
+	}
+
+	var subscriptions []WebhookSubscription
+	query := &bongo.Query{
+		Selector: map[string]interface{}{
+			"channel_id": channelId,
+		},
+		Sort: map[string]string{
+			"created_at": "ASC",
+		},
+	}
+
+	if err := w.Some(&subscriptions, query); err != nil && err != bongo.RecordNotFound {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// ListByEvent returns subscriptions of the given channel which are
+// subscribed to the given event
+func (w *WebhookSubscription) ListByEvent(channelId int64, event string) ([]WebhookSubscription, error) {
+	subscriptions, err := w.ListByChannelId(channelId)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.Events.Has(event) {
+			filtered = append(filtered, subscription)
+		}
+	}
+
+	return filtered, nil
+}
+
+// Sign returns the signature of the given body, receivers verify the
+// requests by computing the same signature with the shared secret
+func (w *WebhookSubscription) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *WebhookSubscription) validate() error {
+	if w.ChannelId == 0 {
+		return ErrChannelIdIsNotSet
+	}
+
+	if w.CreatorId == 0 {
+		return ErrCreatorIdIsNotSet
+	}
+
+	if w.URL == "" {
+		return ErrWebhookURLIsNotSet
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrWebhookURLIsNotValid
+	}
+
+	if len(w.Events) == 0 {
+		return ErrWebhookEventsAreNotSet
+	}
+
+	for _, event := range w.Events {
+		if !isWebhookEvent(event) {
+			return fmt.Errorf("unknown webhook event %q", event)
+		}
+	}
+
+	return nil
+}
+
+// checkHost resolves the host of the url and checks that none of its
+// addresses is private; the worker checks the addresses again when it
+// connects, since they can change after the subscription is saved
+func (w *WebhookSubscription) checkHost() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return ErrWebhookURLIsNotValid
+	}
+
+	ips, err := lookupWebhookIP(u.Hostname())
+	if err != nil {
+		return fmt.Errorf("could not resolve webhook host %q: %s", u.Hostname(), err)
+	}
+
+	for _, ip := range ips {
+		if !IsPublicWebhookIP(ip) {
+			return ErrWebhookHostIsNotPublic
+		}
+	}
+
+	return nil
+}
+
+// IsPublicWebhookIP checks whether webhooks can be sent to the given address;
+// loopback, link-local, private and multicast addresses are not allowed
+func IsPublicWebhookIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, n := range privateWebhookNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets
+}
+
+func isWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func generateWebhookSecret() (string, error) {
+	p := make([]byte, 32)
+	if _, err := rand.Read(p); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(p), nil
+}
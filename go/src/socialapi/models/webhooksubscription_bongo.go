@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+func (w WebhookSubscription) GetId() int64 {
+	return w.Id
+}
+
+func (w WebhookSubscription) BongoName() string {
+	return "api.webhook_subscription"
+}
+
+func NewWebhookSubscription() *WebhookSubscription {
+	return &WebhookSubscription{}
+}
+
+func (w *WebhookSubscription) BeforeCreate() error {
+	w.CreatedAt = time.Now().UTC()
+	w.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (w *WebhookSubscription) BeforeUpdate() error {
+	w.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (w *WebhookSubscription) ById(id int64) error {
+	return bongo.B.ById(w, id)
+}
+
+func (w *WebhookSubscription) One(q *bongo.Query) error {
+	return bongo.B.One(w, w, q)
+}
+
+func (w *WebhookSubscription) Some(data interface{}, q *bongo.Query) error {
+	return bongo.B.Some(w, data, q)
+}
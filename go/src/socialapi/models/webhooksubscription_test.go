@@ -0,0 +1,81 @@
+package models
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWebhookSubscriptionValidate(t *testing.T) {
+	Convey("While validating webhook subscription", t, func() {
+		w := &WebhookSubscription{
+			ChannelId: 1,
+			CreatorId: 1,
+			URL:       "https://ci.example.com/hook",
+			Events:    WebhookEventList{Webhook_EVENT_MESSAGE_CREATED},
+		}
+
+		Convey("valid subscription should pass", func() {
+			So(w.validate(), ShouldBeNil)
+		})
+
+		Convey("it should have an http url", func() {
+			w.URL = "ftp://ci.example.com/hook"
+			So(w.validate(), ShouldEqual, ErrWebhookURLIsNotValid)
+
+			w.URL = "/hook"
+			So(w.validate(), ShouldEqual, ErrWebhookURLIsNotValid)
+		})
+
+		Convey("it should have known events", func() {
+			w.Events = nil
+			So(w.validate(), ShouldEqual, ErrWebhookEventsAreNotSet)
+
+			w.Events = WebhookEventList{"message_liked"}
+			So(w.validate(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestWebhookSubscriptionCheckHost(t *testing.T) {
+	Convey("While checking webhook host", t, func() {
+		lookup := lookupWebhookIP
+		defer func() { lookupWebhookIP = lookup }()
+
+		var ips []net.IP
+		lookupWebhookIP = func(host string) ([]net.IP, error) {
+			return ips, nil
+		}
+
+		w := &WebhookSubscription{URL: "https://ci.example.com/hook"}
+
+		Convey("public addresses should pass", func() {
+			ips = []net.IP{net.ParseIP("93.184.216.34"), net.ParseIP("2606:2800:220:1::248")}
+			So(w.checkHost(), ShouldBeNil)
+		})
+
+		Convey("it should not resolve to a private address", func() {
+			for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "::1", "fe80::1", "fd00::1", "0.0.0.0"} {
+				ips = []net.IP{net.ParseIP("93.184.216.34"), net.ParseIP(ip)}
+				So(w.checkHost(), ShouldEqual, ErrWebhookHostIsNotPublic)
+			}
+		})
+	})
+}
+
+func TestWebhookEventList(t *testing.T) {
+	Convey("While storing webhook events", t, func() {
+		events := WebhookEventList{Webhook_EVENT_MESSAGE_CREATED, Webhook_EVENT_CHANNEL_UPDATED}
+
+		value, err := events.Value()
+		So(err, ShouldBeNil)
+		So(value, ShouldEqual, "message_created,channel_updated")
+
+		var scanned WebhookEventList
+		So(scanned.Scan([]byte("message_created,channel_updated")), ShouldBeNil)
+		So(scanned, ShouldResemble, events)
+		So(scanned.Has(Webhook_EVENT_CHANNEL_UPDATED), ShouldBeTrue)
+		So(scanned.Has(Webhook_EVENT_MESSAGE_UPDATED), ShouldBeFalse)
+	})
+}
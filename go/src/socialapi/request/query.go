@@ -41,6 +41,8 @@ type Query struct {
 	ReplySkip       int       `url:"replySkip"`
 	AddIsInteracted bool      `url:"addIsInteracted"`
 	ObjectId        int64     `url:"objectId"`
+	Before          int64     `url:"before,omitempty"`
+	After           int64     `url:"after,omitempty"`
 	Exclude         map[string]interface{}
 	Sort            map[string]string
 }
@@ -140,6 +142,10 @@ func (q *Query) MapURL(u *url.URL) *Query {
 
 	q.ObjectId, _ = GetURIInt64(u, "objectId")
 
+	// message id cursors for pagination, before takes precedence
+	q.Before, _ = GetURIInt64(u, "before")
+	q.After, _ = GetURIInt64(u, "after")
+
 	return q
 }
 
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"socialapi/models"
+)
+
+func ListReactions(messageId int64, token string) ([]*models.MessageReactionSummary, error) {
+	url := fmt.Sprintf("/message/%d/reaction", messageId)
+	res, err := sendRequestWithAuth("GET", url, nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalReactions(res)
+}
+
+func AddReaction(messageId int64, emoji, token string) ([]*models.MessageReactionSummary, error) {
+	url := fmt.Sprintf("/message/%d/reaction", messageId)
+	return reactionOp(url, emoji, token)
+}
+
+func RemoveReaction(messageId int64, emoji, token string) ([]*models.MessageReactionSummary, error) {
+	url := fmt.Sprintf("/message/%d/reaction/remove", messageId)
+	return reactionOp(url, emoji, token)
+}
+
+func MarkChannelAsRead(channelId, messageId int64, token string) (*models.ChannelParticipant, error) {
+	url := fmt.Sprintf("/channel/%d/read", channelId)
+	req := map[string]string{
+		"messageId": fmt.Sprintf("%d", messageId),
+	}
+
+	res, err := marshallAndSendRequestWithAuth("POST", url, req, token)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := models.NewChannelParticipant()
+	if err := json.Unmarshal(res, cp); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+func reactionOp(url, emoji, token string) ([]*models.MessageReactionSummary, error) {
+	req := map[string]string{
+		"emoji": emoji,
+	}
+
+	res, err := marshallAndSendRequestWithAuth("POST", url, req, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalReactions(res)
+}
+
+func unmarshalReactions(res []byte) ([]*models.MessageReactionSummary, error) {
+	var reactions []*models.MessageReactionSummary
+	if err := json.Unmarshal(res, &reactions); err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
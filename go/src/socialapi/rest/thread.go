@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"socialapi/models"
+)
+
+func AddReply(parentId int64, body, token string) (*models.ChannelMessage, error) {
+	cm := models.NewChannelMessage()
+	cm.Body = body
+
+	url := fmt.Sprintf("/message/%d/reply", parentId)
+	res, err := marshallAndSendRequestWithAuth("POST", url, cm, token)
+	if err != nil {
+		return nil, err
+	}
+
+	cmc := models.NewChannelMessageContainer()
+	if err := json.Unmarshal(res, cmc); err != nil {
+		return nil, err
+	}
+
+	return cmc.Message, nil
+}
+
+// ListReplies lists replies of the message, cursor is the query string of
+// before/after parameters, like "before=42"
+func ListReplies(parentId int64, cursor, token string) ([]*models.ChannelMessageContainer, error) {
+	url := fmt.Sprintf("/message/%d/reply?%s", parentId, cursor)
+	res, err := sendRequestWithAuth("GET", url, nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []*models.ChannelMessageContainer
+	if err := json.Unmarshal(res, &containers); err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+func FollowThread(messageId int64, token string) (*models.ThreadFollow, error) {
+	url := fmt.Sprintf("/message/%d/follow", messageId)
+	return threadFollowOp(url, nil, token)
+}
+
+func UnfollowThread(messageId int64, token string) error {
+	url := fmt.Sprintf("/message/%d/unfollow", messageId)
+	_, err := sendRequestWithAuth("POST", url, nil, token)
+	return err
+}
+
+func MarkThreadAsSeen(messageId, replyId int64, token string) (*models.ThreadFollow, error) {
+	url := fmt.Sprintf("/message/%d/reply/seen", messageId)
+	req := map[string]string{
+		"replyId": fmt.Sprintf("%d", replyId),
+	}
+
+	return threadFollowOp(url, req, token)
+}
+
+func ListFollowedThreads(token string) ([]*models.ChannelMessageContainer, error) {
+	res, err := sendRequestWithAuth("GET", "/account/threads", nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []*models.ChannelMessageContainer
+	if err := json.Unmarshal(res, &containers); err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+func threadFollowOp(url string, req interface{}, token string) (*models.ThreadFollow, error) {
+	res, err := marshallAndSendRequestWithAuth("POST", url, req, token)
+	if err != nil {
+		return nil, err
+	}
+
+	tf := models.NewThreadFollow()
+	if err := json.Unmarshal(res, tf); err != nil {
+		return nil, err
+	}
+
+	return tf, nil
+}
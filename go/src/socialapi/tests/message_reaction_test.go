@@ -0,0 +1,86 @@
+package main
+
+import (
+	"koding/db/mongodb/modelhelper"
+	"socialapi/models"
+	"socialapi/rest"
+	"socialapi/workers/common/tests"
+	"testing"
+
+	"github.com/koding/runner"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessageReaction(t *testing.T) {
+	tests.WithRunner(t, func(r *runner.Runner) {
+		Convey("While testing message reactions given a message", t, func() {
+			account, groupChannel, groupName := models.CreateRandomGroupDataWithChecks()
+
+			nonOwnerAccount := models.CreateAccountInBothDbsWithCheck()
+
+			nonOwnerSes, err := modelhelper.FetchOrCreateSession(nonOwnerAccount.Nick, groupName)
+			So(err, ShouldBeNil)
+
+			ses, err := modelhelper.FetchOrCreateSession(account.Nick, groupName)
+			So(err, ShouldBeNil)
+
+			post, err := rest.CreatePost(groupChannel.Id, ses.ClientId)
+			So(err, ShouldBeNil)
+			So(post, ShouldNotBeNil)
+
+			Convey("users should be able to react to the message", func() {
+				_, err := rest.AddReaction(post.Id, ":+1:", ses.ClientId)
+				So(err, ShouldBeNil)
+
+				reactions, err := rest.AddReaction(post.Id, ":+1:", nonOwnerSes.ClientId)
+				So(err, ShouldBeNil)
+				So(len(reactions), ShouldEqual, 1)
+				So(reactions[0].Emoji, ShouldEqual, ":+1:")
+				So(reactions[0].Count, ShouldEqual, 2)
+
+				Convey("reacting twice with same emoji should not be counted", func() {
+					reactions, err := rest.AddReaction(post.Id, ":+1:", ses.ClientId)
+					So(err, ShouldBeNil)
+					So(len(reactions), ShouldEqual, 1)
+					So(reactions[0].Count, ShouldEqual, 2)
+				})
+
+				Convey("users should be able to remove their reaction", func() {
+					reactions, err := rest.RemoveReaction(post.Id, ":+1:", nonOwnerSes.ClientId)
+					So(err, ShouldBeNil)
+					So(len(reactions), ShouldEqual, 1)
+					So(reactions[0].Count, ShouldEqual, 1)
+
+					reactions, err = rest.ListReactions(post.Id, ses.ClientId)
+					So(err, ShouldBeNil)
+					So(len(reactions), ShouldEqual, 1)
+					So(reactions[0].Count, ShouldEqual, 1)
+				})
+			})
+
+			Convey("empty emoji should not be accepted", func() {
+				_, err := rest.AddReaction(post.Id, "", ses.ClientId)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("participants should be able to mark the channel as read", func() {
+				cp, err := rest.MarkChannelAsRead(groupChannel.Id, post.Id, nonOwnerSes.ClientId)
+				So(err, ShouldBeNil)
+				So(cp, ShouldNotBeNil)
+				So(cp.LastReadMessageId, ShouldEqual, post.Id)
+
+				participants, err := rest.ListChannelParticipants(groupChannel.Id, ses.ClientId)
+				So(err, ShouldBeNil)
+
+				found := false
+				for _, participant := range participants {
+					if participant.AccountId == nonOwnerAccount.Id {
+						found = true
+						So(participant.LastReadMessageId, ShouldEqual, post.Id)
+					}
+				}
+				So(found, ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"koding/db/mongodb/modelhelper"
+	"socialapi/models"
+	"socialapi/rest"
+	"socialapi/workers/common/tests"
+	"testing"
+
+	"github.com/koding/runner"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestThreadFollow(t *testing.T) {
+	tests.WithRunner(t, func(r *runner.Runner) {
+		Convey("While testing threads given a message", t, func() {
+			account, groupChannel, groupName := models.CreateRandomGroupDataWithChecks()
+
+			follower := models.CreateAccountInBothDbsWithCheck()
+
+			followerSes, err := modelhelper.FetchOrCreateSession(follower.Nick, groupName)
+			So(err, ShouldBeNil)
+
+			ses, err := modelhelper.FetchOrCreateSession(account.Nick, groupName)
+			So(err, ShouldBeNil)
+
+			post, err := rest.CreatePost(groupChannel.Id, ses.ClientId)
+			So(err, ShouldBeNil)
+			So(post, ShouldNotBeNil)
+
+			Convey("replies should be paginated with message id cursors", func() {
+				replies := make([]*models.ChannelMessage, 5)
+				for i := range replies {
+					replies[i], err = rest.AddReply(post.Id, fmt.Sprintf("reply %d", i), ses.ClientId)
+					So(err, ShouldBeNil)
+				}
+
+				before, err := rest.ListReplies(post.Id, fmt.Sprintf("before=%d&limit=2", replies[3].Id), ses.ClientId)
+				So(err, ShouldBeNil)
+				So(len(before), ShouldEqual, 2)
+				So(before[0].Message.Id, ShouldEqual, replies[2].Id)
+				So(before[1].Message.Id, ShouldEqual, replies[1].Id)
+
+				after, err := rest.ListReplies(post.Id, fmt.Sprintf("after=%d&limit=2", replies[1].Id), ses.ClientId)
+				So(err, ShouldBeNil)
+				So(len(after), ShouldEqual, 2)
+				So(after[0].Message.Id, ShouldEqual, replies[3].Id)
+				So(after[1].Message.Id, ShouldEqual, replies[2].Id)
+			})
+
+			Convey("followers should get unread replies count", func() {
+				_, err := rest.FollowThread(post.Id, followerSes.ClientId)
+				So(err, ShouldBeNil)
+
+				_, err = rest.AddReply(post.Id, "first", ses.ClientId)
+				So(err, ShouldBeNil)
+
+				last, err := rest.AddReply(post.Id, "second", ses.ClientId)
+				So(err, ShouldBeNil)
+
+				threads, err := rest.ListFollowedThreads(followerSes.ClientId)
+				So(err, ShouldBeNil)
+				So(len(threads), ShouldEqual, 1)
+				So(threads[0].Message.Id, ShouldEqual, post.Id)
+				So(threads[0].IsThreadFollowed, ShouldBeTrue)
+				So(threads[0].UnreadRepliesCount, ShouldEqual, 2)
+
+				Convey("seen replies should not be counted", func() {
+					_, err := rest.MarkThreadAsSeen(post.Id, last.Id, followerSes.ClientId)
+					So(err, ShouldBeNil)
+
+					threads, err := rest.ListFollowedThreads(followerSes.ClientId)
+					So(err, ShouldBeNil)
+					So(len(threads), ShouldEqual, 1)
+					So(threads[0].UnreadRepliesCount, ShouldEqual, 0)
+				})
+
+				Convey("unfollowed threads should not be listed", func() {
+					So(rest.UnfollowThread(post.Id, followerSes.ClientId), ShouldBeNil)
+
+					threads, err := rest.ListFollowedThreads(followerSes.ClientId)
+					So(err, ShouldBeNil)
+					So(len(threads), ShouldEqual, 0)
+				})
+			})
+
+			Convey("repliers should follow the thread", func() {
+				_, err := rest.AddReply(post.Id, "hello", followerSes.ClientId)
+				So(err, ShouldBeNil)
+
+				threads, err := rest.ListFollowedThreads(followerSes.ClientId)
+				So(err, ShouldBeNil)
+				So(len(threads), ShouldEqual, 1)
+				So(threads[0].UnreadRepliesCount, ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -25,6 +25,7 @@ import (
 	presenceapi "socialapi/workers/presence/api"
 	realtimeapi "socialapi/workers/realtime/api"
 	slackapi "socialapi/workers/slack/api"
+	webhookapi "socialapi/workers/webhook/api"
 
 	"github.com/koding/cache"
 	"github.com/koding/runner"
@@ -77,6 +78,7 @@ func main() {
 	slackapi.AddHandlers(m, c)
 	credential.AddHandlers(m, r.Log, c)
 	emailapi.AddHandlers(m)
+	webhookapi.AddHandlers(m)
 
 	mmdb, err := helper.ReadGeoIPDB(c)
 	if err != nil {
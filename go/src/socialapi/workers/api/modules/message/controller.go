@@ -341,7 +341,15 @@ func Get(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, ht
 	}
 
 	cmc := models.NewChannelMessageContainer()
-	return response.HandleResultAndError(cmc, cmc.Fetch(cm.Id, request.GetQuery(u)))
+	if err := cmc.Fetch(cm.Id, request.GetQuery(u)); err != nil {
+		return response.HandleResultAndError(cmc, err)
+	}
+
+	// add thread state of the requester
+	query := ctx.OverrideQuery(request.GetQuery(u))
+	cmc.AddIsThreadFollowed(query).AddUnreadRepliesCount(query)
+
+	return response.HandleResultAndError(cmc, cmc.Err)
 }
 
 func getMessageByUrl(u *url.URL) (*models.ChannelMessage, error) {
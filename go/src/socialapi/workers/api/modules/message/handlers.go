@@ -58,6 +58,33 @@ func AddHandlers(m *mux.Mux) {
 		},
 	)
 
+	m.AddHandler(
+		handler.Request{
+			Handler:  ListReactions,
+			Name:     "message-reaction-list",
+			Type:     handler.GetRequest,
+			Endpoint: "/message/{id}/reaction",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  AddReaction,
+			Name:     "message-reaction-add",
+			Type:     handler.PostRequest,
+			Endpoint: "/message/{id}/reaction",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  RemoveReaction,
+			Name:     "message-reaction-remove",
+			Type:     handler.PostRequest,
+			Endpoint: "/message/{id}/reaction/remove",
+		},
+	)
+
 	// exempt contents are filtered
 	// caching enabled
 	m.AddHandler(
@@ -0,0 +1,144 @@
+package message
+
+import (
+	"net/http"
+	"net/url"
+	"socialapi/models"
+	"socialapi/workers/api/realtimehelper"
+	"socialapi/workers/common/response"
+	"strconv"
+
+	"github.com/koding/bongo"
+	"github.com/koding/runner"
+)
+
+// ReactionRequest is the body of reaction add and remove requests
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+// ListReactions returns the reactions of the message grouped by their emoji
+func ListReactions(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	cm, err := fetchReactedMessage(u, ctx)
+	if err != nil {
+		return reactionErrorResponse(err)
+	}
+
+	mr := models.NewMessageReaction()
+	mr.MessageId = cm.Id
+
+	return response.HandleResultAndError(mr.Summarize())
+}
+
+// AddReaction adds the reaction of the requester to the message
+func AddReaction(u *url.URL, h http.Header, req *ReactionRequest, ctx *models.Context) (int, http.Header, interface{}, error) {
+	cm, err := fetchReactedMessage(u, ctx)
+	if err != nil {
+		return reactionErrorResponse(err)
+	}
+
+	mr := models.NewMessageReaction()
+	mr.MessageId = cm.Id
+	mr.AccountId = ctx.Client.Account.Id
+	mr.Emoji = req.Emoji
+
+	if err := mr.Create(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return sendReactionEvent(cm, mr, realtimehelper.ReactionAddedEventName)
+}
+
+// RemoveReaction removes the reaction of the requester from the message
+func RemoveReaction(u *url.URL, h http.Header, req *ReactionRequest, ctx *models.Context) (int, http.Header, interface{}, error) {
+	cm, err := fetchReactedMessage(u, ctx)
+	if err != nil {
+		return reactionErrorResponse(err)
+	}
+
+	mr := models.NewMessageReaction()
+	mr.MessageId = cm.Id
+	mr.AccountId = ctx.Client.Account.Id
+	mr.Emoji = req.Emoji
+
+	if err := mr.Delete(); err != nil {
+		if err == bongo.RecordNotFound {
+			return response.NewNotFound()
+		}
+
+		return response.NewBadRequest(err)
+	}
+
+	return sendReactionEvent(cm, mr, realtimehelper.ReactionRemovedEventName)
+}
+
+// sendReactionEvent notifies the clients following the message about the
+// changed reaction and responds with the current reactions of the message
+func sendReactionEvent(cm *models.ChannelMessage, mr *models.MessageReaction, eventName string) (int, http.Header, interface{}, error) {
+	summary := models.NewMessageReaction()
+	summary.MessageId = cm.Id
+
+	reactions, err := summary.Summarize()
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	event := map[string]interface{}{
+		"messageId": strconv.FormatInt(cm.Id, 10),
+		"accountId": strconv.FormatInt(mr.AccountId, 10),
+		"emoji":     mr.Emoji,
+		"reactions": reactions,
+	}
+
+	go func() {
+		if err := realtimehelper.UpdateInstance(cm, eventName, event); err != nil {
+			runner.MustGetLogger().Error("Could not send %s event: %s", eventName, err)
+		}
+	}()
+
+	return response.NewOK(reactions)
+}
+
+// fetchReactedMessage fetches the message given in the url, and checks that
+// the requester can open its channel
+func fetchReactedMessage(u *url.URL, ctx *models.Context) (*models.ChannelMessage, error) {
+	if !ctx.IsLoggedIn() {
+		return nil, models.ErrNotLoggedIn
+	}
+
+	cm, err := getMessageByUrl(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if cm.Id == 0 {
+		return nil, bongo.RecordNotFound
+	}
+
+	ch, err := models.Cache.Channel.ById(cm.InitialChannelId)
+	if err != nil {
+		return nil, err
+	}
+
+	canOpen, err := ch.CanOpen(ctx.Client.Account.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canOpen {
+		return nil, models.ErrCannotOpenChannel
+	}
+
+	return cm, nil
+}
+
+func reactionErrorResponse(err error) (int, http.Header, interface{}, error) {
+	switch err {
+	case bongo.RecordNotFound:
+		return response.NewNotFound()
+	case models.ErrNotLoggedIn, models.ErrCannotOpenChannel:
+		return response.NewAccessDenied(err)
+	default:
+		return response.NewBadRequest(err)
+	}
+}
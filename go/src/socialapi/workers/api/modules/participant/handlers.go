@@ -62,6 +62,15 @@ func AddHandlers(m *mux.Mux) {
 		},
 	)
 
+	m.AddHandler(
+		handler.Request{
+			Handler:  MarkAsRead,
+			Name:     "participant-read",
+			Type:     handler.PostRequest,
+			Endpoint: "/channel/{id}/read",
+		},
+	)
+
 	m.AddHandler(
 		handler.Request{
 			Handler:  AcceptInvite,
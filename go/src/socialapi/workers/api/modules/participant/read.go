@@ -0,0 +1,88 @@
+package participant
+
+import (
+	"net/http"
+	"net/url"
+	"socialapi/models"
+	"socialapi/request"
+	"socialapi/workers/api/realtimehelper"
+	"socialapi/workers/common/response"
+	"strconv"
+
+	"github.com/koding/bongo"
+	"github.com/koding/runner"
+)
+
+// ReadRequest is the body of channel read requests
+type ReadRequest struct {
+	// Id of the last message the requester has read
+	MessageId int64 `json:"messageId,string"`
+}
+
+// MarkAsRead moves the read cursor of the requester in the channel to the
+// given message and notifies the channel about it
+func MarkAsRead(u *url.URL, h http.Header, req *ReadRequest, ctx *models.Context) (int, http.Header, interface{}, error) {
+	if !ctx.IsLoggedIn() {
+		return response.NewAccessDenied(models.ErrNotLoggedIn)
+	}
+
+	query := ctx.OverrideQuery(request.GetQuery(u))
+
+	if query.Id == 0 {
+		return response.NewBadRequest(models.ErrChannelIdIsNotSet)
+	}
+
+	if req.MessageId == 0 {
+		return response.NewBadRequest(models.ErrMessageIdIsNotSet)
+	}
+
+	ch, err := models.Cache.Channel.ById(query.Id)
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	// only active participants of the channel have a read cursor
+	cp := models.NewChannelParticipant()
+	cp.ChannelId = ch.Id
+	cp.AccountId = query.AccountId
+	if err := cp.FetchActiveParticipant(); err != nil {
+		if err == bongo.RecordNotFound {
+			return response.NewAccessDenied(models.ErrAccountIsNotParticipant)
+		}
+
+		return response.NewBadRequest(err)
+	}
+
+	isInChannel, err := models.NewChannelMessageList().IsInChannel(req.MessageId, ch.Id)
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if !isInChannel {
+		return response.NewNotFound()
+	}
+
+	moved, err := cp.MarkAsRead(req.MessageId)
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if moved {
+		go notifyRead(ch, cp)
+	}
+
+	return response.NewOK(cp)
+}
+
+func notifyRead(ch *models.Channel, cp *models.ChannelParticipant) {
+	event := map[string]interface{}{
+		"channelId":         strconv.FormatInt(cp.ChannelId, 10),
+		"accountId":         strconv.FormatInt(cp.AccountId, 10),
+		"lastReadMessageId": strconv.FormatInt(cp.LastReadMessageId, 10),
+		"lastReadAt":        cp.LastReadAt,
+	}
+
+	if err := realtimehelper.PushMessage(ch, realtimehelper.MessageReadEventName, event); err != nil {
+		runner.MustGetLogger().Error("Could not notify channel about read receipt: %s", err)
+	}
+}
@@ -24,4 +24,40 @@ func AddHandlers(m *mux.Mux) {
 			Endpoint: "/message/{id}/reply",
 		},
 	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  MarkAsSeen,
+			Name:     "reply-seen",
+			Type:     handler.PostRequest,
+			Endpoint: "/message/{id}/reply/seen",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  Follow,
+			Name:     "thread-follow",
+			Type:     handler.PostRequest,
+			Endpoint: "/message/{id}/follow",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  Unfollow,
+			Name:     "thread-unfollow",
+			Type:     handler.PostRequest,
+			Endpoint: "/message/{id}/unfollow",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  ListFollowed,
+			Name:     "thread-list-followed",
+			Type:     handler.GetRequest,
+			Endpoint: "/account/threads",
+		},
+	)
 }
@@ -7,6 +7,8 @@ import (
 	"socialapi/request"
 	"socialapi/workers/api/modules/helpers"
 	"socialapi/workers/common/response"
+
+	"github.com/koding/runner"
 )
 
 func Create(u *url.URL, h http.Header, reply *models.ChannelMessage, c *models.Context) (int, http.Header, interface{}, error) {
@@ -59,6 +61,18 @@ func Create(u *url.URL, h http.Header, reply *models.ChannelMessage, c *models.C
 		return response.NewBadRequest(err)
 	}
 
+	// repliers follow the thread, their own reply is already seen. the reply
+	// is created at this point, so failing to follow must not fail the request
+	tf := models.NewThreadFollow()
+	tf.MessageId = parentId
+	tf.AccountId = reply.AccountId
+	tf.LastSeenAt = reply.CreatedAt
+	if err := tf.Create(); err != nil {
+		runner.MustGetLogger().Error("Could not follow thread %d: %s", parentId, err)
+	} else if err := tf.MarkAsSeen(reply.CreatedAt); err != nil {
+		runner.MustGetLogger().Error("Could not mark thread %d as seen: %s", parentId, err)
+	}
+
 	return response.HandleResultAndError(
 		reply.BuildEmptyMessageContainer(),
 	)
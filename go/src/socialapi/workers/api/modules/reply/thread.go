@@ -0,0 +1,173 @@
+package reply
+
+import (
+	"net/http"
+	"net/url"
+	"socialapi/models"
+	"socialapi/request"
+	"socialapi/workers/api/modules/helpers"
+	"socialapi/workers/common/response"
+	"time"
+
+	"github.com/koding/bongo"
+)
+
+// SeenRequest is the body of thread seen requests
+type SeenRequest struct {
+	// Id of the last reply the requester has seen, all replies are marked as
+	// seen when it is not set
+	ReplyId int64 `json:"replyId,string"`
+}
+
+// Follow makes the requester follow the replies of the message
+func Follow(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	tf, err := buildThreadFollow(u, ctx)
+	if err != nil {
+		return threadErrorResponse(err)
+	}
+
+	if err := tf.Create(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewOK(tf)
+}
+
+// Unfollow makes the requester stop following the replies of the message
+func Unfollow(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	tf, err := buildThreadFollow(u, ctx)
+	if err != nil {
+		return threadErrorResponse(err)
+	}
+
+	if err := tf.Delete(); err != nil {
+		return threadErrorResponse(err)
+	}
+
+	return response.NewDeleted()
+}
+
+// MarkAsSeen marks the replies of the followed message as seen by the
+// requester, up to the given reply
+func MarkAsSeen(u *url.URL, h http.Header, req *SeenRequest, ctx *models.Context) (int, http.Header, interface{}, error) {
+	tf, err := buildThreadFollow(u, ctx)
+	if err != nil {
+		return threadErrorResponse(err)
+	}
+
+	if req.ReplyId == 0 {
+		err = tf.MarkAsSeen(time.Now().UTC())
+	} else {
+		err = tf.MarkAsSeenUntil(req.ReplyId)
+	}
+
+	if err != nil {
+		return threadErrorResponse(err)
+	}
+
+	return response.NewOK(tf)
+}
+
+// ListFollowed lists the messages followed by the requester with their unread
+// replies count, recently followed first
+func ListFollowed(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	if !ctx.IsLoggedIn() {
+		return response.NewAccessDenied(models.ErrNotLoggedIn)
+	}
+
+	query := ctx.OverrideQuery(request.GetQuery(u))
+
+	tf := models.NewThreadFollow()
+	tf.AccountId = query.AccountId
+
+	messages, err := helpers.FetchMessagesByIds(tf.ListMessageIds(query))
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	// follows are kept when the requester leaves a channel, so the messages
+	// of the channels that can not be opened anymore are skipped here
+	canOpen := make(map[int64]bool)
+	containers := models.NewChannelMessageContainers()
+	for i := range messages {
+		channelId := messages[i].InitialChannelId
+		if _, ok := canOpen[channelId]; !ok {
+			ch, err := models.Cache.Channel.ById(channelId)
+			if err != nil {
+				return response.NewBadRequest(err)
+			}
+
+			if canOpen[channelId], err = ch.CanOpen(query.AccountId); err != nil {
+				return response.NewBadRequest(err)
+			}
+		}
+
+		if !canOpen[channelId] {
+			continue
+		}
+
+		cmc := models.NewChannelMessageContainer()
+		cmc.PopulateWith(&messages[i])
+		cmc.AddRepliesCount(query)
+		cmc.AddUnreadRepliesCount(query)
+		cmc.IsThreadFollowed = true
+		containers.Add(cmc)
+	}
+
+	return response.HandleResultAndError(*containers, containers.Err())
+}
+
+// buildThreadFollow prepares the thread follow of the requester for the
+// message given in the url, the requester must be able to open the channel
+// of the message
+func buildThreadFollow(u *url.URL, ctx *models.Context) (*models.ThreadFollow, error) {
+	if !ctx.IsLoggedIn() {
+		return nil, models.ErrNotLoggedIn
+	}
+
+	messageId, err := request.GetURIInt64(u, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := models.Cache.Message.ById(messageId)
+	if err != nil {
+		return nil, err
+	}
+
+	// replies can not be followed, only their parents
+	if cm.TypeConstant == models.ChannelMessage_TYPE_REPLY {
+		return nil, models.ErrReplyCanNotBeFollowed
+	}
+
+	ch, err := models.Cache.Channel.ById(cm.InitialChannelId)
+	if err != nil {
+		return nil, err
+	}
+
+	canOpen, err := ch.CanOpen(ctx.Client.Account.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canOpen {
+		return nil, models.ErrCannotOpenChannel
+	}
+
+	tf := models.NewThreadFollow()
+	tf.MessageId = cm.Id
+	tf.AccountId = ctx.Client.Account.Id
+
+	return tf, nil
+}
+
+func threadErrorResponse(err error) (int, http.Header, interface{}, error) {
+	switch err {
+	case bongo.RecordNotFound:
+		return response.NewNotFound()
+	case models.ErrNotLoggedIn, models.ErrCannotOpenChannel:
+		return response.NewAccessDenied(err)
+	default:
+		return response.NewBadRequest(err)
+	}
+}
@@ -9,6 +9,17 @@ import (
 
 const NotificationTypeMessage = "message"
 
+const (
+	// ReactionAddedEventName and ReactionRemovedEventName are sent as message
+	// instance events when a reaction of the message changes
+	ReactionAddedEventName   = "ReactionAdded"
+	ReactionRemovedEventName = "ReactionRemoved"
+
+	// MessageReadEventName is sent to the channel when a participant moves
+	// their read cursor
+	MessageReadEventName = "MessageRead"
+)
+
 func PushMessage(c *models.Channel, eventName string, body interface{}) error {
 
 	request := map[string]interface{}{
@@ -21,8 +21,8 @@ func main() {
 	appConfig := config.MustRead(r.Conf.Path)
 
 	// create a realtime service provider instance.
-	pubnub := models.NewPubNub(appConfig.GateKeeper.Pubnub, r.Log)
-	defer pubnub.Close()
+	provider := models.NewProvider(appConfig, r.Log)
+	defer provider.Close()
 
 	// When we use the same RMQ connection for both, we received
 	// 'Exception (504) Reason: "CHANNEL_ERROR - unexpected method in connection state running"'
@@ -36,7 +36,7 @@ func main() {
 
 	broker := models.NewBroker(rmqBroker, r.Log)
 
-	r.SetContext(dispatcher.NewController(r.Bongo.Broker.MQ, pubnub, broker))
+	r.SetContext(dispatcher.NewController(r.Bongo.Broker.MQ, provider, broker))
 	r.ListenFor("dispatcher_channel_updated", (*dispatcher.Controller).UpdateChannel)
 	r.ListenFor("dispatcher_message_updated", (*dispatcher.Controller).UpdateMessage)
 	r.ListenFor("dispatcher_notify_user", (*dispatcher.Controller).NotifyUser)
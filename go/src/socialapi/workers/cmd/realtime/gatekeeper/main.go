@@ -25,13 +25,13 @@ func main() {
 	defer modelhelper.Close()
 
 	// create a realtime service provider instance.
-	pubnub := models.NewPubNub(appConfig.GateKeeper.Pubnub, r.Log)
-	defer pubnub.Close()
+	provider := models.NewProvider(appConfig, r.Log)
+	defer provider.Close()
 
 	mc := mux.NewConfig(Name, appConfig.GateKeeper.Host, appConfig.GateKeeper.Port)
 	m := mux.New(mc, r.Log, r.Metrics)
 
-	h := api.NewHandler(pubnub, appConfig, r.Log)
+	h := api.NewHandler(provider, appConfig, r.Log)
 
 	h.AddHandlers(m)
 
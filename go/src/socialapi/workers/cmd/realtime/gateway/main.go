@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"socialapi/config"
+	"socialapi/workers/common/mux"
+	"socialapi/workers/realtime/gateway"
+
+	"github.com/koding/runner"
+)
+
+const Name = "RealtimeGateway"
+
+func main() {
+	r := runner.New(Name)
+	if err := r.Init(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	appConfig := config.MustRead(r.Conf.Path)
+
+	gc := appConfig.GateKeeper.Gateway
+	if gc.Secret == "" {
+		fmt.Println("realtime gateway secret is not set")
+		return
+	}
+
+	mc := mux.NewConfig(Name, gc.Host, gc.Port)
+	m := mux.New(mc, r.Log, r.Metrics)
+
+	hub := gateway.NewHub(r.Log)
+	hub.Start()
+	defer hub.Close()
+
+	h := gateway.NewHandler(hub, gc.Secret, r.Log)
+
+	h.AddHandlers(m)
+
+	// the gateway gets all of its input over http and keeps its state in
+	// memory, it has no event handlers. runner still declares a queue for
+	// every worker, the queue is drained here, so the events routed to it
+	// don't pile up
+	go r.Listen()
+
+	m.Listen()
+	defer m.Close()
+
+	r.Wait()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"koding/db/mongodb/modelhelper"
+	"log"
+	"socialapi/config"
+	"socialapi/models"
+	"socialapi/workers/webhook"
+
+	"github.com/koding/runner"
+)
+
+var (
+	Name = "Webhook"
+)
+
+func main() {
+	r := runner.New(Name)
+	if err := r.Init(); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// init mongo connection
+	appConfig := config.MustRead(r.Conf.Path)
+	modelhelper.Initialize(appConfig.Mongo)
+	defer modelhelper.Close()
+
+	handler := webhook.New(r.Log)
+	handler.Start()
+	defer handler.Close()
+
+	r.SetContext(handler)
+	r.Register(models.ChannelMessageList{}).OnCreate().Handle((*webhook.Controller).MessageListSaved)
+	r.Register(models.ChannelMessage{}).OnUpdate().Handle((*webhook.Controller).MessageUpdated)
+	r.Register(models.ParticipantEvent{}).On(models.ChannelParticipant_Added_To_Channel_Event).Handle((*webhook.Controller).ChannelParticipantsAdded)
+	r.Register(models.ParticipantEvent{}).On(models.ChannelParticipant_Removed_From_Channel_Event).Handle((*webhook.Controller).ChannelParticipantsRemoved)
+	r.Register(models.Channel{}).OnUpdate().Handle((*webhook.Controller).ChannelUpdated)
+	r.Listen()
+	r.Wait()
+}
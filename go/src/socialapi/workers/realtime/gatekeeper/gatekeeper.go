@@ -21,18 +21,18 @@ const (
 )
 
 type Handler struct {
-	pubnub *models.PubNub
-	logger logging.Logger
+	provider models.Provider
+	logger   logging.Logger
 
 	checkParticipationEndpoint string
 	accountEndpoint            string
 }
 
-func NewHandler(p *models.PubNub, conf *config.Config, l logging.Logger) *Handler {
+func NewHandler(p models.Provider, conf *config.Config, l logging.Logger) *Handler {
 	rootPath := conf.CustomDomain.Local
 	return &Handler{
-		pubnub: p,
-		logger: l,
+		provider: p,
+		logger:   l,
 		checkParticipationEndpoint: fmt.Sprintf("%s%s", rootPath, CheckParticipationPath),
 		accountEndpoint:            fmt.Sprintf("%s%s", rootPath, AccountPath),
 	}
@@ -51,13 +51,14 @@ func (h *Handler) SubscribeChannel(u *url.URL, header http.Header, req *models.C
 		return response.NewAccessDenied(err)
 	}
 
-	// user has access permission, now authenticate user to channel via pubnub
+	// user has access permission, now authenticate user to channel via the
+	// realtime provider
 	a := new(models.Authenticate)
 	a.Channel = models.NewPrivateMessageChannel(*res.Channel)
 	a.Account = res.Account
 	a.Account.Token = res.AccountToken
 
-	err = h.pubnub.Authenticate(a)
+	err = h.provider.Authenticate(a)
 	if err != nil {
 		return response.NewBadRequest(err)
 	}
@@ -81,7 +82,7 @@ func (h *Handler) SubscribeNotification(u *url.URL, header http.Header, temp *so
 	a.Account = account
 
 	// TODO need async requests. Re-try in case of an error
-	err := h.pubnub.Authenticate(a)
+	err := h.provider.Authenticate(a)
 	if err != nil {
 		return response.NewBadRequest(err)
 	}
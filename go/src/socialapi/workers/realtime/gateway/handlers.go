@@ -0,0 +1,299 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"socialapi/workers/common/handler"
+	"socialapi/workers/common/mux"
+	"socialapi/workers/common/response"
+	"socialapi/workers/realtime/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/koding/logging"
+)
+
+const (
+	// TokenCookieName is the cookie which holds the realtime token of the
+	// client, it is set by gatekeeper
+	TokenCookieName = "realtimeToken"
+
+	// PingInterval is the interval of keep-alive messages sent to the
+	// subscribers
+	PingInterval = 30 * time.Second
+
+	// EventStreamDuration is the lifetime of an event stream. Server has an
+	// absolute write timeout of 60 seconds, so streams are ended before it and
+	// clients reconnect, EventSource does it automatically.
+	EventStreamDuration = 50 * time.Second
+
+	// LastEventIdParam is the query parameter which holds the id of the last
+	// message received by the client. It is used by WebSocket clients, they
+	// can't send the Last-Event-ID header of event streams.
+	LastEventIdParam = "lastEventId"
+
+	// writeTimeout is the time limit for writing a message to a subscriber
+	writeTimeout = 10 * time.Second
+)
+
+type Handler struct {
+	hub      *Hub
+	secret   string
+	log      logging.Logger
+	upgrader websocket.Upgrader
+}
+
+// NewHandler creates the gateway handler, secret is the shared secret which
+// the internal services send to the private endpoints.
+func NewHandler(hub *Hub, secret string, log logging.Logger) *Handler {
+	return &Handler{
+		hub:    hub,
+		secret: secret,
+		log:    log,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+	}
+}
+
+func (h *Handler) AddHandlers(m *mux.Mux) {
+	// subscription is a long living request, it is registered without
+	// the default timeout of the handlers
+	m.AddUnscopedHandler(
+		handler.Request{
+			Handler:  h.Subscribe,
+			Type:     handler.GetRequest,
+			Endpoint: models.GatewaySubscribePath,
+		},
+	)
+
+	m.AddSessionlessHandler(
+		handler.Request{
+			Handler:  h.Publish,
+			Name:     "gateway-publish",
+			Type:     handler.PostRequest,
+			Endpoint: models.GatewayPublishPath,
+		},
+	)
+
+	m.AddSessionlessHandler(
+		handler.Request{
+			Handler:  h.Grant,
+			Name:     "gateway-grant",
+			Type:     handler.PostRequest,
+			Endpoint: models.GatewayGrantPath,
+		},
+	)
+
+	m.AddSessionlessHandler(
+		handler.Request{
+			Handler:  h.Revoke,
+			Name:     "gateway-revoke",
+			Type:     handler.PostRequest,
+			Endpoint: models.GatewayRevokePath,
+		},
+	)
+}
+
+// Subscribe streams messages of the channels given with channel query
+// parameters. WebSocket is used when the client asks for an upgrade,
+// otherwise messages are sent as Server-Sent Events.
+func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	lastId, err := getLastEventId(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s, err := h.hub.Subscribe(getToken(r), r.URL.Query()["channel"], lastId)
+	if err != nil {
+		code := http.StatusBadRequest
+		if err == ErrAccessDenied {
+			code = http.StatusForbidden
+		}
+
+		http.Error(w, err.Error(), code)
+		return
+	}
+	defer h.hub.Unsubscribe(s)
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		h.serveWebSocket(w, r, s)
+		return
+	}
+
+	h.serveEvents(w, r, s)
+}
+
+// Publish sends the message to the subscribers of its channel
+func (h *Handler) Publish(u *url.URL, header http.Header, req *models.GatewayEvent) (int, http.Header, interface{}, error) {
+	if !h.isInternal(header) {
+		return response.NewAccessDenied(ErrAccessDenied)
+	}
+
+	if err := h.hub.Publish(req); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewDefaultOK()
+}
+
+// Grant gives access to a channel for a token
+func (h *Handler) Grant(u *url.URL, header http.Header, req *models.GatewayGrant) (int, http.Header, interface{}, error) {
+	if !h.isInternal(header) {
+		return response.NewAccessDenied(ErrAccessDenied)
+	}
+
+	if err := h.hub.Grant(req); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewDefaultOK()
+}
+
+// Revoke takes the access to a channel from a token
+func (h *Handler) Revoke(u *url.URL, header http.Header, req *models.GatewayGrant) (int, http.Header, interface{}, error) {
+	if !h.isInternal(header) {
+		return response.NewAccessDenied(ErrAccessDenied)
+	}
+
+	if err := h.hub.Revoke(req); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewDefaultOK()
+}
+
+// isInternal checks that the request carries the shared secret of the
+// internal services, requests are refused when no secret is configured
+func (h *Handler) isInternal(header http.Header) bool {
+	secret := header.Get(models.GatewaySecretHeader)
+	if h.secret == "" || secret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(h.secret)) == 1
+}
+
+func (h *Handler) serveWebSocket(w http.ResponseWriter, r *http.Request, s *Subscriber) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.log.Debug("Could not upgrade connection: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	// clients don't send messages, reading is only needed for noticing
+	// closed connections and handling control messages
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case m, ok := <-s.Messages:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage, nil, time.Now().Add(writeTimeout))
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, m.Data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func (h *Handler) serveEvents(w http.ResponseWriter, r *http.Request, s *Subscriber) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// ask clients to reconnect right away when the stream ends
+	fmt.Fprint(w, "retry: 1000\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+
+	timeout := time.NewTimer(EventStreamDuration)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case m, ok := <-s.Messages:
+			if !ok {
+				return
+			}
+
+			// EventSource sends the last id back in Last-Event-ID header
+			// when it reconnects
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", m.Id, m.Data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-timeout.C:
+			return
+		case <-r.Context().Done():
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// getToken returns the realtime token of the client. The token is only read
+// from the cookie, so it does not end up in the logs of the requested urls.
+func getToken(r *http.Request) string {
+	if cookie, err := r.Cookie(TokenCookieName); err == nil {
+		return cookie.Value
+	}
+
+	return ""
+}
+
+// getLastEventId returns the id of the last message received by the client,
+// zero is returned for new clients
+func getLastEventId(r *http.Request) (uint64, error) {
+	id := r.Header.Get("Last-Event-ID")
+	if id == "" {
+		id = r.URL.Query().Get(LastEventIdParam)
+	}
+
+	if id == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(id, 10, 64)
+}
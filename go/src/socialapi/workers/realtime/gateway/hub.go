@@ -0,0 +1,344 @@
+// Package gateway provides a self-hosted alternative to PubNub. Clients
+// subscribe to channels over WebSocket or Server-Sent Events, the dispatcher
+// publishes messages and the gatekeeper grants channel access via the
+// private endpoints. Recent messages of each channel are kept for a while, so
+// the clients which reconnect don't miss the messages published meanwhile.
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"socialapi/workers/realtime/models"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/koding/logging"
+)
+
+const (
+	// SubscriberBufferSize is the number of messages which are kept for a
+	// slow subscriber, it is disconnected when the buffer is full
+	SubscriberBufferSize = 64
+
+	// BacklogSize is the number of recent messages which are kept for each
+	// channel, they are replayed to the clients which reconnect
+	BacklogSize = 100
+
+	// BacklogDuration is the time recent messages are kept for
+	BacklogDuration = 2 * time.Minute
+)
+
+var (
+	ErrChannelIsNotSet = errors.New("channel is not set")
+	ErrAccessDenied    = errors.New("access denied")
+)
+
+// Hub keeps the channel grants and the subscribers of the gateway. Grants
+// are kept in memory, clients subscribe via gatekeeper again when the
+// gateway is restarted.
+type Hub struct {
+	log logging.Logger
+
+	mu      sync.Mutex
+	lastId  uint64
+	grants  map[string]map[string]struct{}      // channel -> tokens
+	subs    map[string]map[*Subscriber]struct{} // channel -> subscribers
+	backlog map[string][]*Message               // channel -> recent messages
+
+	closeChan chan struct{}
+	closeOnce sync.Once
+}
+
+// Message is a message published to a channel, messages are identified by
+// increasing ids.
+type Message struct {
+	Id   uint64
+	Data []byte
+
+	publishedAt time.Time
+}
+
+// Subscriber receives messages of the channels it has subscribed to.
+type Subscriber struct {
+	// Messages receives the published messages, it is closed when the
+	// subscriber is removed from the hub
+	Messages chan *Message
+
+	token    string
+	channels map[string]struct{}
+	closed   bool
+}
+
+func NewHub(log logging.Logger) *Hub {
+	return &Hub{
+		log: log,
+		// ids start from the current time, so the ids of a restarted hub
+		// are greater than the ones its clients have seen before
+		lastId:    uint64(time.Now().UnixNano()),
+		grants:    make(map[string]map[string]struct{}),
+		subs:      make(map[string]map[*Subscriber]struct{}),
+		backlog:   make(map[string][]*Message),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// Start starts expiring the recent messages of the channels.
+func (h *Hub) Start() {
+	go func() {
+		ticker := time.NewTicker(BacklogDuration / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				h.Expire(now)
+			case <-h.closeChan:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the hub started with Start.
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() {
+		close(h.closeChan)
+	})
+}
+
+// Expire removes the recent messages which are published BacklogDuration
+// before the given time.
+func (h *Hub) Expire(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for channel := range h.backlog {
+		h.expire(channel, now)
+	}
+}
+
+// Grant gives access to the channel for the token.
+func (h *Hub) Grant(g *models.GatewayGrant) error {
+	if g.Channel == "" {
+		return ErrChannelIsNotSet
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tokens, ok := h.grants[g.Channel]
+	if !ok {
+		tokens = make(map[string]struct{})
+		h.grants[g.Channel] = tokens
+	}
+
+	tokens[g.Token] = struct{}{}
+
+	return nil
+}
+
+// Revoke takes the access to the channel from the token, subscribers with the
+// token stop receiving messages of the channel.
+func (h *Hub) Revoke(g *models.GatewayGrant) error {
+	if g.Channel == "" {
+		return ErrChannelIsNotSet
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if tokens, ok := h.grants[g.Channel]; ok {
+		delete(tokens, g.Token)
+
+		if len(tokens) == 0 {
+			delete(h.grants, g.Channel)
+		}
+	}
+
+	for s := range h.subs[g.Channel] {
+		if s.token == g.Token {
+			h.leave(s, g.Channel)
+		}
+	}
+
+	return nil
+}
+
+// Publish sends the message to the subscribers of the channel and keeps it in
+// the backlog of the channel.
+func (h *Hub) Publish(e *models.GatewayEvent) error {
+	if e.Channel == "" {
+		return ErrChannelIsNotSet
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	event := *e
+	event.Id = strconv.FormatUint(h.lastId+1, 10)
+
+	data, err := json.Marshal(&event)
+	if err != nil {
+		return err
+	}
+
+	h.lastId++
+	m := &Message{
+		Id:          h.lastId,
+		Data:        data,
+		publishedAt: time.Now(),
+	}
+
+	backlog := append(h.backlog[e.Channel], m)
+	if len(backlog) > BacklogSize {
+		backlog = append([]*Message(nil), backlog[len(backlog)-BacklogSize:]...)
+	}
+	h.backlog[e.Channel] = backlog
+
+	for s := range h.subs[e.Channel] {
+		select {
+		case s.Messages <- m:
+		default:
+			h.log.Warning("Dropping slow subscriber of %q", e.Channel)
+			h.remove(s)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe subscribes the token to the given channels, the token must have
+// access to all of them. Recent messages of the channels which are published
+// after the message with lastId are sent to the subscriber first, zero
+// lastId skips them.
+func (h *Hub) Subscribe(token string, channels []string, lastId uint64) (*Subscriber, error) {
+	if len(channels) == 0 {
+		return nil, ErrChannelIsNotSet
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, channel := range channels {
+		if !h.canSubscribe(channel, token) {
+			return nil, ErrAccessDenied
+		}
+	}
+
+	var missed []*Message
+	if lastId != 0 {
+		now := time.Now()
+		for _, channel := range channels {
+			h.expire(channel, now)
+
+			for _, m := range h.backlog[channel] {
+				if m.Id > lastId {
+					missed = append(missed, m)
+				}
+			}
+		}
+
+		sort.Sort(byId(missed))
+	}
+
+	s := &Subscriber{
+		Messages: make(chan *Message, SubscriberBufferSize+len(missed)),
+		token:    token,
+		channels: make(map[string]struct{}),
+	}
+
+	for _, m := range missed {
+		s.Messages <- m
+	}
+
+	for _, channel := range channels {
+		subs, ok := h.subs[channel]
+		if !ok {
+			subs = make(map[*Subscriber]struct{})
+			h.subs[channel] = subs
+		}
+
+		subs[s] = struct{}{}
+		s.channels[channel] = struct{}{}
+	}
+
+	return s, nil
+}
+
+// Unsubscribe removes the subscriber from all of its channels.
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(s)
+}
+
+func (h *Hub) canSubscribe(channel, token string) bool {
+	tokens, ok := h.grants[channel]
+	if !ok {
+		return false
+	}
+
+	if _, ok := tokens[models.PublicToken]; ok {
+		return true
+	}
+
+	_, ok = tokens[token]
+	return token != "" && ok
+}
+
+// expire removes the messages of the channel backlog which are published
+// BacklogDuration before now.
+func (h *Hub) expire(channel string, now time.Time) {
+	backlog := h.backlog[channel]
+
+	i := 0
+	for i < len(backlog) && now.Sub(backlog[i].publishedAt) > BacklogDuration {
+		i++
+	}
+
+	switch {
+	case i == len(backlog):
+		delete(h.backlog, channel)
+	case i != 0:
+		h.backlog[channel] = append([]*Message(nil), backlog[i:]...)
+	}
+}
+
+// leave removes the subscriber from the channel, the subscriber is closed
+// when it has no channels left.
+func (h *Hub) leave(s *Subscriber, channel string) {
+	delete(s.channels, channel)
+
+	if subs, ok := h.subs[channel]; ok {
+		delete(subs, s)
+
+		if len(subs) == 0 {
+			delete(h.subs, channel)
+		}
+	}
+
+	if len(s.channels) == 0 && !s.closed {
+		s.closed = true
+		close(s.Messages)
+	}
+}
+
+func (h *Hub) remove(s *Subscriber) {
+	for channel := range s.channels {
+		h.leave(s, channel)
+	}
+
+	if !s.closed {
+		s.closed = true
+		close(s.Messages)
+	}
+}
+
+type byId []*Message
+
+func (b byId) Len() int           { return len(b) }
+func (b byId) Less(i, j int) bool { return b[i].Id < b[j].Id }
+func (b byId) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -0,0 +1,334 @@
+package gateway
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	socialapimodels "socialapi/models"
+	"socialapi/workers/realtime/models"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koding/logging"
+	"github.com/koding/runner"
+)
+
+func newTestHub() *Hub {
+	return NewHub(logging.NewLogger("gateway-test"))
+}
+
+func receive(t *testing.T, s *Subscriber) *models.GatewayEvent {
+	select {
+	case m, ok := <-s.Messages:
+		if !ok {
+			t.Fatal("subscriber is closed")
+		}
+
+		var e models.GatewayEvent
+		if err := json.Unmarshal(m.Data, &e); err != nil {
+			t.Fatal(err)
+		}
+
+		return &e
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a message")
+	}
+
+	return nil
+}
+
+func TestHubSubscribe(t *testing.T) {
+	h := newTestHub()
+
+	if _, err := h.Subscribe("token", []string{"channel-1"}, 0); err != ErrAccessDenied {
+		t.Fatalf("got %v, want %v", err, ErrAccessDenied)
+	}
+
+	if err := h.Grant(&models.GatewayGrant{Channel: "channel-1", Token: "token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Subscribe("other", []string{"channel-1"}, 0); err != ErrAccessDenied {
+		t.Fatalf("got %v, want %v", err, ErrAccessDenied)
+	}
+
+	s, err := h.Subscribe("token", []string{"channel-1"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Publish(&models.GatewayEvent{Channel: "channel-1", Message: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if e := receive(t, s); e.Channel != "channel-1" || e.Message != "hello" {
+		t.Fatalf("got %+v", e)
+	}
+
+	// messages of other channels should not be received
+	h.Publish(&models.GatewayEvent{Channel: "channel-2", Message: "hello"})
+
+	select {
+	case m := <-s.Messages:
+		t.Fatalf("got unexpected message %s", m.Data)
+	default:
+	}
+}
+
+func TestHubPublicAccess(t *testing.T) {
+	h := newTestHub()
+
+	h.Grant(&models.GatewayGrant{Channel: "channel-1", Token: models.PublicToken})
+
+	for _, token := range []string{"", "token"} {
+		if _, err := h.Subscribe(token, []string{"channel-1"}, 0); err != nil {
+			t.Fatalf("%q: %s", token, err)
+		}
+	}
+}
+
+func TestHubRevoke(t *testing.T) {
+	h := newTestHub()
+
+	h.Grant(&models.GatewayGrant{Channel: "channel-1", Token: "token"})
+	h.Grant(&models.GatewayGrant{Channel: "channel-2", Token: "token"})
+
+	s, err := h.Subscribe("token", []string{"channel-1", "channel-2"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Revoke(&models.GatewayGrant{Channel: "channel-1", Token: "token"})
+
+	if _, err := h.Subscribe("token", []string{"channel-1"}, 0); err != ErrAccessDenied {
+		t.Fatalf("got %v, want %v", err, ErrAccessDenied)
+	}
+
+	h.Publish(&models.GatewayEvent{Channel: "channel-1", Message: "revoked"})
+	h.Publish(&models.GatewayEvent{Channel: "channel-2", Message: "granted"})
+
+	if e := receive(t, s); e.Message != "granted" {
+		t.Fatalf("got %+v", e)
+	}
+
+	// subscriber is closed when it has no channels left
+	h.Revoke(&models.GatewayGrant{Channel: "channel-2", Token: "token"})
+
+	if _, ok := <-s.Messages; ok {
+		t.Fatal("subscriber is not closed")
+	}
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	h := newTestHub()
+
+	h.Grant(&models.GatewayGrant{Channel: "channel-1", Token: "token"})
+
+	s, err := h.Subscribe("token", []string{"channel-1"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i <= SubscriberBufferSize; i++ {
+		h.Publish(&models.GatewayEvent{Channel: "channel-1", Message: i})
+	}
+
+	n := 0
+	for range s.Messages {
+		n++
+	}
+
+	if n != SubscriberBufferSize {
+		t.Fatalf("got %d messages, want %d", n, SubscriberBufferSize)
+	}
+}
+
+func TestHubBacklog(t *testing.T) {
+	h := newTestHub()
+
+	h.Grant(&models.GatewayGrant{Channel: "channel-1", Token: "token"})
+	h.Grant(&models.GatewayGrant{Channel: "channel-2", Token: "token"})
+
+	s, err := h.Subscribe("token", []string{"channel-1", "channel-2"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.Publish(&models.GatewayEvent{Channel: "channel-1", Message: "seen"})
+
+	e := receive(t, s)
+	h.Unsubscribe(s)
+
+	// messages published while the client is away are replayed in order
+	h.Publish(&models.GatewayEvent{Channel: "channel-2", Message: "first"})
+	h.Publish(&models.GatewayEvent{Channel: "channel-1", Message: "second"})
+	h.Publish(&models.GatewayEvent{Channel: "channel-3", Message: "other"})
+
+	lastId, err := strconv.ParseUint(e.Id, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = h.Subscribe("token", []string{"channel-1", "channel-2"}, lastId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"first", "second"} {
+		if e := receive(t, s); e.Message != want {
+			t.Fatalf("got %+v, want %q", e, want)
+		}
+	}
+
+	select {
+	case m := <-s.Messages:
+		t.Fatalf("got unexpected message %s", m.Data)
+	default:
+	}
+
+	// expired messages are not replayed
+	h.Expire(time.Now().Add(BacklogDuration + time.Second))
+
+	s, err = h.Subscribe("token", []string{"channel-1", "channel-2"}, lastId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case m := <-s.Messages:
+		t.Fatalf("got expired message %s", m.Data)
+	default:
+	}
+}
+
+func TestPrivateEndpoints(t *testing.T) {
+	h := newTestHub()
+	handler := NewHandler(h, "secret", runner.CreateLogger("gateway-test", false))
+
+	g := &models.GatewayGrant{Channel: "channel-1", Token: "token"}
+
+	for _, secret := range []string{"", "wrong"} {
+		header := http.Header{}
+		header.Set(models.GatewaySecretHeader, secret)
+
+		// access denied responses are not found responses
+		if code, _, _, _ := handler.Grant(nil, header, g); code != http.StatusNotFound {
+			t.Fatalf("%q: got %d, want %d", secret, code, http.StatusNotFound)
+		}
+	}
+
+	if _, err := h.Subscribe("token", []string{"channel-1"}, 0); err != ErrAccessDenied {
+		t.Fatalf("got %v, want %v", err, ErrAccessDenied)
+	}
+
+	header := http.Header{}
+	header.Set(models.GatewaySecretHeader, "secret")
+
+	if code, _, _, _ := handler.Grant(nil, header, g); code != http.StatusOK {
+		t.Fatalf("got %d, want %d", code, http.StatusOK)
+	}
+
+	if _, err := h.Subscribe("token", []string{"channel-1"}, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGatewayProvider(t *testing.T) {
+	h := newTestHub()
+	g := models.NewGateway(h, logging.NewLogger("gateway-test"))
+
+	a := &models.Authenticate{
+		Account: &socialapimodels.Account{Token: "account-token"},
+		Channel: models.NewPrivateMessageChannel(models.Channel{Token: "channel-token", Group: "team"}),
+	}
+
+	if err := g.Authenticate(a); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := h.Subscribe("account-token", []string{"channel-channel-token"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pm := &models.PushMessage{Channel: &models.Channel{Id: 1, Token: "channel-token", Group: "team"}}
+	pm.EventName = "MessageAdded"
+
+	if err := g.UpdateChannel(pm); err != nil {
+		t.Fatal(err)
+	}
+
+	e := receive(t, s)
+	if e.Channel != "channel-channel-token" {
+		t.Fatalf("got %+v", e)
+	}
+
+	if m, _ := e.Message.(map[string]interface{}); m["eventName"] != "MessageAdded" {
+		t.Fatalf("got %+v", e.Message)
+	}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	h := newTestHub()
+	h.Grant(&models.GatewayGrant{Channel: "channel-1", Token: "token"})
+
+	server := httptest.NewServer(http.HandlerFunc(NewHandler(h, "secret", logging.NewLogger("gateway-test")).Subscribe))
+	defer server.Close()
+
+	// the token is only accepted from the cookie
+	for _, query := range []string{"?channel=channel-1", "?channel=channel-1&token=token"} {
+		resp, err := http.Get(server.URL + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("%s: got %d, want %d", query, resp.StatusCode, http.StatusForbidden)
+		}
+	}
+
+	req, err := http.NewRequest("GET", server.URL+"?channel=channel-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.AddCookie(&http.Cookie{Name: TokenCookieName, Value: "token"})
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("got %q content type", ct)
+	}
+
+	h.Publish(&models.GatewayEvent{Channel: "channel-1", Message: "hello"})
+
+	r := bufio.NewReader(resp.Body)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var e models.GatewayEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err != nil {
+			t.Fatal(err)
+		}
+
+		if e.Message != "hello" {
+			t.Fatalf("got %+v", e)
+		}
+
+		return
+	}
+}
@@ -28,7 +28,7 @@ func (c *Channel) IsPrivateChannel() bool {
 
 type ChannelManager interface {
 	PrepareName() string
-	GrantAccess(p *PubNub, a *Authenticate) error
+	GrantAccess(p AccessGranter, a *Authenticate) error
 }
 
 ////////// PrivateMessageChannel //////////
@@ -46,7 +46,7 @@ func (pmc *PrivateMessageChannel) PrepareName() string {
 	return fmt.Sprintf("channel-%s", pmc.Token)
 }
 
-func (pmc *PrivateMessageChannel) GrantAccess(p *PubNub, a *Authenticate) error {
+func (pmc *PrivateMessageChannel) GrantAccess(p AccessGranter, a *Authenticate) error {
 	if pmc.IsPrivateChannel() {
 		return p.GrantAccess(a, pmc)
 	}
@@ -71,7 +71,7 @@ func (nc *NotificationChannel) PrepareName() string {
 	return fmt.Sprintf("notification-%s-%s", env, nc.Account.Nick)
 }
 
-func (nc *NotificationChannel) GrantAccess(p *PubNub, a *Authenticate) error {
+func (nc *NotificationChannel) GrantAccess(p AccessGranter, a *Authenticate) error {
 	return p.GrantAccess(a, nc)
 }
 
@@ -90,6 +90,6 @@ func (mc *MessageUpdateChannel) PrepareName() string {
 	return fmt.Sprintf("channel-%s", mc.ChannelToken)
 }
 
-func (mc *MessageUpdateChannel) GrantAccess(p *PubNub, a *Authenticate) error {
+func (mc *MessageUpdateChannel) GrantAccess(p AccessGranter, a *Authenticate) error {
 	return p.GrantPublicAccess(mc)
 }
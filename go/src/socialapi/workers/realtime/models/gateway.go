@@ -0,0 +1,183 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"socialapi/config"
+
+	"github.com/cenkalti/backoff"
+	"github.com/koding/logging"
+)
+
+const (
+	// endpoints of the realtime gateway, private ones are only reachable
+	// by the internal services
+	GatewaySubscribePath = "/subscribe"
+	GatewayPublishPath   = "/private/publish"
+	GatewayGrantPath     = "/private/grant"
+	GatewayRevokePath    = "/private/revoke"
+
+	// GatewaySecretHeader carries the shared secret of the internal services,
+	// requests to the private endpoints are refused without it
+	GatewaySecretHeader = "X-Gateway-Secret"
+
+	// PublicToken is the token of the grants which are given to everyone
+	PublicToken = ""
+)
+
+// GatewayTransport publishes messages to the clients of the realtime gateway
+// and keeps their channel grants
+type GatewayTransport interface {
+	Publish(e *GatewayEvent) error
+	Grant(g *GatewayGrant) error
+	Revoke(g *GatewayGrant) error
+}
+
+// Gateway is the realtime provider which uses the self-hosted realtime
+// gateway instead of PubNub. Channel names and the authorization model are
+// the same with PubNub, so clients only change their transport.
+type Gateway struct {
+	transport GatewayTransport
+	log       logging.Logger
+}
+
+func NewGateway(t GatewayTransport, log logging.Logger) *Gateway {
+	return &Gateway{
+		transport: t,
+		log:       log,
+	}
+}
+
+func (g *Gateway) UpdateChannel(pm *PushMessage) error {
+	pmc := NewPrivateMessageChannel(*pm.Channel)
+
+	// channel grant public access for public channels
+	if !pmc.IsPrivateChannel() {
+		if err := g.GrantPublicAccess(pmc); err != nil {
+			return err
+		}
+	}
+
+	return g.publish(pmc, pm)
+}
+
+func (g *Gateway) UpdateInstance(um *UpdateInstanceMessage) error {
+	mc := NewMessageUpdateChannel(*um)
+
+	if err := g.GrantPublicAccess(mc); err != nil {
+		return err
+	}
+
+	prepareInstanceMessage(um)
+
+	if err := g.publish(mc, *um); err != nil {
+		g.log.Error("Could not push update instance event: %s", err)
+	}
+
+	return nil
+}
+
+func (g *Gateway) NotifyUser(nm *NotificationMessage) error {
+	return g.publish(NewNotificationChannel(nm.Account), nm)
+}
+
+func (g *Gateway) Authenticate(a *Authenticate) error {
+	return a.Channel.GrantAccess(g, a)
+}
+
+// GrantAccess grants access for the channel with the given token.
+func (g *Gateway) GrantAccess(a *Authenticate, c ChannelManager) error {
+	return g.transport.Grant(&GatewayGrant{
+		Channel: c.PrepareName(),
+		Token:   a.Account.Token,
+	})
+}
+
+func (g *Gateway) RevokeAccess(a *Authenticate, c ChannelManager) error {
+	return g.transport.Revoke(&GatewayGrant{
+		Channel: c.PrepareName(),
+		Token:   a.Account.Token,
+	})
+}
+
+func (g *Gateway) GrantPublicAccess(c ChannelManager) error {
+	return g.transport.Grant(&GatewayGrant{
+		Channel: c.PrepareName(),
+		Token:   PublicToken,
+	})
+}
+
+func (g *Gateway) Close() {}
+
+func (g *Gateway) publish(c ChannelManager, message interface{}) error {
+	return g.transport.Publish(&GatewayEvent{
+		Channel: c.PrepareName(),
+		Message: message,
+	})
+}
+
+// GatewayClient is the transport of the services which are running apart
+// from the realtime gateway, it uses the private gateway endpoints
+type GatewayClient struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewGatewayClient(conf config.Gateway) *GatewayClient {
+	return &GatewayClient{
+		url:    fmt.Sprintf("http://%s:%s", conf.Host, conf.Port),
+		secret: conf.Secret,
+		client: &http.Client{
+			Timeout: PublishTimeout,
+		},
+	}
+}
+
+func (gc *GatewayClient) Publish(e *GatewayEvent) error {
+	return gc.post(GatewayPublishPath, e)
+}
+
+func (gc *GatewayClient) Grant(g *GatewayGrant) error {
+	return gc.post(GatewayGrantPath, g)
+}
+
+func (gc *GatewayClient) Revoke(g *GatewayGrant) error {
+	return gc.post(GatewayRevokePath, g)
+}
+
+// post sends the request to the gateway, it retries until MaxRetryDuration
+// passes
+func (gc *GatewayClient) post(path string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = MaxRetryDuration
+
+	return backoff.Retry(func() error {
+		req, err := http.NewRequest("POST", gc.url+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(GatewaySecretHeader, gc.secret)
+
+		resp, err := gc.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("gateway responded with %s", resp.Status)
+		}
+
+		return nil
+	}, bo)
+}
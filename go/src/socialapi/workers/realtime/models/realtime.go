@@ -1,7 +1,65 @@
 package models
 
+import (
+	"fmt"
+	"socialapi/config"
+
+	"github.com/koding/logging"
+)
+
+const (
+	// ProviderPubNub delivers realtime messages via the hosted PubNub
+	// service, it is the default provider
+	ProviderPubNub = "pubnub"
+
+	// ProviderGateway delivers realtime messages via the self-hosted
+	// realtime gateway
+	ProviderGateway = "gateway"
+)
+
 type Realtimer interface {
 	UpdateChannel(req *PushMessage) error
 	UpdateInstance(req *UpdateInstanceMessage) error
 	NotifyUser(req *NotificationMessage) error
 }
+
+// AccessGranter grants access to channels, channel managers use it for
+// deciding on the access type
+type AccessGranter interface {
+	GrantAccess(a *Authenticate, c ChannelManager) error
+	GrantPublicAccess(c ChannelManager) error
+}
+
+// Provider is a realtime service which delivers messages to clients and
+// controls their access to channels
+type Provider interface {
+	Realtimer
+	AccessGranter
+	Authenticate(a *Authenticate) error
+	RevokeAccess(a *Authenticate, c ChannelManager) error
+	Close()
+}
+
+// NewProvider creates the realtime provider selected in the config
+func NewProvider(conf *config.Config, log logging.Logger) Provider {
+	if conf.GateKeeper.Provider == ProviderGateway {
+		return NewGateway(NewGatewayClient(conf.GateKeeper.Gateway), log)
+	}
+
+	return NewPubNub(conf.GateKeeper.Pubnub, log)
+}
+
+// prepareInstanceMessage converts the message update into the format clients
+// expect, it is shared by all providers
+func prepareInstanceMessage(um *UpdateInstanceMessage) {
+	// um.Body is just message data itself in a map. Since we are going
+	// to apply the changes via MongoOp in client side, we are sending
+	// the changes with '$set' key.
+	if um.EventName == "updateInstance" {
+		um.Body = map[string]interface{}{"$set": um.Body}
+	}
+
+	// Prepend instance id to event name. We are no longer creating a channel
+	// for each message by doing this.
+	um.EventName = fmt.Sprintf("instance-%s.%s", um.Token, um.EventName)
+}
@@ -66,3 +66,20 @@ type RevokeChannelAccess struct {
 	Tokens       []string `json:"tokens"`
 	ChannelToken string   `json:"channelToken"`
 }
+
+// GatewayEvent is a message published to a channel of the realtime gateway
+type GatewayEvent struct {
+	// Id is set by the gateway when the message is published, clients
+	// send the id of the last message they have received when they
+	// reconnect
+	Id      string      `json:"id,omitempty"`
+	Channel string      `json:"channel"`
+	Message interface{} `json:"message"`
+}
+
+// GatewayGrant gives or takes the access of a token to a channel of the
+// realtime gateway, empty token stands for public access
+type GatewayGrant struct {
+	Channel string `json:"channel"`
+	Token   string `json:"token"`
+}
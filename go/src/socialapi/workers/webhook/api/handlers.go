@@ -0,0 +1,116 @@
+package api
+
+import (
+	"socialapi/workers/common/handler"
+	"socialapi/workers/common/mux"
+	"socialapi/workers/helper"
+
+	throttled "gopkg.in/throttled/throttled.v2"
+)
+
+// AddHandlers adds the webhook handlers to the given Muxer
+func AddHandlers(m *mux.Mux) {
+	m.AddHandler(
+		handler.Request{
+			Handler:  List,
+			Name:     "webhook-list",
+			Type:     handler.GetRequest,
+			Endpoint: "/channel/{id}/webhook/outgoing",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  Create,
+			Name:     "webhook-create",
+			Type:     handler.PostRequest,
+			Endpoint: "/channel/{id}/webhook/outgoing",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  Update,
+			Name:     "webhook-update",
+			Type:     handler.PostRequest,
+			Endpoint: "/webhook/outgoing/{id}",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  Delete,
+			Name:     "webhook-delete",
+			Type:     handler.DeleteRequest,
+			Endpoint: "/webhook/outgoing/{id}",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  ListDeliveries,
+			Name:     "webhook-delivery-list",
+			Type:     handler.GetRequest,
+			Endpoint: "/webhook/outgoing/{id}/delivery",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  ListIncoming,
+			Name:     "webhook-incoming-list",
+			Type:     handler.GetRequest,
+			Endpoint: "/channel/{id}/webhook/incoming",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  CreateIncoming,
+			Name:     "webhook-incoming-create",
+			Type:     handler.PostRequest,
+			Endpoint: "/channel/{id}/webhook/incoming",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  UpdateIncoming,
+			Name:     "webhook-incoming-update",
+			Type:     handler.PostRequest,
+			Endpoint: "/webhook/incoming/{id}",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  DeleteIncoming,
+			Name:     "webhook-incoming-delete",
+			Type:     handler.DeleteRequest,
+			Endpoint: "/webhook/incoming/{id}",
+		},
+	)
+
+	m.AddHandler(
+		handler.Request{
+			Handler:  RegenerateToken,
+			Name:     "webhook-incoming-token",
+			Type:     handler.PostRequest,
+			Endpoint: "/webhook/incoming/{id}/token",
+		},
+	)
+
+	// posting is limited per token, external systems don't have a session
+	httpRateLimiter := helper.NewDefaultRateLimiter()
+	httpRateLimiter.VaryBy = &throttled.VaryBy{Path: true}
+
+	m.AddSessionlessHandler(
+		handler.Request{
+			Handler:   Post,
+			Name:      "webhook-incoming-post",
+			Type:      handler.PostRequest,
+			Endpoint:  "/webhook/{token}",
+			Ratelimit: httpRateLimiter,
+		},
+	)
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"socialapi/models"
+	"socialapi/request"
+	"socialapi/workers/common/response"
+	"strconv"
+
+	"github.com/koding/bongo"
+)
+
+const (
+	// MaxAttachmentsLength is the maximum size of attachments of a message
+	// posted by an incoming webhook
+	MaxAttachmentsLength = 16 * 1024
+
+	// payload keys of the messages posted by incoming webhooks
+	payloadKeyBotName     = "botName"
+	payloadKeyWebhookId   = "webhookId"
+	payloadKeyAttachments = "attachments"
+)
+
+// IncomingMessage is the request body of the incoming webhook endpoint
+type IncomingMessage struct {
+	Body        string          `json:"body"`
+	Attachments json.RawMessage `json:"attachments,omitempty"`
+}
+
+// ListIncoming lists the incoming webhooks of the channel
+func ListIncoming(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	channelId, err := request.GetURIInt64(u, "id")
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if err := checkChannelAccess(channelId, ctx); err != nil {
+		return accessErrorResponse(err)
+	}
+
+	return response.HandleResultAndError(
+		models.NewIncomingWebhook().ListByChannelId(channelId),
+	)
+}
+
+// CreateIncoming creates an incoming webhook for the channel, the response
+// contains the token of the webhook
+func CreateIncoming(u *url.URL, h http.Header, req *models.IncomingWebhook, ctx *models.Context) (int, http.Header, interface{}, error) {
+	channelId, err := request.GetURIInt64(u, "id")
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if err := checkChannelAccess(channelId, ctx); err != nil {
+		return accessErrorResponse(err)
+	}
+
+	w := models.NewIncomingWebhook()
+	w.ChannelId = channelId
+	w.CreatorId = ctx.Client.Account.Id
+	w.Name = req.Name
+
+	if err := w.Create(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewOK(w)
+}
+
+// UpdateIncoming updates the name of the incoming webhook
+func UpdateIncoming(u *url.URL, h http.Header, req *models.IncomingWebhook, ctx *models.Context) (int, http.Header, interface{}, error) {
+	w, err := fetchIncoming(u, ctx)
+	if err != nil {
+		return accessErrorResponse(err)
+	}
+
+	w.Name = req.Name
+
+	if err := w.Update(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewOK(w)
+}
+
+// RegenerateToken gives the incoming webhook a new token, messages posted
+// with the old one are rejected afterwards
+func RegenerateToken(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	w, err := fetchIncoming(u, ctx)
+	if err != nil {
+		return accessErrorResponse(err)
+	}
+
+	if err := w.RegenerateToken(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewOK(w)
+}
+
+// DeleteIncoming deletes the incoming webhook
+func DeleteIncoming(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	w, err := fetchIncoming(u, ctx)
+	if err != nil {
+		return accessErrorResponse(err)
+	}
+
+	if err := w.Delete(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewDeleted()
+}
+
+// Post creates a bot message in the channel of the incoming webhook with the
+// given token. The endpoint is sessionless, token is the only credential.
+func Post(u *url.URL, h http.Header, req *IncomingMessage) (int, http.Header, interface{}, error) {
+	if req == nil || req.Body == "" {
+		return response.NewBadRequest(models.ErrWebhookMessageIsNotSet)
+	}
+
+	if err := validateAttachments(req.Attachments); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	w := models.NewIncomingWebhook()
+	if err := w.ByToken(u.Query().Get("token")); err != nil {
+		if err == bongo.RecordNotFound {
+			return response.NewNotFound()
+		}
+
+		return response.NewBadRequest(err)
+	}
+
+	ch, err := models.Cache.Channel.ById(w.ChannelId)
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	// the webhook posts on behalf of its creator, it stops working when the
+	// creator can not open the channel anymore
+	canOpen, err := ch.CanOpen(w.CreatorId)
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if !canOpen {
+		return response.NewAccessDenied(models.ErrAccessDenied)
+	}
+
+	bot, err := models.Cache.Account.ByNick("bot")
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	cm := models.NewChannelMessage()
+	cm.TypeConstant = models.ChannelMessage_TYPE_BOT
+	cm.AccountId = bot.Id
+	cm.InitialChannelId = ch.Id
+	cm.Body = req.Body
+	cm.SetPayload(payloadKeyBotName, w.Name)
+	cm.SetPayload(payloadKeyWebhookId, strconv.FormatInt(w.Id, 10))
+	if len(req.Attachments) != 0 {
+		cm.SetPayload(payloadKeyAttachments, string(req.Attachments))
+	}
+
+	if err := cm.Create(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if _, err := ch.AddMessage(cm); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewOK(cm)
+}
+
+// validateAttachments checks that the attachments, when given, are a json
+// array within the size limit
+func validateAttachments(attachments json.RawMessage) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	if len(attachments) > MaxAttachmentsLength {
+		return models.ErrAttachmentsTooLong
+	}
+
+	var list []interface{}
+	if err := json.Unmarshal(attachments, &list); err != nil {
+		return models.ErrAttachmentsNotValid
+	}
+
+	return nil
+}
+
+// fetchIncoming fetches the incoming webhook given in the url and checks
+// that the requester can manage the webhooks of its channel
+func fetchIncoming(u *url.URL, ctx *models.Context) (*models.IncomingWebhook, error) {
+	id, err := request.GetURIInt64(u, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	w := models.NewIncomingWebhook()
+	if err := w.ById(id); err != nil {
+		return nil, err
+	}
+
+	if err := checkChannelAccess(w.ChannelId, ctx); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
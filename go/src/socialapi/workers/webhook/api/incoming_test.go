@@ -0,0 +1,28 @@
+package api
+
+import (
+	"encoding/json"
+	"socialapi/models"
+	"strings"
+	"testing"
+)
+
+func TestValidateAttachments(t *testing.T) {
+	tests := []struct {
+		attachments string
+		err         error
+	}{
+		{"", nil},
+		{`[]`, nil},
+		{`[{"title":"build #42","color":"good"}]`, nil},
+		{`{"title":"build #42"}`, models.ErrAttachmentsNotValid},
+		{`"build #42"`, models.ErrAttachmentsNotValid},
+		{`["` + strings.Repeat("a", MaxAttachmentsLength) + `"]`, models.ErrAttachmentsTooLong},
+	}
+
+	for i, test := range tests {
+		if err := validateAttachments(json.RawMessage(test.attachments)); err != test.err {
+			t.Errorf("%d: got %v, want %v", i, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"socialapi/models"
+	"socialapi/request"
+	"socialapi/workers/common/response"
+
+	"github.com/koding/bongo"
+)
+
+// List lists the webhook subscriptions of the channel
+func List(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	channelId, err := request.GetURIInt64(u, "id")
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if err := checkChannelAccess(channelId, ctx); err != nil {
+		return accessErrorResponse(err)
+	}
+
+	return response.HandleResultAndError(
+		models.NewWebhookSubscription().ListByChannelId(channelId),
+	)
+}
+
+// Create creates a webhook subscription for the channel
+func Create(u *url.URL, h http.Header, req *models.WebhookSubscription, ctx *models.Context) (int, http.Header, interface{}, error) {
+	channelId, err := request.GetURIInt64(u, "id")
+	if err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	if err := checkChannelAccess(channelId, ctx); err != nil {
+		return accessErrorResponse(err)
+	}
+
+	s := models.NewWebhookSubscription()
+	s.ChannelId = channelId
+	s.CreatorId = ctx.Client.Account.Id
+	s.URL = req.URL
+	s.Secret = req.Secret
+	s.Events = req.Events
+
+	if err := s.Create(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewOK(s)
+}
+
+// Update updates the url, the secret and the events of the subscription
+func Update(u *url.URL, h http.Header, req *models.WebhookSubscription, ctx *models.Context) (int, http.Header, interface{}, error) {
+	s, err := fetchSubscription(u, ctx)
+	if err != nil {
+		return accessErrorResponse(err)
+	}
+
+	s.URL = req.URL
+	s.Events = req.Events
+	if req.Secret != "" {
+		s.Secret = req.Secret
+	}
+
+	if err := s.Update(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewOK(s)
+}
+
+// Delete deletes the subscription with its delivery log
+func Delete(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	s, err := fetchSubscription(u, ctx)
+	if err != nil {
+		return accessErrorResponse(err)
+	}
+
+	if err := s.Delete(); err != nil {
+		return response.NewBadRequest(err)
+	}
+
+	return response.NewDeleted()
+}
+
+// ListDeliveries lists the delivery log of the subscription, newest first
+func ListDeliveries(u *url.URL, h http.Header, _ interface{}, ctx *models.Context) (int, http.Header, interface{}, error) {
+	s, err := fetchSubscription(u, ctx)
+	if err != nil {
+		return accessErrorResponse(err)
+	}
+
+	return response.HandleResultAndError(
+		models.NewWebhookDelivery().ListBySubscriptionId(s.Id, request.GetQuery(u)),
+	)
+}
+
+// fetchSubscription fetches the subscription given in the url and checks
+// that the requester can manage the webhooks of its channel
+func fetchSubscription(u *url.URL, ctx *models.Context) (*models.WebhookSubscription, error) {
+	id, err := request.GetURIInt64(u, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	s := models.NewWebhookSubscription()
+	if err := s.ById(id); err != nil {
+		return nil, err
+	}
+
+	if err := checkChannelAccess(s.ChannelId, ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// checkChannelAccess checks that the requester can manage the webhooks of the
+// channel, only the creator of the channel and the admins of its group can
+func checkChannelAccess(channelId int64, ctx *models.Context) error {
+	if !ctx.IsLoggedIn() {
+		return models.ErrNotLoggedIn
+	}
+
+	if channelId == 0 {
+		return models.ErrChannelIdIsNotSet
+	}
+
+	ch, err := models.Cache.Channel.ById(channelId)
+	if err != nil {
+		return err
+	}
+
+	if ch.GroupName != ctx.GroupName {
+		return models.ErrAccessDenied
+	}
+
+	if ch.CreatorId == ctx.Client.Account.Id {
+		return nil
+	}
+
+	if err := ctx.IsGroupAdmin(); err != nil {
+		if err == models.ErrNotAdmin {
+			return models.ErrAccessDenied
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func accessErrorResponse(err error) (int, http.Header, interface{}, error) {
+	switch err {
+	case bongo.RecordNotFound:
+		return response.NewNotFound()
+	case models.ErrNotLoggedIn, models.ErrAccessDenied:
+		return response.NewAccessDenied(err)
+	default:
+		return response.NewBadRequest(err)
+	}
+}
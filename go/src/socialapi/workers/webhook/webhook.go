@@ -0,0 +1,520 @@
+// Package webhook provides the logical part of the webhook worker, which
+// delivers channel events to outgoing webhooks of the channels
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"socialapi/models"
+	"sync"
+	"time"
+
+	"github.com/koding/bongo"
+	"github.com/koding/logging"
+	"github.com/streadway/amqp"
+)
+
+const (
+	// EventHeader holds the name of the delivered event
+	EventHeader = "X-Koding-Event"
+
+	// DeliveryHeader holds the unique id of the delivery, it is the same for
+	// all attempts of an event
+	DeliveryHeader = "X-Koding-Delivery"
+
+	// SignatureHeader holds the HMAC-SHA256 signature of the request body,
+	// computed with the secret of the subscription
+	SignatureHeader = "X-Koding-Signature"
+
+	// DefaultMaxAttempts is the number of attempts made before a delivery
+	// is given up
+	DefaultMaxAttempts = 5
+
+	// DefaultTimeout is the timeout of a single delivery attempt
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultPollInterval is how often pending deliveries are looked up
+	DefaultPollInterval = 5 * time.Second
+
+	// DefaultRetention is how long finished deliveries are kept in the
+	// delivery log
+	DefaultRetention = 7 * 24 * time.Hour
+
+	// maxInterval limits the wait time between retries
+	maxInterval = time.Hour
+
+	// DefaultConcurrency is the number of subscriptions deliveries are sent
+	// to at the same time
+	DefaultConcurrency = 10
+
+	// pollLimit is the number of pending deliveries handled in one poll
+	pollLimit = 100
+
+	// cleanupInterval is how often old deliveries are removed
+	cleanupInterval = time.Hour
+)
+
+// errPrivateAddress is returned when the webhook host resolves to an address
+// webhooks can not be sent to
+var errPrivateAddress = errors.New("webhook host resolves to a private address")
+
+// Payload is the body of webhook requests
+type Payload struct {
+	Event     string      `json:"event"`
+	ChannelId int64       `json:"channelId,string"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// Controller holds the basic context data for handlers
+type Controller struct {
+	log    logging.Logger
+	client *http.Client
+
+	// MaxAttempts is the number of attempts made for each delivery
+	MaxAttempts int
+
+	// InitialInterval is the wait time before the first retry, it grows
+	// exponentially with each retry
+	InitialInterval time.Duration
+
+	// PollInterval is how often pending deliveries are looked up
+	PollInterval time.Duration
+
+	// Concurrency is the number of subscriptions deliveries are sent to at
+	// the same time
+	Concurrency int
+
+	// Retention is how long finished deliveries are kept
+	Retention time.Duration
+
+	// createDelivery and updateDelivery store the deliveries
+	createDelivery func(*models.WebhookDelivery) error
+	updateDelivery func(*models.WebhookDelivery) error
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+// New creates a controller
+func New(log logging.Logger) *Controller {
+	return &Controller{
+		log:             log,
+		client:          newClient(),
+		MaxAttempts:     DefaultMaxAttempts,
+		InitialInterval: time.Second,
+		PollInterval:    DefaultPollInterval,
+		Concurrency:     DefaultConcurrency,
+		Retention:       DefaultRetention,
+		createDelivery:  (*models.WebhookDelivery).Create,
+		updateDelivery:  (*models.WebhookDelivery).Update,
+		closeChan:       make(chan struct{}),
+	}
+}
+
+// newClient creates an http client which connects only to public addresses.
+// Addresses are checked when connecting, so neither redirects nor changed
+// dns records can make the worker reach internal services
+func newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   DefaultTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ip := range ips {
+			if !models.IsPublicWebhookIP(ip.IP) {
+				return nil, errPrivateAddress
+			}
+		}
+
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("no addresses found for %q", host)
+		}
+
+		return dialer.DialContext(ctx, network, net.JoinHostPort(ips[0].IP.String(), port))
+	}
+
+	return &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &http.Transport{
+			DialContext:         dial,
+			TLSHandshakeTimeout: DefaultTimeout,
+		},
+	}
+}
+
+// DefaultErrHandler handles the errors for webhook worker
+func (c *Controller) DefaultErrHandler(delivery amqp.Delivery, err error) bool {
+	c.log.Error("an error occurred while sending webhook event: %s", err)
+	delivery.Ack(false)
+	return false
+}
+
+// MessageListSaved sends message created event to the webhooks of the channel
+func (c *Controller) MessageListSaved(cml *models.ChannelMessageList) error {
+	cm, err := models.Cache.Message.ById(cml.MessageId)
+	if err != nil {
+		return err
+	}
+
+	return c.send(cml.ChannelId, models.Webhook_EVENT_MESSAGE_CREATED, cm)
+}
+
+// MessageUpdated sends message updated event to the webhooks of all channels
+// of the message
+func (c *Controller) MessageUpdated(cm *models.ChannelMessage) error {
+	cmls, err := cm.GetChannelMessageLists()
+	if err != nil {
+		return err
+	}
+
+	for _, cml := range cmls {
+		if err := c.send(cml.ChannelId, models.Webhook_EVENT_MESSAGE_UPDATED, cm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ChannelParticipantsAdded sends participant added event to the webhooks of
+// the channel
+func (c *Controller) ChannelParticipantsAdded(pe *models.ParticipantEvent) error {
+	return c.send(pe.Id, models.Webhook_EVENT_PARTICIPANT_ADDED, pe.Participants)
+}
+
+// ChannelParticipantsRemoved sends participant removed event to the webhooks
+// of the channel
+func (c *Controller) ChannelParticipantsRemoved(pe *models.ParticipantEvent) error {
+	return c.send(pe.Id, models.Webhook_EVENT_PARTICIPANT_REMOVED, pe.Participants)
+}
+
+// ChannelUpdated sends channel updated event to the webhooks of the channel
+func (c *Controller) ChannelUpdated(ch *models.Channel) error {
+	return c.send(ch.Id, models.Webhook_EVENT_CHANNEL_UPDATED, ch)
+}
+
+// send queues the event for all subscribed webhooks of the channel. Queued
+// deliveries are stored, so they are not lost when the worker restarts, and
+// they are sent by Poll, so a slow receiver does not block the processing of
+// other events.
+func (c *Controller) send(channelId int64, event string, data interface{}) error {
+	subscriptions, err := models.NewWebhookSubscription().ListByEvent(channelId, event)
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(&Payload{
+		Event:     event,
+		ChannelId: channelId,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, s := range subscriptions {
+		d := models.NewWebhookDelivery()
+		d.SubscriptionId = s.Id
+		d.DeliveryId = models.NewToken(time.Now()).String()
+		d.Event = event
+		d.Payload = string(body)
+
+		if err := c.createDelivery(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start starts sending queued deliveries and removing old ones in the
+// background until Close is called
+func (c *Controller) Start() {
+	go c.run()
+}
+
+// Close stops the background processing started by Start
+func (c *Controller) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closeChan)
+	})
+}
+
+func (c *Controller) run() {
+	poll := time.NewTicker(c.PollInterval)
+	defer poll.Stop()
+
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-poll.C:
+			if err := c.Poll(); err != nil {
+				c.log.Error("Could not send pending webhook deliveries: %s", err)
+			}
+		case <-cleanup.C:
+			if err := c.Cleanup(); err != nil {
+				c.log.Error("Could not remove old webhook deliveries: %s", err)
+			}
+		case <-c.closeChan:
+			return
+		}
+	}
+}
+
+// Poll sends pending deliveries which are due. Each delivery is claimed
+// before it is sent, so a delivery is not sent twice by concurrent workers
+func (c *Controller) Poll() error {
+	deliveries, err := models.NewWebhookDelivery().ListDue(time.Now().UTC(), pollLimit)
+	if err != nil {
+		return err
+	}
+
+	return c.dispatch(deliveries, c.deliverAll)
+}
+
+// dispatch groups deliveries by their subscriptions and passes the groups to
+// fn from at most Concurrency goroutines, so a slow receiver does not hold
+// back deliveries to the other ones. Deliveries of a group keep their order.
+// The first error returned by fn is returned after all groups are handled
+func (c *Controller) dispatch(deliveries []models.WebhookDelivery, fn func([]*models.WebhookDelivery) error) error {
+	var ids []int64
+	groups := make(map[int64][]*models.WebhookDelivery)
+
+	for i := range deliveries {
+		d := &deliveries[i]
+
+		if _, ok := groups[d.SubscriptionId]; !ok {
+			ids = append(ids, d.SubscriptionId)
+		}
+
+		groups[d.SubscriptionId] = append(groups[d.SubscriptionId], d)
+	}
+
+	workers := c.Concurrency
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	groupC := make(chan []*models.WebhookDelivery)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for group := range groupC {
+				if err := fn(group); err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		groupC <- groups[id]
+	}
+
+	close(groupC)
+	wg.Wait()
+
+	return firstErr
+}
+
+// deliverAll claims and sends deliveries of a single subscription one by one
+func (c *Controller) deliverAll(deliveries []*models.WebhookDelivery) error {
+	var s *models.WebhookSubscription
+
+	for _, d := range deliveries {
+		// the claim expires after the request timeout, so a delivery of a
+		// crashed worker is picked up again
+		ok, err := d.Claim(time.Now().UTC().Add(2 * DefaultTimeout))
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if s == nil {
+			s = models.NewWebhookSubscription()
+			if err := s.ById(d.SubscriptionId); err != nil && err != bongo.RecordNotFound {
+				return err
+			}
+		}
+
+		if s.Id == 0 {
+			// subscription was deleted in the meantime
+			c.Deliver(nil, d)
+		} else {
+			c.Deliver(s, d)
+		}
+	}
+
+	return nil
+}
+
+// Cleanup removes finished deliveries older than Retention from the
+// delivery log
+func (c *Controller) Cleanup() error {
+	return models.NewWebhookDelivery().DeleteFinishedBefore(time.Now().UTC().Add(-c.Retention))
+}
+
+// Deliver makes a single attempt of the pending delivery and stores its
+// result. A failed delivery is scheduled for a retry with an exponential
+// backoff until MaxAttempts is reached; errors which can not be fixed by
+// retrying fail the delivery immediately. Nil subscription means that it
+// was deleted in the meantime.
+func (c *Controller) Deliver(s *models.WebhookSubscription, d *models.WebhookDelivery) {
+	d.Attempt++
+
+	var permanent bool
+	if s != nil {
+		permanent = isPrivateAddressErr(c.post(s, d))
+	} else {
+		d.StatusCode = 0
+		d.Error = "webhook subscription does not exist"
+		permanent = true
+	}
+
+	switch {
+	case d.IsDelivered():
+		d.Status = models.WebhookDelivery_STATUS_DELIVERED
+	case permanent || d.Attempt >= c.MaxAttempts || !isRetryable(d):
+		d.Status = models.WebhookDelivery_STATUS_FAILED
+		c.log.Error("Giving up webhook delivery %s after %d attempts: %s", d.DeliveryId, d.Attempt, d.Error)
+	default:
+		d.NextAttemptAt = time.Now().UTC().Add(c.backoff(d.Attempt))
+	}
+
+	if err := c.updateDelivery(d); err != nil {
+		c.log.Error("Could not store webhook delivery %s: %s", d.DeliveryId, err)
+	}
+}
+
+// backoff returns the wait time before the next attempt after the given
+// number of failed ones
+func (c *Controller) backoff(attempt int) time.Duration {
+	interval := c.InitialInterval
+	for i := 1; i < attempt && interval < maxInterval; i++ {
+		interval *= 2
+	}
+
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	return interval
+}
+
+// post makes a single delivery attempt, the returned error is the error of
+// the request if it was not sent. Response bodies are not stored, since they
+// can contain anything the receiver returns
+func (c *Controller) post(s *models.WebhookSubscription, d *models.WebhookDelivery) error {
+	d.StatusCode = 0
+	d.Error = ""
+
+	body := []byte(d.Payload)
+
+	req, err := http.NewRequest("POST", s.URL, bytes.NewReader(body))
+	if err != nil {
+		d.Error = err.Error()
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, d.DeliveryId)
+	req.Header.Set(SignatureHeader, s.Sign(body))
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	d.Duration = int64(time.Since(start) / time.Millisecond)
+
+	if err != nil {
+		d.Error = err.Error()
+		return err
+	}
+	defer resp.Body.Close()
+
+	d.StatusCode = resp.StatusCode
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		d.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+
+	// drain the body, so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return nil
+}
+
+// isPrivateAddressErr checks whether the request failed because the webhook
+// host resolves to a private address
+func isPrivateAddressErr(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case *url.Error:
+			err = e.Err
+		case *net.OpError:
+			err = e.Err
+		default:
+			return err == errPrivateAddress
+		}
+	}
+
+	return false
+}
+
+// isRetryable checks whether a failed delivery can succeed later; client
+// errors other than rate limiting are not retried
+func isRetryable(d *models.WebhookDelivery) bool {
+	switch {
+	case d.StatusCode == 0:
+		return true
+	case d.StatusCode == http.StatusTooManyRequests:
+		return true
+	case d.StatusCode >= 500:
+		return true
+	default:
+		return false
+	}
+}
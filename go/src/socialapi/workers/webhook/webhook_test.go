@@ -0,0 +1,272 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"socialapi/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koding/logging"
+)
+
+func newTestController() (*Controller, *[]models.WebhookDelivery) {
+	var mu sync.Mutex
+	var deliveries []models.WebhookDelivery
+
+	c := New(logging.NewLogger("webhook_test"))
+	c.InitialInterval = time.Millisecond
+	c.updateDelivery = func(d *models.WebhookDelivery) error {
+		mu.Lock()
+		deliveries = append(deliveries, *d)
+		mu.Unlock()
+		return nil
+	}
+
+	// test servers listen on loopback, which the default client refuses
+	c.client = &http.Client{Timeout: DefaultTimeout}
+
+	return c, &deliveries
+}
+
+func newTestDelivery(event string, body []byte) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		SubscriptionId: 1,
+		DeliveryId:     models.NewToken(time.Now()).String(),
+		Event:          event,
+		Payload:        string(body),
+		Status:         models.WebhookDelivery_STATUS_PENDING,
+	}
+}
+
+func TestDeliverSigned(t *testing.T) {
+	body := []byte(`{"event":"message_created"}`)
+	s := &models.WebhookSubscription{Id: 1, Secret: "secret"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("ReadAll()=%s", err)
+		}
+
+		if string(p) != string(body) {
+			t.Errorf("got body %q, want %q", p, body)
+		}
+
+		if got := r.Header.Get(EventHeader); got != models.Webhook_EVENT_MESSAGE_CREATED {
+			t.Errorf("got event %q, want %q", got, models.Webhook_EVENT_MESSAGE_CREATED)
+		}
+
+		if got, want := r.Header.Get(SignatureHeader), s.Sign(p); got != want {
+			t.Errorf("got signature %q, want %q", got, want)
+		}
+
+		if r.Header.Get(DeliveryHeader) == "" {
+			t.Error("delivery id is empty")
+		}
+	}))
+	defer ts.Close()
+
+	s.URL = ts.URL
+
+	c, deliveries := newTestController()
+
+	d := newTestDelivery(models.Webhook_EVENT_MESSAGE_CREATED, body)
+	c.Deliver(s, d)
+
+	if !d.IsDelivered() {
+		t.Fatalf("delivery failed: %d %s", d.StatusCode, d.Error)
+	}
+
+	if len(*deliveries) != 1 {
+		t.Fatalf("got %d stored deliveries, want 1", len(*deliveries))
+	}
+
+	if d.Status != models.WebhookDelivery_STATUS_DELIVERED || d.Attempt != 1 {
+		t.Fatalf("unexpected delivery: %+v", d)
+	}
+}
+
+func TestDeliverRetry(t *testing.T) {
+	cases := map[string]struct {
+		statuses []int
+		attempts int
+		ok       bool
+	}{
+		"server error is retried": {
+			statuses: []int{500, 502, 200},
+			attempts: 3,
+			ok:       true,
+		},
+		"rate limit is retried": {
+			statuses: []int{429, 200},
+			attempts: 2,
+			ok:       true,
+		},
+		"client error is not retried": {
+			statuses: []int{404},
+			attempts: 1,
+			ok:       false,
+		},
+		"attempts are limited": {
+			statuses: []int{500, 500, 500, 500},
+			attempts: 3,
+			ok:       false,
+		},
+	}
+
+	for name, cas := range cases {
+		// capture range variable here
+		cas := cas
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var n int
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				status := cas.statuses[n]
+				n++
+				mu.Unlock()
+
+				w.WriteHeader(status)
+			}))
+			defer ts.Close()
+
+			s := &models.WebhookSubscription{Id: 1, Secret: "secret", URL: ts.URL}
+
+			c, deliveries := newTestController()
+			c.MaxAttempts = 3
+
+			d := newTestDelivery(models.Webhook_EVENT_CHANNEL_UPDATED, []byte("{}"))
+			for d.IsPending() {
+				before := time.Now().UTC()
+				c.Deliver(s, d)
+
+				if d.IsPending() && !d.NextAttemptAt.After(before) {
+					t.Fatalf("got next attempt at %s, want it after %s", d.NextAttemptAt, before)
+				}
+			}
+
+			if d.IsDelivered() != cas.ok {
+				t.Fatalf("got delivered=%t, want %t: %s", d.IsDelivered(), cas.ok, d.Error)
+			}
+
+			if len(*deliveries) != cas.attempts {
+				t.Fatalf("got %d attempts, want %d", len(*deliveries), cas.attempts)
+			}
+
+			if d.Attempt != cas.attempts {
+				t.Fatalf("got last attempt %d, want %d", d.Attempt, cas.attempts)
+			}
+		})
+	}
+}
+
+func TestDeliverPrivateAddress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request to a private address was sent")
+	}))
+	defer ts.Close()
+
+	s := &models.WebhookSubscription{Id: 1, Secret: "secret", URL: ts.URL}
+
+	c, _ := newTestController()
+	c.client = newClient()
+
+	d := newTestDelivery(models.Webhook_EVENT_CHANNEL_UPDATED, []byte("{}"))
+	c.Deliver(s, d)
+
+	if d.Status != models.WebhookDelivery_STATUS_FAILED {
+		t.Fatalf("got status %q, want %q: %s", d.Status, models.WebhookDelivery_STATUS_FAILED, d.Error)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := New(logging.NewLogger("webhook_test"))
+
+	cases := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		4:  8 * time.Second,
+		30: maxInterval,
+	}
+
+	for attempt, want := range cases {
+		if got := c.backoff(attempt); got != want {
+			t.Errorf("backoff(%d)=%s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	c := New(logging.NewLogger("webhook_test"))
+	c.Concurrency = 2
+
+	var deliveries []models.WebhookDelivery
+	for i, id := range []int64{1, 2, 1, 3, 2, 3} {
+		deliveries = append(deliveries, models.WebhookDelivery{Id: int64(i + 1), SubscriptionId: id})
+	}
+
+	// deliveries to the first subscription are blocked until the other
+	// ones are sent
+	var others sync.WaitGroup
+	others.Add(2)
+
+	release := make(chan struct{})
+	go func() {
+		others.Wait()
+		close(release)
+	}()
+
+	var mu sync.Mutex
+	got := make(map[int64][]int64)
+
+	err := c.dispatch(deliveries, func(group []*models.WebhookDelivery) error {
+		id := group[0].SubscriptionId
+
+		if id == 1 {
+			select {
+			case <-release:
+			case <-time.After(5 * time.Second):
+				t.Errorf("deliveries to other subscriptions were held back")
+			}
+		}
+
+		mu.Lock()
+		for _, d := range group {
+			got[d.SubscriptionId] = append(got[d.SubscriptionId], d.Id)
+		}
+		mu.Unlock()
+
+		if id != 1 {
+			others.Done()
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("dispatch()=%s", err)
+	}
+
+	want := map[int64][]int64{
+		1: {1, 3},
+		2: {2, 5},
+		3: {4, 6},
+	}
+
+	for id, ids := range want {
+		if len(got[id]) != len(ids) {
+			t.Fatalf("got %v deliveries for subscription %d, want %v", got[id], id, ids)
+		}
+
+		for i := range ids {
+			if got[id][i] != ids[i] {
+				t.Errorf("got %v deliveries for subscription %d, want %v", got[id], id, ids)
+				break
+			}
+		}
+	}
+}